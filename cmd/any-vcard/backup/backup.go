@@ -0,0 +1,87 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/backup"
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/sink"
+	"github.com/rubiojr/any-vcard/internal/source"
+	"github.com/urfave/cli/v3"
+)
+
+var Command = &cli.Command{
+	Name:  "backup",
+	Usage: "Snapshot every contact in the space (vCard, JSON and photos) into a dated archive",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "out",
+			Usage: "Directory the backup archive is written to",
+			Value: "./backups",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if err := util.RequireFlags(cmd, "app-key", "space"); err != nil {
+			return err
+		}
+		if err := util.Preflight(ctx, cmd, cmd.String("space")); err != nil {
+			return err
+		}
+		return backupContacts(ctx, cmd)
+	},
+}
+
+func backupContacts(ctx context.Context, cmd *cli.Command) error {
+	client := util.NewClient(cmd)
+	spaceID := cmd.String("space")
+	outDir := cmd.String("out")
+
+	typeKey, err := util.FindContactType(ctx, client, spaceID)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	path := filepath.Join(outDir, backup.FileName(time.Now()))
+
+	// Write to a temporary file so an interrupted backup never looks complete
+	file, err := os.CreateTemp(outDir, ".backup-*.zip")
+	if err != nil {
+		return fmt.Errorf("failed to create backup file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	src := &source.Anytype{Client: client, SpaceID: spaceID, TypeKey: typeKey}
+	defer src.Close()
+
+	w := backup.NewWriter(file, spaceID, typeKey)
+	n, err := sink.Copy(ctx, w, src)
+	if err != nil {
+		return fmt.Errorf("backup failed after %d contact(s): %w", n, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to finish backup archive: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("failed to save backup: %w", err)
+	}
+
+	for _, err := range w.PhotoErrors {
+		log.Printf("Warning: photo not backed up: %v", err)
+	}
+	m := w.Manifest()
+	i18n.Printf("✓ Backed up %d contact(s) and %d photo(s) to %s\n", m.Contacts, m.Photos, path)
+	return nil
+}
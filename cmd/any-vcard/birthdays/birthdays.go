@@ -0,0 +1,89 @@
+package birthdays
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/rubiojr/anytype-go"
+	"github.com/urfave/cli/v3"
+)
+
+var Command = &cli.Command{
+	Name:  "birthdays",
+	Usage: "Manage computed birthday properties",
+	Commands: []*cli.Command{
+		refreshCommand,
+	},
+}
+
+var refreshCommand = &cli.Command{
+	Name:  "refresh",
+	Usage: "Recompute age and next_birthday for every contact (run daily to keep them current)",
+	Flags: []cli.Flag{
+		util.BirthdayPlaceholderYearFlag,
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if err := util.RequireFlags(cmd, "app-key", "space"); err != nil {
+			return err
+		}
+		if err := util.Preflight(ctx, cmd, cmd.String("space")); err != nil {
+			return err
+		}
+		return refreshBirthdays(ctx, cmd)
+	},
+}
+
+func refreshBirthdays(ctx context.Context, cmd *cli.Command) error {
+	client := util.NewClient(cmd)
+	spaceID := cmd.String("space")
+
+	typeKey, err := util.FindContactType(ctx, client, spaceID)
+	if err != nil {
+		return err
+	}
+
+	if err := util.EnsureProperties(ctx, client, spaceID, util.BirthdayFieldProperties); err != nil {
+		return fmt.Errorf("failed to ensure birthday properties: %w", err)
+	}
+
+	objects, err := util.SearchAll(ctx, client, spaceID, anytype.SearchRequest{Types: []string{typeKey}})
+	if err != nil {
+		return fmt.Errorf("failed to search contacts: %w", err)
+	}
+
+	now := time.Now()
+	placeholderYear := cmd.Int("birthday-placeholder-year")
+	var updated int
+	for _, obj := range objects {
+		var contact vcard.Contact
+		for _, prop := range obj.Properties {
+			switch prop.Key {
+			case "birthday":
+				contact.Birthday = prop.Date
+			case "birthday_text":
+				contact.BirthdayText = prop.Text
+			}
+		}
+
+		contact.ComputeBirthdayFields(now, placeholderYear)
+		props := vcard.BirthdayFieldProperties(contact)
+		if len(props) == 0 {
+			continue
+		}
+
+		err := client.Space(spaceID).Object(obj.ID).Update(ctx, anytype.UpdateObjectRequest{Properties: props})
+		if err != nil {
+			log.Printf("Error updating %s: %v", obj.Name, err)
+			continue
+		}
+		updated++
+	}
+
+	i18n.Printf("✓ Refreshed birthday fields for %d/%d contacts\n", updated, len(objects))
+	return nil
+}
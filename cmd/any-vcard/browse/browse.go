@@ -0,0 +1,105 @@
+package browse
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/browse"
+	"github.com/rubiojr/any-vcard/internal/service"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/term"
+)
+
+var Command = &cli.Command{
+	Name:  "browse",
+	Usage: "Browse, search and edit the space contacts interactively",
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if err := util.RequireFlags(cmd, "app-key", "space"); err != nil {
+			return err
+		}
+		if err := util.Preflight(ctx, cmd, cmd.String("space")); err != nil {
+			return err
+		}
+		fd := int(os.Stdin.Fd())
+		if !term.IsTerminal(fd) || !term.IsTerminal(int(os.Stdout.Fd())) {
+			return fmt.Errorf("browse needs an interactive terminal")
+		}
+
+		svc, err := util.NewContactService(ctx, cmd)
+		if err != nil {
+			return err
+		}
+		contacts, err := svc.Search(ctx, "", 0)
+		if err != nil {
+			return err
+		}
+		col, err := vcard.NewCollator(vcard.Locale)
+		if err != nil {
+			return err
+		}
+		slices.SortStableFunc(contacts, func(a, b *vcard.Contact) int {
+			return col.CompareString(a.DisplayName(), b.DisplayName())
+		})
+
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("failed to set up terminal: %w", err)
+		}
+		// Alternate screen, cursor hidden
+		fmt.Print("\x1b[?1049h\x1b[?25l")
+		defer func() {
+			fmt.Print("\x1b[?25h\x1b[?1049l")
+			term.Restore(fd, state)
+		}()
+
+		return run(ctx, svc, cmd.String("space"), browse.New(contacts))
+	},
+}
+
+func run(ctx context.Context, svc *service.Service, spaceID string, m *browse.Model) error {
+	buf := make([]byte, 64)
+	for {
+		width, height, err := term.GetSize(int(os.Stdout.Fd()))
+		if err != nil {
+			width, height = 80, 24
+		}
+		fmt.Print("\x1b[H\x1b[2J" + strings.Join(m.Render(width, height), "\r\n"))
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return err
+		}
+		for _, k := range browse.ParseKeys(buf[:n]) {
+			a := m.Update(k)
+			switch a.Kind {
+			case browse.ActionQuit:
+				return nil
+			case browse.ActionOpen:
+				if err := util.OpenURL(util.ObjectURL(spaceID, a.Contact.ObjectID)); err != nil {
+					m.Status = fmt.Sprintf("Could not open Anytype: %v", err)
+				}
+			case browse.ActionSave:
+				if err := svc.Save(ctx, a.Contact); err != nil {
+					m.Status = err.Error()
+					continue
+				}
+				m.Replace(a.Contact)
+				m.Status = fmt.Sprintf("✓ Saved %s", a.Contact.DisplayName())
+			case browse.ActionMerge:
+				merged, err := svc.Merge(ctx, a.Contact.ObjectID, a.Duplicate.ObjectID)
+				if err != nil {
+					m.Status = err.Error()
+					continue
+				}
+				m.Replace(merged)
+				m.Remove(a.Duplicate.ObjectID)
+				m.Status = fmt.Sprintf("✓ Merged %s into %s", a.Duplicate.DisplayName(), merged.DisplayName())
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package convert
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/sink"
+	"github.com/rubiojr/any-vcard/internal/source"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/urfave/cli/v3"
+)
+
+var Command = &cli.Command{
+	Name:      "convert",
+	Usage:     "Convert contacts between file formats (.vcf, .csv)",
+	ArgsUsage: "<input-file|-> <output-file|->",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "Input file format: vcard, ldif, mecard, html, csv, outlook-csv or thunderbird-csv (default: detected from the file extension)",
+		},
+		&cli.StringFlag{
+			Name:  "vcard-version",
+			Usage: "vCard version for .vcf output: 3.0 (older phones) or 4.0",
+			Value: vcard.Version4,
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if cmd.Args().Len() != 2 {
+			return fmt.Errorf("input and output files are required")
+		}
+		return convertFile(ctx, cmd.Args().Get(0), cmd.String("format"), cmd.Args().Get(1), cmd.String("vcard-version"))
+	},
+}
+
+func convertFile(ctx context.Context, input, format, output, version string) error {
+	src, err := source.OpenFormat(input, format)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := sink.CreateVersion(output, version)
+	if err != nil {
+		return err
+	}
+
+	n, err := sink.Copy(ctx, dst, src)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("conversion failed after %d contact(s): %w", n, err)
+	}
+
+	if output != "-" {
+		i18n.Printf("✓ Converted %d contact(s) to %s\n", n, output)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package copy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/progress"
+	"github.com/rubiojr/any-vcard/internal/sink"
+	"github.com/rubiojr/any-vcard/internal/source"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/rubiojr/anytype-go"
+	"github.com/urfave/cli/v3"
+)
+
+var Command = &cli.Command{
+	Name:  "copy",
+	Usage: "Copy contacts from one space into another, deduplicating in the target",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:     "from",
+			Usage:    "Space ID to read contacts from",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:     "to",
+			Usage:    "Space ID to copy contacts into",
+			Required: true,
+		},
+		&cli.StringSliceFlag{
+			Name:  "tag",
+			Usage: "Only copy contacts with this tag (repeatable, matches any)",
+		},
+		&cli.StringFlag{
+			Name:  "org",
+			Usage: "Only copy contacts whose organization contains this text",
+		},
+		&cli.StringFlag{
+			Name:  "modified-since",
+			Usage: "Only copy contacts modified since a date (YYYY-MM-DD or RFC3339)",
+		},
+		&cli.StringFlag{
+			Name:  "query",
+			Usage: "Only copy contacts matching a full-text search query",
+		},
+		util.WhereFlag,
+		&cli.BoolFlag{
+			Name:  "merge-duplicates",
+			Usage: "Merge contacts that already exist in the target space",
+			Value: true,
+		},
+		&cli.BoolFlag{
+			Name:  "skip-duplicates",
+			Usage: "Skip contacts that already exist in the target space (overrides --merge-duplicates)",
+		},
+		util.MinScoreFlag,
+		util.PhoneticFlag,
+		util.IndexDirFlag,
+		util.WebhookFlag,
+	}, util.MergeFlags...),
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if err := util.RequireFlags(cmd, "app-key"); err != nil {
+			return err
+		}
+		if cmd.String("from") == cmd.String("to") {
+			return fmt.Errorf("--from and --to must be different spaces")
+		}
+		if err := util.Preflight(ctx, cmd, cmd.String("from"), cmd.String("to")); err != nil {
+			return err
+		}
+		started := time.Now()
+		var summary util.ImportSummary
+		err := copyContacts(ctx, cmd, &summary)
+		if !cmd.Bool("dry-run") {
+			util.NotifyWebhook(ctx, cmd, cmd.String("to"), started, summary, err)
+			util.EmitDone(cmd.String("to"), summary, err)
+		}
+		return err
+	},
+}
+
+// copyContacts runs the copy, recording its results in summary
+func copyContacts(ctx context.Context, cmd *cli.Command, summary *util.ImportSummary) (err error) {
+	client := util.NewClient(cmd)
+	from, to := cmd.String("from"), cmd.String("to")
+
+	filter := vcard.Filter{
+		Tags:         cmd.StringSlice("tag"),
+		Organization: cmd.String("org"),
+	}
+	where, err := util.ParseWhere(cmd)
+	if err != nil {
+		return err
+	}
+	filter.Where = where
+	if since := cmd.String("modified-since"); since != "" {
+		t, err := vcard.ParseFilterTime(since)
+		if err != nil {
+			return err
+		}
+		filter.ModifiedSince = t
+	}
+
+	contacts, err := readContacts(ctx, client, from, cmd.String("query"), filter)
+	if err != nil {
+		return err
+	}
+	for i := range contacts {
+		contacts[i].Source = "space " + from
+	}
+	i18n.Printf("Found %d contact(s) to copy from space %s\n", len(contacts), from)
+	progress.Emit(progress.Event{Event: progress.Parsed, Source: "space " + from, Count: len(contacts)})
+	summary.Contacts = len(contacts)
+
+	if cmd.Bool("dry-run") {
+		for _, c := range contacts {
+			fmt.Fprintf(i18n.Output(), "+ %s\n", c.DisplayName())
+		}
+		return nil
+	}
+	defer util.TrackThroughput()(summary)
+
+	typeKey, err := util.EnsureContactType(ctx, client, to, true)
+	if err != nil {
+		return err
+	}
+	phoneKeys, emailKeys, err := util.EnsureContactProperties(ctx, client, to)
+	if err != nil {
+		return fmt.Errorf("failed to ensure properties: %w", err)
+	}
+	if err := util.EnsureProperties(ctx, client, to, append([]anytype.PropertyDefinition{util.UIDProperty, util.BirthdayTextProperty, util.DepartmentProperty, util.RoleProperty, util.FavoriteProperty, util.HistoryProperty}, util.RelationProperties...)); err != nil {
+		return fmt.Errorf("failed to ensure copy properties: %w", err)
+	}
+
+	dedupIndex, closeIndex, err := util.NewDedupIndex(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := closeIndex(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+	existing, err := vcard.FetchContacts(ctx, client, to, typeKey)
+	if err != nil {
+		return fmt.Errorf("failed to fetch contacts of the target space: %w", err)
+	}
+	for _, c := range existing {
+		dedupIndex.Add(c)
+	}
+	i18n.Printf("✓ Found %d existing contacts\n", len(existing))
+
+	dst := &sink.Anytype{
+		Client:     client,
+		SpaceID:    to,
+		TypeKey:    typeKey,
+		PhoneKeys:  phoneKeys,
+		EmailKeys:  emailKeys,
+		UIDKey:     util.UIDProperty.Key,
+		HistoryKey: util.HistoryProperty.Key, // Copied contacts keep theirs
+		Refresh:    util.RefreshSchema,
+	}
+	skip := cmd.Bool("skip-duplicates")
+	merge, err := util.MergeOptions(cmd, cmd.Bool("merge-duplicates") && !skip)
+	if err != nil {
+		return err
+	}
+	*summary, err = util.ImportContacts(ctx, dst, contacts, dedupIndex, merge, nil)
+	util.InvalidateMirror(to)
+	return err
+}
+
+// readContacts fetches the matching contacts of a space, detached from their
+// source objects so they are created anew. The source object ID is kept as
+// UID when the contact has none.
+func readContacts(ctx context.Context, client anytype.Client, spaceID, query string, filter vcard.Filter) ([]vcard.Contact, error) {
+	typeKey, err := util.FindContactType(ctx, client, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	src := source.Filter(&source.Anytype{Client: client, SpaceID: spaceID, TypeKey: typeKey, Query: query}, filter.Match)
+	defer src.Close()
+
+	found, err := source.ReadAll(ctx, src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contacts from space %s: %w", spaceID, err)
+	}
+
+	contacts := make([]vcard.Contact, len(found))
+	for i, c := range found {
+		if c.UID == "" {
+			c.UID = c.ObjectID
+		}
+		c.ObjectID = ""
+		c.LastModified = ""
+		c.Snapshot = ""
+		contacts[i] = c
+	}
+	return contacts, nil
+}
@@ -0,0 +1,179 @@
+package dedupe
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/htmlreport"
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/service"
+	"github.com/rubiojr/anytype-go"
+	"github.com/urfave/cli/v3"
+)
+
+var Command = &cli.Command{
+	Name:  "dedupe",
+	Usage: "Merge duplicate contacts already in the space",
+	Flags: []cli.Flag{
+		util.MinScoreFlag,
+		util.PhoneticFlag,
+		util.WhereFlag,
+		&cli.BoolFlag{
+			Name:  "conflicts-to-notes",
+			Usage: "Append the values a merge discards to the notes of the surviving contact",
+		},
+		&cli.BoolFlag{
+			Name:  "history",
+			Usage: "Record the date and changed fields of every merge in a History property of the surviving contact",
+		},
+		&cli.StringFlag{
+			Name:  "report",
+			Usage: "Write the duplicate clusters as CSV to this file (- for stdout) without changing anything",
+		},
+		&cli.StringFlag{
+			Name:  "report-html",
+			Usage: "Write the duplicate clusters as an HTML page to this file without changing anything",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if err := util.RequireFlags(cmd, "app-key", "space"); err != nil {
+			return err
+		}
+		if err := util.Preflight(ctx, cmd, cmd.String("space")); err != nil {
+			return err
+		}
+		where, err := util.ParseWhere(cmd)
+		if err != nil {
+			return err
+		}
+		report, reportHTML := cmd.String("report"), cmd.String("report-html")
+		dryRun := cmd.Bool("dry-run") || report != "" || reportHTML != ""
+		if cmd.Bool("history") && !dryRun {
+			if err := util.EnsureProperties(ctx, util.NewClient(cmd), cmd.String("space"), []anytype.PropertyDefinition{util.HistoryProperty}); err != nil {
+				return fmt.Errorf("failed to ensure history property: %w", err)
+			}
+		}
+		svc, err := util.NewContactService(ctx, cmd)
+		if err != nil {
+			return err
+		}
+		svc.MinScore = cmd.Float("min-score")
+		svc.Phonetic = cmd.Bool("phonetic")
+		svc.NoteConflicts = cmd.Bool("conflicts-to-notes")
+		svc.History = cmd.Bool("history")
+		svc.Where = where
+
+		groups, weak, err := svc.DedupeGroups(ctx)
+		if err != nil {
+			return err
+		}
+		if !dryRun && len(groups) > 0 {
+			duplicates := 0
+			for _, g := range groups {
+				duplicates += len(g.Duplicates)
+			}
+			summary := i18n.Sprintf("Merging deletes %d duplicate contact object(s) and overwrites %d contact(s) in space %s", duplicates, len(groups), cmd.String("space"))
+			if err := util.Confirm(cmd, summary); err != nil {
+				return err
+			}
+			groups, err = svc.ApplyDedupe(ctx, groups)
+			util.InvalidateMirror(cmd.String("space"))
+			if err != nil {
+				return err
+			}
+		}
+
+		if reportHTML != "" {
+			if err := writeHTMLReport(reportHTML, groups); err != nil {
+				return err
+			}
+		}
+		if report != "" {
+			return writeReport(report, groups)
+		}
+		if reportHTML != "" {
+			return nil
+		}
+
+		duplicates := 0
+		for _, g := range groups {
+			fmt.Printf("  %s (%s)\n", g.Contact.DisplayName(), g.Contact.ObjectID)
+			for _, d := range g.Duplicates {
+				via := ""
+				if d.Via != "" {
+					via = " via " + d.Via
+				}
+				fmt.Printf("    ← %s (%s) [%.2f %s%s: %s]\n", d.Contact.DisplayName(), d.Contact.ObjectID, d.Score, d.Strength, via, strings.Join(d.Reasons, ", "))
+				for _, c := range d.Conflicts {
+					i18n.Printf("      ⚠ conflict %s\n", c)
+				}
+			}
+			duplicates += len(g.Duplicates)
+		}
+		if len(weak) > 0 {
+			i18n.Printf("\nWeak matches, not merged; review them with diff:\n")
+			for _, w := range weak {
+				fmt.Printf("  %s (%s) ~ %s (%s) [%.2f %s: %s]\n", w.Contact.DisplayName(), w.Contact.ObjectID, w.Match.DisplayName(), w.Match.ObjectID, w.Score, w.Strength, strings.Join(w.Reasons, ", "))
+			}
+		}
+		if dryRun {
+			i18n.Printf("\nDry run: would merge %d duplicate(s) into %d contact(s)\n", duplicates, len(groups))
+			return nil
+		}
+		i18n.Printf("✓ Merged %d duplicate(s) into %d contact(s)\n", duplicates, len(groups))
+		return nil
+	},
+}
+
+func writeReport(name string, groups []service.DedupeGroup) error {
+	var w io.Writer = os.Stdout
+	if name != "-" {
+		f, err := os.Create(name)
+		if err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := service.WriteDedupeReport(w, groups); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if name != "-" {
+		i18n.Printf("✓ Wrote %d duplicate cluster(s) to %s\n", len(groups), name)
+	}
+	return nil
+}
+
+func writeHTMLReport(name string, groups []service.DedupeGroup) error {
+	clusters := make([]htmlreport.Cluster, len(groups))
+	for i, g := range groups {
+		members := []htmlreport.Member{{Contact: g.Contact, Label: "survivor (merged)"}}
+		for _, d := range g.Duplicates {
+			details := []string{fmt.Sprintf("score %.2f, %s", d.Score, d.Strength)}
+			if d.Via != "" {
+				details = append(details, "via "+d.Via)
+			}
+			details = append(details, d.Reasons...)
+			for _, c := range d.Conflicts {
+				details = append(details, "conflict "+c.String())
+			}
+			members = append(members, htmlreport.Member{Contact: d.Contact, Label: "duplicate", Details: details})
+		}
+		clusters[i] = htmlreport.Cluster{Title: g.Contact.DisplayName(), Members: members}
+	}
+
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	defer f.Close()
+	if err := htmlreport.Write(f, "Duplicate contacts", clusters); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	i18n.Printf("✓ Wrote %d duplicate cluster(s) to %s\n", len(groups), name)
+	return nil
+}
@@ -3,13 +3,13 @@ package diff
 import (
 	"context"
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 
 	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/htmlreport"
 	"github.com/rubiojr/any-vcard/internal/vcard"
-	"github.com/rubiojr/anytype-go"
-	"github.com/rubiojr/anytype-go/options"
 	"github.com/urfave/cli/v3"
 )
 
@@ -21,23 +21,40 @@ type contactWithObjName struct {
 
 var Command = &cli.Command{
 	Name:  "diff",
-	Usage: "Find and diff contacts with the same display name",
-	Flags: []cli.Flag{
+	Usage: "Find and diff contacts with the same display name or duplicate cluster",
+	Flags: append([]cli.Flag{
 		&cli.StringFlag{
 			Name:    "name",
 			Aliases: []string{"n"},
 			Usage:   "Filter by contact name (case-insensitive substring match)",
 		},
+		&cli.FloatFlag{
+			Name:      "min-score",
+			Usage:     "Only show contacts matching the first of their group with at least this confidence (0-1)",
+			Validator: util.ValidateScore,
+		},
+		&cli.StringFlag{
+			Name:  "group-by",
+			Usage: "Group contacts with the same name, or with match: whole duplicate clusters linked by phone or email",
+			Value: "name",
+		},
+		&cli.StringFlag{
+			Name:  "report-html",
+			Usage: "Write the groups as an HTML page to this file instead of printing them",
+		},
 		&cli.BoolFlag{
 			Name:    "verbose",
 			Aliases: []string{"v"},
 			Usage:   "Show debug output",
 		},
-	},
+	}, util.MirrorFlags...),
 	Action: func(ctx context.Context, cmd *cli.Command) error {
 		if err := util.RequireFlags(cmd, "app-key", "space"); err != nil {
 			return err
 		}
+		if err := util.ReadPreflight(ctx, cmd, cmd.String("space")); err != nil {
+			return err
+		}
 		return runDiff(ctx, cmd)
 	},
 }
@@ -47,49 +64,13 @@ func runDiff(ctx context.Context, cmd *cli.Command) error {
 	spaceID := cmd.String("space")
 	nameFilter := cmd.String("name")
 	verbose := cmd.Bool("verbose")
+	minScore := cmd.Float("min-score")
+	dedup := util.DedupOptions(cmd)
 
-	// Find contact type
-	typesResp, err := client.Space(spaceID).Types().List(ctx)
+	// Fetch all contacts, from the mirror with --mirror
+	allObjects, err := util.ContactObjects(ctx, cmd, client, spaceID, "")
 	if err != nil {
-		return fmt.Errorf("failed to list types: %w", err)
-	}
-
-	var contactTypeKey string
-	for _, t := range typesResp {
-		if t.Key == "contact" || strings.ToLower(t.Name) == "contact" {
-			contactTypeKey = t.Key
-			break
-		}
-	}
-
-	if contactTypeKey == "" {
-		return fmt.Errorf("contact type not found in space")
-	}
-
-	// Fetch all contacts with pagination using Search
-	var allObjects []anytype.Object
-	const pageSize = 100
-	offset := 0
-
-	searchReq := anytype.SearchRequest{
-		Types: []string{contactTypeKey},
-	}
-
-	for {
-		searchResp, err := client.Space(spaceID).Search(ctx, searchReq,
-			options.WithLimit(pageSize),
-			options.WithOffset(offset),
-		)
-		if err != nil {
-			return fmt.Errorf("failed to search contacts: %w", err)
-		}
-
-		allObjects = append(allObjects, searchResp.Data...)
-
-		if len(searchResp.Data) < pageSize {
-			break // No more pages
-		}
-		offset += pageSize
+		return fmt.Errorf("failed to search contacts: %w", err)
 	}
 
 	if verbose {
@@ -110,11 +91,20 @@ func runDiff(ctx context.Context, cmd *cli.Command) error {
 		}
 	}
 
-	// Group contacts by Anytype object name
+	// Group contacts by Anytype object name, or by duplicate cluster
+	byMatch := false
+	switch cmd.String("group-by") {
+	case "name":
+	case "match":
+		byMatch = true
+	default:
+		return fmt.Errorf("unsupported grouping %q (supported: name, match)", cmd.String("group-by"))
+	}
 	byName := make(map[string][]*contactWithObjName)
+	var filtered []*contactWithObjName
 	for i := range allObjects {
 		obj := &allObjects[i]
-		contact := objectToContact(obj)
+		contact := vcard.FromObject(*obj)
 		objName := obj.Name // Use Anytype object name, not contact.DisplayName()
 		normalizedName := vcard.NormalizeNameForDedup(objName)
 
@@ -127,16 +117,29 @@ func runDiff(ctx context.Context, cmd *cli.Command) error {
 			continue
 		}
 
-		byName[normalizedName] = append(byName[normalizedName], &contactWithObjName{
-			Contact: contact,
-			ObjName: objName,
-		})
+		c := &contactWithObjName{Contact: contact, ObjName: objName}
+		filtered = append(filtered, c)
+		if !byMatch {
+			byName[normalizedName] = append(byName[normalizedName], c)
+		}
+	}
+	if byMatch {
+		byName = groupByMatch(filtered, dedup, minScore)
 	}
 
-	// Find and display duplicates
+	// Find and display duplicates, keeping the contacts of each group
+	// that match its first one well enough
 	var names []string
 	for name, contacts := range byName {
-		if len(contacts) > 1 {
+		kept := contacts[:1]
+		for _, c := range contacts[1:] {
+			// Clusters were built from matches of at least minScore
+			if byMatch || vcard.ScoreContacts(contacts[0].Contact, c.Contact, dedup).Score >= minScore {
+				kept = append(kept, c)
+			}
+		}
+		byName[name] = kept
+		if len(kept) > 1 {
 			names = append(names, name)
 		}
 	}
@@ -147,6 +150,10 @@ func runDiff(ctx context.Context, cmd *cli.Command) error {
 		return nil
 	}
 
+	if path := cmd.String("report-html"); path != "" {
+		return writeHTMLReport(path, names, byName, dedup)
+	}
+
 	for _, name := range names {
 		contacts := byName[name]
 		fmt.Printf("=== %s (%d contacts) ===\n", contacts[0].ObjName, len(contacts))
@@ -161,7 +168,8 @@ func runDiff(ctx context.Context, cmd *cli.Command) error {
 			fmt.Printf("\n--- Differences ---\n")
 			base := contacts[0].Contact
 			for i := 1; i < len(contacts); i++ {
-				fmt.Printf("\n[1] vs [%d]:\n", i+1)
+				m := vcard.ScoreContacts(base, contacts[i].Contact, dedup)
+				fmt.Printf("\n[1] vs [%d]: score %.2f (%s: %s)\n", i+1, m.Score, m.Strength, strings.Join(m.Reasons, ", "))
 				printDiff(base, contacts[i].Contact)
 			}
 		}
@@ -171,84 +179,53 @@ func runDiff(ctx context.Context, cmd *cli.Command) error {
 	return nil
 }
 
-func objectToContact(obj *anytype.Object) *vcard.Contact {
-	c := &vcard.Contact{
-		ObjectID: obj.ID,
+// groupByMatch groups the contacts into duplicate clusters, matched as
+// opts say and keyed so they sort in the order of their first contact
+func groupByMatch(contacts []*contactWithObjName, opts vcard.DedupOptions, minScore float64) map[string][]*contactWithObjName {
+	byContact := make(map[*vcard.Contact]*contactWithObjName, len(contacts))
+	all := make([]*vcard.Contact, len(contacts))
+	for i, c := range contacts {
+		byContact[c.Contact] = c
+		all[i] = c.Contact
+	}
+	groups := make(map[string][]*contactWithObjName)
+	clusters, _ := vcard.ClusterDuplicates(all, opts, false, func(m vcard.Match) bool { return m.Score >= minScore })
+	for i, cl := range clusters {
+		key := fmt.Sprintf("%06d", i)
+		for _, c := range cl.Contacts {
+			groups[key] = append(groups[key], byContact[c])
+		}
 	}
+	return groups
+}
 
-	for _, prop := range obj.Properties {
-		switch prop.Key {
-		case "name":
-			c.FormattedName = prop.Text
-		case "given_name":
-			c.GivenName = prop.Text
-		case "family_name":
-			c.FamilyName = prop.Text
-		case "middle_name":
-			c.MiddleName = prop.Text
-		case "prefix":
-			c.Prefix = prop.Text
-		case "suffix":
-			c.Suffix = prop.Text
-		case "organization":
-			c.Organization = prop.Text
-		case "title":
-			c.Title = prop.Text
-		case "notes":
-			c.Note = prop.Text
-		case "birthday":
-			c.Birthday = prop.Date
-		case "email", "email2", "email3":
-			if prop.Email != "" {
-				c.Emails = append(c.Emails, prop.Email)
-			}
-		case "phone", "phone2", "phone3":
-			if prop.Phone != "" {
-				c.Phones = append(c.Phones, prop.Phone)
-			}
-		case "url":
-			if prop.URL != "" {
-				c.URLs = append(c.URLs, prop.URL)
-			}
-		case "address":
-			if prop.Text != "" {
-				if len(c.Addresses) == 0 {
-					c.Addresses = append(c.Addresses, vcard.Address{})
-				}
-				c.Addresses[0].Street = prop.Text
-			}
-		case "city":
-			if prop.Text != "" {
-				if len(c.Addresses) == 0 {
-					c.Addresses = append(c.Addresses, vcard.Address{})
-				}
-				c.Addresses[0].City = prop.Text
-			}
-		case "region":
-			if prop.Text != "" {
-				if len(c.Addresses) == 0 {
-					c.Addresses = append(c.Addresses, vcard.Address{})
-				}
-				c.Addresses[0].Region = prop.Text
-			}
-		case "postal_code":
-			if prop.Text != "" {
-				if len(c.Addresses) == 0 {
-					c.Addresses = append(c.Addresses, vcard.Address{})
-				}
-				c.Addresses[0].PostalCode = prop.Text
-			}
-		case "country":
-			if prop.Text != "" {
-				if len(c.Addresses) == 0 {
-					c.Addresses = append(c.Addresses, vcard.Address{})
-				}
-				c.Addresses[0].Country = prop.Text
+// writeHTMLReport writes the groups of the given names as an HTML page,
+// scoring their contacts as opts say
+func writeHTMLReport(path string, names []string, byName map[string][]*contactWithObjName, opts vcard.DedupOptions) error {
+	clusters := make([]htmlreport.Cluster, len(names))
+	for i, name := range names {
+		contacts := byName[name]
+		members := make([]htmlreport.Member, len(contacts))
+		for j, c := range contacts {
+			members[j] = htmlreport.Member{Contact: c.Contact, Label: fmt.Sprintf("[%d]", j+1)}
+			if j > 0 {
+				m := vcard.ScoreContacts(contacts[0].Contact, c.Contact, opts)
+				members[j].Details = append([]string{fmt.Sprintf("score %.2f, %s", m.Score, m.Strength)}, m.Reasons...)
 			}
 		}
+		clusters[i] = htmlreport.Cluster{Title: contacts[0].ObjName, Members: members}
 	}
 
-	return c
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	defer f.Close()
+	if err := htmlreport.Write(f, "Contacts with the same name", clusters); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	fmt.Printf("✓ Wrote %d group(s) to %s\n", len(names), path)
+	return nil
 }
 
 func printContact(c *vcard.Contact) {
@@ -261,6 +238,9 @@ func printContact(c *vcard.Contact) {
 	if c.Title != "" {
 		fmt.Printf("  Title: %s\n", c.Title)
 	}
+	if c.Role != "" {
+		fmt.Printf("  Role: %s\n", c.Role)
+	}
 	for i, phone := range c.Phones {
 		fmt.Printf("  Phone %d: %s\n", i+1, phone)
 	}
@@ -297,6 +277,7 @@ func printDiff(a, b *vcard.Contact) {
 	diffField("Suffix", a.Suffix, b.Suffix)
 	diffField("Organization", a.Organization, b.Organization)
 	diffField("Title", a.Title, b.Title)
+	diffField("Role", a.Role, b.Role)
 	diffField("Birthday", a.Birthday, b.Birthday)
 	diffSlice("Phones", a.Phones, b.Phones)
 	diffSlice("Emails", a.Emails, b.Emails)
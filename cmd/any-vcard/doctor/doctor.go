@@ -0,0 +1,250 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/schemacache"
+	"github.com/rubiojr/anytype-go"
+	"github.com/urfave/cli/v3"
+)
+
+var Command = &cli.Command{
+	Name:  "doctor",
+	Usage: "Check the API, app key, space, contact schema and local cache, suggesting fixes",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "fix",
+			Usage: "Drop a stale or corrupt schema cache",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		d := &doctor{}
+		d.run(ctx, cmd)
+		if d.failed > 0 {
+			return fmt.Errorf("%d check(s) failed", d.failed)
+		}
+		fmt.Println("\nEverything looks good")
+		return nil
+	},
+}
+
+// knownProperties are the properties any-vcard creates, by key, with the
+// format it writes them in
+func knownProperties() map[string]string {
+	defs := slices.Concat(
+		util.ContactTypeProperties,
+		[]anytype.PropertyDefinition{
+			{Key: "phone2", Format: "phone"}, {Key: "phone3", Format: "phone"},
+			{Key: "email2", Format: "email"}, {Key: "email3", Format: "email"},
+			util.UIDProperty, util.SnapshotProperty, util.BirthdayTextProperty, util.HistoryProperty, util.CompletenessProperty,
+		},
+		util.GeoProperties, util.RelationProperties, util.RelationLinkProperties, util.BirthdayFieldProperties,
+	)
+	known := make(map[string]string, len(defs))
+	for _, def := range defs {
+		known[def.Key] = def.Format
+	}
+	return known
+}
+
+type doctor struct {
+	failed int
+}
+
+func (d *doctor) ok(format string, args ...any) {
+	fmt.Printf("✓ "+format+"\n", args...)
+}
+
+func (d *doctor) warn(msg, fix string) {
+	fmt.Printf("⚠ %s\n  → %s\n", msg, fix)
+}
+
+func (d *doctor) fail(msg, fix string) {
+	d.failed++
+	fmt.Printf("✗ %s\n  → %s\n", msg, fix)
+}
+
+// skip reports checks that can't run because an earlier one failed
+func (d *doctor) skip(checks string) {
+	fmt.Printf("- Skipped %s\n", checks)
+}
+
+func (d *doctor) run(ctx context.Context, cmd *cli.Command) {
+	baseURL := cmd.String("url")
+	if !d.checkReachable(ctx, baseURL) {
+		d.skip("app key, space and schema checks")
+		d.checkCache(cmd, "", nil, nil)
+		return
+	}
+
+	if cmd.String("app-key") == "" {
+		d.fail("No app key set", "Run `any-vcard auth`, then export ANYTYPE_APP_KEY or pass --app-key")
+		d.skip("space and schema checks")
+		d.checkCache(cmd, "", nil, nil)
+		return
+	}
+	client := util.NewClient(cmd)
+	spaces, err := client.Spaces().List(ctx)
+	if err != nil {
+		d.fail(fmt.Sprintf("The app key was rejected: %v", err), "Run `any-vcard auth` for a new key; keys are revoked when the app is removed in Anytype's settings")
+		d.skip("space and schema checks")
+		d.checkCache(cmd, "", nil, nil)
+		return
+	}
+	d.ok("App key is valid (%d space(s) visible)", len(spaces.Data))
+
+	spaceID := cmd.String("space")
+	if spaceID == "" {
+		d.warn("No space set", "Pick one from `any-vcard space list` and export ANYTYPE_SPACE_ID or pass --space")
+		d.skip("schema checks")
+		d.checkCache(cmd, "", nil, nil)
+		return
+	}
+	i := slices.IndexFunc(spaces.Data, func(s anytype.Space) bool { return s.ID == spaceID })
+	if i < 0 {
+		d.fail(fmt.Sprintf("Space %s not found", spaceID), "Check the ID against `any-vcard space list`; spaces deleted in Anytype disappear from it")
+		d.skip("schema checks")
+		d.checkCache(cmd, "", nil, nil)
+		return
+	}
+	d.ok("Space %s exists (%s)", spaceID, spaces.Data[i].Name)
+
+	// Straight from the API, so the cache can be compared against them
+	types, err := client.Space(spaceID).Types().List(ctx)
+	if err != nil {
+		d.fail(fmt.Sprintf("Failed to list types: %v", err), "Check that the app key has access to the space")
+		d.checkCache(cmd, spaceID, nil, nil)
+		return
+	}
+	props, err := client.Space(spaceID).Properties().List(ctx)
+	if err != nil {
+		d.fail(fmt.Sprintf("Failed to list properties: %v", err), "Check that the app key has access to the space")
+		d.checkCache(cmd, spaceID, nil, nil)
+		return
+	}
+	d.checkSchema(types, props)
+	d.checkCache(cmd, spaceID, types, props)
+}
+
+// checkReachable reports whether anything answers HTTP at baseURL
+func (d *doctor) checkReachable(ctx context.Context, baseURL string) bool {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL, nil)
+	if err != nil {
+		d.fail(fmt.Sprintf("Invalid API URL %q: %v", baseURL, err), "Set --url or ANYTYPE_URL to the API address, e.g. http://localhost:31009")
+		return false
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		d.fail(fmt.Sprintf("API not reachable at %s: %v", baseURL, err), "Start the Anytype desktop app (the API runs while it is open), or point --url / ANYTYPE_URL at it")
+		return false
+	}
+	resp.Body.Close()
+	d.ok("API reachable at %s", baseURL)
+	return true
+}
+
+// checkSchema checks the Contact type exists and the properties any-vcard
+// writes have the formats it writes them in
+func (d *doctor) checkSchema(types []anytype.Type, props []anytype.Property) {
+	i := slices.IndexFunc(types, func(t anytype.Type) bool {
+		return strings.EqualFold(t.Key, util.ContactTypeKey) || strings.EqualFold(t.Name, "contact")
+	})
+	if i < 0 {
+		d.warn("No Contact type in the space", "Run `any-vcard types create`, or `any-vcard import`, which creates it")
+	} else {
+		d.ok("Contact type found (key %s)", types[i].Key)
+	}
+
+	known := knownProperties()
+	mismatched := 0
+	for _, p := range props {
+		if want, ok := known[p.Key]; ok && p.Format != want {
+			mismatched++
+			d.fail(fmt.Sprintf("Property %q (%s) has format %s, any-vcard writes %s", p.Key, p.Name, p.Format, want),
+				fmt.Sprintf("Rename or delete the %q property in Anytype so any-vcard can create its own", p.Name))
+		}
+	}
+	if mismatched == 0 {
+		d.ok("Contact properties have the expected formats")
+	}
+}
+
+// checkCache checks the schema cache file parses and, when the live types
+// and properties of the space are known, that its entry matches them
+func (d *doctor) checkCache(cmd *cli.Command, spaceID string, types []anytype.Type, props []anytype.Property) {
+	ttl := cmd.Duration("schema-cache-ttl")
+	if ttl <= 0 {
+		d.ok("Schema cache disabled")
+		return
+	}
+	path, err := schemacache.DefaultPath()
+	if err != nil {
+		d.warn(fmt.Sprintf("No user cache directory: %v", err), "Set XDG_CACHE_HOME, or disable the cache with --schema-cache-ttl 0")
+		return
+	}
+	if err := schemacache.Check(path); err != nil {
+		if cmd.Bool("fix") {
+			if rerr := os.Remove(path); rerr == nil {
+				d.ok("Deleted the corrupt schema cache %s", path)
+				return
+			}
+		}
+		d.fail(err.Error(), fmt.Sprintf("Delete %s or run `any-vcard doctor --fix`; it is rebuilt on the next run", path))
+		return
+	}
+	cache, err := schemacache.Load(path, cmd.String("url"), ttl)
+	if err != nil {
+		d.fail(err.Error(), fmt.Sprintf("Check the permissions of %s", path))
+		return
+	}
+	if spaceID == "" || types == nil {
+		d.ok("Schema cache readable (%s)", path)
+		return
+	}
+
+	cachedTypes, cachedProps, ok := cache.Cached(spaceID)
+	if !ok {
+		d.ok("Schema cache readable, nothing cached for the space")
+		return
+	}
+	if stale := staleEntries(cachedTypes, cachedProps, types, props); len(stale) > 0 {
+		if cmd.Bool("fix") {
+			cache.Invalidate(spaceID)
+			d.ok("Dropped the stale schema cache of the space (%s)", strings.Join(stale, ", "))
+			return
+		}
+		d.fail(fmt.Sprintf("Schema cache is out of date with the space: %s", strings.Join(stale, ", ")), "Run `any-vcard doctor --fix`, or use --schema-cache-ttl 0 once")
+		return
+	}
+	d.ok("Schema cache matches the space")
+}
+
+// staleEntries describes the cached types and properties that are missing
+// from, or differ in, the live schema
+func staleEntries(cachedTypes []anytype.Type, cachedProps []anytype.Property, types []anytype.Type, props []anytype.Property) []string {
+	var stale []string
+	for _, ct := range cachedTypes {
+		if !slices.ContainsFunc(types, func(t anytype.Type) bool { return t.Key == ct.Key }) {
+			stale = append(stale, "type "+ct.Key+" was deleted")
+		}
+	}
+	for _, cp := range cachedProps {
+		i := slices.IndexFunc(props, func(p anytype.Property) bool { return p.Key == cp.Key })
+		switch {
+		case i < 0:
+			stale = append(stale, "property "+cp.Key+" was deleted")
+		case props[i].Format != cp.Format:
+			stale = append(stale, fmt.Sprintf("property %s is now %s", cp.Key, props[i].Format))
+		}
+	}
+	return stale
+}
@@ -0,0 +1,237 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/sink"
+	"github.com/rubiojr/any-vcard/internal/source"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/urfave/cli/v3"
+)
+
+var Command = &cli.Command{
+	Name:      "export",
+	Usage:     "Export contacts from the space to a file (.vcf, .csv, .jsonl, .html)",
+	ArgsUsage: "<output-file|output-dir|->",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "Output format: vcard, csv, jsonl or html-sheet, a printable address book (default: detected from the file extension)",
+		},
+		&cli.StringFlag{
+			Name:  "vcard-version",
+			Usage: "vCard version for .vcf output: 3.0 (older phones) or 4.0",
+			Value: vcard.Version4,
+		},
+		&cli.StringSliceFlag{
+			Name:  "tag",
+			Usage: "Only export contacts with this tag (repeatable, matches any)",
+		},
+		&cli.StringFlag{
+			Name:  "org",
+			Usage: "Only export contacts whose organization contains this text",
+		},
+		&cli.StringFlag{
+			Name:  "modified-since",
+			Usage: "Only export contacts modified since a date (YYYY-MM-DD or RFC3339)",
+		},
+		&cli.BoolFlag{
+			Name:  "split-per-contact",
+			Usage: "Write one .vcf file per contact into the output directory",
+		},
+		&cli.StringFlag{
+			Name:  "name-template",
+			Usage: "Go template for per-contact file names (e.g. {{.FamilyName}}_{{.GivenName}}.vcf)",
+			Value: sink.DefaultNameTemplate,
+		},
+		&cli.StringFlag{
+			Name:  "sort",
+			Usage: "Order the contacts by family (name), given (name) or org, in the alphabet of --locale (default: the order of the space)",
+		},
+		&cli.StringFlag{
+			Name:  "photos",
+			Usage: "How to export contact photos: embed (base64), link (URI) or none",
+			Value: vcard.PhotosEmbed,
+		},
+		&cli.StringFlag{
+			Name:  "photos-dir",
+			Usage: "Also write each contact's photo to this directory as <name>.jpg (or .png, ...)",
+		},
+		&cli.StringFlag{
+			Name:  "query",
+			Usage: "Only export contacts matching a full-text search query",
+		},
+		&cli.StringFlag{
+			Name:  "since",
+			Usage: "Only export contacts the mirror saw created or updated since a date (YYYY-MM-DD or RFC3339), or since the last --since export with \"last\"; implies --mirror",
+		},
+		&cli.StringFlag{
+			Name:  "deleted-ids",
+			Usage: "With --since, write the object IDs of the contacts deleted since then to this file, one per line",
+		},
+		util.WhereFlag,
+	}, util.MirrorFlags...),
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if err := util.RequireFlags(cmd, "app-key", "space"); err != nil {
+			return err
+		}
+		if cmd.String("since") != "" {
+			// The change journal lives in the mirror
+			if err := cmd.Set("mirror", "true"); err != nil {
+				return err
+			}
+		} else if cmd.String("deleted-ids") != "" {
+			return fmt.Errorf("--deleted-ids requires --since")
+		}
+		if err := util.ReadPreflight(ctx, cmd, cmd.String("space")); err != nil {
+			return err
+		}
+		if cmd.Args().Len() != 1 {
+			return fmt.Errorf("output file is required")
+		}
+		switch cmd.String("sort") {
+		case "", vcard.SortFamily, vcard.SortGiven, vcard.SortOrg:
+		default:
+			return fmt.Errorf("unknown sort key %q (supported: family, given, org)", cmd.String("sort"))
+		}
+		switch cmd.String("photos") {
+		case vcard.PhotosEmbed, vcard.PhotosLink, vcard.PhotosNone:
+		default:
+			return fmt.Errorf("unsupported photo mode %q (supported: embed, link, none)", cmd.String("photos"))
+		}
+		return exportContacts(ctx, cmd)
+	},
+}
+
+func exportContacts(ctx context.Context, cmd *cli.Command) error {
+	client := util.NewClient(cmd)
+	spaceID := cmd.String("space")
+	output := cmd.Args().Get(0)
+
+	filter := vcard.Filter{
+		Tags:         cmd.StringSlice("tag"),
+		Organization: cmd.String("org"),
+	}
+	where, err := util.ParseWhere(cmd)
+	if err != nil {
+		return err
+	}
+	filter.Where = where
+	if since := cmd.String("modified-since"); since != "" {
+		t, err := vcard.ParseFilterTime(since)
+		if err != nil {
+			return err
+		}
+		filter.ModifiedSince = t
+	}
+
+	var src source.Source
+	var snapshot time.Time
+	if since := cmd.String("since"); since != "" {
+		objects, deleted, at, err := util.ContactChanges(ctx, cmd, client, spaceID, since, cmd.String("query"))
+		if err != nil {
+			return err
+		}
+		if path := cmd.String("deleted-ids"); path != "" {
+			if err := writeDeletedIDs(path, deleted); err != nil {
+				return err
+			}
+		}
+		src = source.Objects(objects)
+		snapshot = at
+	} else if cmd.Bool("mirror") {
+		objects, err := util.ContactObjects(ctx, cmd, client, spaceID, cmd.String("query"))
+		if err != nil {
+			return err
+		}
+		src = source.Objects(objects)
+	} else {
+		typeKey, err := util.FindContactType(ctx, client, spaceID)
+		if err != nil {
+			return err
+		}
+		src = &source.Anytype{Client: client, SpaceID: spaceID, TypeKey: typeKey, Query: cmd.String("query")}
+	}
+	defer src.Close()
+	src = source.Filter(src, filter.Match)
+	if by := cmd.String("sort"); by != "" {
+		src = source.Sorted(src, by, vcard.Locale)
+	}
+	var photos *sink.Photos
+	if dir := cmd.String("photos-dir"); dir != "" {
+		if photos, err = sink.NewPhotos(dir, vcard.PhotoClient); err != nil {
+			return err
+		}
+	}
+	photoMode := cmd.String("photos")
+	src = source.Map(src, func(ctx context.Context, c *vcard.Contact) error {
+		if photos != nil {
+			photo, err := photos.Save(ctx, c)
+			if err != nil {
+				log.Printf("Warning: could not save photo of %s: %v", c.DisplayName(), err)
+			} else if photo != "" && photoMode == vcard.PhotosEmbed {
+				c.Photo = photo
+			}
+		}
+		if err := c.ApplyPhotoMode(ctx, vcard.PhotoClient, photoMode); err != nil {
+			log.Printf("Warning: skipping photo of %s: %v", c.DisplayName(), err)
+		}
+		return nil
+	})
+
+	dst, err := createSink(cmd, output)
+	if err != nil {
+		return err
+	}
+
+	n, err := sink.Copy(ctx, dst, src)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("export failed after %d contact(s): %w", n, err)
+	}
+
+	if output != "-" {
+		i18n.Printf("✓ Exported %d contact(s) to %s\n", n, output)
+	}
+	if !snapshot.IsZero() {
+		return util.MarkExported(spaceID, snapshot)
+	}
+	return nil
+}
+
+// writeDeletedIDs writes the IDs of deleted contacts to path, one per line
+func writeDeletedIDs(path string, ids []string) error {
+	var b strings.Builder
+	for _, id := range ids {
+		b.WriteString(id + "\n")
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write deleted IDs: %w", err)
+	}
+	return nil
+}
+
+// createSink returns the file sink for output, or a directory sink when
+// splitting the export per contact
+func createSink(cmd *cli.Command, output string) (sink.Sink, error) {
+	version := cmd.String("vcard-version")
+	if !cmd.Bool("split-per-contact") {
+		return sink.CreateFormat(output, cmd.String("format"), version)
+	}
+	if output == "-" {
+		return nil, fmt.Errorf("--split-per-contact requires an output directory")
+	}
+	if format := cmd.String("format"); format != "" && format != "vcard" {
+		return nil, fmt.Errorf("--split-per-contact only writes vCard files")
+	}
+	return sink.NewDir(output, cmd.String("name-template"), version)
+}
@@ -0,0 +1,122 @@
+package vcardimport
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/urfave/cli/v3"
+)
+
+var archiveFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "archive-processed",
+		Usage: "Move imported files into this directory under a timestamped name, and files that failed into --quarantine",
+	},
+	&cli.StringFlag{
+		Name:  "quarantine",
+		Usage: "Directory --archive-processed moves the files that failed to parse or import into (default: quarantine in the archive directory)",
+	},
+}
+
+// archiveFiles moves the files of the run out of the drop directory:
+// imported and already imported ones into --archive-processed, those that
+// failed to parse or had contacts failing to import into --quarantine.
+// When the import failed as a whole every file read is quarantined.
+// Standard input, directories and URLs are left alone.
+func archiveFiles(cmd *cli.Command, files *fileLedger, runFailed bool, failedSources []string) error {
+	archive := cmd.String("archive-processed")
+	if archive == "" {
+		return nil
+	}
+	quarantine := cmd.String("quarantine")
+	if quarantine == "" {
+		quarantine = filepath.Join(archive, "quarantine")
+	}
+
+	var toArchive, toQuarantine []string
+	for _, r := range files.parsed {
+		// Sources are cleaned paths, as files of different directories
+		// can share a name
+		if runFailed || slices.Contains(failedSources, filepath.Clean(r.Path)) {
+			toQuarantine = append(toQuarantine, r.Path)
+		} else {
+			toArchive = append(toArchive, r.Path)
+		}
+	}
+	toArchive = append(toArchive, files.skipped...)
+	toQuarantine = append(toQuarantine, files.unreadable...)
+
+	stamp := time.Now().Format("20060102-150405")
+	var errs []error
+	move := func(paths []string, dir string) int {
+		moved := 0
+		for _, path := range paths {
+			if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			if err := moveFile(path, dir, stamp); err != nil {
+				errs = append(errs, fmt.Errorf("failed to move %s to %s: %w", path, dir, err))
+				continue
+			}
+			moved++
+		}
+		return moved
+	}
+	if n := move(toArchive, archive); n > 0 {
+		i18n.Printf("✓ Archived %d file(s) to %s\n", n, archive)
+	}
+	if n := move(toQuarantine, quarantine); n > 0 {
+		i18n.Printf("⚠ Quarantined %d file(s) that failed in %s\n", n, quarantine)
+	}
+	return errors.Join(errs...)
+}
+
+// moveFile moves the file at path into dir, prefixing its name with stamp
+// and numbering it when a file with that name exists
+func moveFile(path, dir, stamp string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	name := stamp + "-" + filepath.Base(path)
+	dest := filepath.Join(dir, name)
+	for i := 2; ; i++ {
+		if _, err := os.Lstat(dest); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		ext := filepath.Ext(name)
+		dest = filepath.Join(dir, fmt.Sprintf("%s-%d%s", name[:len(name)-len(ext)], i, ext))
+	}
+
+	if err := os.Rename(path, dest); err == nil {
+		return nil
+	}
+	// Renames fail across file systems, copy instead
+	if err := copyFile(path, dest); err != nil {
+		os.Remove(dest)
+		return err
+	}
+	return os.Remove(path)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,58 @@
+package vcardimport
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/urfave/cli/v3"
+)
+
+func TestArchiveFiles_SameName(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "archive")
+	a := filepath.Join(dir, "a", "contacts.vcf")
+	b := filepath.Join(dir, "b", "contacts.vcf")
+	for _, path := range []string{a, b} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(path), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ledger := &fileLedger{}
+	ledger.parsedFile(a, "", 1)
+	ledger.parsedFile(b, "", 1)
+	cmd := &cli.Command{
+		Flags: archiveFlags,
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			// Only a/contacts.vcf had a contact failing to import
+			return archiveFiles(cmd, ledger, false, []string{filepath.Clean(a)})
+		},
+	}
+	if err := cmd.Run(context.Background(), []string{"import", "--archive-processed", archive}); err != nil {
+		t.Fatalf("archiveFiles() error = %v", err)
+	}
+
+	moved := func(dir string) []string {
+		entries, _ := filepath.Glob(filepath.Join(dir, "*contacts.vcf"))
+		var contents []string
+		for _, e := range entries {
+			data, err := os.ReadFile(e)
+			if err != nil {
+				t.Fatal(err)
+			}
+			contents = append(contents, string(data))
+		}
+		return contents
+	}
+	if got := moved(filepath.Join(archive, "quarantine")); len(got) != 1 || got[0] != a {
+		t.Errorf("quarantined %q, want only %s", got, a)
+	}
+	if got := moved(archive); len(got) != 1 || got[0] != b {
+		t.Errorf("archived %q, want only %s", got, b)
+	}
+}
@@ -0,0 +1,80 @@
+package vcardimport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/urfave/cli/v3"
+)
+
+// auditHeader is the header row of the --audit report
+var auditHeader = []string{"source", "contact", "field", "value"}
+
+// auditDropped writes every value the import drops to the --audit report
+// and, with --strict, refuses the import when there is any
+func auditDropped(cmd *cli.Command, contacts []vcard.Contact) error {
+	path, strict := cmd.String("audit"), cmd.Bool("strict")
+	if path == "" && !strict {
+		return nil
+	}
+
+	var rows [][]string
+	losing := 0
+	fields := make(map[string]int) // Contacts by dropped field
+	for _, c := range contacts {
+		dropped := vcard.DroppedValues(c, util.PhoneSlots)
+		if len(dropped) > 0 {
+			losing++
+		}
+		for _, v := range dropped {
+			rows = append(rows, []string{c.Source, c.DisplayName(), v.Field, v.Value})
+		}
+		for _, field := range vcard.DroppedFields(c, util.PhoneSlots) {
+			fields[field]++
+		}
+	}
+
+	if path != "" {
+		if err := writeAudit(path, rows); err != nil {
+			return err
+		}
+	}
+	if !strict || losing == 0 {
+		return nil
+	}
+	summary := make([]string, 0, len(fields))
+	for _, field := range slices.Sorted(maps.Keys(fields)) {
+		summary = append(summary, fmt.Sprintf("%s (%d)", field, fields[field]))
+	}
+	return fmt.Errorf("--strict: %d contact(s) would lose values: %s; list them with --audit FILE", losing, strings.Join(summary, ", "))
+}
+
+// writeAudit writes the --audit report rows as CSV to path, stdout for -
+func writeAudit(path string, rows [][]string) error {
+	w := i18n.Output()
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create audit report: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	cw := csv.NewWriter(w)
+	cw.Write(auditHeader)
+	cw.WriteAll(rows)
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write audit report: %w", err)
+	}
+	if path != "-" {
+		i18n.Printf("✓ Wrote %d dropped value(s) to %s\n", len(rows), path)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package vcardimport
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/source"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/term"
+)
+
+// csvSampleRows is how many rows the mapping wizard shows per column
+const csvSampleRows = 3
+
+// csvMapping returns the column mapping to read a CSV file with, or nil
+// to read filePath with --format as usual. A --csv-mapping file is loaded
+// when it exists. CSV files whose header the format doesn't recognize
+// start the mapping wizard on a terminal, which saves its result to
+// --csv-mapping (or next to the file) for the next import.
+func csvMapping(cmd *cli.Command, filePath string) (source.Mapping, error) {
+	mappingPath := cmd.String("csv-mapping")
+	if mappingPath != "" {
+		if _, err := os.Stat(mappingPath); err == nil {
+			return source.LoadMapping(mappingPath)
+		}
+	}
+
+	format := cmd.String("format")
+	if format == "" && strings.EqualFold(filepath.Ext(filePath), ".csv") {
+		format = "csv"
+	}
+	defaultMapping, ok := source.CSVFormats[format]
+	if !ok || filePath == "-" {
+		return nil, nil
+	}
+	header, rows, err := source.PeekCSV(filePath, csvSampleRows)
+	if err != nil || defaultMapping().Matches(header) {
+		return nil, nil // Left for the CSV source to report
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, fmt.Errorf("%s has no %s columns; run import in a terminal to map them or pass --csv-mapping", filePath, format)
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	i18n.Printf("\n%s has columns %s doesn't recognize. Assign each column to a contact field.\n", filePath, format)
+	mapping, err := csvWizard(in, i18n.Output(), header, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if mappingPath == "" {
+		mappingPath = strings.TrimSuffix(filePath, filepath.Ext(filePath)) + ".mapping.json"
+	}
+	answer, err := prompt(in, i18n.Output(), fmt.Sprintf("Save mapping to [%s] (- to skip): ", mappingPath))
+	if err != nil {
+		return nil, err
+	}
+	switch answer {
+	case "-":
+		return mapping, nil
+	case "":
+	default:
+		mappingPath = answer
+	}
+	if err := mapping.Save(mappingPath); err != nil {
+		return nil, err
+	}
+	i18n.Printf("✓ Saved mapping to %s; reuse it with --csv-mapping %s\n\n", mappingPath, mappingPath)
+	return mapping, nil
+}
+
+// csvWizard asks which contact field each column holds, showing sample
+// values. Several columns may feed the same field, e.g. two email columns.
+func csvWizard(in *bufio.Reader, out io.Writer, header []string, rows [][]string) (source.Mapping, error) {
+	fmt.Fprintf(out, "Fields: %s\n", strings.Join(source.MappingFields, ", "))
+	fmt.Fprintf(out, "Press Enter to skip a column.\n")
+
+	mapping := source.Mapping{}
+	for i, column := range header {
+		var samples []string
+		for _, row := range rows {
+			if i < len(row) && strings.TrimSpace(row[i]) != "" {
+				samples = append(samples, strings.TrimSpace(row[i]))
+			}
+		}
+		fmt.Fprintf(out, "\nColumn %d/%d %q", i+1, len(header), column)
+		if len(samples) > 0 {
+			fmt.Fprintf(out, " (e.g. %s)", strings.Join(samples, " | "))
+		}
+		fmt.Fprintln(out)
+
+		for {
+			field, err := prompt(in, out, "Field: ")
+			if err != nil {
+				return nil, err
+			}
+			if field == "" {
+				break
+			}
+			field = strings.ToLower(field)
+			if !slices.Contains(source.MappingFields, field) {
+				fmt.Fprintf(out, "Unknown field %q\n", field)
+				continue
+			}
+			mapping[field] = append(mapping[field], column)
+			break
+		}
+	}
+	if len(mapping) == 0 {
+		return nil, fmt.Errorf("no columns were mapped")
+	}
+	return mapping, nil
+}
+
+// prompt prints a question and returns the trimmed answer line
+func prompt(in *bufio.Reader, out io.Writer, question string) (string, error) {
+	fmt.Fprint(out, question)
+	line, err := in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("failed to read answer: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
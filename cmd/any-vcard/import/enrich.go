@@ -0,0 +1,84 @@
+package vcardimport
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/enrich"
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/urfave/cli/v3"
+)
+
+var enrichFlags = []cli.Flag{
+	&cli.StringSliceFlag{
+		Name:  "enrich",
+		Usage: "Fill in missing photos, organizations and titles from public sources: gravatar, logo (of organization email domains), whois (repeatable)",
+	},
+	&cli.StringFlag{
+		Name:  "enrich-cache",
+		Usage: "Enrichment lookup cache file (default: user cache dir)",
+	},
+}
+
+// enrichmentSources returns the --enrich sources by name, nil when there
+// are none
+func enrichmentSources(cmd *cli.Command) (map[string]enrich.Source, error) {
+	var sources map[string]enrich.Source
+	for _, name := range cmd.StringSlice("enrich") {
+		source, err := enrich.NewSource(name)
+		if err != nil {
+			return nil, err
+		}
+		if sources == nil {
+			sources = make(map[string]enrich.Source)
+		}
+		sources[name] = source
+	}
+	return sources, nil
+}
+
+// enrichContacts fills in the missing details of contacts from the
+// --enrich sources. Lookups are cached on disk between runs.
+func enrichContacts(ctx context.Context, cmd *cli.Command, contacts []vcard.Contact) error {
+	sources, err := enrichmentSources(cmd)
+	if err != nil {
+		return err
+	}
+
+	cachePath := cmd.String("enrich-cache")
+	if cachePath == "" {
+		if cachePath, err = enrich.DefaultCachePath(); err != nil {
+			return fmt.Errorf("failed to locate enrichment cache: %w", err)
+		}
+	}
+	cache, err := enrich.NewCache(cachePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := cache.Save(); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}()
+	for name, source := range sources {
+		sources[name] = cache.Wrap(name, source)
+	}
+
+	i18n.Printf("Enriching contacts...\n")
+	enricher := &enrich.Enricher{Sources: sources, Email: util.DedupOptions(cmd).Email}
+	var enriched int
+	for i := range contacts {
+		before := len(contacts[i].Enriched)
+		if err := enricher.Enrich(ctx, &contacts[i]); err != nil {
+			log.Printf("Warning: could not enrich %s: %v", contacts[i].DisplayName(), err)
+		}
+		if len(contacts[i].Enriched) > before {
+			enriched++
+		}
+	}
+	i18n.Printf("✓ Enriched %d contact(s)\n", enriched)
+	return nil
+}
@@ -0,0 +1,57 @@
+package vcardimport
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/oauth"
+	"github.com/rubiojr/any-vcard/internal/source"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/urfave/cli/v3"
+)
+
+var googleFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name:  "google",
+		Usage: "Import contacts from Google Contacts (People API) instead of files",
+	},
+	&cli.StringFlag{
+		Name:    "google-client-id",
+		Usage:   "OAuth client ID of a Google Cloud desktop app",
+		Sources: cli.EnvVars("GOOGLE_CLIENT_ID"),
+	},
+	&cli.StringFlag{
+		Name:    "google-client-secret",
+		Usage:   "OAuth client secret of a Google Cloud desktop app",
+		Sources: cli.EnvVars("GOOGLE_CLIENT_SECRET"),
+	},
+}
+
+// readGoogle authorizes against Google (reusing the cached token when
+// possible) and fetches every contact through the People API
+func readGoogle(ctx context.Context, cmd *cli.Command) ([]vcard.Contact, error) {
+	if cmd.String("google-client-id") == "" {
+		return nil, fmt.Errorf("--google requires --google-client-id (or GOOGLE_CLIENT_ID)")
+	}
+
+	config := source.GoogleOAuthConfig(cmd.String("google-client-id"), cmd.String("google-client-secret"))
+	store, err := oauth.DefaultStore("google")
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate token store: %w", err)
+	}
+
+	client, err := config.Client(ctx, store, func(ctx context.Context) (*oauth.Token, error) {
+		return config.LoopbackFlow(ctx, func(authURL string) {
+			i18n.Printf("\nOpen this URL in your browser to grant read access to your Google contacts:\n\n%s\n\n", authURL)
+			i18n.Printf("Waiting for authorization...\n")
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("google authorization failed: %w", err)
+	}
+
+	src := source.NewGoogle(client)
+	defer src.Close()
+	return source.ReadAll(ctx, src)
+}
@@ -0,0 +1,100 @@
+package vcardimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/hook"
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/urfave/cli/v3"
+)
+
+var hookFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "pre-hook",
+		Usage:   "Executable run before importing; a non-zero exit aborts the import. Hooks get the environment without ANYTYPE_APP_KEY and other credentials",
+		Sources: cli.EnvVars("ANYVCARD_PRE_HOOK"),
+	},
+	&cli.StringFlag{
+		Name:    "post-hook",
+		Usage:   "Executable run after importing, with the results in ANYVCARD_* variables",
+		Sources: cli.EnvVars("ANYVCARD_POST_HOOK"),
+	},
+	&cli.StringFlag{
+		Name:    "contact-hook",
+		Usage:   "Executable run per contact with its JSON on stdin; may print a modified contact, non-zero exit skips it",
+		Sources: cli.EnvVars("ANYVCARD_CONTACT_HOOK"),
+	},
+}
+
+// runContactHook passes every contact through the contact hook, dropping
+// the contacts it rejects
+func runContactHook(ctx context.Context, cmd *cli.Command, spaceIDs []string, contacts []vcard.Contact) ([]vcard.Contact, error) {
+	path := cmd.String("contact-hook")
+	if path == "" {
+		return contacts, nil
+	}
+
+	h := hook.Hook{Path: path}
+	env := map[string]string{"ANYVCARD_HOOK": "contact", "ANYVCARD_SPACE_ID": strings.Join(spaceIDs, ",")}
+	kept := contacts[:0]
+	for i := range contacts {
+		c := &contacts[i]
+		err := h.Contact(ctx, c, env)
+		if errors.Is(err, hook.ErrRejected) {
+			log.Printf("Skipping %s: %v", c.DisplayName(), err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		kept = append(kept, *c)
+	}
+	return kept, nil
+}
+
+// runPreHook runs the pre-import hook, if configured
+func runPreHook(ctx context.Context, cmd *cli.Command, spaceIDs []string, contacts []vcard.Contact) error {
+	path := cmd.String("pre-hook")
+	if path == "" {
+		return nil
+	}
+	return hook.Hook{Path: path, Stdout: i18n.Output()}.Run(ctx, map[string]string{
+		"ANYVCARD_HOOK":          "pre-import",
+		"ANYVCARD_SPACE_ID":      strings.Join(spaceIDs, ","),
+		"ANYVCARD_CONTACT_COUNT": strconv.Itoa(len(contacts)),
+	})
+}
+
+// runPostHook runs the post-import hook, if configured
+func runPostHook(ctx context.Context, cmd *cli.Command, spaceID string, contacts []vcard.Contact, summary util.ImportSummary) error {
+	path := cmd.String("post-hook")
+	if path == "" {
+		return nil
+	}
+	err := hook.Hook{Path: path, Stdout: i18n.Output()}.Run(ctx, map[string]string{
+		"ANYVCARD_HOOK":           "post-import",
+		"ANYVCARD_SPACE_ID":       spaceID,
+		"ANYVCARD_CONTACT_COUNT":  strconv.Itoa(len(contacts)),
+		"ANYVCARD_IMPORTED":       strconv.Itoa(summary.Imported),
+		"ANYVCARD_MERGED":         strconv.Itoa(summary.Merged),
+		"ANYVCARD_SKIPPED":        strconv.Itoa(summary.Skipped),
+		"ANYVCARD_BLOCKED":        strconv.Itoa(summary.Blocked),
+		"ANYVCARD_FAILED":         strconv.Itoa(summary.Failed),
+		"ANYVCARD_CROSS_SPACE":    strconv.Itoa(summary.CrossSpace),
+		"ANYVCARD_CONFLICTS":      strconv.Itoa(summary.Conflicts),
+		"ANYVCARD_DURATION":       strconv.FormatFloat(summary.Duration.Seconds(), 'f', 3, 64),
+		"ANYVCARD_API_CALLS":      strconv.FormatInt(summary.APICalls, 10),
+		"ANYVCARD_BYTES_UPLOADED": strconv.FormatInt(summary.BytesUploaded, 10),
+	})
+	if err != nil {
+		return fmt.Errorf("post-import %w", err)
+	}
+	return nil
+}
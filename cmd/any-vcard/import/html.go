@@ -0,0 +1,56 @@
+package vcardimport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rubiojr/any-vcard/internal/source"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/urfave/cli/v3"
+)
+
+var htmlFlags = []cli.Flag{
+	&cli.StringSliceFlag{
+		Name:  "html",
+		Usage: "Import h-card microformats from a web page URL or HTML file (repeatable)",
+	},
+}
+
+// readHTML extracts the h-cards published on a web page or local HTML file
+func readHTML(ctx context.Context, location string) ([]vcard.Contact, error) {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		file, err := os.Open(location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open file: %w", err)
+		}
+		src := source.NewHTML(file, nil)
+		defer src.Close()
+		return source.ReadAll(ctx, src)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", location, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch %s: status %d", location, resp.StatusCode)
+	}
+
+	// Resolve relative links against the final URL after redirects
+	src := source.NewHTML(resp.Body, resp.Request.URL)
+	defer src.Close()
+	return source.ReadAll(ctx, src)
+}
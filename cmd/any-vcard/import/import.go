@@ -2,27 +2,60 @@ package vcardimport
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
 	"strings"
+	"sync"
+	"text/template"
+	"time"
 
 	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/geocode"
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/mxcheck"
+	"github.com/rubiojr/any-vcard/internal/profile"
+	"github.com/rubiojr/any-vcard/internal/progress"
+	"github.com/rubiojr/any-vcard/internal/sink"
+	"github.com/rubiojr/any-vcard/internal/source"
+	"github.com/rubiojr/any-vcard/internal/transform"
 	"github.com/rubiojr/any-vcard/internal/vcard"
 	"github.com/rubiojr/anytype-go"
-	"github.com/rubiojr/anytype-go/options"
 	"github.com/urfave/cli/v3"
 )
 
 var Command = &cli.Command{
 	Name:      "import",
 	Usage:     "Import vCard file(s) into Anytype",
-	ArgsUsage: "<vcard-file> [vcard-file...]",
-	Flags: []cli.Flag{
+	ArgsUsage: "<vcard-file|-> [vcard-file...]",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "Input file format: vcard, ldif, mecard, html, csv, outlook-csv, thunderbird-csv, or the experimental mbox, eml and maildir (default: detected from the file extension, directories are read as Maildirs)",
+		},
+		&cli.IntFlag{
+			Name:  "mail-min-messages",
+			Usage: "Skip the addresses found in fewer email messages than this when importing from mail archives",
+			Value: source.DefaultMailMinMessages,
+		},
+		&cli.StringFlag{
+			Name:  "csv-mapping",
+			Usage: "JSON file mapping CSV columns to contact fields; unknown CSV layouts start a wizard that saves one",
+		},
 		&cli.BoolFlag{
 			Name:  "create-type",
 			Usage: "Create Contact object type if it doesn't exist",
 			Value: true,
 		},
+		&cli.BoolFlag{
+			Name:  "company-type",
+			Usage: "Import company cards (KIND:org) as objects of a Company type, creating it if needed",
+		},
 		&cli.BoolFlag{
 			Name:  "merge-duplicates",
 			Usage: "Merge missing fields into existing duplicates (default: true)",
@@ -34,293 +67,976 @@ var Command = &cli.Command{
 			Value: false,
 		},
 		&cli.BoolFlag{
-			Name:  "dry-run",
-			Usage: "Parse vCard files without importing",
+			Name:  "review",
+			Usage: "Show new contacts, duplicates and dropped fields per space and ask before writing anything",
+		},
+		&cli.BoolFlag{
+			Name:  "replace",
+			Usage: "Delete and recreate contacts matched by UID from the incoming card, dropping properties it no longer has, instead of merging",
+		},
+		&cli.BoolFlag{
+			Name:  "three-way",
+			Usage: "Store each imported card and merge re-imports against it, keeping edits made in Anytype",
+		},
+		&cli.BoolFlag{
+			Name:  "strict",
+			Usage: "Refuse to import when a value would be dropped: phones and addresses beyond the slots, photos, and vCard properties with no contact field such as IMPP or X- fields",
+		},
+		&cli.StringFlag{
+			Name:  "audit",
+			Usage: "Write every value the import drops to this CSV file (source, contact, field, value), - for stdout",
+		},
+		util.MinScoreFlag,
+		util.PhoneticFlag,
+		util.IndexDirFlag,
+		&cli.StringFlag{
+			Name:    "max-memory",
+			Usage:   "Memory budget, e.g. 256MiB, Go's memory limit is set to; inputs too large for it skip embedded photos (never uploaded) and keep the duplicate index on disk",
+			Sources: cli.EnvVars("ANYVCARD_MAX_MEMORY"),
+		},
+		&cli.BoolFlag{
+			Name:  "targeted-lookup",
+			Usage: "Search the space for the incoming emails, phones and names instead of loading every contact (faster for small imports)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "dedup-space",
+			Usage: "Also check this space for duplicates (repeatable); they are reported, not merged",
 		},
 		&cli.StringFlag{
 			Name:    "template",
 			Aliases: []string{"t"},
 			Usage:   "Template ID to use when creating new contacts",
 		},
-	},
+		&cli.BoolFlag{
+			Name:  "fix-name-case",
+			Usage: "Title-case names written in ALL CAPS or all lowercase",
+		},
+		&cli.BoolFlag{
+			Name:  "link-relations",
+			Usage: "Link imported contacts to the contact objects named as their assistant or manager",
+		},
+		&cli.StringFlag{
+			Name:  "star-matching",
+			Usage: "Mark contacts whose name, organization, email or group matches this regexp as favorites",
+		},
+		&cli.BoolFlag{
+			Name:  "completeness",
+			Usage: "Store a 0-100 completeness score (phone, email, photo, birthday, address, organization) in a Completeness property",
+		},
+		&cli.BoolFlag{
+			Name:  "skip-role-emails",
+			Usage: "Skip cards with no phone and only role account (info@, noreply@) or disposable emails",
+		},
+		&cli.BoolFlag{
+			Name:  "check-emails",
+			Usage: "Report invalid or misspelled email addresses",
+		},
+		&cli.BoolFlag{
+			Name:  "fix-emails",
+			Usage: "Correct common email typos (implies --check-emails)",
+		},
+		&cli.BoolFlag{
+			Name:  "check-mx",
+			Usage: "Report emails whose domain has no MX or A records (implies --check-emails)",
+		},
+		&cli.DurationFlag{
+			Name:  "mx-timeout",
+			Usage: "Timeout of the DNS lookups of each email domain checked by --check-mx",
+			Value: mxcheck.DefaultTimeout,
+		},
+		&cli.StringFlag{
+			Name:  "format-phones",
+			Usage: "Rewrite phone numbers in the given format (supported: e164)",
+		},
+		&cli.StringFlag{
+			Name:  "default-region",
+			Usage: "ISO country code used to format national phone numbers (e.g. ES, US)",
+		},
+		&cli.BoolFlag{
+			Name:  "keep-original-phones",
+			Usage: "Keep the original phone strings in notes when using --format-phones",
+		},
+		&cli.StringFlag{
+			Name:  "normalize-country",
+			Usage: "Normalize address countries to a canonical English name or ISO-3166 code (name, iso)",
+		},
+		util.BirthdayPlaceholderYearFlag,
+		&cli.BoolFlag{
+			Name:  "yearless-birthday-text",
+			Usage: "Store birthdays without a year in a text property instead of a date",
+		},
+		&cli.BoolFlag{
+			Name:  "birthday-fields",
+			Usage: "Store computed age and next_birthday properties derived from the birthday",
+		},
+		&cli.BoolFlag{
+			Name:  "geocode",
+			Usage: "Resolve addresses to coordinates and store latitude, longitude and a map URL",
+		},
+		&cli.StringFlag{
+			Name:  "geocode-provider",
+			Usage: "Geocoding provider (supported: nominatim)",
+			Value: "nominatim",
+		},
+		&cli.StringFlag{
+			Name:  "geocode-url",
+			Usage: "Geocoding provider base URL (default: public Nominatim server)",
+		},
+		&cli.StringFlag{
+			Name:  "name-format",
+			Usage: "Object name format: first-last, last-first or a Go template (e.g. \"{{.FamilyName}}, {{.GivenName}}\")",
+		},
+		&cli.StringFlag{
+			Name:  "description",
+			Usage: "Object description shown in galleries: auto (title @ organization), none or a Go template (e.g. \"{{.Department}}, {{.Organization}}\")",
+			Value: "auto",
+		},
+		&cli.StringFlag{
+			Name:  "note-template",
+			Usage: "Go template file rendering the notes property from each contact",
+		},
+		&cli.BoolFlag{
+			Name:  "note-links",
+			Usage: "Write the extra emails and original phones kept in the notes as clickable mailto:/tel: links",
+		},
+		&cli.StringFlag{
+			Name:  "transform",
+			Usage: "Starlark script whose transform(contact) edits, tags or drops each contact before import",
+		},
+		util.WhereFlag,
+		util.BlocklistFlag,
+		&cli.StringFlag{
+			Name:  "geocode-cache",
+			Usage: "Geocoding cache file (default: user cache dir)",
+		},
+	}, slices.Concat(spaceFlags, googleFlags, microsoftFlags, ldapFlags, htmlFlags, hookFlags, util.MergeFlags, queueFlags, enrichFlags, importedFlags, archiveFlags, []cli.Flag{util.WebhookFlag})...),
 	Action: func(ctx context.Context, cmd *cli.Command) error {
-		if err := util.RequireFlags(cmd, "app-key", "space"); err != nil {
+		if err := util.RequireFlags(cmd, "app-key"); err != nil {
 			return err
 		}
-		if cmd.Args().Len() == 0 {
+		if cmd.Args().Len() == 0 && !cmd.Bool("google") && !cmd.Bool("microsoft") && cmd.String("ldap") == "" && len(cmd.StringSlice("html")) == 0 {
 			return fmt.Errorf("at least one vCard file is required")
 		}
-		return importVCards(ctx, cmd)
+		if format := cmd.String("format-phones"); format != "" && format != "e164" {
+			return fmt.Errorf("unsupported phone format %q (supported: e164)", format)
+		}
+		if region := cmd.String("default-region"); region != "" && !vcard.IsSupportedPhoneRegion(region) {
+			return fmt.Errorf("unsupported region %q", region)
+		}
+		if _, err := util.MergeOptions(cmd, false); err != nil {
+			return err
+		}
+		if _, err := util.ParseWhere(cmd); err != nil {
+			return err
+		}
+		if _, err := enrichmentSources(cmd); err != nil {
+			return err
+		}
+		if _, err := regexp.Compile(cmd.String("star-matching")); err != nil {
+			return fmt.Errorf("invalid --star-matching pattern: %w", err)
+		}
+		if err := applyMemoryBudget(cmd); err != nil {
+			return fmt.Errorf("invalid --max-memory: %w", err)
+		}
+		switch vcard.CountryFormat(cmd.String("normalize-country")) {
+		case "", vcard.CountryFormatName, vcard.CountryFormatISO:
+		default:
+			return fmt.Errorf("unsupported country format %q (supported: name, iso)", cmd.String("normalize-country"))
+		}
+		spaceIDs, err := targetSpaces(ctx, cmd)
+		if err != nil {
+			return err
+		}
+		dryRun := cmd.Bool("dry-run")
+		offlineQueue, err := openQueue(cmd)
+		if err != nil {
+			return err
+		}
+		// A dry run only parses, it doesn't need the API
+		var unreachable error
+		if !dryRun {
+			if err := util.Preflight(ctx, cmd, spaceIDs...); err != nil {
+				if offlineQueue == nil || !util.IsUnreachable(err) {
+					return err
+				}
+				unreachable = err
+			}
+		}
+
+		started := time.Now()
+		ledger := openLedger(cmd, spaceIDs)
+		contacts, emailReport, blocked, err := prepareContacts(ctx, cmd, spaceIDs, ledger)
+		defer printEmailReport(emailReport) // Also when the import is cancelled or fails
+		if err == nil {
+			err = auditDropped(cmd, contacts)
+		}
+		if err == nil && len(contacts) == 0 && len(ledger.skipped) > 0 && offlineQueue == nil {
+			i18n.Printf("Nothing to import, the files were imported before\n")
+			if !dryRun {
+				return archiveFiles(cmd, ledger, false, nil)
+			}
+			return nil
+		}
+		if err == nil && unreachable != nil {
+			return enqueue(offlineQueue, spaceIDs, contacts, unreachable)
+		}
+		if err == nil && cmd.Bool("review") && !dryRun {
+			var ok bool
+			if ok, err = reviewImport(ctx, cmd, spaceIDs, contacts); err == nil && !ok {
+				i18n.Printf("Import cancelled, nothing was written\n")
+				return nil
+			}
+		}
+		if err == nil && !dryRun {
+			if err = runPreHook(ctx, cmd, spaceIDs, contacts); err != nil {
+				err = fmt.Errorf("pre-import %w", err)
+			}
+		}
+		if err != nil {
+			if !dryRun {
+				for _, spaceID := range spaceIDs {
+					util.NotifyWebhook(ctx, cmd, spaceID, started, util.ImportSummary{Contacts: len(contacts)}, err)
+					util.EmitDone(spaceID, util.ImportSummary{Contacts: len(contacts)}, err)
+				}
+			}
+			return err
+		}
+		if dryRun {
+			printDryRun(contacts)
+			return nil
+		}
+
+		// Parse once, import into every space
+		var errs []error
+		var failedSources []string
+		runFailed, queued := false, false
+		for _, spaceID := range spaceIDs {
+			if len(spaceIDs) > 1 {
+				i18n.Printf("\n=== Space %s ===\n", spaceID)
+			}
+			started := time.Now()
+			batch := queuedContacts(offlineQueue, spaceID, contacts)
+			summary, err := importVCards(ctx, cmd, spaceID, slices.Clone(batch))
+			summary.Blocked = blocked
+			util.InvalidateMirror(spaceID)
+			if settleQueue(offlineQueue, spaceID, batch, err) {
+				queued = true
+				continue
+			}
+			// Failures of replacements are not attributed to a file
+			failedSources = append(failedSources, summary.FailedSources...)
+			runFailed = runFailed || err != nil || summary.Failed > len(summary.FailedSources)
+			util.NotifyWebhook(ctx, cmd, spaceID, started, summary, err)
+			util.EmitDone(spaceID, summary, err)
+			if err == nil && summary.Failed == 0 {
+				ledger.imported(spaceID)
+			}
+			if err == nil {
+				err = runPostHook(ctx, cmd, spaceID, contacts, summary)
+			}
+			if err != nil {
+				if len(spaceIDs) > 1 {
+					err = fmt.Errorf("space %s: %w", spaceID, err)
+				}
+				errs = append(errs, err)
+			}
+		}
+		if offlineQueue != nil {
+			if err := offlineQueue.Save(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if err := ledger.save(); err != nil {
+			errs = append(errs, err)
+		}
+		// Queued files are imported by a later run
+		if !queued {
+			if err := archiveFiles(cmd, ledger, runFailed, failedSources); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
 	},
 }
 
-func importVCards(ctx context.Context, cmd *cli.Command) error {
+// prepareContacts parses the input files and applies every transformation
+// that doesn't depend on the target space, returning the contacts, the
+// email check report and the number of blocklisted contacts dropped
+func prepareContacts(ctx context.Context, cmd *cli.Command, spaceIDs []string, ledger *fileLedger) ([]vcard.Contact, []string, int, error) {
+	// Catch template and blocklist errors before parsing anything
+	if _, _, _, err := parseTemplates(cmd); err != nil {
+		return nil, nil, 0, err
+	}
+	blocklist, err := util.Blocklist(cmd)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	stop := profile.Start(profile.Parse)
+	allContacts, err := parseAllFiles(ctx, cmd, ledger)
+	stop()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	if cmd.Bool("fix-name-case") {
+		for i := range allContacts {
+			allContacts[i].FixNameCase()
+		}
+	}
+
+	if cmd.String("format-phones") != "" {
+		formatPhones(allContacts, cmd.String("default-region"), cmd.Bool("keep-original-phones"))
+	}
+
+	if format := vcard.CountryFormat(cmd.String("normalize-country")); format != "" {
+		for i := range allContacts {
+			allContacts[i].NormalizeCountries(format)
+		}
+	}
+
+	placeholderYear := cmd.Int("birthday-placeholder-year")
+	for i := range allContacts {
+		allContacts[i].ResolveYearlessBirthday(placeholderYear, cmd.Bool("yearless-birthday-text"))
+	}
+
+	if cmd.Bool("birthday-fields") {
+		now := time.Now()
+		for i := range allContacts {
+			allContacts[i].ComputeBirthdayFields(now, placeholderYear)
+		}
+	}
+
+	var starred *regexp.Regexp
+	if pattern := cmd.String("star-matching"); pattern != "" {
+		starred = regexp.MustCompile(pattern) // Validated by the action
+	}
+	vcard.MarkFavorites(allContacts, starred)
+
+	var emailReport []string
+	if cmd.Bool("check-emails") || cmd.Bool("fix-emails") || cmd.Bool("check-mx") {
+		emailReport = checkEmails(allContacts, cmd.Bool("fix-emails"))
+	}
+	if cmd.Bool("check-mx") {
+		emailReport = append(emailReport, checkEmailDomains(ctx, allContacts, cmd.Duration("mx-timeout"))...)
+	}
+
+	if path := cmd.String("transform"); path != "" {
+		if allContacts, err = transformContacts(path, allContacts); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	if allContacts, err = runContactHook(ctx, cmd, spaceIDs, allContacts); err != nil {
+		return nil, nil, 0, err
+	}
+
+	// Blocklisted contacts are dropped silently, only counted
+	blocked := 0
+	if blocklist != nil {
+		total := len(allContacts)
+		allContacts = slices.DeleteFunc(allContacts, func(c vcard.Contact) bool { return blocklist.Blocks(&c, util.DedupOptions(cmd)) })
+		blocked = total - len(allContacts)
+		if blocked > 0 {
+			i18n.Printf("✓ Dropped %d blocklisted contact(s)\n", blocked)
+		}
+	}
+
+	if where, _ := util.ParseWhere(cmd); where != nil { // Validated by the action
+		allContacts = slices.DeleteFunc(allContacts, func(c vcard.Contact) bool { return !where.Match(&c) })
+	}
+
+	if cmd.Bool("skip-role-emails") {
+		allContacts = slices.DeleteFunc(allContacts, func(c vcard.Contact) bool {
+			if !c.OnlyRoleEmails(util.DedupOptions(cmd).Email) {
+				return false
+			}
+			log.Printf("Skipping %s (only role or disposable emails: %s)", c.DisplayName(), strings.Join(c.Emails, ", "))
+			return true
+		})
+	}
+
+	// Public lookups are left out of dry runs, like geocoding
+	if len(cmd.StringSlice("enrich")) > 0 && !cmd.Bool("dry-run") {
+		if err := enrichContacts(ctx, cmd, allContacts); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	if cmd.Bool("completeness") {
+		for i := range allContacts {
+			allContacts[i].ComputeCompleteness()
+		}
+	}
+
+	if cmd.Bool("geocode") && !cmd.Bool("dry-run") {
+		if err := geocodeContacts(ctx, cmd, allContacts); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+	return allContacts, emailReport, blocked, nil
+}
+
+// parseTemplates parses the --name-format, --note-template and
+// --description templates, returning nil for those not set
+func parseTemplates(cmd *cli.Command) (nameFormat, notesTemplate, description *template.Template, err error) {
+	if format := cmd.String("name-format"); format != "" {
+		if nameFormat, err = vcard.ParseNameFormat(format); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if path := cmd.String("note-template"); path != "" {
+		text, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to read note template: %w", err)
+		}
+		if notesTemplate, err = vcard.ParseNotesTemplate(string(text)); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if format := cmd.String("description"); format != "" && format != "none" {
+		if description, err = vcard.ParseDescriptionFormat(format); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return nameFormat, notesTemplate, description, nil
+}
+
+// importVCards imports the prepared contacts into one space, setting up
+// the contact type and properties it needs first
+func importVCards(ctx context.Context, cmd *cli.Command, spaceID string, allContacts []vcard.Contact) (summary util.ImportSummary, err error) {
 	client := util.NewClient(cmd)
-	spaceID := cmd.String("space")
-	dryRun := cmd.Bool("dry-run")
+	summary = util.ImportSummary{Contacts: len(allContacts)}
+	defer util.TrackThroughput()(&summary)
 	skipDuplicates := cmd.Bool("skip-duplicates")
 	mergeDuplicates := cmd.Bool("merge-duplicates") && !skipDuplicates // skip overrides merge
 	templateID := cmd.String("template")
 
-	allContacts, err := parseAllFiles(cmd)
+	nameFormat, notesTemplate, description, err := parseTemplates(cmd)
 	if err != nil {
-		return err
+		return summary, err
 	}
 
-	if dryRun {
-		printDryRun(allContacts)
-		return nil
-	}
-
-	typeKey, err := ensureContactType(ctx, client, spaceID, cmd.Bool("create-type"))
+	typeKey, err := util.EnsureContactType(ctx, client, spaceID, cmd.Bool("create-type"))
 	if err != nil {
-		return err
+		return summary, err
 	}
 
 	phoneKeys, emailKeys, err := util.EnsureContactProperties(ctx, client, spaceID)
 	if err != nil {
-		return fmt.Errorf("failed to ensure properties: %w", err)
+		return summary, fmt.Errorf("failed to ensure properties: %w", err)
 	}
 
-	var dedupIndex *vcard.DedupIndex
-	if skipDuplicates || mergeDuplicates {
-		dedupIndex = fetchExistingContacts(ctx, client, spaceID, typeKey)
-	} else {
-		dedupIndex = vcard.NewDedupIndex(nil)
+	if cmd.Bool("yearless-birthday-text") {
+		if err := util.EnsureProperties(ctx, client, spaceID, []anytype.PropertyDefinition{util.BirthdayTextProperty}); err != nil {
+			return summary, fmt.Errorf("failed to ensure birthday property: %w", err)
+		}
 	}
 
-	return importContacts(ctx, client, spaceID, typeKey, phoneKeys, emailKeys, allContacts, dedupIndex, mergeDuplicates, templateID)
-}
+	if cmd.Bool("birthday-fields") {
+		if err := util.EnsureProperties(ctx, client, spaceID, util.BirthdayFieldProperties); err != nil {
+			return summary, fmt.Errorf("failed to ensure birthday properties: %w", err)
+		}
+	}
 
-func parseAllFiles(cmd *cli.Command) ([]vcard.Contact, error) {
-	var allContacts []vcard.Contact
-	for i := 0; i < cmd.Args().Len(); i++ {
-		filePath := cmd.Args().Get(i)
-		contacts, err := vcard.ParseFile(filePath)
-		if err != nil {
-			log.Printf("Error parsing %s: %v", filePath, err)
-			continue
+	if cmd.Bool("completeness") {
+		if err := util.EnsureProperties(ctx, client, spaceID, []anytype.PropertyDefinition{util.CompletenessProperty}); err != nil {
+			return summary, fmt.Errorf("failed to ensure completeness property: %w", err)
 		}
-		allContacts = append(allContacts, contacts...)
-		fmt.Printf("✓ Parsed %d contact(s) from %s\n", len(contacts), filePath)
 	}
 
-	if len(allContacts) == 0 {
-		return nil, fmt.Errorf("no contacts found in provided files")
+	if cmd.Bool("geocode") {
+		if err := util.EnsureProperties(ctx, client, spaceID, util.GeoProperties); err != nil {
+			return summary, fmt.Errorf("failed to ensure geo properties: %w", err)
+		}
 	}
-	return allContacts, nil
-}
 
-func printDryRun(contacts []vcard.Contact) {
-	fmt.Printf("\nDry run mode - would import %d contact(s):\n", len(contacts))
-	for i, contact := range contacts {
-		fmt.Printf("\n%d. %s\n", i+1, contact.DisplayName())
-		if len(contact.Emails) > 0 {
-			fmt.Printf("   Email: %s\n", strings.Join(contact.Emails, ", "))
+	if slices.ContainsFunc(allContacts, func(c vcard.Contact) bool { return c.Department != "" }) {
+		if err := util.EnsureProperties(ctx, client, spaceID, []anytype.PropertyDefinition{util.DepartmentProperty}); err != nil {
+			return summary, fmt.Errorf("failed to ensure department property: %w", err)
 		}
-		if len(contact.Phones) > 0 {
-			fmt.Printf("   Phone: %s\n", strings.Join(contact.Phones, ", "))
+	}
+
+	if slices.ContainsFunc(allContacts, func(c vcard.Contact) bool { return c.Role != "" }) {
+		if err := util.EnsureProperties(ctx, client, spaceID, []anytype.PropertyDefinition{util.RoleProperty}); err != nil {
+			return summary, fmt.Errorf("failed to ensure role property: %w", err)
 		}
 	}
-}
 
-func ensureContactType(ctx context.Context, client anytype.Client, spaceID string, createType bool) (string, error) {
-	types, err := client.Space(spaceID).Types().List(ctx)
-	if err != nil {
-		return "", fmt.Errorf("failed to list types: %w", err)
+	if slices.ContainsFunc(allContacts, func(c vcard.Contact) bool { return c.Assistant != "" || c.Manager != "" }) {
+		defs := util.RelationProperties
+		if cmd.Bool("link-relations") {
+			defs = slices.Concat(defs, util.RelationLinkProperties)
+		}
+		if err := util.EnsureProperties(ctx, client, spaceID, defs); err != nil {
+			return summary, fmt.Errorf("failed to ensure relation properties: %w", err)
+		}
 	}
 
-	for _, t := range types {
-		if strings.EqualFold(t.Key, util.ContactTypeKey) || strings.EqualFold(t.Name, "contact") {
-			fmt.Printf("✓ Found existing Contact type with key: %s\n", t.Key)
-			return t.Key, nil
+	if slices.ContainsFunc(allContacts, func(c vcard.Contact) bool { return c.Favorite }) {
+		if err := util.EnsureProperties(ctx, client, spaceID, []anytype.PropertyDefinition{util.FavoriteProperty}); err != nil {
+			return summary, fmt.Errorf("failed to ensure favorite property: %w", err)
+		}
+	}
+
+	if cmd.Bool("three-way") {
+		if err := util.EnsureProperties(ctx, client, spaceID, []anytype.PropertyDefinition{util.SnapshotProperty}); err != nil {
+			return summary, fmt.Errorf("failed to ensure snapshot property: %w", err)
+		}
+	}
+	if cmd.Bool("history") {
+		if err := util.EnsureProperties(ctx, client, spaceID, []anytype.PropertyDefinition{util.HistoryProperty}); err != nil {
+			return summary, fmt.Errorf("failed to ensure history property: %w", err)
+		}
+	}
+	if cmd.Bool("replace") {
+		if err := util.EnsureProperties(ctx, client, spaceID, []anytype.PropertyDefinition{util.UIDProperty}); err != nil {
+			return summary, fmt.Errorf("failed to ensure UID property: %w", err)
 		}
 	}
 
-	if !createType {
-		return "", fmt.Errorf("Contact type not found and --create-type=false")
+	typeKeys := []string{typeKey}
+	var companyTypeKey string
+	if cmd.Bool("company-type") && slices.ContainsFunc(allContacts, vcard.Contact.IsOrganization) {
+		if companyTypeKey, err = util.EnsureCompanyType(ctx, client, spaceID); err != nil {
+			return summary, err
+		}
+		typeKeys = append(typeKeys, companyTypeKey)
 	}
 
-	fmt.Printf("Creating Contact object type...\n")
-	typeResp, err := util.CreateContactType(ctx, client, spaceID)
+	dst := &sink.Anytype{
+		Client:         client,
+		SpaceID:        spaceID,
+		TypeKey:        typeKey,
+		CompanyTypeKey: companyTypeKey,
+		PhoneKeys:      phoneKeys,
+		EmailKeys:      emailKeys,
+		TemplateID:     templateID,
+		NotesTemplate:  notesTemplate,
+		NameFormat:     nameFormat,
+		Description:    description,
+		NoteLinks:      cmd.Bool("note-links"),
+		Refresh:        util.RefreshSchema,
+	}
+	merge, err := util.MergeOptions(cmd, mergeDuplicates)
 	if err != nil {
-		return "", fmt.Errorf("failed to create Contact type: %w", err)
+		return summary, err
+	}
+	if cmd.Bool("history") {
+		dst.HistoryKey = util.HistoryProperty.Key
+	}
+	if cmd.Bool("replace") {
+		dst.UIDKey = util.UIDProperty.Key
+		if merge != nil {
+			merge.AdoptUID = true
+		}
+	}
+	if cmd.Bool("three-way") {
+		dst.SnapshotKey = util.SnapshotProperty.Key
+		if merge != nil {
+			merge.ThreeWay = true
+		}
+	}
+	// Replaced objects are gone before the duplicate lookup sees them
+	var replaced []vcard.Contact
+	var replaceErrs []string
+	if cmd.Bool("replace") {
+		if replaced, allContacts, replaceErrs, err = replaceByUID(ctx, client, dst, typeKeys, allContacts, func(summary string) error { return util.Confirm(cmd, summary) }); err != nil {
+			return summary, err
+		}
 	}
-	fmt.Printf("✓ Created Contact type with key: %s\n", typeResp.Type.Key)
-	return typeResp.Type.Key, nil
-}
-
-func fetchExistingContacts(ctx context.Context, client anytype.Client, spaceID, typeKey string) *vcard.DedupIndex {
-	fmt.Printf("Checking for existing contacts...\n")
 
-	// Fetch all contacts with pagination using Search
-	var allObjects []anytype.Object
-	const pageSize = 100
-	offset := 0
+	dedupIndex, closeIndex, err := util.NewDedupIndex(cmd)
+	if err != nil {
+		return summary, err
+	}
+	defer func() {
+		if cerr := closeIndex(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+	switch {
+	case !skipDuplicates && !mergeDuplicates:
+	case cmd.Bool("targeted-lookup"):
+		fetchMatchingContacts(ctx, client, spaceID, typeKeys, allContacts, dedupIndex)
+	default:
+		fetchExistingContacts(ctx, client, spaceID, typeKeys, dedupIndex)
+	}
+	others, err := util.LoadSpaceIndexes(ctx, client, cmd.StringSlice("dedup-space"), util.DedupOptions(cmd), cmd.Bool("phonetic"))
+	if err != nil {
+		return summary, err
+	}
 
-	searchReq := anytype.SearchRequest{
-		Types: []string{typeKey},
+	total := summary.Contacts
+	if summary, err = util.ImportContacts(ctx, dst, allContacts, dedupIndex, merge, others); err != nil {
+		return summary, err
+	}
+	if cmd.Bool("replace") {
+		i18n.Printf("✓ Replaced %d contact(s) matched by UID\n", len(replaced))
+		summary.Contacts, summary.Replaced = total, len(replaced)
+		summary.Failed += len(replaceErrs)
+		summary.Errors = append(summary.Errors, replaceErrs...)
+	}
+	if cmd.Bool("link-relations") {
+		if n := linkRelations(ctx, client, spaceID, slices.Concat(replaced, allContacts), dedupIndex); n > 0 {
+			i18n.Printf("✓ Linked the assistant or manager of %d contact(s)\n", n)
+		}
 	}
+	return summary, nil
+}
 
-	for {
-		searchResp, err := client.Space(spaceID).Search(ctx, searchReq,
-			options.WithLimit(pageSize),
-			options.WithOffset(offset),
-		)
+// transformContacts runs the Starlark transform script over the contacts,
+// dropping those it rejects
+func transformContacts(path string, contacts []vcard.Contact) ([]vcard.Contact, error) {
+	script, err := transform.Load(path, os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	kept := contacts[:0]
+	for i := range contacts {
+		keep, err := script.Apply(&contacts[i])
 		if err != nil {
-			log.Printf("Warning: could not search contacts: %v", err)
-			return vcard.NewDedupIndex(nil)
+			return nil, err
+		}
+		if !keep {
+			log.Printf("Skipping %s (dropped by transform)", contacts[i].DisplayName())
+			continue
 		}
+		kept = append(kept, contacts[i])
+	}
+	return kept, nil
+}
 
-		allObjects = append(allObjects, searchResp.Data...)
+// geocodeContacts resolves the primary address of every contact through the
+// configured provider. Lookups are cached on disk between runs.
+func geocodeContacts(ctx context.Context, cmd *cli.Command, contacts []vcard.Contact) error {
+	provider, err := geocode.NewProvider(cmd.String("geocode-provider"), cmd.String("geocode-url"))
+	if err != nil {
+		return err
+	}
 
-		if len(searchResp.Data) < pageSize {
-			break // No more pages
+	cachePath := cmd.String("geocode-cache")
+	if cachePath == "" {
+		if cachePath, err = geocode.DefaultCachePath(); err != nil {
+			return fmt.Errorf("failed to locate geocode cache: %w", err)
 		}
-		offset += pageSize
 	}
+	cache, err := geocode.NewCache(provider, cachePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := cache.Save(); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}()
 
-	fmt.Printf("✓ Found %d existing contacts\n", len(allObjects))
+	i18n.Printf("Geocoding addresses...\n")
+	var resolved int
+	for i := range contacts {
+		if len(contacts[i].Addresses) == 0 {
+			continue
+		}
+		addr := &contacts[i].Addresses[0]
+		query := geocode.Query(addr.Street, addr.City, addr.Region, addr.PostalCode, addr.Country)
+		if query == "" {
+			continue
+		}
 
-	// Convert Anytype objects to contacts for indexing
-	contacts := make([]*vcard.Contact, 0, len(allObjects))
-	for _, obj := range allObjects {
-		contacts = append(contacts, anytypeObjectToContact(obj))
+		loc, err := cache.Geocode(ctx, query)
+		if err != nil {
+			log.Printf("Warning: could not geocode %s: %v", contacts[i].DisplayName(), err)
+			continue
+		}
+		if loc == nil {
+			log.Printf("Warning: no location found for %s (%s)", contacts[i].DisplayName(), query)
+			continue
+		}
+		addr.Geo = &vcard.Geo{Lat: loc.Lat, Lon: loc.Lon, MapURL: loc.MapURL()}
+		resolved++
 	}
-
-	return vcard.NewDedupIndex(contacts)
+	i18n.Printf("✓ Geocoded %d address(es)\n", resolved)
+	return nil
 }
 
-// anytypeObjectToContact converts an Anytype object to a Contact for dedup
-func anytypeObjectToContact(obj anytype.Object) *vcard.Contact {
-	c := &vcard.Contact{
-		FormattedName: obj.Name,
-		ObjectID:      obj.ID,
+// formatPhones rewrites all contact phones as E.164, logging numbers that
+// cannot be formatted (those are imported unchanged)
+func formatPhones(contacts []vcard.Contact, region string, keepOriginal bool) {
+	for i := range contacts {
+		for _, err := range contacts[i].FormatPhones(region, keepOriginal) {
+			log.Printf("Warning: %s: %v", contacts[i].DisplayName(), err)
+		}
 	}
+}
 
-	for _, prop := range obj.Properties {
-		switch prop.Key {
-		case "email", "email_2", "email_3":
-			if prop.Email != "" {
-				c.Emails = append(c.Emails, prop.Email)
-			}
-		case "phone", "phone_2", "phone_3":
-			if prop.Phone != "" {
-				c.Phones = append(c.Phones, prop.Phone)
-			}
-		case "organization":
-			c.Organization = prop.Text
-		case "title":
-			c.Title = prop.Text
-		case "birthday":
-			c.Birthday = prop.Date
-		case "given_name":
-			c.GivenName = prop.Text
-		case "family_name":
-			c.FamilyName = prop.Text
-		case "middle_name":
-			c.MiddleName = prop.Text
-		case "prefix":
-			c.Prefix = prop.Text
-		case "suffix":
-			c.Suffix = prop.Text
-		case "notes":
-			c.Note = prop.Text
-		case "url":
-			if prop.URL != "" {
-				c.URLs = append(c.URLs, prop.URL)
-			}
-		case "address":
-			if prop.Text != "" && len(c.Addresses) == 0 {
-				c.Addresses = append(c.Addresses, vcard.Address{Street: prop.Text})
-			} else if len(c.Addresses) > 0 {
-				c.Addresses[0].Street = prop.Text
-			}
-		case "city":
-			if len(c.Addresses) == 0 {
-				c.Addresses = append(c.Addresses, vcard.Address{})
-			}
-			c.Addresses[0].City = prop.Text
-		case "region":
-			if len(c.Addresses) == 0 {
-				c.Addresses = append(c.Addresses, vcard.Address{})
+// checkEmails validates all contact emails, optionally fixing known typos,
+// and returns a human readable line per issue found
+func checkEmails(contacts []vcard.Contact, fix bool) []string {
+	var report []string
+	for i := range contacts {
+		for _, issue := range contacts[i].CheckEmails(fix) {
+			line := fmt.Sprintf("%s: %s (%s)", contacts[i].DisplayName(), issue.Original, issue.Reason)
+			if issue.Fixed != "" {
+				if fix {
+					line += fmt.Sprintf(" → corrected to %s", issue.Fixed)
+				} else {
+					line += fmt.Sprintf(" → did you mean %s?", issue.Fixed)
+				}
 			}
-			c.Addresses[0].Region = prop.Text
-		case "postal_code":
-			if len(c.Addresses) == 0 {
-				c.Addresses = append(c.Addresses, vcard.Address{})
+			report = append(report, line)
+		}
+	}
+	return report
+}
+
+// checkEmailDomains looks up the domains of every email concurrently,
+// reporting the addresses at domains that can't receive mail
+func checkEmailDomains(ctx context.Context, contacts []vcard.Contact, timeout time.Duration) []string {
+	var domains []string
+	for _, c := range contacts {
+		for _, email := range c.Emails {
+			if _, domain, ok := strings.Cut(email, "@"); ok {
+				domains = append(domains, domain)
 			}
-			c.Addresses[0].PostalCode = prop.Text
-		case "country":
-			if len(c.Addresses) == 0 {
-				c.Addresses = append(c.Addresses, vcard.Address{})
+		}
+	}
+	if len(domains) == 0 {
+		return nil
+	}
+
+	i18n.Printf("Checking email domains...\n")
+	checker := mxcheck.New()
+	checker.Timeout = timeout
+	statuses := checker.Check(ctx, domains)
+
+	var report []string
+	var unknown int
+	for _, c := range contacts {
+		for _, email := range c.Emails {
+			_, domain, _ := strings.Cut(email, "@")
+			switch status := statuses[strings.ToLower(strings.TrimSpace(domain))]; status {
+			case mxcheck.StatusDead, mxcheck.StatusNoMail:
+				report = append(report, fmt.Sprintf("%s: %s (%s %s)", c.DisplayName(), email, domain, status))
+			case mxcheck.StatusUnknown:
+				unknown++
 			}
-			c.Addresses[0].Country = prop.Text
 		}
 	}
+	i18n.Printf("✓ Checked %d email domain(s)\n", len(statuses))
+	if unknown > 0 {
+		log.Printf("Warning: the domains of %d email(s) could not be checked", unknown)
+	}
+	return report
+}
 
-	return c
+func printEmailReport(report []string) {
+	if len(report) == 0 {
+		return
+	}
+	i18n.Printf("\n⚠ Email issues (%d):\n", len(report))
+	for _, line := range report {
+		fmt.Fprintf(i18n.Output(), "  %s\n", line)
+	}
 }
 
-func importContacts(ctx context.Context, client anytype.Client, spaceID, typeKey string, phoneKeys, emailKeys []string, contacts []vcard.Contact, dedupIndex *vcard.DedupIndex, mergeDuplicates bool, templateID string) error {
-	fmt.Printf("\nImporting %d contact(s)...\n", len(contacts))
+func parseAllFiles(ctx context.Context, cmd *cli.Command, ledger *fileLedger) ([]vcard.Contact, error) {
+	var paths, hashes []string
+	for i := 0; i < cmd.Args().Len(); i++ {
+		filePath := cmd.Args().Get(i)
+		hash, skip := ledger.check(filePath)
+		if skip {
+			continue
+		}
+		paths = append(paths, filePath)
+		hashes = append(hashes, hash)
+	}
 
-	var successCount, skippedCount, mergedCount int
-	for i := range contacts {
-		contact := &contacts[i]
-
-		duplicates := dedupIndex.FindDuplicates(contact)
-		if len(duplicates) > 0 {
-			if mergeDuplicates {
-				// Merge into the first duplicate found
-				existing := duplicates[0]
-				if vcard.MergeContacts(existing, contact) {
-					// Update the existing contact in Anytype
-					if err := updateContact(ctx, client, spaceID, phoneKeys, emailKeys, existing); err != nil {
-						log.Printf("Error merging contact %d (%s): %v", i+1, contact.DisplayName(), err)
-						continue
-					}
-					mergedCount++
-					fmt.Printf("⊕ Merged: %s → %s\n", contact.DisplayName(), existing.DisplayName())
-				} else {
-					log.Printf("Skipping %s (nothing new to merge)", contact.DisplayName())
-					skippedCount++
-				}
-			} else {
-				log.Printf("Skipping duplicate contact %d (%s)", i+1, contact.DisplayName())
-				skippedCount++
-			}
+	var allContacts []vcard.Contact
+	for i, file := range readSources(ctx, cmd, paths) {
+		filePath, contacts := paths[i], file.contacts
+		if file.err != nil {
+			log.Printf("Error parsing %s: %v", filePath, file.err)
+			ledger.unreadableFile(filePath)
 			continue
 		}
+		ledger.parsedFile(filePath, hashes[i], len(contacts))
+		setSource(contacts, filepath.Clean(filePath))
+		emitParsed(filePath, len(contacts))
+		allContacts = append(allContacts, contacts...)
+		i18n.Printf("✓ Parsed %d contact(s) from %s\n", len(contacts), filePath)
+	}
+
+	if cmd.Bool("google") {
+		contacts, err := readGoogle(ctx, cmd)
+		if err != nil {
+			return nil, err
+		}
+		setSource(contacts, "Google")
+		emitParsed("Google", len(contacts))
+		allContacts = append(allContacts, contacts...)
+		i18n.Printf("✓ Fetched %d contact(s) from Google\n", len(contacts))
+	}
 
-		if err := importContact(ctx, client, spaceID, typeKey, phoneKeys, emailKeys, *contact, templateID); err != nil {
-			log.Printf("Error importing contact %d (%s): %v", i+1, contact.DisplayName(), err)
+	if cmd.Bool("microsoft") {
+		contacts, err := readMicrosoft(ctx, cmd)
+		if err != nil {
+			return nil, err
+		}
+		setSource(contacts, "Microsoft")
+		emitParsed("Microsoft", len(contacts))
+		allContacts = append(allContacts, contacts...)
+		i18n.Printf("✓ Fetched %d contact(s) from Microsoft\n", len(contacts))
+	}
+
+	if url := cmd.String("ldap"); url != "" {
+		contacts, err := readLDAP(ctx, cmd)
+		if err != nil {
+			return nil, err
+		}
+		setSource(contacts, url)
+		emitParsed(url, len(contacts))
+		allContacts = append(allContacts, contacts...)
+		i18n.Printf("✓ Fetched %d contact(s) from %s\n", len(contacts), url)
+	}
+
+	for _, location := range cmd.StringSlice("html") {
+		contacts, err := readHTML(ctx, location)
+		if err != nil {
+			log.Printf("Error reading %s: %v", location, err)
 			continue
 		}
+		setSource(contacts, location)
+		emitParsed(location, len(contacts))
+		allContacts = append(allContacts, contacts...)
+		i18n.Printf("✓ Found %d h-card(s) on %s\n", len(contacts), location)
+	}
+
+	if len(allContacts) == 0 && len(ledger.skipped) > 0 {
+		return nil, nil // Nothing new, which the action reports
+	}
+	if len(allContacts) == 0 {
+		return nil, fmt.Errorf("no contacts found in provided files")
+	}
+	return allContacts, nil
+}
 
-		// Add to index to catch duplicates within the import batch
-		dedupIndex.Add(contact)
+// emitParsed reports the contacts read from source to the --progress-json
+// stream
+func emitParsed(source string, n int) {
+	progress.Emit(progress.Event{Event: progress.Parsed, Source: source, Count: n})
+}
 
-		successCount++
-		fmt.Printf("✓ Imported: %s\n", contact.DisplayName())
+// setSource records where the contacts were read from, for the provenance
+// of merged notes and to tell which files had contacts failing to import
+func setSource(contacts []vcard.Contact, source string) {
+	if source == "-" {
+		source = "stdin"
+	}
+	for i := range contacts {
+		contacts[i].Source = source
 	}
+}
 
-	fmt.Printf("\n✓ Successfully imported %d/%d contacts", successCount, len(contacts))
-	if mergedCount > 0 {
-		fmt.Printf(" (merged %d)", mergedCount)
+// sourceContacts are the contacts read from a file, or why it couldn't be
+// read
+type sourceContacts struct {
+	contacts []vcard.Contact
+	err      error
+}
+
+// readSources reads the files concurrently, as many at a time as there are
+// CPUs, see readSource. CSV mappings are settled first, one file at a time,
+// as they may be asked for. The results are in the order of paths so the
+// import doesn't depend on which file finishes first.
+func readSources(ctx context.Context, cmd *cli.Command, paths []string) []sourceContacts {
+	results := make([]sourceContacts, len(paths))
+	mappings := make([]source.Mapping, len(paths))
+	for i, path := range paths {
+		mappings[i], results[i].err = csvMapping(cmd, path)
 	}
-	if skippedCount > 0 {
-		fmt.Printf(" (skipped %d duplicates)", skippedCount)
+
+	dropPhotos := overBudget(cmd)
+	sem := make(chan struct{}, runtime.NumCPU())
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		if results[i].err != nil {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			results[i].contacts, results[i].err = readSource(ctx, cmd, path, mappings[i], dropPhotos)
+		}()
 	}
-	fmt.Printf("\n")
-	return nil
+	wg.Wait()
+	return results
 }
 
-func importContact(ctx context.Context, client anytype.Client, spaceID, typeKey string, phoneKeys, emailKeys []string, contact vcard.Contact, templateID string) error {
-	return vcard.Import(ctx, client, spaceID, typeKey, phoneKeys, emailKeys, contact, templateID)
+// readSource reads every contact from filePath in the --format format, or
+// with mapping, a custom CSV column mapping when not nil, see csvMapping.
+// dropPhotos leaves embedded photos out as they are read.
+func readSource(ctx context.Context, cmd *cli.Command, filePath string, mapping source.Mapping, dropPhotos bool) ([]vcard.Contact, error) {
+	var src source.Source
+	var err error
+	switch {
+	case mapping != nil:
+		src, err = source.OpenCSV(filePath, mapping)
+	case source.IsMail(filePath, cmd.String("format")):
+		src, err = source.OpenMail(filePath, int(cmd.Int("mail-min-messages")))
+	default:
+		src, err = source.OpenFormat(filePath, cmd.String("format"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	if dropPhotos {
+		src = source.Map(src, dropEmbeddedPhoto)
+	}
+	return source.ReadAll(ctx, src)
 }
 
-// updateContact updates an existing contact with merged data
-func updateContact(ctx context.Context, client anytype.Client, spaceID string, phoneKeys, emailKeys []string, contact *vcard.Contact) error {
-	return vcard.Update(ctx, client, spaceID, phoneKeys, emailKeys, contact)
+func printDryRun(contacts []vcard.Contact) {
+	i18n.Printf("\nDry run mode - would import %d contact(s):\n", len(contacts))
+	for i, contact := range contacts {
+		fmt.Fprintf(i18n.Output(), "\n%d. %s\n", i+1, contact.DisplayName())
+		if len(contact.Emails) > 0 {
+			i18n.Printf("   Email: %s\n", strings.Join(contact.Emails, ", "))
+		}
+		if len(contact.Phones) > 0 {
+			i18n.Printf("   Phone: %s\n", strings.Join(contact.Phones, ", "))
+		}
+	}
+}
+
+// fetchExistingContacts adds the contacts of the space to idx, a page at a
+// time so large spaces needn't be held in memory with a disk-backed index
+func fetchExistingContacts(ctx context.Context, client anytype.Client, spaceID string, typeKeys []string, idx *vcard.DedupIndex) {
+	i18n.Printf("Checking for existing contacts...\n")
+
+	// Progress is redrawn in place on terminals, and left out of logs
+	progress := util.IsTerminal(i18n.Output())
+	found := 0
+	err := vcard.SearchPages(ctx, client, spaceID, anytype.SearchRequest{Types: typeKeys}, func(page []anytype.Object) error {
+		// Convert Anytype objects to contacts for indexing
+		defer profile.Start(profile.Dedup)()
+		for _, obj := range page {
+			idx.Add(vcard.FromObject(obj))
+		}
+		found += len(page)
+		if progress {
+			i18n.Printf("\r  fetched %d contacts...", found)
+		}
+		return nil
+	})
+	if progress {
+		fmt.Fprint(i18n.Output(), "\r\033[K")
+	}
+	if err != nil {
+		log.Printf("Warning: could not search contacts: %v", err)
+		return
+	}
+
+	i18n.Printf("✓ Found %d existing contacts\n", found)
 }
@@ -0,0 +1,120 @@
+package vcardimport
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rubiojr/any-vcard/internal/imported"
+	"github.com/urfave/cli/v3"
+)
+
+var importedFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name:  "skip-imported-files",
+		Usage: "Skip files whose exact content was already imported into the target spaces, instead of warning",
+	},
+}
+
+// fileLedger keeps track of the file arguments of a run: those already
+// imported into the target spaces according to the imported files log,
+// and those read or failing to parse
+type fileLedger struct {
+	log        *imported.Log // Nil when the log can't be read
+	spaceIDs   []string
+	skip       bool
+	parsed     []imported.Record // Files read this run, recorded once imported
+	skipped    []string
+	unreadable []string
+}
+
+// openLedger loads the imported files log. A broken log is only warned
+// about, it never stops an import.
+func openLedger(cmd *cli.Command, spaceIDs []string) *fileLedger {
+	f := &fileLedger{spaceIDs: spaceIDs, skip: cmd.Bool("skip-imported-files")}
+	path, err := imported.DefaultPath()
+	if err != nil {
+		log.Printf("Warning: could not locate the imported files log: %v", err)
+		return f
+	}
+	if f.log, err = imported.Load(path); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	return f
+}
+
+// check hashes the file at path and reports whether to skip it: it was
+// already imported into every target space and --skip-imported-files is
+// set. Otherwise previous imports are warned about.
+func (f *fileLedger) check(path string) (hash string, skip bool) {
+	if f.log == nil {
+		return "", false
+	}
+	if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+		return "", false
+	}
+	hash, err := imported.HashFile(path)
+	if err != nil {
+		log.Printf("Warning: could not hash %s: %v", path, err)
+		return "", false
+	}
+
+	var previous []imported.Record
+	for _, spaceID := range f.spaceIDs {
+		if r, ok := f.log.Find(spaceID, hash); ok {
+			previous = append(previous, r)
+		}
+	}
+	if len(previous) == 0 {
+		return hash, false
+	}
+	last := previous[len(previous)-1]
+	if f.skip && len(previous) == len(f.spaceIDs) {
+		f.skipped = append(f.skipped, path)
+		log.Printf("Skipping %s (already imported into space %s on %s as %s)", path, last.SpaceID, last.Imported.Format(time.DateTime), last.Path)
+		return hash, true
+	}
+	log.Printf("Warning: %s was already imported into space %s on %s as %s (--skip-imported-files skips it)", path, last.SpaceID, last.Imported.Format(time.DateTime), last.Path)
+	return hash, false
+}
+
+// parsedFile notes a file read this run
+func (f *fileLedger) parsedFile(path, hash string, contacts int) {
+	f.parsed = append(f.parsed, imported.Record{Hash: hash, Path: path, Contacts: contacts})
+}
+
+// unreadableFile notes a file that failed to parse
+func (f *fileLedger) unreadableFile(path string) {
+	f.unreadable = append(f.unreadable, path)
+}
+
+// imported records the files read this run as imported into spaceID
+func (f *fileLedger) imported(spaceID string) {
+	if f.log == nil {
+		return
+	}
+	now := time.Now()
+	for _, r := range f.parsed {
+		if r.Hash == "" {
+			continue
+		}
+		if abs, err := filepath.Abs(r.Path); err == nil {
+			r.Path = abs
+		}
+		r.SpaceID, r.Imported = spaceID, now
+		f.log.Add(r)
+	}
+}
+
+// save writes the log back
+func (f *fileLedger) save() error {
+	if f.log == nil {
+		return nil
+	}
+	if err := f.log.Save(); err != nil {
+		return fmt.Errorf("failed to save the imported files log: %w", err)
+	}
+	return nil
+}
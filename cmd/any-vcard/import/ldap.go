@@ -0,0 +1,69 @@
+package vcardimport
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/rubiojr/any-vcard/internal/source"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/urfave/cli/v3"
+)
+
+var ldapFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "ldap",
+		Usage: "Import contacts from an LDAP directory URL (ldap:// or ldaps://) instead of files",
+	},
+	&cli.StringFlag{
+		Name:  "base-dn",
+		Usage: "LDAP search base (e.g. ou=people,dc=example,dc=com)",
+	},
+	&cli.StringFlag{
+		Name:  "filter",
+		Usage: "LDAP search filter",
+		Value: "(objectClass=person)",
+	},
+	&cli.StringFlag{
+		Name:  "bind-dn",
+		Usage: "DN to bind as (anonymous when empty)",
+	},
+	&cli.StringFlag{
+		Name:    "bind-password",
+		Usage:   "Password for --bind-dn",
+		Sources: cli.EnvVars("LDAP_BIND_PASSWORD"),
+	},
+	&cli.StringSliceFlag{
+		Name:  "ldap-attr",
+		Usage: "Override an attribute mapping as field=attr[,attr] (e.g. email=mail,proxyAddresses)",
+	},
+}
+
+// readLDAP searches the directory and maps every matching entry to a contact
+func readLDAP(ctx context.Context, cmd *cli.Command) ([]vcard.Contact, error) {
+	if cmd.String("base-dn") == "" {
+		return nil, fmt.Errorf("--ldap requires --base-dn")
+	}
+
+	mapping := source.DefaultLDAPMapping()
+	for _, spec := range cmd.StringSlice("ldap-attr") {
+		if err := mapping.Set(spec); err != nil {
+			return nil, err
+		}
+	}
+
+	conn, err := ldap.DialURL(cmd.String("ldap"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", cmd.String("ldap"), err)
+	}
+	if bindDN := cmd.String("bind-dn"); bindDN != "" {
+		if err := conn.Bind(bindDN, cmd.String("bind-password")); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("ldap bind failed: %w", err)
+		}
+	}
+
+	src := source.NewLDAP(conn, cmd.String("base-dn"), cmd.String("filter"), mapping)
+	defer src.Close()
+	return source.ReadAll(ctx, src)
+}
@@ -0,0 +1,80 @@
+package vcardimport
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/rubiojr/anytype-go"
+)
+
+// lookupQueries returns the full-text queries finding the possible
+// duplicates of contacts: their emails, phones, UIDs and names. Phones are
+// also searched by their digits and by the suffix duplicates are matched
+// on with opts, as the space may store them formatted differently.
+func lookupQueries(contacts []vcard.Contact, opts vcard.DedupOptions) []string {
+	seen := make(map[string]struct{})
+	var queries []string
+	add := func(q string) {
+		if _, ok := seen[q]; ok || q == "" {
+			return
+		}
+		seen[q] = struct{}{}
+		queries = append(queries, q)
+	}
+	for _, c := range contacts {
+		for _, e := range c.Emails {
+			add(strings.ToLower(strings.TrimSpace(e)))
+		}
+		for _, p := range c.Phones {
+			add(p)
+			add(phoneDigits(p))
+			add(opts.NormalizePhone(p))
+		}
+		add(c.UID)
+		if name := c.DisplayName(); name != "Unnamed Contact" {
+			add(name)
+		}
+	}
+	return queries
+}
+
+// phoneDigits returns the digits of phone
+func phoneDigits(phone string) string {
+	return strings.Map(func(r rune) rune {
+		if r < '0' || r > '9' {
+			return -1
+		}
+		return r
+	}, phone)
+}
+
+// fetchMatchingContacts adds to idx only the objects of the space found by
+// searching for the incoming contacts, instead of every contact. For small
+// imports into large spaces this downloads a fraction of the objects, but
+// relies on the full-text search matching the stored property values.
+func fetchMatchingContacts(ctx context.Context, client anytype.Client, spaceID string, typeKeys []string, contacts []vcard.Contact, idx *vcard.DedupIndex) {
+	queries := lookupQueries(contacts, idx.Options())
+	i18n.Printf("Looking up existing contacts (%d searches)...\n", len(queries))
+
+	seen := make(map[string]struct{})
+	for _, q := range queries {
+		err := vcard.SearchPages(ctx, client, spaceID, anytype.SearchRequest{Query: q, Types: typeKeys}, func(page []anytype.Object) error {
+			for _, obj := range page {
+				if _, ok := seen[obj.ID]; ok {
+					continue
+				}
+				seen[obj.ID] = struct{}{}
+				idx.Add(vcard.FromObject(obj))
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("Warning: could not search contacts for %q: %v", q, err)
+		}
+	}
+
+	i18n.Printf("✓ Found %d possibly matching contacts\n", len(seen))
+}
@@ -0,0 +1,79 @@
+package vcardimport
+
+import (
+	"context"
+	"log"
+	"os"
+	"runtime/debug"
+
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/urfave/cli/v3"
+)
+
+// memoryPerInputByte estimates the memory parsed contacts take for each
+// byte of their files: strings, slices and the duplicate index add up
+const memoryPerInputByte = 4
+
+// memoryBudget returns the --max-memory budget in bytes, 0 when unlimited
+func memoryBudget(cmd *cli.Command) (int64, error) {
+	size := cmd.String("max-memory")
+	if size == "" {
+		return 0, nil
+	}
+	return util.ParseBytes(size)
+}
+
+// inputSize returns the total size of the files to import. Stdin and
+// directories aren't counted.
+func inputSize(cmd *cli.Command) int64 {
+	var total int64
+	for _, path := range cmd.Args().Slice() {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			total += info.Size()
+		}
+	}
+	return total
+}
+
+// overBudget reports whether the input is too large to import within the
+// --max-memory budget the usual way
+func overBudget(cmd *cli.Command) bool {
+	budget, _ := memoryBudget(cmd) // Validated by applyMemoryBudget
+	return budget > 0 && inputSize(cmd)*memoryPerInputByte > budget
+}
+
+// applyMemoryBudget sets Go's memory limit to the --max-memory budget, and
+// keeps the duplicate index in a temporary file when the input won't fit
+// in it. Embedded photos are dropped then too, see readSource.
+func applyMemoryBudget(cmd *cli.Command) error {
+	budget, err := memoryBudget(cmd)
+	if err != nil || budget == 0 {
+		return err
+	}
+	debug.SetMemoryLimit(budget)
+	if !overBudget(cmd) {
+		return nil
+	}
+
+	i18n.Printf("Input of %s is too large for a %s memory budget: skipping embedded photos", util.FormatBytes(inputSize(cmd)), util.FormatBytes(budget))
+	if cmd.String("index-dir") == "" {
+		if err := cmd.Set("index-dir", os.TempDir()); err != nil {
+			log.Printf("Warning: could not move the duplicate index to disk: %v", err)
+		} else {
+			i18n.Printf(" and keeping the duplicate index on disk")
+		}
+	}
+	i18n.Printf("\n")
+	return nil
+}
+
+// dropEmbeddedPhoto clears the photo of c unless it's a link. Photos
+// aren't uploaded to Anytype, so decoded images would only take up memory.
+func dropEmbeddedPhoto(_ context.Context, c *vcard.Contact) error {
+	if !vcard.IsPhotoURL(c.Photo) {
+		c.Photo = ""
+	}
+	return nil
+}
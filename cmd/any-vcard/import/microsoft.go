@@ -0,0 +1,58 @@
+package vcardimport
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/oauth"
+	"github.com/rubiojr/any-vcard/internal/source"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/urfave/cli/v3"
+)
+
+var microsoftFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name:  "microsoft",
+		Usage: "Import contacts from Outlook.com / Microsoft 365 (Microsoft Graph) instead of files",
+	},
+	&cli.StringFlag{
+		Name:    "microsoft-client-id",
+		Usage:   "Application (client) ID of an Azure app registration with public client flows enabled",
+		Sources: cli.EnvVars("MICROSOFT_CLIENT_ID"),
+	},
+	&cli.StringFlag{
+		Name:    "microsoft-tenant",
+		Usage:   "Tenant to sign in to (common, consumers, organizations or a tenant ID)",
+		Value:   "common",
+		Sources: cli.EnvVars("MICROSOFT_TENANT"),
+	},
+}
+
+// readMicrosoft authorizes against Microsoft with a device code (reusing
+// the cached token when possible) and fetches every contact through Graph
+func readMicrosoft(ctx context.Context, cmd *cli.Command) ([]vcard.Contact, error) {
+	if cmd.String("microsoft-client-id") == "" {
+		return nil, fmt.Errorf("--microsoft requires --microsoft-client-id (or MICROSOFT_CLIENT_ID)")
+	}
+
+	config := source.GraphOAuthConfig(cmd.String("microsoft-client-id"), cmd.String("microsoft-tenant"))
+	store, err := oauth.DefaultStore("microsoft")
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate token store: %w", err)
+	}
+
+	client, err := config.Client(ctx, store, func(ctx context.Context) (*oauth.Token, error) {
+		return config.DeviceFlow(ctx, func(code *oauth.DeviceCode) {
+			i18n.Printf("\nTo grant read access to your Microsoft contacts, open %s\nand enter the code: %s\n\n", code.VerificationURI, code.UserCode)
+			i18n.Printf("Waiting for authorization...\n")
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("microsoft authorization failed: %w", err)
+	}
+
+	src := source.NewGraph(client)
+	defer src.Close()
+	return source.ReadAll(ctx, src)
+}
@@ -0,0 +1,83 @@
+package vcardimport
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/queue"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/urfave/cli/v3"
+)
+
+var queueFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name:  "queue-offline",
+		Usage: "When the Anytype API is unreachable, queue the parsed contacts instead of failing; the next run with this flag imports them",
+	},
+	&cli.StringFlag{
+		Name:  "queue-file",
+		Usage: "Offline queue file (default: user cache dir)",
+	},
+}
+
+// openQueue loads the offline queue, or returns nil without --queue-offline
+func openQueue(cmd *cli.Command) (*queue.Queue, error) {
+	if !cmd.Bool("queue-offline") {
+		return nil, nil
+	}
+	path := cmd.String("queue-file")
+	if path == "" {
+		var err error
+		if path, err = queue.DefaultPath(); err != nil {
+			return nil, fmt.Errorf("failed to locate offline queue: %w", err)
+		}
+	}
+	return queue.Load(path)
+}
+
+// enqueue queues contacts for every space while the API is unreachable,
+// cause being the failed preflight check
+func enqueue(q *queue.Queue, spaceIDs []string, contacts []vcard.Contact, cause error) error {
+	for _, spaceID := range spaceIDs {
+		q.Add(spaceID, contacts)
+	}
+	if err := q.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(i18n.Output(), "⚠ %v\n", cause)
+	i18n.Printf("Queued %d contact(s) for %d space(s); the next import with --queue-offline writes them\n", len(contacts), len(spaceIDs))
+	return nil
+}
+
+// queuedContacts prepends the contacts queued for spaceID to contacts
+func queuedContacts(q *queue.Queue, spaceID string, contacts []vcard.Contact) []vcard.Contact {
+	if q == nil {
+		return contacts
+	}
+	queued := q.Contacts(spaceID)
+	if len(queued) > 0 {
+		i18n.Printf("Replaying %d contact(s) queued while Anytype was unreachable\n", len(queued))
+	}
+	return slices.Concat(queued, contacts)
+}
+
+// settleQueue updates the queue of spaceID once contacts were imported into
+// it: they are dropped on success and queued again when the API went away
+// mid-run, which reports true as the failure is then taken care of
+func settleQueue(q *queue.Queue, spaceID string, contacts []vcard.Contact, err error) bool {
+	if q == nil {
+		return false
+	}
+	switch {
+	case err == nil:
+		q.Drop(spaceID)
+	case util.IsUnreachable(err):
+		q.Drop(spaceID)
+		q.Add(spaceID, contacts)
+		i18n.Printf("⚠ Anytype became unreachable, queued %d contact(s) for space %s\n", len(contacts), spaceID)
+		return true
+	}
+	return false
+}
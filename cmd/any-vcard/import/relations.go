@@ -0,0 +1,51 @@
+package vcardimport
+
+import (
+	"context"
+	"log"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/rubiojr/anytype-go"
+)
+
+// linkRelations points the assistant and manager of the imported contacts
+// at the contact objects with those names, when exactly one matches. It
+// returns the number of contacts linked.
+func linkRelations(ctx context.Context, client anytype.Client, spaceID string, contacts []vcard.Contact, idx *vcard.DedupIndex) int {
+	linked := 0
+	for i := range contacts {
+		c := &contacts[i]
+		if c.ObjectID == "" {
+			continue // Merged or skipped
+		}
+
+		var props []map[string]any
+		for _, rel := range []struct{ key, name string }{
+			{"assistant_contact", c.Assistant},
+			{"manager_contact", c.Manager},
+		} {
+			if rel.name == "" {
+				continue
+			}
+			targets := idx.FindByName(rel.name)
+			if len(targets) > 1 {
+				log.Printf("Warning: %d contacts are named %s, not linking %s", len(targets), rel.name, c.DisplayName())
+				continue
+			}
+			if len(targets) == 0 || targets[0].ObjectID == "" || targets[0].ObjectID == c.ObjectID {
+				continue
+			}
+			props = append(props, map[string]any{"key": rel.key, "objects": []string{targets[0].ObjectID}})
+		}
+		if len(props) == 0 {
+			continue
+		}
+
+		if err := vcard.UpdateObject(ctx, client, spaceID, c.ObjectID, props); err != nil {
+			log.Printf("Warning: could not link the relations of %s: %v", c.DisplayName(), err)
+			continue
+		}
+		linked++
+	}
+	return linked
+}
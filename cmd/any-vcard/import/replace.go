@@ -0,0 +1,142 @@
+package vcardimport
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/sink"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/rubiojr/anytype-go"
+)
+
+// link is an objects property of a contact object pointing at other objects
+type link struct {
+	objectID string
+	key      string
+	targets  []string
+}
+
+// replaceByUID recreates the contact objects sharing a UID with an incoming
+// contact from that contact alone, so properties the source no longer has
+// are removed, which merging can't do. The API can't create an object with
+// a given ID, so the links of other objects to a replaced object are moved
+// to its new object before the old one is deleted. confirm is asked before
+// anything is replaced. It returns the replaced contacts, those left to
+// import and an error line per failed replacement.
+func replaceByUID(ctx context.Context, client anytype.Client, dst *sink.Anytype, typeKeys []string, contacts []vcard.Contact, confirm func(summary string) error) (replaced, rest []vcard.Contact, failed []string, err error) {
+	incoming := make(map[string]bool)
+	for _, c := range contacts {
+		if c.UID != "" {
+			incoming[c.UID] = true
+		}
+	}
+	if len(incoming) == 0 {
+		return nil, contacts, nil, nil
+	}
+
+	i18n.Printf("Looking up contacts to replace...\n")
+	existing := make(map[string][]string) // UID → object IDs
+	var links []link
+	// The whole space, as objects of any type can link to a contact
+	err = vcard.SearchPages(ctx, client, dst.SpaceID, anytype.SearchRequest{}, func(page []anytype.Object) error {
+		for _, obj := range page {
+			contact := obj.Type != nil && slices.Contains(typeKeys, obj.Type.Key)
+			// Cards exported without a stored UID carry the one of their object
+			if uid := vcard.ObjectUID(obj.ID); contact && incoming[uid] {
+				existing[uid] = append(existing[uid], obj.ID)
+			}
+			for _, prop := range obj.Properties {
+				switch {
+				case prop.Key == util.UIDProperty.Key && contact && incoming[prop.Text]:
+					existing[prop.Text] = append(existing[prop.Text], obj.ID)
+				case prop.Format == "objects" && len(prop.Objects) > 0:
+					links = append(links, link{obj.ID, prop.Key, prop.Objects})
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to search contacts to replace: %w", err)
+	}
+	if n := countReplaced(contacts, existing); n > 0 {
+		if err := confirm(i18n.Sprintf("--replace deletes and re-creates %d contact object(s) in space %s", n, dst.SpaceID)); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	renamed := make(map[string]string) // Old object ID → new object ID
+	for i := range contacts {
+		c := &contacts[i]
+		old := existing[c.UID]
+		if c.UID == "" || len(old) == 0 {
+			rest = append(rest, *c)
+			continue
+		}
+		// Two incoming cards with one UID: the first replaces, the second merges
+		delete(existing, c.UID)
+
+		c.ObjectID = ""
+		if err := dst.Write(ctx, c); err != nil {
+			log.Printf("Error replacing %s: %v", c.DisplayName(), err)
+			failed = append(failed, fmt.Sprintf("replacing %s: %v", c.DisplayName(), err))
+			continue
+		}
+		for _, id := range old {
+			renamed[id] = c.ObjectID
+		}
+		replaced = append(replaced, *c)
+		i18n.Printf("↻ Replaced: %s\n", c.DisplayName())
+	}
+
+	relink(ctx, client, dst.SpaceID, links, renamed)
+	for old := range renamed {
+		if err := client.Space(dst.SpaceID).Object(old).Delete(ctx); err != nil {
+			log.Printf("Warning: could not delete the replaced object %s: %v", old, err)
+		}
+	}
+	return replaced, rest, failed, nil
+}
+
+// countReplaced returns the number of objects replaceByUID deletes: those
+// with the UID of an incoming contact
+func countReplaced(contacts []vcard.Contact, existing map[string][]string) int {
+	n := 0
+	seen := make(map[string]bool)
+	for _, c := range contacts {
+		if c.UID != "" && !seen[c.UID] {
+			seen[c.UID] = true
+			n += len(existing[c.UID])
+		}
+	}
+	return n
+}
+
+// relink points the links to renamed objects at their new objects. Links
+// held by a renamed object are set on its new object.
+func relink(ctx context.Context, client anytype.Client, spaceID string, links []link, renamed map[string]string) {
+	for _, l := range links {
+		if renamed[l.objectID] == "" && !slices.ContainsFunc(l.targets, func(id string) bool { return renamed[id] != "" }) {
+			continue
+		}
+		targets := make([]string, len(l.targets))
+		for i, id := range l.targets {
+			targets[i] = id
+			if to := renamed[id]; to != "" {
+				targets[i] = to
+			}
+		}
+		objectID := l.objectID
+		if to := renamed[objectID]; to != "" {
+			objectID = to
+		}
+		props := []map[string]any{{"key": l.key, "objects": targets}}
+		if err := vcard.UpdateObject(ctx, client, spaceID, objectID, props); err != nil {
+			log.Printf("Warning: could not relink %s of object %s: %v", l.key, objectID, err)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package vcardimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rubiojr/any-vcard/internal/sink"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/rubiojr/anytype-go"
+	"github.com/rubiojr/anytype-go/options"
+)
+
+// fakeClient serves objects to searches and records the writes
+type fakeClient struct {
+	anytype.Client
+	objects   []anytype.Object
+	createErr error
+	created   []anytype.CreateObjectRequest
+	updated   map[string][]map[string]any // Properties of the last update by object ID
+	deleted   []string
+}
+
+func (f *fakeClient) Space(id string) anytype.SpaceClient { return &fakeSpace{client: f} }
+
+type fakeSpace struct {
+	anytype.SpaceClient
+	client *fakeClient
+}
+
+func (f *fakeSpace) Search(ctx context.Context, req anytype.SearchRequest, opts ...options.Option) (*anytype.SearchResponse, error) {
+	return &anytype.SearchResponse{Data: f.client.objects}, nil
+}
+
+func (f *fakeSpace) Types() anytype.TypesClient { return fakeTypes{} }
+
+func (f *fakeSpace) Objects() anytype.ObjectsClient { return &fakeObjects{client: f.client} }
+func (f *fakeSpace) Object(id string) anytype.ObjectClient {
+	return &fakeObject{client: f.client, id: id}
+}
+
+type fakeTypes struct {
+	anytype.TypesClient
+}
+
+func (fakeTypes) List(ctx context.Context) ([]anytype.Type, error) {
+	return []anytype.Type{{Key: "contact", Name: "Contact"}}, nil
+}
+
+type fakeObjects struct {
+	anytype.ObjectsClient
+	client *fakeClient
+}
+
+func (f *fakeObjects) Create(ctx context.Context, req anytype.CreateObjectRequest) (*anytype.ObjectResponse, error) {
+	if f.client.createErr != nil {
+		return nil, f.client.createErr
+	}
+	f.client.created = append(f.client.created, req)
+	id := fmt.Sprintf("new%d", len(f.client.created))
+	return &anytype.ObjectResponse{Object: anytype.Object{ID: id, Name: req.Name}}, nil
+}
+
+type fakeObject struct {
+	anytype.ObjectClient
+	client *fakeClient
+	id     string
+}
+
+func (f *fakeObject) Update(ctx context.Context, req anytype.UpdateObjectRequest) error {
+	f.client.updated[f.id] = req.Properties
+	return nil
+}
+
+func (f *fakeObject) Delete(ctx context.Context) error {
+	f.client.deleted = append(f.client.deleted, f.id)
+	return nil
+}
+
+// replaceSpace has the contact old1 with UID u1, linking to its assistant,
+// and a note linking to old1
+func replaceSpace() *fakeClient {
+	contactType := &anytype.Type{Key: "contact"}
+	return &fakeClient{
+		updated: make(map[string][]map[string]any),
+		objects: []anytype.Object{
+			{ID: "old1", Name: "Jane Doe", Type: contactType, Properties: []anytype.Property{
+				{Key: "uid", Format: "text", Text: "u1"},
+				{Key: "assistant", Format: "objects", Objects: []string{"bob1"}},
+			}},
+			{ID: "bob1", Name: "Bob", Type: contactType},
+			{ID: "note1", Name: "Meeting", Type: &anytype.Type{Key: "page"}, Properties: []anytype.Property{
+				{Key: "attendees", Format: "objects", Objects: []string{"old1", "bob1"}},
+			}},
+		},
+	}
+}
+
+func TestReplaceByUID(t *testing.T) {
+	client := replaceSpace()
+	dst := &sink.Anytype{Client: client, SpaceID: "sp", TypeKey: "contact"}
+	contacts := []vcard.Contact{
+		{FormattedName: "Jane Doe", UID: "u1"},
+		{FormattedName: "John Roe", UID: "u2"},
+	}
+	var summary string
+	confirm := func(s string) error { summary = s; return nil }
+
+	replaced, rest, failed, err := replaceByUID(context.Background(), client, dst, []string{"contact"}, contacts, confirm)
+	if err != nil || len(failed) > 0 {
+		t.Fatalf("replaceByUID() failed = %v, error = %v", failed, err)
+	}
+	if !strings.Contains(summary, "1 contact object") {
+		t.Errorf("confirmed %q, want 1 object replaced", summary)
+	}
+	if len(replaced) != 1 || replaced[0].ObjectID != "new1" {
+		t.Errorf("replaced = %+v, want Jane Doe as new1", replaced)
+	}
+	if len(rest) != 1 || rest[0].UID != "u2" {
+		t.Errorf("rest = %+v, want John Roe", rest)
+	}
+
+	// Links to the old object point at the new one, and those the old
+	// object held are set on the new one
+	want := map[string][]map[string]any{
+		"note1": {{"key": "attendees", "objects": []string{"new1", "bob1"}}},
+		"new1":  {{"key": "assistant", "objects": []string{"bob1"}}},
+	}
+	if !reflect.DeepEqual(client.updated, want) {
+		t.Errorf("updated = %v, want %v", client.updated, want)
+	}
+	if !reflect.DeepEqual(client.deleted, []string{"old1"}) {
+		t.Errorf("deleted = %v, want old1", client.deleted)
+	}
+}
+
+func TestReplaceByUID_CreateFails(t *testing.T) {
+	client := replaceSpace()
+	client.createErr = errors.New("server unavailable")
+	dst := &sink.Anytype{Client: client, SpaceID: "sp", TypeKey: "contact"}
+	contacts := []vcard.Contact{{FormattedName: "Jane Doe", UID: "u1"}}
+
+	replaced, rest, failed, err := replaceByUID(context.Background(), client, dst, []string{"contact"}, contacts, func(string) error { return nil })
+	if err != nil {
+		t.Fatalf("replaceByUID() error = %v", err)
+	}
+	if len(failed) != 1 || len(replaced) != 0 || len(rest) != 0 {
+		t.Errorf("replaced = %d, rest = %d, failed = %v; want one failure", len(replaced), len(rest), failed)
+	}
+	if len(client.deleted) > 0 || len(client.updated) > 0 {
+		t.Errorf("deleted %v and updated %v after a failed create", client.deleted, client.updated)
+	}
+}
+
+func TestReplaceByUID_Declined(t *testing.T) {
+	client := replaceSpace()
+	dst := &sink.Anytype{Client: client, SpaceID: "sp", TypeKey: "contact"}
+	contacts := []vcard.Contact{{FormattedName: "Jane Doe", UID: "u1"}}
+	declined := errors.New("declined")
+
+	_, _, _, err := replaceByUID(context.Background(), client, dst, []string{"contact"}, contacts, func(string) error { return declined })
+	if !errors.Is(err, declined) {
+		t.Errorf("replaceByUID() error = %v, want the confirmation's", err)
+	}
+	if len(client.created) > 0 || len(client.deleted) > 0 {
+		t.Errorf("created %d and deleted %v after declining", len(client.created), client.deleted)
+	}
+}
+
+func TestCountReplaced(t *testing.T) {
+	existing := map[string][]string{"u1": {"a", "b"}, "u2": {"c"}}
+	tests := []struct {
+		name     string
+		contacts []vcard.Contact
+		want     int
+	}{
+		{"none", nil, 0},
+		{"every object of a UID", []vcard.Contact{{UID: "u1"}}, 2},
+		{"a UID counted once", []vcard.Contact{{UID: "u1"}, {UID: "u1"}, {UID: "u2"}}, 3},
+		{"without UID or object", []vcard.Contact{{}, {UID: "u3"}}, 0},
+	}
+	for _, tt := range tests {
+		if got := countReplaced(tt.contacts, existing); got != tt.want {
+			t.Errorf("%s: countReplaced() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package vcardimport
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/rubiojr/anytype-go"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/term"
+)
+
+// reviewSamples is how many new contacts the review lists per space
+const reviewSamples = 10
+
+// reviewImport shows what the import would do in every space, without
+// writing anything, and asks whether to go ahead
+func reviewImport(ctx context.Context, cmd *cli.Command, spaceIDs []string, contacts []vcard.Contact) (bool, error) {
+	// Contacts read from stdin leave no terminal to answer on
+	interactive := term.IsTerminal(int(os.Stdin.Fd())) && !slices.Contains(cmd.Args().Slice(), "-")
+	return reviewImportFrom(ctx, cmd, util.NewClient(cmd), bufio.NewReader(os.Stdin), interactive, spaceIDs, contacts)
+}
+
+// reviewImportFrom is reviewImport reading the answers from in. Without
+// interactive input it fails unless --yes is given, which imports after
+// printing the review.
+func reviewImportFrom(ctx context.Context, cmd *cli.Command, client anytype.Client, in *bufio.Reader, interactive bool, spaceIDs []string, contacts []vcard.Contact) (bool, error) {
+	if !interactive && !cmd.Bool("yes") {
+		return false, fmt.Errorf("--review needs a terminal to ask for confirmation; pass --yes to import after the review")
+	}
+
+	var page strings.Builder
+	fmt.Fprintf(&page, "\nReview: %d contact(s) parsed\n", len(contacts))
+	dropped := make(map[string]int)
+	for _, c := range contacts {
+		for _, field := range vcard.DroppedFields(c, util.PhoneSlots) {
+			dropped[field]++
+		}
+	}
+	if len(dropped) > 0 {
+		fmt.Fprintf(&page, "\nFields that will be dropped:\n")
+		for _, field := range slices.Sorted(maps.Keys(dropped)) {
+			fmt.Fprintf(&page, "  %s: %d contact(s)\n", field, dropped[field])
+		}
+	}
+
+	for _, spaceID := range spaceIDs {
+		if err := reviewSpace(ctx, cmd, client, spaceID, slices.Clone(contacts), &page); err != nil {
+			return false, err
+		}
+	}
+
+	if !interactive {
+		fmt.Fprintln(i18n.Output(), page.String())
+		return true, nil
+	}
+	if err := pageOut(in, i18n.Output(), page.String()); err != nil {
+		return false, err
+	}
+	summary := i18n.Sprintf("Importing writes %d contact(s) to %d space(s)", len(contacts), len(spaceIDs))
+	err := util.ConfirmFrom(cmd, in, true, summary)
+	if errors.Is(err, util.ErrNotConfirmed) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// reviewSpace runs the duplicate analysis of the import into one space
+// against a throwaway index and describes the outcome
+func reviewSpace(ctx context.Context, cmd *cli.Command, client anytype.Client, spaceID string, contacts []vcard.Contact, w io.Writer) error {
+	skip := cmd.Bool("skip-duplicates")
+	merge := cmd.Bool("merge-duplicates") && !skip
+
+	idx, closeIndex, err := util.NewDedupIndex(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeIndex()
+	if typeKey, err := util.FindContactType(ctx, client, spaceID); err == nil && (skip || merge) {
+		if cmd.Bool("targeted-lookup") {
+			fetchMatchingContacts(ctx, client, spaceID, []string{typeKey}, contacts, idx)
+		} else {
+			fetchExistingContacts(ctx, client, spaceID, []string{typeKey}, idx)
+		}
+	}
+
+	var created []*vcard.Contact
+	var duplicates []string
+	for i := range contacts {
+		c := &contacts[i]
+		matches := idx.FindMatches(c)
+		if i := slices.IndexFunc(matches, vcard.Joins); i >= 0 && (skip || merge) {
+			duplicates = append(duplicates, fmt.Sprintf("%s: %s", c.DisplayName(), matches[i].Explain()))
+			continue
+		}
+		idx.Add(c)
+		created = append(created, c)
+	}
+
+	fmt.Fprintf(w, "\n=== Space %s ===\n", spaceID)
+	action := "merged into"
+	if skip {
+		action = "skipped as"
+	}
+	fmt.Fprintf(w, "%d new contact(s), %d %s existing or earlier contacts\n", len(created), len(duplicates), action)
+	if len(created) > 0 {
+		fmt.Fprintf(w, "\nNew contacts:\n")
+		for _, c := range created[:min(len(created), reviewSamples)] {
+			fmt.Fprintf(w, "  + %s", c.DisplayName())
+			if details := slices.Concat(c.Emails[:min(len(c.Emails), 1)], c.Phones[:min(len(c.Phones), 1)]); len(details) > 0 {
+				fmt.Fprintf(w, " (%s)", strings.Join(details, ", "))
+			}
+			fmt.Fprintln(w)
+		}
+		if len(created) > reviewSamples {
+			fmt.Fprintf(w, "  ... and %d more\n", len(created)-reviewSamples)
+		}
+	}
+	if len(duplicates) > 0 {
+		fmt.Fprintf(w, "\nDuplicates:\n")
+		for _, d := range duplicates {
+			fmt.Fprintf(w, "  = %s\n", d)
+		}
+	}
+	return nil
+}
+
+// pageOut writes text a screenful at a time, waiting for Enter between
+// pages; q skips the rest
+func pageOut(in *bufio.Reader, out io.Writer, text string) error {
+	height := 24
+	if f, ok := out.(*os.File); ok {
+		if _, h, err := term.GetSize(int(f.Fd())); err == nil && h > 2 {
+			height = h
+		}
+	}
+	lines := strings.SplitAfter(strings.TrimSuffix(text, "\n"), "\n")
+	for len(lines) > 0 {
+		n := min(len(lines), height-1)
+		fmt.Fprint(out, strings.Join(lines[:n], ""))
+		lines = lines[n:]
+		if len(lines) == 0 {
+			break
+		}
+		answer, err := prompt(in, out, "-- more (Enter), q to skip --")
+		if err != nil {
+			return err
+		}
+		if strings.EqualFold(answer, "q") {
+			break
+		}
+	}
+	fmt.Fprintln(out)
+	return nil
+}
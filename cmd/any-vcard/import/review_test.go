@@ -0,0 +1,72 @@
+package vcardimport
+
+import (
+	"bufio"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/urfave/cli/v3"
+)
+
+// runReview reviews importing contacts into replaceSpace with the given
+// flags, answering from input
+func runReview(t *testing.T, client *fakeClient, input string, interactive bool, args ...string) (bool, error) {
+	t.Helper()
+	contacts := []vcard.Contact{
+		{FormattedName: "Jane Doe", UID: "u1"},
+		{FormattedName: "John Roe", Emails: []string{"john@example.com"}},
+	}
+	var ok bool
+	cmd := &cli.Command{
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "yes"},
+			&cli.BoolFlag{Name: "dry-run"},
+			&cli.BoolFlag{Name: "skip-duplicates"},
+			&cli.BoolFlag{Name: "merge-duplicates"},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			var err error
+			ok, err = reviewImportFrom(ctx, cmd, client, bufio.NewReader(strings.NewReader(input)), interactive, []string{"sp"}, contacts)
+			return err
+		},
+	}
+	err := cmd.Run(context.Background(), append([]string{"import", "--merge-duplicates"}, args...))
+	return ok, err
+}
+
+func TestReviewImport_Declined(t *testing.T) {
+	client := replaceSpace()
+	ok, err := runReview(t, client, "n\n", true)
+	if err != nil || ok {
+		t.Fatalf("reviewImport() = %v, %v; want declined without error", ok, err)
+	}
+	if len(client.created) > 0 || len(client.updated) > 0 || len(client.deleted) > 0 {
+		t.Errorf("created %d, updated %v and deleted %v during the review", len(client.created), client.updated, client.deleted)
+	}
+}
+
+func TestReviewImport_Confirmed(t *testing.T) {
+	client := replaceSpace()
+	if ok, err := runReview(t, client, "y\n", true); err != nil || !ok {
+		t.Fatalf("reviewImport() = %v, %v; want confirmed", ok, err)
+	}
+	if len(client.created) > 0 || len(client.updated) > 0 || len(client.deleted) > 0 {
+		t.Errorf("created %d, updated %v and deleted %v during the review", len(client.created), client.updated, client.deleted)
+	}
+}
+
+func TestReviewImport_NonInteractive(t *testing.T) {
+	ok, err := runReview(t, replaceSpace(), "y\n", false)
+	if err == nil || ok {
+		t.Errorf("reviewImport() = %v, %v; want a refusal without --yes", ok, err)
+	}
+	if err != nil && !strings.Contains(err.Error(), "--yes") {
+		t.Errorf("error %q does not mention --yes", err)
+	}
+
+	if ok, err := runReview(t, replaceSpace(), "", false, "--yes"); err != nil || !ok {
+		t.Errorf("reviewImport(--yes) = %v, %v; want it to go ahead", ok, err)
+	}
+}
@@ -0,0 +1,60 @@
+package vcardimport
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/urfave/cli/v3"
+)
+
+// spaceFlags select the spaces to import into. The local --space shadows
+// the global one so it can be repeated.
+var spaceFlags = []cli.Flag{
+	&cli.StringSliceFlag{
+		Name:    "space",
+		Aliases: []string{"s"},
+		Usage:   "Space ID to import contacts into (repeatable)",
+		Sources: cli.EnvVars("ANYTYPE_SPACE_ID"),
+	},
+	&cli.BoolFlag{
+		Name:  "all-spaces",
+		Usage: "Import into every space (see --space-filter)",
+	},
+	&cli.StringFlag{
+		Name:  "space-filter",
+		Usage: "With --all-spaces, only spaces whose name contains this text (case-insensitive)",
+	},
+}
+
+// targetSpaces returns the IDs of the spaces to import into
+func targetSpaces(ctx context.Context, cmd *cli.Command) ([]string, error) {
+	if !cmd.Bool("all-spaces") {
+		spaceIDs := cmd.StringSlice("space")
+		if len(spaceIDs) == 0 && cmd.Root().String("space") != "" {
+			// Global --space given before the command name
+			spaceIDs = []string{cmd.Root().String("space")}
+		}
+		if len(spaceIDs) == 0 {
+			return nil, fmt.Errorf("required flags %q not set", "space")
+		}
+		return spaceIDs, nil
+	}
+
+	resp, err := util.NewClient(cmd).Spaces().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spaces: %w", err)
+	}
+	filter := strings.ToLower(cmd.String("space-filter"))
+	var spaceIDs []string
+	for _, s := range resp.Data {
+		if strings.Contains(strings.ToLower(s.Name), filter) {
+			spaceIDs = append(spaceIDs, s.ID)
+		}
+	}
+	if len(spaceIDs) == 0 {
+		return nil, fmt.Errorf("no spaces match %q", cmd.String("space-filter"))
+	}
+	return spaceIDs, nil
+}
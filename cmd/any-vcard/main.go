@@ -6,12 +6,24 @@ import (
 	"os"
 
 	"github.com/rubiojr/any-vcard/cmd/any-vcard/auth"
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/backup"
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/birthdays"
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/browse"
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/convert"
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/copy"
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/dedupe"
 	"github.com/rubiojr/any-vcard/cmd/any-vcard/diff"
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/doctor"
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/export"
 	vcardimport "github.com/rubiojr/any-vcard/cmd/any-vcard/import"
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/open"
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/restore"
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/serve"
 	"github.com/rubiojr/any-vcard/cmd/any-vcard/space"
 	"github.com/rubiojr/any-vcard/cmd/any-vcard/template"
 	"github.com/rubiojr/any-vcard/cmd/any-vcard/types"
 	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/vcf"
 	"github.com/rubiojr/any-vcard/cmd/any-vcard/version"
 	"github.com/urfave/cli/v3"
 )
@@ -22,18 +34,33 @@ func main() {
 		Usage:   "Import vCard files into Anytype",
 		Version: util.Version,
 		Flags:   util.GlobalFlags(),
+		Before:  util.Configure,
+		After:   util.Finish,
 		Commands: []*cli.Command{
 			auth.Command,
+			backup.Command,
+			birthdays.Command,
+			browse.Command,
+			convert.Command,
+			copy.Command,
+			dedupe.Command,
 			diff.Command,
+			doctor.Command,
+			export.Command,
 			vcardimport.Command,
+			open.Command,
+			restore.Command,
+			serve.Command,
 			space.Command,
 			template.Command,
 			types.Command,
+			vcf.Command,
 			version.Command,
 		},
 	}
 
 	if err := cmd.Run(context.Background(), os.Args); err != nil {
-		log.Fatal(err)
+		// Errors of the API client can surface anywhere in a run
+		log.Fatal(util.ExplainError(err, cmd.String("url")))
 	}
 }
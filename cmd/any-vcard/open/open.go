@@ -0,0 +1,53 @@
+package open
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/urfave/cli/v3"
+)
+
+var Command = &cli.Command{
+	Name:      "open",
+	Usage:     "Open a contact in the Anytype app",
+	ArgsUsage: "<object-id|name>",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "print",
+			Usage: "Print the anytype:// link instead of opening it",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if err := util.RequireFlags(cmd, "app-key", "space"); err != nil {
+			return err
+		}
+		if err := util.Preflight(ctx, cmd, cmd.String("space")); err != nil {
+			return err
+		}
+		if cmd.Args().Len() == 0 {
+			return fmt.Errorf("a contact name or object ID is required")
+		}
+
+		svc, err := util.NewContactService(ctx, cmd)
+		if err != nil {
+			return err
+		}
+		contact, err := svc.Resolve(ctx, strings.Join(cmd.Args().Slice(), " "))
+		if err != nil {
+			return err
+		}
+
+		link := util.ObjectURL(cmd.String("space"), contact.ObjectID)
+		if cmd.Bool("print") {
+			fmt.Println(link)
+			return nil
+		}
+		if err := util.OpenURL(link); err != nil {
+			return fmt.Errorf("failed to open Anytype: %w", err)
+		}
+		fmt.Printf("✓ Opened %s\n", contact.DisplayName())
+		return nil
+	},
+}
@@ -0,0 +1,133 @@
+package restore
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/backup"
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/sink"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/rubiojr/anytype-go"
+	"github.com/urfave/cli/v3"
+)
+
+var Command = &cli.Command{
+	Name:      "restore",
+	Usage:     "Re-create contacts from a backup archive, skipping those that still exist",
+	ArgsUsage: "<backup.zip>",
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if err := util.RequireFlags(cmd, "app-key", "space"); err != nil {
+			return err
+		}
+		if err := util.Preflight(ctx, cmd, cmd.String("space")); err != nil {
+			return err
+		}
+		if cmd.Args().Len() != 1 {
+			return fmt.Errorf("backup archive is required")
+		}
+		return restoreContacts(ctx, cmd)
+	},
+}
+
+func restoreContacts(ctx context.Context, cmd *cli.Command) error {
+	client := util.NewClient(cmd)
+	spaceID := cmd.String("space")
+
+	archive, err := backup.Open(cmd.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	archived, err := archive.Contacts()
+	if err != nil {
+		return err
+	}
+	i18n.Printf("Backup of space %s taken %s: %d contact(s)\n",
+		archive.Manifest.SpaceID, archive.Manifest.CreatedAt.Local().Format("2006-01-02 15:04"), len(archived))
+
+	typeKey, err := util.EnsureContactType(ctx, client, spaceID, true)
+	if err != nil {
+		return err
+	}
+
+	existing, err := vcard.FetchContacts(ctx, client, spaceID, typeKey)
+	if err != nil {
+		return fmt.Errorf("failed to fetch existing contacts: %w", err)
+	}
+	missing := backup.Missing(archived, existing)
+	skipped := len(archived) - len(missing)
+
+	if cmd.Bool("dry-run") {
+		for _, c := range missing {
+			fmt.Printf("+ %s\n", c.DisplayName())
+		}
+		i18n.Printf("\nWould restore %d contact(s) (%d still exist)\n", len(missing), skipped)
+		warnPhotos(countPhotos(missing))
+		return nil
+	}
+
+	phoneKeys, emailKeys, err := util.EnsureContactProperties(ctx, client, spaceID)
+	if err != nil {
+		return fmt.Errorf("failed to ensure properties: %w", err)
+	}
+	if err := util.EnsureProperties(ctx, client, spaceID, append([]anytype.PropertyDefinition{util.UIDProperty, util.BirthdayTextProperty, util.DepartmentProperty, util.RoleProperty, util.FavoriteProperty}, util.RelationProperties...)); err != nil {
+		return fmt.Errorf("failed to ensure restore properties: %w", err)
+	}
+
+	dst := &sink.Anytype{
+		Client:    client,
+		SpaceID:   spaceID,
+		TypeKey:   typeKey,
+		PhoneKeys: phoneKeys,
+		EmailKeys: emailKeys,
+		UIDKey:    util.UIDProperty.Key,
+		Refresh:   util.RefreshSchema,
+	}
+
+	var restored, photos int
+	defer util.InvalidateMirror(spaceID)
+	for i := range missing {
+		c := &missing[i]
+		if err := dst.Write(ctx, c); err != nil {
+			log.Printf("Error restoring %s: %v", c.DisplayName(), err)
+			continue
+		}
+		restored++
+		i18n.Printf("✓ Restored: %s\n", c.DisplayName())
+		if c.Photo != "" {
+			photos++
+			i18n.Printf("  ⚠ photo not restored\n")
+		}
+	}
+
+	i18n.Printf("\n✓ Restored %d/%d contact(s)", restored, len(missing))
+	if skipped > 0 {
+		i18n.Printf(" (skipped %d that still exist)", skipped)
+	}
+	fmt.Printf("\n")
+	warnPhotos(photos)
+	return nil
+}
+
+// countPhotos returns the number of contacts with a photo
+func countPhotos(contacts []vcard.Contact) int {
+	n := 0
+	for _, c := range contacts {
+		if c.Photo != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// warnPhotos reports the photos a restore leaves out: objects are written
+// without them, as the API has no file upload
+func warnPhotos(n int) {
+	if n > 0 {
+		i18n.Printf("⚠ %d photo(s) were not restored: Anytype's API can't upload files, they stay in the backup\n", n)
+	}
+}
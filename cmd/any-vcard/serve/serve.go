@@ -0,0 +1,117 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/httpserver"
+	"github.com/rubiojr/any-vcard/internal/mcpserver"
+	"github.com/urfave/cli/v3"
+)
+
+var Command = &cli.Command{
+	Name:  "serve",
+	Usage: "Serve the space contacts to other programs",
+	Commands: []*cli.Command{
+		httpCommand,
+		mcpCommand,
+	},
+}
+
+var mcpCommand = &cli.Command{
+	Name:  "mcp",
+	Usage: "Run a Model Context Protocol server on stdio with contact tools for AI assistants",
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if err := util.RequireFlags(cmd, "app-key", "space"); err != nil {
+			return err
+		}
+		if err := util.Preflight(ctx, cmd, cmd.String("space")); err != nil {
+			return err
+		}
+		svc, err := util.NewContactService(ctx, cmd)
+		if err != nil {
+			return err
+		}
+		return mcpserver.ServeStdio(mcpserver.New(svc, util.AppName, util.Version))
+	},
+}
+
+var httpCommand = &cli.Command{
+	Name:  "http",
+	Usage: "Run a JSON REST API to search, create, import and dedupe contacts",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "listen",
+			Usage: "Address to listen on; addresses other than loopback need a --token",
+			Value: "127.0.0.1:8080",
+		},
+		&cli.StringFlag{
+			Name:    "token",
+			Usage:   "Require \"Authorization: Bearer TOKEN\" on every request but /health",
+			Sources: cli.EnvVars("ANYVCARD_SERVER_TOKEN"),
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if err := util.RequireFlags(cmd, "app-key", "space"); err != nil {
+			return err
+		}
+		listen := cmd.String("listen")
+		if cmd.String("token") == "" && !isLoopback(listen) {
+			return fmt.Errorf("refusing to serve on %s without a --token: anyone who can reach it could change your contacts", listen)
+		}
+		if err := util.Preflight(ctx, cmd, cmd.String("space")); err != nil {
+			return err
+		}
+		svc, err := util.NewContactService(ctx, cmd)
+		if err != nil {
+			return err
+		}
+		if cmd.String("token") == "" {
+			log.Printf("Warning: no --token set, every program on this machine can change your contacts")
+		}
+
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+		defer stop()
+
+		srv := &http.Server{
+			Addr:              listen,
+			Handler:           httpserver.New(svc, cmd.String("token")),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			srv.Shutdown(shutdownCtx)
+		}()
+
+		fmt.Printf("✓ Serving contacts on %s\n", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("server failed: %w", err)
+		}
+		return nil
+	},
+}
+
+// isLoopback reports whether addr, a host:port, only listens on the
+// loopback interface. An empty host listens on every interface.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return false
+	}
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
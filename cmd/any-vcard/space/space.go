@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/rubiojr/anytype-go"
 	"github.com/rubiojr/any-vcard/cmd/any-vcard/util"
+	"github.com/rubiojr/any-vcard/internal/vcard"
 	"github.com/urfave/cli/v3"
 )
 
@@ -17,6 +19,7 @@ var Command = &cli.Command{
 	Commands: []*cli.Command{
 		listCommand,
 		createCommand,
+		showCommand,
 	},
 }
 
@@ -27,6 +30,9 @@ var listCommand = &cli.Command{
 		if err := util.RequireFlags(cmd, "app-key"); err != nil {
 			return err
 		}
+		if err := util.Preflight(ctx, cmd); err != nil {
+			return err
+		}
 		return listSpaces(ctx, cmd)
 	},
 }
@@ -42,10 +48,32 @@ var createCommand = &cli.Command{
 		if cmd.Args().Len() == 0 {
 			return fmt.Errorf("space name is required")
 		}
+		if err := util.Preflight(ctx, cmd); err != nil {
+			return err
+		}
 		return createSpace(ctx, cmd)
 	},
 }
 
+var showCommand = &cli.Command{
+	Name:      "show",
+	Usage:     "Show space details and contact statistics",
+	ArgsUsage: "[space-id]",
+	Flags:     util.MirrorFlags,
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if err := util.RequireFlags(cmd, "app-key"); err != nil {
+			return err
+		}
+		if cmd.Args().Len() == 0 && cmd.String("space") == "" {
+			return fmt.Errorf("space ID is required")
+		}
+		if err := util.Preflight(ctx, cmd, cmd.Args().First()); err != nil {
+			return err
+		}
+		return showSpace(ctx, cmd)
+	},
+}
+
 func listSpaces(ctx context.Context, cmd *cli.Command) error {
 	client := util.NewClientWithAppKey(cmd.String("url"), cmd.String("app-key"))
 
@@ -65,7 +93,7 @@ func listSpaces(ctx context.Context, cmd *cli.Command) error {
 }
 
 func createSpace(ctx context.Context, cmd *cli.Command) error {
-	client := util.NewClientWithAppKey(cmd.String("url"), cmd.String("app-key"))
+	client := util.DryRunClient(util.NewClientWithAppKey(cmd.String("url"), cmd.String("app-key")))
 	spaceName := cmd.Args().Get(0)
 
 	fmt.Printf("Creating space %q...\n", spaceName)
@@ -83,3 +111,115 @@ func createSpace(ctx context.Context, cmd *cli.Command) error {
 
 	return nil
 }
+
+func showSpace(ctx context.Context, cmd *cli.Command) error {
+	client := util.NewClientWithAppKey(cmd.String("url"), cmd.String("app-key"))
+	spaceID := cmd.Args().Get(0)
+	if spaceID == "" {
+		spaceID = cmd.String("space")
+	}
+
+	resp, err := client.Space(spaceID).Get(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get space: %w", err)
+	}
+	fmt.Printf("Name: %s\n", resp.Space.Name)
+	fmt.Printf("ID: %s\n", resp.Space.ID)
+	if resp.Space.Description != "" {
+		fmt.Printf("Description: %s\n", resp.Space.Description)
+	}
+
+	types, err := client.Space(spaceID).Types().List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list types: %w", err)
+	}
+	var contactType *anytype.Type
+	for i, t := range types {
+		if strings.EqualFold(t.Key, util.ContactTypeKey) || strings.EqualFold(t.Name, "contact") {
+			contactType = &types[i]
+			break
+		}
+	}
+	if contactType == nil {
+		fmt.Printf("\nNo Contact type in this space (create it with: any-vcard types create)\n")
+		return nil
+	}
+
+	objects, err := util.ContactObjects(ctx, cmd, client, spaceID, "")
+	if err != nil {
+		return err
+	}
+	contacts := make([]*vcard.Contact, len(objects))
+	for i, obj := range objects {
+		contacts[i] = vcard.FromObject(obj)
+	}
+	var withEmail, withPhone, withBirthday, totalScore int
+	var scores [5]int // Contacts per 20 points of completeness
+	missing := make(map[string]int)
+	var latest *vcard.Contact
+	for _, c := range contacts {
+		score := c.CompletenessScore()
+		totalScore += score
+		scores[min(score/20, len(scores)-1)]++
+		for _, criterion := range c.MissingForCompleteness() {
+			missing[criterion]++
+		}
+		if len(c.Emails) > 0 {
+			withEmail++
+		}
+		if len(c.Phones) > 0 {
+			withPhone++
+		}
+		if c.Birthday != "" || c.BirthdayText != "" {
+			withBirthday++
+		}
+		if c.LastModified != "" && (latest == nil || c.LastModified > latest.LastModified) {
+			latest = c
+		}
+	}
+
+	fmt.Printf("\nContacts: %d (type key: %s)\n", len(contacts), contactType.Key)
+	fmt.Printf("  With email: %d\n", withEmail)
+	fmt.Printf("  With phone: %d\n", withPhone)
+	fmt.Printf("  With birthday: %d\n", withBirthday)
+	if latest != nil {
+		fmt.Printf("  Last change: %s (%s)\n", latest.LastModified, latest.DisplayName())
+	}
+	if len(contacts) > 0 {
+		fmt.Printf("\nCompleteness: %d average\n", totalScore/len(contacts))
+		for i, n := range scores {
+			high := i*20 + 19
+			if i == len(scores)-1 {
+				high = 100
+			}
+			fmt.Printf("  %3d-%-3d %5d %s\n", i*20, high, n, strings.Repeat("█", n*40/len(contacts)))
+		}
+		fmt.Printf("  Missing:")
+		for _, criterion := range vcard.CompletenessCriteria() {
+			fmt.Printf(" %s %d", criterion, missing[criterion])
+		}
+		fmt.Printf("\n")
+	}
+
+	fmt.Printf("\nProperties:\n")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, prop := range contactType.PropertyDefinitions {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", prop.Key, prop.Format, prop.Name)
+	}
+	w.Flush()
+
+	templates, err := client.Space(spaceID).Type(contactType.ID).Templates().List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list templates: %w", err)
+	}
+	fmt.Printf("\nTemplates: %d\n", len(templates))
+	for _, tmpl := range templates {
+		status := ""
+		if tmpl.Archived {
+			status = " (archived)"
+		}
+		fmt.Printf("  - %s (id: %s)%s\n", tmpl.Name, tmpl.ID, status)
+	}
+
+	return nil
+}
@@ -30,6 +30,9 @@ var listCommand = &cli.Command{
 		if err := util.RequireFlags(cmd, "app-key", "space"); err != nil {
 			return err
 		}
+		if err := util.Preflight(ctx, cmd, cmd.String("space")); err != nil {
+			return err
+		}
 		return listTemplates(ctx, cmd)
 	},
 }
@@ -40,7 +43,7 @@ func listTemplates(ctx context.Context, cmd *cli.Command) error {
 	verbose := cmd.Bool("verbose")
 
 	// List all types
-	typesResp, err := client.Space(spaceID).Types().List(ctx)
+	typesResp, err := util.ListTypes(ctx, client, spaceID)
 	if err != nil {
 		return fmt.Errorf("failed to list types: %w", err)
 	}
@@ -25,6 +25,9 @@ var listCommand = &cli.Command{
 		if err := util.RequireFlags(cmd, "app-key", "space"); err != nil {
 			return err
 		}
+		if err := util.Preflight(ctx, cmd, cmd.String("space")); err != nil {
+			return err
+		}
 		return listTypes(ctx, cmd)
 	},
 }
@@ -36,6 +39,9 @@ var createCommand = &cli.Command{
 		if err := util.RequireFlags(cmd, "app-key", "space"); err != nil {
 			return err
 		}
+		if err := util.Preflight(ctx, cmd, cmd.String("space")); err != nil {
+			return err
+		}
 		return createContactType(ctx, cmd)
 	},
 }
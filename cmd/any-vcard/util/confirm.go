@@ -0,0 +1,59 @@
+package util
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/term"
+)
+
+// ErrNotConfirmed is returned when the user declines a change
+var ErrNotConfirmed = errors.New("cancelled, nothing was changed")
+
+// yesAnswers accept a confirmation, in every supported language
+var yesAnswers = []string{"y", "yes", "s", "si", "sí"}
+
+// Confirm asks before a change that deletes or overwrites objects, showing
+// summary, what the change affects. It returns nil to go ahead: when
+// confirmed, with --yes or with --dry-run, as nothing is changed then.
+// Without a terminal to ask on it fails unless --yes is given.
+func Confirm(cmd *cli.Command, summary string) error {
+	return ConfirmFrom(cmd, os.Stdin, term.IsTerminal(int(os.Stdin.Fd())), summary)
+}
+
+// ConfirmFrom is Confirm reading the answer from in, which interactive
+// says a user can answer on
+func ConfirmFrom(cmd *cli.Command, in io.Reader, interactive bool, summary string) error {
+	if cmd.Bool("yes") || cmd.Bool("dry-run") {
+		return nil
+	}
+	if !interactive {
+		return fmt.Errorf("%s; pass --yes to go ahead without confirming", summary)
+	}
+	return confirm(in, i18n.Output(), summary)
+}
+
+func confirm(in io.Reader, out io.Writer, summary string) error {
+	fmt.Fprintf(out, "\n⚠ %s\n%s", summary, i18n.T("Continue? [y/N] "))
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return fmt.Errorf("failed to read answer: %w", err)
+	}
+	if !slices.Contains(yesAnswers, strings.ToLower(strings.TrimSpace(line))) {
+		return ErrNotConfirmed
+	}
+	return nil
+}
+
+// IsTerminal reports whether w writes to a terminal
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,209 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/rubiojr/any-vcard/internal/mirror"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/rubiojr/anytype-go"
+	"github.com/urfave/cli/v3"
+)
+
+// MirrorFlags enable the local contact mirror of the read-only commands
+var MirrorFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name:    "mirror",
+		Usage:   "Read contacts from a local SQLite mirror of the space, refreshed incrementally, which also works while Anytype is unreachable",
+		Sources: cli.EnvVars("ANYVCARD_MIRROR"),
+	},
+	&cli.DurationFlag{
+		Name:    "mirror-ttl",
+		Value:   mirror.DefaultTTL,
+		Usage:   "How long to trust the mirror before refreshing it",
+		Sources: cli.EnvVars("ANYVCARD_MIRROR_TTL"),
+	},
+	&cli.BoolFlag{
+		Name:  "refresh",
+		Usage: "Resync the mirror before reading it",
+	},
+}
+
+// offline is set by ReadPreflight when the API is unreachable and the
+// mirror stands in for it, so ContactObjects doesn't try to refresh it
+var offline bool
+
+// ReadPreflight is Preflight for commands reading contacts through
+// ContactObjects: with --mirror, an unreachable API is only a warning when
+// the space was mirrored before, as the mirror can stand in for it
+func ReadPreflight(ctx context.Context, cmd *cli.Command, spaceID string) error {
+	err := Preflight(ctx, cmd, spaceID)
+	if err == nil || !cmd.Bool("mirror") || !IsUnreachable(err) {
+		return err
+	}
+	m, merr := openMirror()
+	if merr != nil {
+		return err
+	}
+	defer m.Close()
+	if _, at, ok, _ := m.Synced(spaceID); ok {
+		log.Printf("Warning: %v\nReading the local mirror from %s", err, at.Format(time.DateTime))
+		offline = true
+		return nil
+	}
+	return err
+}
+
+// ContactObjects returns the contact objects of the space, only those
+// matching a full-text query when not empty. With --mirror they are read
+// from the local mirror, which is refreshed first when older than
+// --mirror-ttl or with --refresh, unless the API is unreachable.
+func ContactObjects(ctx context.Context, cmd *cli.Command, client anytype.Client, spaceID, query string) ([]anytype.Object, error) {
+	if !cmd.Bool("mirror") {
+		typeKey, err := FindContactType(ctx, client, spaceID)
+		if err != nil {
+			return nil, err
+		}
+		return SearchAll(ctx, client, spaceID, anytype.SearchRequest{Query: query, Types: []string{typeKey}})
+	}
+
+	m, _, err := syncedMirror(ctx, cmd, client, spaceID, cmd.Bool("refresh"))
+	if err != nil {
+		return nil, err
+	}
+	defer m.Close()
+	return m.Objects(spaceID, query)
+}
+
+// ContactChanges returns the contact objects of the space the mirror saw
+// created or updated after since, only those matching a full-text query
+// when not empty, and the IDs of those it saw deleted. since is a date, an
+// RFC3339 time or "last" for the snapshot of the last export recorded with
+// MarkExported. The mirror is refreshed first unless the API is
+// unreachable; snapshot is the time of the state returned.
+func ContactChanges(ctx context.Context, cmd *cli.Command, client anytype.Client, spaceID, since, query string) (objects []anytype.Object, deleted []string, snapshot time.Time, err error) {
+	m, snapshot, err := syncedMirror(ctx, cmd, client, spaceID, true)
+	if err != nil {
+		return nil, nil, snapshot, err
+	}
+	defer m.Close()
+
+	var from time.Time
+	if since == "last" {
+		// Everything when nothing was exported yet
+		if from, _, err = m.LastExport(spaceID); err != nil {
+			return nil, nil, snapshot, err
+		}
+	} else if from, err = vcard.ParseFilterTime(since); err != nil {
+		return nil, nil, snapshot, err
+	}
+
+	changes, err := m.Changes(spaceID, from)
+	if err != nil {
+		return nil, nil, snapshot, err
+	}
+	changed := make(map[string]bool)
+	for _, c := range changes {
+		if c.Op == mirror.Deleted {
+			deleted = append(deleted, c.ObjectID)
+		} else {
+			changed[c.ObjectID] = true
+		}
+	}
+	all, err := m.Objects(spaceID, query)
+	if err != nil {
+		return nil, nil, snapshot, err
+	}
+	for _, obj := range all {
+		if changed[obj.ID] {
+			objects = append(objects, obj)
+		}
+	}
+	return objects, deleted, snapshot, nil
+}
+
+// MarkExported records the mirror snapshot of the space as exported, the
+// starting point of the next export of changes since "last"
+func MarkExported(spaceID string, snapshot time.Time) error {
+	m, err := openMirror()
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	return m.MarkExported(spaceID, snapshot)
+}
+
+// syncedMirror opens the mirror, refreshing the space first when it was
+// never mirrored, is older than --mirror-ttl or force is set, unless the
+// API is unreachable and an older copy exists. It returns the time of the
+// mirrored state.
+func syncedMirror(ctx context.Context, cmd *cli.Command, client anytype.Client, spaceID string, force bool) (*mirror.Mirror, time.Time, error) {
+	m, err := openMirror()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	_, at, ok, err := m.Synced(spaceID)
+	if err != nil {
+		m.Close()
+		return nil, time.Time{}, err
+	}
+	if offline || (ok && !force && time.Since(at) <= cmd.Duration("mirror-ttl")) {
+		return m, at, nil
+	}
+	now := time.Now()
+	if err := refreshMirror(ctx, m, client, spaceID, now); err != nil {
+		if !ok || !IsUnreachable(err) {
+			m.Close()
+			return nil, time.Time{}, err
+		}
+		log.Printf("Warning: could not refresh the mirror, reading it as of %s: %v", at.Format(time.DateTime), err)
+		return m, at, nil
+	}
+	return m, now, nil
+}
+
+// InvalidateMirror marks the mirror of a space stale after writing to it,
+// so the next read refreshes it. Nothing happens when there is no mirror.
+func InvalidateMirror(spaceID string) {
+	path, err := mirror.DefaultPath()
+	if err != nil {
+		return
+	}
+	if _, err := os.Stat(path); err != nil {
+		return
+	}
+	m, err := mirror.Open(path)
+	if err == nil {
+		err = m.Invalidate(spaceID)
+		m.Close()
+	}
+	if err != nil && !errors.Is(err, mirror.ErrUnavailable) {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+func openMirror() (*mirror.Mirror, error) {
+	path, err := mirror.DefaultPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate mirror: %w", err)
+	}
+	return mirror.Open(path)
+}
+
+// refreshMirror fetches the contact objects of the space into the mirror
+func refreshMirror(ctx context.Context, m *mirror.Mirror, client anytype.Client, spaceID string, now time.Time) error {
+	typeKey, err := FindContactType(ctx, client, spaceID)
+	if err != nil {
+		return err
+	}
+	objects, err := SearchAll(ctx, client, spaceID, anytype.SearchRequest{Types: []string{typeKey}})
+	if err != nil {
+		return fmt.Errorf("failed to fetch contacts: %w", err)
+	}
+	_, err = m.Refresh(spaceID, typeKey, objects, now)
+	return err
+}
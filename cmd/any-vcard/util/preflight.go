@@ -0,0 +1,102 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rubiojr/anytype-go"
+	"github.com/urfave/cli/v3"
+)
+
+// preflightTimeout bounds the connectivity check of Preflight
+const preflightTimeout = 5 * time.Second
+
+// Preflight checks that the Anytype API answers, the app key is accepted
+// and the given spaces exist before a command starts working, so failures
+// are reported up front and in plain words rather than as raw client
+// errors halfway through a run. Empty space IDs are ignored.
+func Preflight(ctx context.Context, cmd *cli.Command, spaceIDs ...string) error {
+	baseURL := cmd.String("url")
+	reqCtx, cancel := context.WithTimeout(ctx, preflightTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, baseURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid API URL %q: %w", baseURL, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return ExplainError(err, baseURL)
+	}
+	resp.Body.Close()
+
+	spaces, err := NewClient(cmd).Spaces().List(ctx)
+	if err != nil {
+		return ExplainError(err, baseURL)
+	}
+	for _, id := range spaceIDs {
+		if id == "" {
+			continue
+		}
+		if !slices.ContainsFunc(spaces.Data, func(s anytype.Space) bool { return s.ID == id }) {
+			return fmt.Errorf("space %s not found; `%s space list` shows the spaces the app key can see", id, AppName)
+		}
+	}
+	return nil
+}
+
+// explainedError is an error ExplainError already reworded
+type explainedError struct{ error }
+
+func (e explainedError) Unwrap() error { return e.error }
+
+// ExplainError rewords the common ways talking to the Anytype API at
+// baseURL fails with what to do about them. Other errors, and errors it
+// already reworded, are returned unchanged.
+func ExplainError(err error, baseURL string) error {
+	if err == nil || errors.As(err, new(explainedError)) {
+		return err
+	}
+	if explained := explain(err, baseURL); explained != err {
+		return explainedError{explained}
+	}
+	return err
+}
+
+func explain(err error, baseURL string) error {
+	msg := strings.ToLower(err.Error())
+	var dnsErr *net.DNSError
+	switch {
+	case refused(err):
+		return fmt.Errorf("cannot connect to the Anytype API at %s. Is the Anytype desktop app running with the local API enabled? (%w)", baseURL, err)
+	case errors.As(err, &dnsErr):
+		return fmt.Errorf("cannot resolve the host of the Anytype API URL %s; check --url (%w)", baseURL, err)
+	case timedOut(err):
+		return fmt.Errorf("the Anytype API at %s did not answer in time. Is the desktop app busy syncing or stuck? (%w)", baseURL, err)
+	case strings.Contains(msg, "401") || strings.Contains(msg, "unauthorized") || strings.Contains(msg, "403") || strings.Contains(msg, "forbidden"):
+		return fmt.Errorf("the Anytype API rejected the app key; run `%s auth` for a new one and export ANYTYPE_APP_KEY (%w)", AppName, err)
+	}
+	return err
+}
+
+// IsUnreachable reports whether err means the Anytype API is down or not
+// answering, as opposed to rejecting the request, so retrying later may
+// succeed
+func IsUnreachable(err error) bool {
+	return err != nil && (refused(err) || timedOut(err))
+}
+
+func refused(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED) || strings.Contains(strings.ToLower(err.Error()), "connection refused")
+}
+
+func timedOut(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
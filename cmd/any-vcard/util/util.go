@@ -2,11 +2,31 @@ package util
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/rubiojr/any-vcard/internal/config"
+	"github.com/rubiojr/any-vcard/internal/dryrun"
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/notify"
+	"github.com/rubiojr/any-vcard/internal/profile"
+	"github.com/rubiojr/any-vcard/internal/progress"
+	"github.com/rubiojr/any-vcard/internal/schemacache"
+	"github.com/rubiojr/any-vcard/internal/service"
+	"github.com/rubiojr/any-vcard/internal/sink"
+	"github.com/rubiojr/any-vcard/internal/vcard"
 	"github.com/rubiojr/anytype-go"
 	_ "github.com/rubiojr/anytype-go/client"
 	"github.com/urfave/cli/v3"
@@ -14,6 +34,7 @@ import (
 
 const (
 	ContactTypeKey = "contact"
+	CompanyTypeKey = "company"
 	AppName        = "any-vcard"
 	Version        = "0.2.1"
 )
@@ -32,12 +53,26 @@ func RequireFlags(cmd *cli.Command, flags ...string) error {
 	return nil
 }
 
-// NewClient creates a new Anytype client from CLI flags
+// NewClient creates a new Anytype client from CLI flags, and loads the
+// schema cache the first time
 func NewClient(cmd *cli.Command) anytype.Client {
-	return anytype.NewClient(
+	schemaOnce.Do(func() { schema = loadSchemaCache(cmd.String("url"), cmd.Duration("schema-cache-ttl")) })
+	return DryRunClient(anytype.NewClient(
 		anytype.WithBaseURL(cmd.String("url")),
 		anytype.WithAppKey(cmd.String("app-key")),
-	)
+	))
+}
+
+// dryRun is set by --dry-run
+var dryRun bool
+
+// DryRunClient returns client printing its writes instead of making them
+// with --dry-run, client itself otherwise
+func DryRunClient(client anytype.Client) anytype.Client {
+	if !dryRun {
+		return client
+	}
+	return dryrun.Wrap(client, i18n.Output())
 }
 
 // NewClientWithURL creates a new Anytype client with just a URL (for auth)
@@ -55,89 +90,148 @@ func NewClientWithAppKey(baseURL, appKey string) anytype.Client {
 	)
 }
 
-// EnsureContactProperties creates required properties if they don't exist
-// Returns phoneKeys and emailKeys for all available phone/email properties
-func EnsureContactProperties(ctx context.Context, client anytype.Client, spaceID string) ([]string, []string, error) {
-	existingProps, err := client.Space(spaceID).Properties().List(ctx)
-	if err != nil {
-		log.Printf("Warning: could not list properties: %v", err)
-		existingProps = []anytype.Property{}
+// schema caches the types and properties of spaces between runs. Nil
+// until NewClient loads it, which disables caching.
+var (
+	schema     *schemacache.Cache
+	schemaOnce sync.Once
+)
+
+func loadSchemaCache(server string, ttl time.Duration) *schemacache.Cache {
+	if ttl <= 0 {
+		return nil
 	}
+	path, err := schemacache.DefaultPath()
+	if err == nil {
+		var cache *schemacache.Cache
+		if cache, err = schemacache.Load(path, server, ttl); err == nil {
+			return cache
+		}
+	}
+	log.Printf("Warning: schema cache disabled: %v", err)
+	return nil
+}
 
-	var existingPhoneKeys []string
-	var existingEmailKeys []string
-	existingPhoneByName := make(map[string]string)
-	existingEmailByName := make(map[string]string)
+// ListTypes returns the object types of the space, from the schema cache
+// when fresh. Only requests the API is sent are counted as API calls.
+func ListTypes(ctx context.Context, client anytype.Client, spaceID string) ([]anytype.Type, error) {
+	if schema == nil {
+		defer profile.Start(profile.API)()
+		return client.Space(spaceID).Types().List(ctx)
+	}
+	return schema.Types(ctx, client, spaceID)
+}
 
-	for _, prop := range existingProps {
-		if prop.Format == "phone" {
-			existingPhoneKeys = append(existingPhoneKeys, prop.Key)
-			existingPhoneByName[prop.Name] = prop.Key
-		} else if prop.Format == "email" {
-			existingEmailKeys = append(existingEmailKeys, prop.Key)
-			existingEmailByName[prop.Name] = prop.Key
-		}
+// ListProperties returns the properties of the space, from the schema
+// cache when fresh, counting API calls like ListTypes
+func ListProperties(ctx context.Context, client anytype.Client, spaceID string) ([]anytype.Property, error) {
+	if schema == nil {
+		defer profile.Start(profile.API)()
+		return client.Space(spaceID).Properties().List(ctx)
 	}
+	return schema.Properties(ctx, client, spaceID)
+}
 
-	phoneProps := []struct {
-		Name string
-		Key  string
-	}{
-		{"Phone", "phone"},
-		{"Phone 2", "phone2"},
-		{"Phone 3", "phone3"},
+// invalidateSchema drops the cached schema of a space after creating types
+// or properties in it
+func invalidateSchema(spaceID string) {
+	if schema != nil {
+		schema.Invalidate(spaceID)
 	}
+}
+
+// ensured lists the properties EnsureProperties was asked for, by space,
+// for RefreshSchema to ensure them again
+var ensured = make(map[string][]anytype.PropertyDefinition)
 
-	emailProps := []struct {
-		Name string
-		Key  string
-	}{
-		{"Email", "email"},
-		{"Email 2", "email2"},
-		{"Email 3", "email3"},
+// RefreshSchema is the sink.Anytype Refresh of the commands: a property
+// key the API rejects may come from a cached schema that is out of date,
+// so the cache of the space is dropped and the properties this run
+// ensured are ensured again, creating those that are gone
+func RefreshSchema(ctx context.Context, s *sink.Anytype) error {
+	if schema == nil {
+		return errors.New("schema not cached") // Nothing to refresh
 	}
+	invalidateSchema(s.SpaceID)
+	phoneKeys, emailKeys, err := EnsureContactProperties(ctx, s.Client, s.SpaceID)
+	if err != nil {
+		return err
+	}
+	s.PhoneKeys, s.EmailKeys = phoneKeys, emailKeys
+	return EnsureProperties(ctx, s.Client, s.SpaceID, ensured[s.SpaceID])
+}
 
-	var phoneKeys []string
-	var emailKeys []string
-	var createdKeys []string
+// PhoneSlots is the number of phone properties EnsureContactProperties
+// provides; further phones are not imported
+const PhoneSlots = 3
 
-	for _, phoneProp := range phoneProps {
-		if existingKey, exists := existingPhoneByName[phoneProp.Name]; exists {
-			phoneKeys = append(phoneKeys, existingKey)
-		} else {
-			resp, err := client.Space(spaceID).Properties().Create(ctx, anytype.CreatePropertyRequest{
-				Key:    phoneProp.Key,
-				Name:   phoneProp.Name,
-				Format: "phone",
-			})
-			if err != nil {
-				log.Printf("Warning: could not create property %s: %v", phoneProp.Name, err)
-				continue
+// phoneProperties and emailProperties are the phone and email slots of
+// contacts, in order. They are found by name, as the keys of properties
+// created in the Anytype app are generated.
+var (
+	phoneProperties = []anytype.PropertyDefinition{
+		{Key: "phone", Name: "Phone", Format: "phone"},
+		{Key: "phone2", Name: "Phone 2", Format: "phone"},
+		{Key: "phone3", Name: "Phone 3", Format: "phone"},
+	}
+	emailProperties = []anytype.PropertyDefinition{
+		{Key: "email", Name: "Email", Format: "email"},
+		{Key: "email2", Name: "Email 2", Format: "email"},
+		{Key: "email3", Name: "Email 3", Format: "email"},
+	}
+)
+
+// slotKeys returns the key of the property of props with the name and
+// format of each slot, "" for the slots the space has no property for
+func slotKeys(props []anytype.Property, slots []anytype.PropertyDefinition) []string {
+	keys := make([]string, len(slots))
+	for i, slot := range slots {
+		for _, p := range props {
+			if p.Name == slot.Name && p.Format == slot.Format {
+				keys[i] = p.Key
+				break
 			}
-			phoneKeys = append(phoneKeys, resp.Property.Key)
-			createdKeys = append(createdKeys, resp.Property.Key)
-			fmt.Printf("  Created property: %s (key: %s)\n", phoneProp.Name, resp.Property.Key)
 		}
 	}
+	return keys
+}
+
+// EnsureContactProperties creates required properties if they don't exist
+// Returns phoneKeys and emailKeys for all available phone/email properties
+func EnsureContactProperties(ctx context.Context, client anytype.Client, spaceID string) ([]string, []string, error) {
+	existingProps, err := ListProperties(ctx, client, spaceID)
+	if err != nil {
+		log.Printf("Warning: could not list properties: %v", err)
+		existingProps = []anytype.Property{}
+	}
 
-	for _, emailProp := range emailProps {
-		if existingKey, exists := existingEmailByName[emailProp.Name]; exists {
-			emailKeys = append(emailKeys, existingKey)
-		} else {
+	var createdKeys []string
+	ensure := func(slots []anytype.PropertyDefinition) []string {
+		var keys []string
+		for i, key := range slotKeys(existingProps, slots) {
+			if key != "" {
+				keys = append(keys, key)
+				continue
+			}
+			stop := profile.Start(profile.API)
 			resp, err := client.Space(spaceID).Properties().Create(ctx, anytype.CreatePropertyRequest{
-				Key:    emailProp.Key,
-				Name:   emailProp.Name,
-				Format: "email",
+				Key:    slots[i].Key,
+				Name:   slots[i].Name,
+				Format: slots[i].Format,
 			})
+			stop()
 			if err != nil {
-				log.Printf("Warning: could not create property %s: %v", emailProp.Name, err)
+				log.Printf("Warning: could not create property %s: %v", slots[i].Name, err)
 				continue
 			}
-			emailKeys = append(emailKeys, resp.Property.Key)
+			keys = append(keys, resp.Property.Key)
 			createdKeys = append(createdKeys, resp.Property.Key)
-			fmt.Printf("  Created property: %s (key: %s)\n", emailProp.Name, resp.Property.Key)
+			i18n.Printf("  Created property: %s (key: %s)\n", slots[i].Name, resp.Property.Key)
 		}
+		return keys
 	}
+	phoneKeys := ensure(phoneProperties)
+	emailKeys := ensure(emailProperties)
 
 	if len(createdKeys) > 0 {
 		allKeys := append(phoneKeys, emailKeys...)
@@ -156,9 +250,136 @@ func EnsureContactProperties(ctx context.Context, client anytype.Client, spaceID
 	return phoneKeys, emailKeys, nil
 }
 
-// WaitForProperties polls the server until all specified property keys are available
+// ContactTypeProperties are the properties of the Contact type created by
+// CreateContactType
+var ContactTypeProperties = []anytype.PropertyDefinition{
+	{Key: "name", Name: "Name", Format: "text"},
+	{Key: "given_name", Name: "Given Name", Format: "text"},
+	{Key: "family_name", Name: "Family Name", Format: "text"},
+	{Key: "middle_name", Name: "Middle Name", Format: "text"},
+	{Key: "prefix", Name: "Prefix", Format: "text"},
+	{Key: "suffix", Name: "Suffix", Format: "text"},
+	{Key: "email", Name: "Email", Format: "email"},
+	{Key: "phone", Name: "Phone", Format: "phone"},
+	{Key: "address", Name: "Address", Format: "text"},
+	{Key: "city", Name: "City", Format: "text"},
+	{Key: "region", Name: "Region", Format: "text"},
+	{Key: "postal_code", Name: "Postal Code", Format: "text"},
+	{Key: "country", Name: "Country", Format: "text"},
+	{Key: "organization", Name: "Organization", Format: "text"},
+	DepartmentProperty,
+	{Key: "title", Name: "Title", Format: "text"},
+	RoleProperty,
+	{Key: "url", Name: "URL", Format: "url"},
+	{Key: "birthday", Name: "Birthday", Format: "date"},
+	{Key: "notes", Name: "Notes", Format: "text"},
+	FavoriteProperty,
+}
+
+// GeoProperties are the properties written for geocoded addresses
+var GeoProperties = []anytype.PropertyDefinition{
+	{Key: "latitude", Name: "Latitude", Format: "number"},
+	{Key: "longitude", Name: "Longitude", Format: "number"},
+	{Key: "map_url", Name: "Map", Format: "url"},
+}
+
+// BirthdayTextProperty stores year-less birthdays that are not written as dates
+var BirthdayTextProperty = anytype.PropertyDefinition{Key: "birthday_text", Name: "Birthday (no year)", Format: "text"}
+
+// UIDProperty stores the vCard UID so contacts can be matched across spaces and restores
+var UIDProperty = anytype.PropertyDefinition{Key: "uid", Name: "UID", Format: "text"}
+
+// SnapshotProperty stores the vCard a contact was last imported from, the
+// base of import --three-way
+var SnapshotProperty = anytype.PropertyDefinition{Key: "vcard_snapshot", Name: "Imported vCard", Format: "text"}
+
+// HistoryProperty stores the revision history merges record with --history
+var HistoryProperty = anytype.PropertyDefinition{Key: "history", Name: "History", Format: "text"}
+
+// DepartmentProperty stores the departments of the contact's organization
+var DepartmentProperty = anytype.PropertyDefinition{Key: "department", Name: "Department", Format: "text"}
+
+// RoleProperty stores the vCard ROLE, the contact's function as opposed to
+// their job title
+var RoleProperty = anytype.PropertyDefinition{Key: "role", Name: "Role", Format: "text"}
+
+// FavoriteProperty flags starred contacts
+var FavoriteProperty = anytype.PropertyDefinition{Key: "favorite", Name: "Favorite", Format: "checkbox"}
+
+// CompletenessProperty stores the 0–100 completeness score of import
+// --completeness, see vcard.Contact.CompletenessScore
+var CompletenessProperty = anytype.PropertyDefinition{Key: "completeness", Name: "Completeness", Format: "number"}
+
+// RelationProperties store the names of a contact's assistant and manager
+var RelationProperties = []anytype.PropertyDefinition{
+	{Key: "assistant", Name: "Assistant", Format: "text"},
+	{Key: "manager", Name: "Manager", Format: "text"},
+}
+
+// RelationLinkProperties link a contact to the objects of its assistant
+// and manager
+var RelationLinkProperties = []anytype.PropertyDefinition{
+	{Key: "assistant_contact", Name: "Assistant Contact", Format: "objects"},
+	{Key: "manager_contact", Name: "Manager Contact", Format: "objects"},
+}
+
+// BirthdayFieldProperties are the computed properties derived from the birthday
+var BirthdayFieldProperties = []anytype.PropertyDefinition{
+	{Key: "age", Name: "Age", Format: "number"},
+	{Key: "next_birthday", Name: "Next Birthday", Format: "date"},
+}
+
+// EnsureProperties creates the given properties if no property with the same key exists
+func EnsureProperties(ctx context.Context, client anytype.Client, spaceID string, defs []anytype.PropertyDefinition) error {
+	for _, def := range defs {
+		if !slices.ContainsFunc(ensured[spaceID], func(d anytype.PropertyDefinition) bool { return d.Key == def.Key }) {
+			ensured[spaceID] = append(ensured[spaceID], def)
+		}
+	}
+	existingProps, err := ListProperties(ctx, client, spaceID)
+	if err != nil {
+		return fmt.Errorf("could not list properties: %w", err)
+	}
+
+	existing := make(map[string]struct{}, len(existingProps))
+	for _, prop := range existingProps {
+		existing[prop.Key] = struct{}{}
+	}
+
+	var createdKeys []string
+	for _, def := range defs {
+		if _, ok := existing[def.Key]; ok {
+			continue
+		}
+		stop := profile.Start(profile.API)
+		resp, err := client.Space(spaceID).Properties().Create(ctx, anytype.CreatePropertyRequest{
+			Key:    def.Key,
+			Name:   def.Name,
+			Format: def.Format,
+		})
+		stop()
+		if err != nil {
+			return fmt.Errorf("could not create property %s: %w", def.Name, err)
+		}
+		createdKeys = append(createdKeys, resp.Property.Key)
+		i18n.Printf("  Created property: %s (key: %s)\n", def.Name, resp.Property.Key)
+	}
+
+	if len(createdKeys) > 0 {
+		return WaitForProperties(ctx, client, spaceID, createdKeys)
+	}
+	return nil
+}
+
+// WaitForProperties polls the server until all specified property keys are
+// available. The cached schema of the space is dropped, since it lacks them.
 func WaitForProperties(ctx context.Context, client anytype.Client, spaceID string, keys []string) error {
-	fmt.Printf("  Waiting for properties to be available...\n")
+	if dryRun {
+		return nil // Nothing was created
+	}
+	defer profile.Start(profile.Wait)()
+	invalidateSchema(spaceID)
+	i18n.Printf("  Waiting for properties to be available...\n")
 	for i := 0; i < 20; i++ {
 		props, err := client.Space(spaceID).Properties().List(ctx)
 		if err != nil {
@@ -181,7 +402,7 @@ func WaitForProperties(ctx context.Context, client anytype.Client, spaceID strin
 		}
 		if allFound {
 			time.Sleep(2 * time.Second)
-			fmt.Printf("  Properties ready.\n")
+			i18n.Printf("  Properties ready.\n")
 			return nil
 		}
 		time.Sleep(500 * time.Millisecond)
@@ -189,29 +410,585 @@ func WaitForProperties(ctx context.Context, client anytype.Client, spaceID strin
 	return fmt.Errorf("timeout waiting for properties to be available")
 }
 
-// CreateContactType creates the Contact object type in a space
-func CreateContactType(ctx context.Context, client anytype.Client, spaceID string) (*anytype.TypeResponse, error) {
-	properties := []anytype.PropertyDefinition{
-		{Key: "name", Name: "Name", Format: "text"},
-		{Key: "given_name", Name: "Given Name", Format: "text"},
-		{Key: "family_name", Name: "Family Name", Format: "text"},
-		{Key: "middle_name", Name: "Middle Name", Format: "text"},
-		{Key: "prefix", Name: "Prefix", Format: "text"},
-		{Key: "suffix", Name: "Suffix", Format: "text"},
-		{Key: "email", Name: "Email", Format: "email"},
-		{Key: "phone", Name: "Phone", Format: "phone"},
-		{Key: "address", Name: "Address", Format: "text"},
-		{Key: "city", Name: "City", Format: "text"},
-		{Key: "region", Name: "Region", Format: "text"},
-		{Key: "postal_code", Name: "Postal Code", Format: "text"},
-		{Key: "country", Name: "Country", Format: "text"},
-		{Key: "organization", Name: "Organization", Format: "text"},
-		{Key: "title", Name: "Title", Format: "text"},
-		{Key: "url", Name: "URL", Format: "url"},
-		{Key: "birthday", Name: "Birthday", Format: "date"},
-		{Key: "notes", Name: "Notes", Format: "text"},
+// FindContactType returns the key of the Contact type in the space
+func FindContactType(ctx context.Context, client anytype.Client, spaceID string) (string, error) {
+	types, err := ListTypes(ctx, client, spaceID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list types: %w", err)
+	}
+	for _, t := range types {
+		if strings.EqualFold(t.Key, ContactTypeKey) || strings.EqualFold(t.Name, "contact") {
+			return t.Key, nil
+		}
+	}
+	return "", fmt.Errorf("contact type not found in space")
+}
+
+// EnsureContactType returns the key of the Contact type, creating it when
+// missing and create is set
+func EnsureContactType(ctx context.Context, client anytype.Client, spaceID string, create bool) (string, error) {
+	types, err := ListTypes(ctx, client, spaceID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list types: %w", err)
+	}
+
+	for _, t := range types {
+		if strings.EqualFold(t.Key, ContactTypeKey) || strings.EqualFold(t.Name, "contact") {
+			i18n.Printf("✓ Found existing Contact type with key: %s\n", t.Key)
+			return t.Key, nil
+		}
+	}
+
+	if !create {
+		return "", fmt.Errorf("Contact type not found and --create-type=false")
+	}
+
+	i18n.Printf("Creating Contact object type...\n")
+	typeResp, err := CreateContactType(ctx, client, spaceID)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Contact type: %w", err)
+	}
+	i18n.Printf("✓ Created Contact type with key: %s\n", typeResp.Type.Key)
+	return typeResp.Type.Key, nil
+}
+
+// EnsureCompanyType returns the key of the Company type used for company
+// cards, creating it when missing
+func EnsureCompanyType(ctx context.Context, client anytype.Client, spaceID string) (string, error) {
+	types, err := ListTypes(ctx, client, spaceID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list types: %w", err)
+	}
+	for _, t := range types {
+		if strings.EqualFold(t.Key, CompanyTypeKey) || strings.EqualFold(t.Name, "company") {
+			i18n.Printf("✓ Found existing Company type with key: %s\n", t.Key)
+			return t.Key, nil
+		}
+	}
+
+	i18n.Printf("Creating Company object type...\n")
+	defer invalidateSchema(spaceID)
+	stop := profile.Start(profile.API)
+	typeResp, err := client.Space(spaceID).Types().Create(ctx, anytype.CreateTypeRequest{
+		Key:        CompanyTypeKey,
+		Name:       "Company",
+		Layout:     "basic",
+		PluralName: "Companies",
+		Icon: &anytype.Icon{
+			Format: anytype.IconFormatEmoji,
+			Emoji:  "🏢",
+		},
+		Properties: []anytype.PropertyDefinition{
+			{Key: "name", Name: "Name", Format: "text"},
+			{Key: "email", Name: "Email", Format: "email"},
+			{Key: "phone", Name: "Phone", Format: "phone"},
+			{Key: "url", Name: "URL", Format: "url"},
+			{Key: "address", Name: "Address", Format: "text"},
+			{Key: "city", Name: "City", Format: "text"},
+			{Key: "region", Name: "Region", Format: "text"},
+			{Key: "postal_code", Name: "Postal Code", Format: "text"},
+			{Key: "country", Name: "Country", Format: "text"},
+			{Key: "notes", Name: "Notes", Format: "text"},
+			FavoriteProperty,
+		},
+	})
+	stop()
+	if err != nil {
+		return "", fmt.Errorf("failed to create Company type: %w", err)
+	}
+	i18n.Printf("✓ Created Company type with key: %s\n", typeResp.Type.Key)
+	return typeResp.Type.Key, nil
+}
+
+// ImportSummary counts the outcomes of an import
+type ImportSummary struct {
+	Contacts   int
+	Imported   int
+	Merged     int
+	Replaced   int // Recreated by import --replace
+	Blocked    int // Dropped by the blocklist
+	Skipped    int
+	Failed     int
+	CrossSpace int // Imported contacts that duplicate one in another space
+	Conflicts  int // Values discarded by merges
+	Weak       int // Imported contacts that only match an existing one weakly
+	Errors     []string
+
+	FailedSources []string // Source of each contact that failed, see vcard.Contact.Source
+
+	// Throughput, recorded by TrackThroughput
+	Duration      time.Duration
+	APICalls      int64
+	BytesUploaded int64
+}
+
+// ContactsPerSecond returns the rate contacts were processed at
+func (s ImportSummary) ContactsPerSecond() float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+	return float64(s.Contacts) / s.Duration.Seconds()
+}
+
+// TrackThroughput starts measuring a run. The returned function records
+// the time taken, API calls made and bytes uploaded since in summary, and
+// prints them.
+func TrackThroughput() func(summary *ImportSummary) {
+	started := time.Now()
+	calls, uploaded := profile.APICalls(), profile.BytesUploaded()
+	return func(summary *ImportSummary) {
+		summary.Duration = time.Since(started)
+		summary.APICalls = profile.APICalls() - calls
+		summary.BytesUploaded = profile.BytesUploaded() - uploaded
+		i18n.Printf("  %.1f contacts/s, %d API calls, %s uploaded in %s\n",
+			summary.ContactsPerSecond(), summary.APICalls, FormatBytes(summary.BytesUploaded), summary.Duration.Round(time.Millisecond))
+	}
+}
+
+// FormatBytes formats a byte count with a binary unit: 512 B, 1.5 KiB
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// ParseBytes parses a byte count with an optional unit, binary (KiB, MiB,
+// GiB) or its short form (K, M, G, KB, MB, GB, all powers of 1024): 512MiB,
+// 1G, 4096
+func ParseBytes(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	num := strings.TrimRightFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+	unit := strings.ToUpper(strings.TrimSpace(s[len(num):]))
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	if unit == "" || unit == "B" {
+		return n, nil
 	}
+	prefix, _ := strings.CutSuffix(unit, "B")
+	prefix, _ = strings.CutSuffix(prefix, "I")
+	shift := strings.Index("KMGT", prefix) + 1
+	if len(prefix) != 1 || shift == 0 {
+		return 0, fmt.Errorf("invalid size %q (expected e.g. 512MiB or 1G)", s)
+	}
+	if n > math.MaxInt64>>(10*shift) {
+		return 0, fmt.Errorf("invalid size %q (too large)", s)
+	}
+	return n << (10 * shift), nil
+}
 
+// SpaceIndex is the dedup index of another space. Its duplicates are
+// reported but never merged.
+type SpaceIndex struct {
+	SpaceID string
+	Index   *vcard.DedupIndex
+}
+
+// LoadSpaceIndexes builds the dedup index of each space, matching contacts
+// as opts say
+func LoadSpaceIndexes(ctx context.Context, client anytype.Client, spaceIDs []string, opts vcard.DedupOptions, phonetic bool) ([]SpaceIndex, error) {
+	var indexes []SpaceIndex
+	for _, spaceID := range spaceIDs {
+		typeKey, err := FindContactType(ctx, client, spaceID)
+		if err != nil {
+			return nil, fmt.Errorf("space %s: %w", spaceID, err)
+		}
+		contacts, err := vcard.FetchContacts(ctx, client, spaceID, typeKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch contacts of space %s: %w", spaceID, err)
+		}
+		idx := vcard.NewDedupIndexWith(vcard.NewMemoryIndexStore(), opts, contacts)
+		if phonetic {
+			idx.EnablePhonetic()
+		}
+		i18n.Printf("✓ Found %d contacts in space %s\n", len(contacts), spaceID)
+		indexes = append(indexes, SpaceIndex{SpaceID: spaceID, Index: idx})
+	}
+	return indexes, nil
+}
+
+// ImportContacts writes contacts to dst, merging those found in the dedup
+// index with merge, or skipping them when merge is nil, and prints a
+// summary. Like dedupe, only matches that vcard.Joins are duplicates:
+// contacts that only match weakly are imported and reported, as are new
+// contacts that duplicate one in the other spaces.
+// It stops at the first error of the index, as later lookups could miss
+// duplicates and create them again.
+func ImportContacts(ctx context.Context, dst sink.Sink, contacts []vcard.Contact, dedupIndex *vcard.DedupIndex, merge *vcard.MergeOptions, others []SpaceIndex) (ImportSummary, error) {
+	i18n.Printf("\nImporting %d contact(s)...\n", len(contacts))
+
+	var successCount, skippedCount, mergedCount, failedCount, crossSpaceCount, conflictCount, weakCount int
+	var errs, failedSources []string
+	var indexErr error
+	for i := range contacts {
+		contact := &contacts[i]
+
+		stop := profile.Start(profile.Dedup)
+		matches := dedupIndex.FindMatches(contact)
+		stop()
+		if err := dedupIndex.Err(); err != nil {
+			indexErr = fmt.Errorf("failed to look up duplicates of %s: %w", contact.DisplayName(), err)
+			break
+		}
+		// Only matches that would join a dedupe cluster are duplicates,
+		// others are reported for review
+		best := slices.IndexFunc(matches, vcard.Joins)
+		if best < 0 && len(matches) > 0 {
+			i18n.Printf("  ≈ %s may duplicate %s, not merged: %s\n", contact.DisplayName(), matches[0].Contact.DisplayName(), matches[0].Explain())
+			weakCount++
+		}
+		if best >= 0 {
+			match := matches[best]
+			if merge != nil {
+				// Merge into the best match, reporting the values it discards
+				existing := match.Contact
+				var conflicts []vcard.Conflict
+				opts := *merge
+				opts.OnConflict = func(c vcard.Conflict) { conflicts = append(conflicts, c) }
+				merged := vcard.MergeContactsWith(existing, contact, opts)
+				if merged && contact.Completeness != nil {
+					existing.ComputeCompleteness()
+				}
+				for _, c := range conflicts {
+					i18n.Printf("  ⚠ Conflict merging %s: %s\n", contact.DisplayName(), c)
+				}
+				conflictCount += len(conflicts)
+				if merged {
+					// Update the existing contact in Anytype
+					if err := dst.Write(ctx, existing); err != nil {
+						log.Printf("Error merging contact %d (%s): %v", i+1, contact.DisplayName(), err)
+						progress.Emit(progress.Event{Event: progress.Failed, Source: contact.Source, Contact: contact.DisplayName(), ObjectID: existing.ObjectID, Error: err.Error()})
+						errs = append(errs, fmt.Sprintf("merging %s: %v", contact.DisplayName(), err))
+						failedSources = append(failedSources, contact.Source)
+						failedCount++
+						continue
+					}
+					dedupIndex.Update(existing)
+					mergedCount++
+					i18n.Printf("⊕ Merged: %s → %s (%s)\n", contact.DisplayName(), existing.DisplayName(), match.Explain())
+					emitDeduped(contact, existing, "merged", match)
+					if err := dedupIndex.Err(); err != nil {
+						indexErr = fmt.Errorf("failed to index %s: %w", existing.DisplayName(), err)
+						break
+					}
+				} else {
+					log.Printf("Skipping %s (nothing new to merge): %s", contact.DisplayName(), match.Explain())
+					skippedCount++
+					emitDeduped(contact, existing, "skipped", match)
+				}
+			} else {
+				log.Printf("Skipping duplicate contact %d (%s): %s", i+1, contact.DisplayName(), match.Explain())
+				skippedCount++
+				emitDeduped(contact, match.Contact, "skipped", match)
+			}
+			continue
+		}
+
+		if err := dst.Write(ctx, contact); err != nil {
+			log.Printf("Error importing contact %d (%s): %v", i+1, contact.DisplayName(), err)
+			progress.Emit(progress.Event{Event: progress.Failed, Source: contact.Source, Contact: contact.DisplayName(), Error: err.Error()})
+			errs = append(errs, fmt.Sprintf("importing %s: %v", contact.DisplayName(), err))
+			failedSources = append(failedSources, contact.Source)
+			failedCount++
+			continue
+		}
+
+		// Add to index to catch duplicates within the import batch
+		stop = profile.Start(profile.Dedup)
+		dedupIndex.Add(contact)
+		stop()
+
+		successCount++
+		i18n.Printf("✓ Imported: %s\n", contact.DisplayName())
+		progress.Emit(progress.Event{Event: progress.Created, Source: contact.Source, Contact: contact.DisplayName(), ObjectID: contact.ObjectID})
+		if err := dedupIndex.Err(); err != nil {
+			indexErr = fmt.Errorf("failed to index %s: %w", contact.DisplayName(), err)
+			break
+		}
+		for _, other := range others {
+			if matches := other.Index.FindMatches(contact); len(matches) > 0 {
+				i18n.Printf("  ≈ also in space %s: %s\n", other.SpaceID, matches[0].Explain())
+				crossSpaceCount++
+				break
+			}
+		}
+	}
+
+	i18n.Printf("\n✓ Successfully imported %d/%d contacts", successCount, len(contacts))
+	if mergedCount > 0 {
+		i18n.Printf(" (merged %d)", mergedCount)
+	}
+	if skippedCount > 0 {
+		i18n.Printf(" (skipped %d duplicates)", skippedCount)
+	}
+	if crossSpaceCount > 0 {
+		i18n.Printf(" (%d also in other spaces)", crossSpaceCount)
+	}
+	if conflictCount > 0 {
+		i18n.Printf(" (%d conflicting values discarded)", conflictCount)
+	}
+	if weakCount > 0 {
+		i18n.Printf(" (%d possible duplicates to review)", weakCount)
+	}
+	fmt.Fprintln(i18n.Output())
+	return ImportSummary{
+		Contacts:   len(contacts),
+		Imported:   successCount,
+		Merged:     mergedCount,
+		Skipped:    skippedCount,
+		Failed:     failedCount,
+		CrossSpace: crossSpaceCount,
+		Conflicts:  conflictCount,
+		Weak:       weakCount,
+		Errors:     errs,
+
+		FailedSources: failedSources,
+	}, indexErr
+}
+
+// emitDeduped reports contact, a duplicate of existing, as merged or
+// skipped
+func emitDeduped(contact, existing *vcard.Contact, action string, match vcard.Match) {
+	progress.Emit(progress.Event{
+		Event:    progress.Deduped,
+		Source:   contact.Source,
+		Contact:  contact.DisplayName(),
+		ObjectID: existing.ObjectID,
+		Action:   action,
+		Match:    match.Explain(),
+	})
+}
+
+// EmitDone reports the end of a run into spaceID to the --progress-json
+// stream. runErr is the error the run ended with, if any.
+func EmitDone(spaceID string, summary ImportSummary, runErr error) {
+	e := progress.Event{
+		Event:   progress.Done,
+		SpaceID: spaceID,
+		Totals: &progress.Totals{
+			Contacts: summary.Contacts,
+			Imported: summary.Imported,
+			Merged:   summary.Merged,
+			Replaced: summary.Replaced,
+			Skipped:  summary.Skipped,
+			Failed:   summary.Failed,
+		},
+	}
+	if runErr != nil {
+		e.Error = runErr.Error()
+	}
+	progress.Emit(e)
+}
+
+// PhoneticFlag makes duplicate detection match names that sound alike
+var PhoneticFlag = &cli.BoolFlag{
+	Name:  "phonetic",
+	Usage: "Also treat names that sound alike (Stephen/Steven) as a weak duplicate signal",
+}
+
+// BirthdayPlaceholderYearFlag sets the year stored for birthdays without
+// one, so they can be told apart from real years
+var BirthdayPlaceholderYearFlag = &cli.IntFlag{
+	Name:      "birthday-placeholder-year",
+	Usage:     "Year stored for birthdays without a year (--MM-DD), a leap year",
+	Value:     vcard.YearlessBirthdayPlaceholder,
+	Validator: vcard.ValidatePlaceholderYear,
+}
+
+// MinScoreFlag sets the match confidence below which contacts aren't
+// treated as duplicates
+var MinScoreFlag = &cli.FloatFlag{
+	Name:      "min-score",
+	Usage:     "Only treat contacts as duplicates with at least this match confidence (0-1)",
+	Validator: ValidateScore,
+}
+
+// ValidateScore rejects match confidences outside 0-1
+func ValidateScore(score float64) error {
+	if score < 0 || score > 1 {
+		return fmt.Errorf("invalid score %v (expected 0-1)", score)
+	}
+	return nil
+}
+
+// MergeFlags configure how duplicates are merged
+var MergeFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "merge-strategy",
+		Usage:   "Which value wins when both contacts have one: fill-empty, prefer-src or prefer-newest (by REV)",
+		Value:   string(vcard.MergeFillEmpty),
+		Sources: cli.EnvVars("ANYVCARD_MERGE_STRATEGY"),
+	},
+	&cli.StringSliceFlag{
+		Name:    "merge-field",
+		Usage:   "Per-field merge policy as field=fill|replace|keep, e.g. title=replace (repeatable)",
+		Sources: cli.EnvVars("ANYVCARD_MERGE_FIELDS"),
+	},
+	&cli.BoolFlag{
+		Name:  "conflicts-to-notes",
+		Usage: "Append the values a merge discards to the notes instead of dropping them",
+	},
+	&cli.BoolFlag{
+		Name:  "history",
+		Usage: "Record the date, source and changed fields of every merge in a History property of the contact",
+	},
+	&cli.StringFlag{
+		Name:  "merge-provenance",
+		Usage: "Header above notes appended by a merge, with {source} and {date} replaced; empty for a bare separator",
+		Value: vcard.DefaultProvenance,
+	},
+}
+
+// MergeOptions returns the options to merge duplicates with, or nil when
+// they are skipped instead
+func MergeOptions(cmd *cli.Command, merge bool) (*vcard.MergeOptions, error) {
+	strategy, err := vcard.ParseMergeStrategy(cmd.String("merge-strategy"))
+	if err != nil {
+		return nil, err
+	}
+	fields, err := vcard.ParseFieldPolicies(cmd.StringSlice("merge-field"))
+	if err != nil {
+		return nil, err
+	}
+	if !merge {
+		return nil, nil
+	}
+	return &vcard.MergeOptions{
+		Provenance:    cmd.String("merge-provenance"),
+		Strategy:      strategy,
+		Fields:        fields,
+		NoteConflicts: cmd.Bool("conflicts-to-notes"),
+		History:       cmd.Bool("history"),
+		Dedup:         DedupOptions(cmd),
+	}, nil
+}
+
+// WhereFlag filters contacts with a vcard.Query expression
+var WhereFlag = &cli.StringFlag{
+	Name:  "where",
+	Usage: "Only act on contacts matching a filter expression, e.g. 'org=Acme AND email~@gmail.com AND has(phone)'",
+}
+
+// ParseWhere parses --where, returning nil when it is not set
+func ParseWhere(cmd *cli.Command) (*vcard.Query, error) {
+	if cmd.String("where") == "" {
+		return nil, nil
+	}
+	return vcard.ParseQuery(cmd.String("where"))
+}
+
+// IndexDirFlag moves duplicate indexes from memory to a temporary file
+var IndexDirFlag = &cli.StringFlag{
+	Name:    "index-dir",
+	Usage:   "Keep the duplicate index in a temporary file in this directory instead of memory, for very large spaces",
+	Sources: cli.EnvVars("ANYVCARD_INDEX_DIR"),
+}
+
+// DedupOptions returns how contacts are matched as duplicates, from the
+// --phone-* flags Configure validated and the email rules of the
+// configuration file
+func DedupOptions(cmd *cli.Command) vcard.DedupOptions {
+	return vcard.DedupOptions{
+		PhoneMatch:        vcard.PhoneMatching(cmd.String("phone-match")),
+		PhoneSuffixLength: cmd.Int("phone-suffix-length"),
+		PhoneRegion:       cmd.String("phone-region"),
+		Email:             settings.Email,
+	}
+}
+
+// NewDedupIndex returns an empty duplicate index, kept on disk with
+// --index-dir, matching contacts as DedupOptions says, names that sound
+// alike with --phonetic and ignoring matches below --min-score.
+// closeIndex releases it, returning any error the disk store ran into.
+func NewDedupIndex(cmd *cli.Command) (idx *vcard.DedupIndex, closeIndex func() error, err error) {
+	var store vcard.IndexStore = vcard.NewMemoryIndexStore()
+	closeIndex = func() error { return nil }
+	if dir := cmd.String("index-dir"); dir != "" {
+		disk, err := vcard.NewDiskIndexStore(dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		store, closeIndex = disk, disk.Close
+	}
+	idx = vcard.NewDedupIndexWith(store, DedupOptions(cmd), nil)
+	if cmd.Bool("phonetic") {
+		idx.EnablePhonetic()
+	}
+	idx.SetMinScore(cmd.Float("min-score"))
+	return idx, closeIndex, nil
+}
+
+// WebhookFlag configures the URL run summaries are posted to
+var WebhookFlag = &cli.StringFlag{
+	Name:    "webhook",
+	Usage:   "URL to POST a JSON summary to when the run finishes",
+	Sources: cli.EnvVars("ANYVCARD_WEBHOOK_URL"),
+}
+
+// NotifyWebhook posts the summary of a finished run into spaceID to the
+// --webhook URL, if set. runErr is the error the run ended with, if any.
+func NotifyWebhook(ctx context.Context, cmd *cli.Command, spaceID string, started time.Time, summary ImportSummary, runErr error) {
+	url := cmd.String("webhook")
+	if url == "" {
+		return
+	}
+
+	s := notify.Summary{
+		SessionID:  notify.NewSessionID(),
+		Command:    cmd.Name,
+		SpaceID:    spaceID,
+		Status:     notify.StatusOK,
+		StartedAt:  started,
+		FinishedAt: time.Now(),
+		Contacts:   summary.Contacts,
+		Imported:   summary.Imported,
+		Merged:     summary.Merged,
+		Skipped:    summary.Skipped,
+		Blocked:    summary.Blocked,
+		Failed:     summary.Failed,
+		Errors:     summary.Errors,
+
+		ContactsPerSecond: summary.ContactsPerSecond(),
+		APICalls:          summary.APICalls,
+		BytesUploaded:     summary.BytesUploaded,
+	}
+	if runErr != nil || summary.Failed > 0 {
+		s.Status = notify.StatusFailed
+	}
+	if runErr != nil {
+		s.Errors = append(s.Errors, runErr.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	if err := notify.Post(ctx, http.DefaultClient, url, s); err != nil {
+		log.Printf("Warning: could not notify webhook: %v", err)
+	}
+}
+
+// SearchAll runs a search and follows pagination until all objects are fetched
+func SearchAll(ctx context.Context, client anytype.Client, spaceID string, req anytype.SearchRequest) ([]anytype.Object, error) {
+	var allObjects []anytype.Object
+	err := vcard.SearchPages(ctx, client, spaceID, req, func(page []anytype.Object) error {
+		allObjects = append(allObjects, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return allObjects, nil
+}
+
+// CreateContactType creates the Contact object type in a space
+func CreateContactType(ctx context.Context, client anytype.Client, spaceID string) (*anytype.TypeResponse, error) {
 	req := anytype.CreateTypeRequest{
 		Key:        "contact",
 		Name:       "Contact",
@@ -221,9 +998,11 @@ func CreateContactType(ctx context.Context, client anytype.Client, spaceID strin
 			Format: anytype.IconFormatEmoji,
 			Emoji:  "👤",
 		},
-		Properties: properties,
+		Properties: ContactTypeProperties,
 	}
 
+	defer invalidateSchema(spaceID) // Types also create their properties
+	defer profile.Start(profile.API)()
 	return client.Space(spaceID).Types().Create(ctx, req)
 }
 
@@ -248,5 +1027,301 @@ func GlobalFlags() []cli.Flag {
 			Usage:   "Space ID to import contacts into",
 			Sources: cli.EnvVars("ANYTYPE_SPACE_ID"),
 		},
+		&cli.DurationFlag{
+			Name:    "schema-cache-ttl",
+			Value:   schemacache.DefaultTTL,
+			Usage:   "How long to reuse the cached types and properties of a space (0 disables the cache)",
+			Sources: cli.EnvVars("ANYVCARD_SCHEMA_CACHE_TTL"),
+		},
+		&cli.StringFlag{
+			Name:    "config",
+			Usage:   "Configuration file with the email normalization rules (default: any-vcard/config.json in the user config dir)",
+			Sources: cli.EnvVars("ANYVCARD_CONFIG"),
+		},
+		&cli.IntFlag{
+			Name:    "phone-suffix-length",
+			Value:   vcard.DefaultPhoneSuffixLength,
+			Usage:   "Trailing digits compared to match phone numbers written with and without a country code (6-15)",
+			Sources: cli.EnvVars("ANYVCARD_PHONE_SUFFIX_LENGTH"),
+		},
+		&cli.StringFlag{
+			Name:    "phone-match",
+			Value:   string(vcard.PhoneMatchSuffix),
+			Usage:   "How phone numbers are matched: suffix (the last digits) or strict (the full E.164 form, see --phone-region)",
+			Sources: cli.EnvVars("ANYVCARD_PHONE_MATCH"),
+		},
+		&cli.StringFlag{
+			Name:    "phone-region",
+			Usage:   "ISO country code of national numbers for --phone-match strict (e.g. ES, US)",
+			Sources: cli.EnvVars("ANYVCARD_PHONE_REGION"),
+		},
+		&cli.StringFlag{
+			Name:    "locale",
+			Usage:   "Language whose alphabet names are sorted and grouped by, e.g. es or sv (default: from LC_ALL, LC_COLLATE or LANG)",
+			Sources: cli.EnvVars("ANYVCARD_LOCALE"),
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Change nothing: import, copy, restore and dedupe list what they would do, and every write to Anytype is printed instead of made",
+		},
+		&cli.BoolFlag{
+			Name:    "yes",
+			Aliases: []string{"y"},
+			Usage:   "Don't ask before deleting or overwriting objects (dedupe, import --replace); required without a terminal",
+		},
+		&cli.StringFlag{
+			Name:  "progress-json",
+			Usage: "Stream progress as JSON lines (parsed, deduped, created, failed, done) to this file; - for stdout, moving the other output to stderr",
+		},
+		&cli.StringFlag{
+			Name:    "lang",
+			Usage:   "Language of the messages: en or es (default: from LC_ALL, LC_MESSAGES or LANG)",
+			Sources: cli.EnvVars("ANYVCARD_LANG"),
+		},
+		&cli.BoolFlag{
+			Name:  "profile",
+			Usage: "Report on stderr where the time went (parsing, dedup, API calls, waiting on the server)",
+		},
+		&cli.StringFlag{
+			Name:  "pprof",
+			Usage: "Write CPU and heap pprof profiles into this directory",
+		},
+	}
+}
+
+// settings is the configuration file loaded by Configure
+var settings = &config.Config{}
+
+// BlocklistFlag names a blocklist file, see vcard.ParseBlocklist
+var BlocklistFlag = &cli.StringFlag{
+	Name:    "blocklist",
+	Usage:   "File of phones, emails and name patterns, one per line, of contacts never to import (added to the config file's blocklist)",
+	Sources: cli.EnvVars("ANYVCARD_BLOCKLIST"),
+}
+
+// Blocklist returns the blocklist of the configuration file with the
+// entries of the --blocklist file, nil when both are empty
+func Blocklist(cmd *cli.Command) (*vcard.Blocklist, error) {
+	blocklist := &vcard.Blocklist{}
+	if err := blocklist.Add(&settings.Blocklist); err != nil {
+		return nil, err
 	}
+	if path := cmd.String("blocklist"); path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read blocklist: %w", err)
+		}
+		defer f.Close()
+		entries, err := vcard.ParseBlocklist(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse blocklist %s: %w", path, err)
+		}
+		if err := blocklist.Add(entries); err != nil {
+			return nil, err
+		}
+	}
+	if blocklist.Empty() {
+		return nil, nil
+	}
+	return blocklist, nil
+}
+
+// Configure is the root Before hook loading the configuration file,
+// applying the global settings and starting the profiling, see
+// StartProfiling
+func Configure(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+	path, required := cmd.String("config"), true
+	if path == "" {
+		var err error
+		if path, err = config.DefaultPath(); err != nil {
+			return ctx, fmt.Errorf("failed to locate config: %w", err)
+		}
+		required = false
+	}
+	cfg, err := config.Load(path, required)
+	if err != nil {
+		return ctx, err
+	}
+	settings = cfg
+	dryRun = cmd.Bool("dry-run")
+
+	// Commands build their DedupOptions from these flags
+	if n := cmd.Int("phone-suffix-length"); n < 6 || n > 15 {
+		return ctx, fmt.Errorf("invalid --phone-suffix-length %d (expected 6 to 15 digits)", n)
+	}
+	switch match := vcard.PhoneMatching(cmd.String("phone-match")); match {
+	case vcard.PhoneMatchSuffix, vcard.PhoneMatchStrict:
+	default:
+		return ctx, fmt.Errorf("unsupported phone match %q (supported: suffix, strict)", match)
+	}
+	if region := cmd.String("phone-region"); region != "" && !vcard.IsSupportedPhoneRegion(region) {
+		return ctx, fmt.Errorf("unsupported region %q", region)
+	}
+
+	locale := cmd.String("locale")
+	if locale == "" {
+		locale = vcard.SystemLocale()
+	}
+	if _, err := vcard.NewCollator(locale); err != nil {
+		return ctx, err
+	}
+	vcard.Locale = locale
+
+	lang := cmd.String("lang")
+	if lang == "" {
+		lang = i18n.SystemLanguage()
+	}
+	if err := i18n.SetLanguage(lang); err != nil {
+		return ctx, err
+	}
+
+	switch path := cmd.String("progress-json"); path {
+	case "":
+	case "-":
+		// Events own stdout so wrappers can read them line by line
+		progress.SetOutput(os.Stdout)
+		i18n.SetOutput(os.Stderr)
+	default:
+		f, err := os.Create(path)
+		if err != nil {
+			return ctx, fmt.Errorf("failed to create progress file: %w", err)
+		}
+		progress.SetOutput(f)
+		progressFile = f
+	}
+	return StartProfiling(ctx, cmd)
+}
+
+// progressFile is the --progress-json file, nil when streaming to stdout
+// or not at all
+var progressFile *os.File
+
+// Finish is the root After hook closing the --progress-json file and
+// stopping the profiling, see StopProfiling
+func Finish(ctx context.Context, cmd *cli.Command) error {
+	var errs []error
+	if progressFile != nil {
+		progress.SetOutput(nil)
+		if err := progressFile.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close progress file: %w", err))
+		}
+		progressFile = nil
+	}
+	if err := StopProfiling(ctx, cmd); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// stopPprof ends the --pprof profiles, nil when not profiling
+var stopPprof func() error
+
+// StartProfiling is the root Before hook enabling --profile and --pprof
+func StartProfiling(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+	if cmd.Bool("profile") {
+		profile.Enable()
+	}
+	if dir := cmd.String("pprof"); dir != "" {
+		stop, err := profile.StartPprof(dir)
+		if err != nil {
+			return ctx, err
+		}
+		stopPprof = stop
+	}
+	return ctx, nil
+}
+
+// StopProfiling is the root After hook printing the --profile report and
+// writing the --pprof profiles
+func StopProfiling(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Bool("profile") {
+		profile.Report(os.Stderr)
+	}
+	if stopPprof != nil {
+		return stopPprof()
+	}
+	return nil
+}
+
+// ObjectURL returns the deep link opening an object in the Anytype app
+func ObjectURL(spaceID, objectID string) string {
+	return fmt.Sprintf("anytype://object?objectId=%s&spaceId=%s", url.QueryEscape(objectID), url.QueryEscape(spaceID))
+}
+
+// OpenURL opens url with the desktop's default handler
+func OpenURL(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+// NewContactService creates the contact service for the space of the
+// command. Unlike the import setup it creates nothing and prints nothing,
+// so stdout stays free for servers and full-screen UIs.
+func NewContactService(ctx context.Context, cmd *cli.Command) (*service.Service, error) {
+	client := NewClient(cmd)
+	spaceID := cmd.String("space")
+
+	typeKey, err := FindContactType(ctx, client, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	phoneKeys, emailKeys, err := contactPropertyKeys(ctx, client, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	dst := &sink.Anytype{
+		Client:    client,
+		SpaceID:   spaceID,
+		TypeKey:   typeKey,
+		PhoneKeys: phoneKeys,
+		EmailKeys: emailKeys,
+	}
+	// Commands offering --history create the property beforehand
+	if cmd.Bool("history") {
+		dst.HistoryKey = HistoryProperty.Key
+	}
+	contactTypes := []string{typeKey}
+	if companyTypeKey := findCompanyType(ctx, client, spaceID); companyTypeKey != "" {
+		contactTypes = append(contactTypes, companyTypeKey)
+	}
+	return &service.Service{Store: &service.Anytype{Client: client, Sink: dst}, ContactTypes: contactTypes, Dedup: DedupOptions(cmd)}, nil
+}
+
+// findCompanyType returns the key of the Company type in the space, ""
+// when it has none
+func findCompanyType(ctx context.Context, client anytype.Client, spaceID string) string {
+	types, err := ListTypes(ctx, client, spaceID)
+	if err != nil {
+		return ""
+	}
+	for _, t := range types {
+		if strings.EqualFold(t.Key, CompanyTypeKey) || strings.EqualFold(t.Name, "company") {
+			return t.Key
+		}
+	}
+	return ""
+}
+
+// contactPropertyKeys returns the phone and email property keys of the
+// space without creating any, unlike EnsureContactProperties which
+// prints to stdout. The slots are found the same way, by name.
+func contactPropertyKeys(ctx context.Context, client anytype.Client, spaceID string) ([]string, []string, error) {
+	props, err := ListProperties(ctx, client, spaceID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list properties: %w", err)
+	}
+	phoneKeys, emailKeys := slotKeys(props, phoneProperties), slotKeys(props, emailProperties)
+	if slices.Contains(phoneKeys, "") || slices.Contains(emailKeys, "") {
+		return nil, nil, fmt.Errorf("space has no phone/email properties; run an import first")
+	}
+	return phoneKeys, emailKeys, nil
 }
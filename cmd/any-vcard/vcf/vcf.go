@@ -0,0 +1,99 @@
+package vcf
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/rubiojr/any-vcard/internal/i18n"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/urfave/cli/v3"
+)
+
+var Command = &cli.Command{
+	Name:  "vcf",
+	Usage: "Work on .vcf files without touching Anytype",
+	Commands: []*cli.Command{
+		sortCommand,
+	},
+}
+
+var sortCommand = &cli.Command{
+	Name:      "sort",
+	Usage:     "Sort the cards of a .vcf file, keeping their full contents",
+	ArgsUsage: "<vcard-file|->",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "by",
+			Usage: "Sort by family (name), given (name) or org",
+			Value: vcard.SortFamily,
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "Write the sorted cards here instead of back into the input file (- for stdout)",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if cmd.Args().Len() != 1 {
+			return fmt.Errorf("one vCard file is required")
+		}
+		input := cmd.Args().First()
+		output := cmd.String("output")
+		if output == "" {
+			output = input
+		}
+
+		var data []byte
+		var err error
+		if input == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(input)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", input, err)
+		}
+
+		sorted, err := vcard.SortCards(data, cmd.String("by"), vcard.Locale)
+		if err != nil {
+			return err
+		}
+
+		if output == "-" {
+			_, err = os.Stdout.Write(sorted)
+			return err
+		}
+		if err := writeFile(output, sorted); err != nil {
+			return err
+		}
+		i18n.Printf("✓ Sorted %s by %s\n", output, cmd.String("by"))
+		return nil
+	},
+}
+
+// writeFile replaces path through a temporary file, so an interrupted
+// write doesn't truncate the cards being sorted in place
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".sort-*.vcf")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if info, err := os.Stat(path); err == nil {
+		os.Chmod(tmp.Name(), info.Mode().Perm())
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
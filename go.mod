@@ -6,14 +6,33 @@ replace github.com/rubiojr/anytype-go => ./anytype-go
 
 require (
 	github.com/emersion/go-vcard v0.0.0-20230815062825-8fda7d206ec9
+	github.com/go-ldap/ldap/v3 v3.4.12
+	github.com/mark3labs/mcp-go v0.44.0
+	github.com/mattn/go-sqlite3 v1.14.33
 	github.com/rubiojr/anytype-go v0.5.0
 	github.com/stretchr/testify v1.11.1
 	github.com/urfave/cli/v3 v3.6.1
+	go.etcd.io/bbolt v1.4.3
+	go.starlark.net v0.0.0-20231121155337-90ade8b19d09
+	golang.org/x/net v0.38.0
+	golang.org/x/term v0.30.0
 	golang.org/x/text v0.33.0
 )
 
 require (
+	github.com/Azure/go-ntlmssp v0.0.0-20221128193559-754e69321358 // indirect
+	github.com/bahlo/generic-list-go v0.2.0 // indirect
+	github.com/buger/jsonparser v1.1.1 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/go-asn1-ber/asn1-ber v1.5.8-0.20250403174932-29230038a667 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/invopop/jsonschema v0.13.0 // indirect
+	github.com/mailru/easyjson v0.7.7 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/spf13/cast v1.7.1 // indirect
+	github.com/wk8/go-ordered-map/v2 v2.1.8 // indirect
+	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
+	golang.org/x/crypto v0.36.0 // indirect
+	golang.org/x/sys v0.31.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,191 @@
+// Package backup writes and reads space snapshots: zip archives holding the
+// contacts as vCard and JSON Lines together with their photos.
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// Archive entry names
+const (
+	ManifestFile = "manifest.json"
+	VCardFile    = "contacts.vcf"
+	JSONLFile    = "contacts.jsonl"
+	PhotosDir    = "photos/"
+)
+
+// FormatVersion is bumped when the archive layout changes
+const FormatVersion = 1
+
+// Manifest describes the snapshot stored in an archive
+type Manifest struct {
+	FormatVersion int       `json:"format_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	SpaceID       string    `json:"space_id"`
+	TypeKey       string    `json:"type_key"`
+	Contacts      int       `json:"contacts"`
+	Photos        int       `json:"photos"`
+}
+
+// FileName returns the dated archive name for a snapshot taken at t
+func FileName(t time.Time) string {
+	return "contacts-" + t.UTC().Format("20060102T150405Z") + ".zip"
+}
+
+// Writer streams contacts into a backup archive. Photos referenced by URL
+// are downloaded into photos/ and embedded in the vCards; the JSON Lines
+// copy points at the archived file.
+type Writer struct {
+	HTTPClient  *http.Client // Used to download photos, vcard.PhotoClient when nil
+	PhotoErrors []error      // Photos that could not be downloaded
+
+	zw       *zip.Writer
+	vcf      bytes.Buffer
+	jsonl    *json.Encoder
+	jsonlBuf bytes.Buffer
+	manifest Manifest
+}
+
+// NewWriter creates a Writer producing an archive on w
+func NewWriter(w io.Writer, spaceID, typeKey string) *Writer {
+	b := &Writer{
+		zw: zip.NewWriter(w),
+		manifest: Manifest{
+			FormatVersion: FormatVersion,
+			CreatedAt:     time.Now().UTC(),
+			SpaceID:       spaceID,
+			TypeKey:       typeKey,
+		},
+	}
+	b.jsonl = json.NewEncoder(&b.jsonlBuf)
+	b.jsonl.SetEscapeHTML(false)
+	return b
+}
+
+// Write adds a contact to the archive
+func (b *Writer) Write(ctx context.Context, c *vcard.Contact) error {
+	card := *c
+	record := *c
+
+	if photo := c.Photo; photo != "" {
+		dataURI := photo
+		if vcard.IsPhotoURL(photo) {
+			client := b.HTTPClient
+			if client == nil {
+				client = vcard.PhotoClient
+			}
+			var err error
+			if dataURI, err = vcard.FetchPhoto(ctx, client, photo); err != nil {
+				b.PhotoErrors = append(b.PhotoErrors, fmt.Errorf("%s: %w", c.DisplayName(), err))
+				dataURI = ""
+			}
+		}
+
+		card.Photo, record.Photo = dataURI, ""
+		if name, data, ok := decodeDataURI(dataURI); ok {
+			path := PhotosDir + photoID(c, b.manifest.Contacts) + name
+			if err := b.writeEntry(path, data); err != nil {
+				return err
+			}
+			record.Photo = path
+			b.manifest.Photos++
+		}
+	}
+
+	if err := vcard.Write(&b.vcf, []vcard.Contact{card}, vcard.Version4); err != nil {
+		return err
+	}
+	if err := b.jsonl.Encode(record); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", c.DisplayName(), err)
+	}
+	b.manifest.Contacts++
+	return nil
+}
+
+// Close writes the vCard, JSON Lines and manifest entries and finishes the
+// archive. It does not close the underlying writer.
+func (b *Writer) Close() error {
+	if err := b.writeEntry(VCardFile, b.vcf.Bytes()); err != nil {
+		return err
+	}
+	if err := b.writeEntry(JSONLFile, b.jsonlBuf.Bytes()); err != nil {
+		return err
+	}
+	manifest, err := json.MarshalIndent(b.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := b.writeEntry(ManifestFile, manifest); err != nil {
+		return err
+	}
+	return b.zw.Close()
+}
+
+// Manifest returns the manifest describing the contacts written so far
+func (b *Writer) Manifest() Manifest {
+	return b.manifest
+}
+
+func (b *Writer) writeEntry(name string, data []byte) error {
+	w, err := b.zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: b.manifest.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// photoID names archived photos after the object, falling back to the UID
+// or the position in the archive
+func photoID(c *vcard.Contact, index int) string {
+	for _, id := range []string{c.ObjectID, c.UID} {
+		if id != "" && !strings.ContainsAny(id, `/\:`) {
+			return id
+		}
+	}
+	return fmt.Sprintf("contact-%d", index+1)
+}
+
+// decodeDataURI returns the file extension and bytes of a base64 data URI
+func decodeDataURI(uri string) (string, []byte, bool) {
+	rest, ok := strings.CutPrefix(uri, "data:")
+	if !ok {
+		return "", nil, false
+	}
+	header, payload, ok := strings.Cut(rest, ",")
+	if !ok || !strings.HasSuffix(header, ";base64") {
+		return "", nil, false
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", nil, false
+	}
+
+	ext := ".jpg"
+	mediaType := strings.TrimSuffix(header, ";base64")
+	if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+		ext = exts[0]
+		if mediaType == "image/jpeg" {
+			ext = ".jpg"
+		}
+	}
+	return ext, data, true
+}
@@ -0,0 +1,174 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+func TestWriter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("png-bytes"))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "space1", "contact")
+	contacts := []vcard.Contact{
+		{FormattedName: "Jane Doe", ObjectID: "obj1", Photo: srv.URL + "/jane.png", Properties: map[string]any{"city": "Madrid"}},
+		{FormattedName: "John Roe", ObjectID: "obj2", Photo: srv.URL + "/missing.png"},
+		{FormattedName: "No Photo", ObjectID: "obj3"},
+	}
+	for i := range contacts {
+		if err := w.Write(context.Background(), &contacts[i]); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if len(w.PhotoErrors) != 1 {
+		t.Errorf("PhotoErrors = %v, want 1 error", w.PhotoErrors)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader() error = %v", err)
+	}
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+
+	if got := files[PhotosDir+"obj1.png"]; got != "png-bytes" {
+		t.Errorf("photo entry = %q, want png-bytes", got)
+	}
+
+	parsed, err := vcard.Parse(strings.NewReader(files[VCardFile]))
+	if err != nil || len(parsed) != 3 {
+		t.Fatalf("vCard entry has %d contact(s), err %v", len(parsed), err)
+	}
+	if !strings.HasPrefix(parsed[0].Photo, "data:image/png;base64,") {
+		t.Errorf("vCard photo = %q, want embedded data URI", parsed[0].Photo)
+	}
+
+	lines := strings.Split(strings.TrimSpace(files[JSONLFile]), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("JSON Lines entry has %d line(s), want 3", len(lines))
+	}
+	var first vcard.Contact
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	if first.Photo != PhotosDir+"obj1.png" || first.Properties["city"] != "Madrid" {
+		t.Errorf("JSON record = %+v", first)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal([]byte(files[ManifestFile]), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Contacts != 3 || m.Photos != 1 || m.SpaceID != "space1" || m.FormatVersion != FormatVersion {
+		t.Errorf("manifest = %+v", m)
+	}
+}
+
+func TestFileName(t *testing.T) {
+	ts := time.Date(2024, 3, 5, 14, 7, 9, 0, time.FixedZone("CET", 3600))
+	if got, want := FileName(ts), "contacts-20240305T130709Z.zip"; got != want {
+		t.Errorf("FileName() = %q, want %q", got, want)
+	}
+}
+
+func TestArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName(time.Now()))
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWriter(f, "space1", "contact")
+	photo := "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("png-bytes"))
+	for _, c := range []vcard.Contact{
+		{FormattedName: "Jane Doe", ObjectID: "obj1", Photo: photo},
+		{FormattedName: "John Roe", UID: "urn:uuid:1", Emails: []string{"john@example.com"}},
+	} {
+		if err := w.Write(context.Background(), &c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	a, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer a.Close()
+	if a.Manifest.SpaceID != "space1" || a.Manifest.Contacts != 2 {
+		t.Errorf("Manifest = %+v", a.Manifest)
+	}
+	contacts, err := a.Contacts()
+	if err != nil {
+		t.Fatalf("Contacts() error = %v", err)
+	}
+	if len(contacts) != 2 {
+		t.Fatalf("Contacts() returned %d contact(s), want 2", len(contacts))
+	}
+	if contacts[0].Photo != photo {
+		t.Errorf("Photo = %q, want %q", contacts[0].Photo, photo)
+	}
+	if contacts[1].UID != "urn:uuid:1" || contacts[1].Emails[0] != "john@example.com" {
+		t.Errorf("Contacts()[1] = %+v", contacts[1])
+	}
+}
+
+func TestMissing(t *testing.T) {
+	archived := []vcard.Contact{
+		{FormattedName: "Still there", ObjectID: "obj1"},
+		{FormattedName: "Restored before", ObjectID: "obj2"},
+		{FormattedName: "Same UID", UID: "uid-3", ObjectID: "obj3"},
+		{FormattedName: "Deleted", ObjectID: "obj4", LastModified: "2024-01-01T00:00:00Z"},
+		{FormattedName: "Deleted with UID", UID: "uid-5", ObjectID: "obj5"},
+	}
+	existing := []*vcard.Contact{
+		{ObjectID: "obj1"},
+		{ObjectID: "other2", UID: "obj2"},
+		{ObjectID: "other3", UID: "uid-3"},
+	}
+
+	got := Missing(archived, existing)
+	if len(got) != 2 {
+		t.Fatalf("Missing() returned %d contact(s), want 2: %+v", len(got), got)
+	}
+	if got[0].UID != "obj4" || got[0].ObjectID != "" || got[0].LastModified != "" {
+		t.Errorf("Missing()[0] = %+v, want UID obj4 and no object ID", got[0])
+	}
+	if got[1].UID != "uid-5" {
+		t.Errorf("Missing()[1].UID = %q, want uid-5", got[1].UID)
+	}
+}
@@ -0,0 +1,124 @@
+package backup
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// Archive is an opened backup archive
+type Archive struct {
+	Manifest Manifest
+
+	zr    *zip.ReadCloser
+	files map[string]*zip.File
+}
+
+// Open opens a backup archive and reads its manifest
+func Open(name string) (*Archive, error) {
+	zr, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup: %w", err)
+	}
+
+	a := &Archive{zr: zr, files: make(map[string]*zip.File, len(zr.File))}
+	for _, f := range zr.File {
+		a.files[f.Name] = f
+	}
+
+	data, err := a.read(ManifestFile)
+	if err != nil {
+		zr.Close()
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &a.Manifest); err != nil {
+		zr.Close()
+		return nil, fmt.Errorf("failed to decode backup manifest: %w", err)
+	}
+	if a.Manifest.FormatVersion > FormatVersion {
+		zr.Close()
+		return nil, fmt.Errorf("backup format version %d is newer than supported (%d)", a.Manifest.FormatVersion, FormatVersion)
+	}
+	return a, nil
+}
+
+// Contacts returns the contacts stored in the archive. Archived photos are
+// returned as data URIs.
+func (a *Archive) Contacts() ([]vcard.Contact, error) {
+	f, ok := a.files[JSONLFile]
+	if !ok {
+		return nil, fmt.Errorf("backup has no %s", JSONLFile)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", JSONLFile, err)
+	}
+	defer rc.Close()
+
+	var contacts []vcard.Contact
+	scanner := bufio.NewScanner(rc)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var c vcard.Contact
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", JSONLFile, line, err)
+		}
+		if strings.HasPrefix(c.Photo, PhotosDir) {
+			if c.Photo, err = a.photoDataURI(c.Photo); err != nil {
+				return nil, err
+			}
+		}
+		contacts = append(contacts, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", JSONLFile, err)
+	}
+	return contacts, nil
+}
+
+// Close closes the archive
+func (a *Archive) Close() error {
+	return a.zr.Close()
+}
+
+func (a *Archive) photoDataURI(name string) (string, error) {
+	data, err := a.read(name)
+	if err != nil {
+		return "", err
+	}
+	mediaType := mime.TypeByExtension(path.Ext(name))
+	if !strings.HasPrefix(mediaType, "image/") {
+		mediaType = http.DetectContentType(data)
+	}
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+func (a *Archive) read(name string) ([]byte, error) {
+	f, ok := a.files[name]
+	if !ok {
+		return nil, fmt.Errorf("backup has no %s", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return data, nil
+}
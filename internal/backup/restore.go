@@ -0,0 +1,38 @@
+package backup
+
+import "github.com/rubiojr/any-vcard/internal/vcard"
+
+// Missing returns the archived contacts that no longer exist among
+// existing, ready to be created. Contacts are matched by UID; archived
+// contacts without one use the object ID they were backed up from, which
+// also matches the original objects when restoring into the same space.
+// The returned contacts carry that UID and no object ID.
+func Missing(archived []vcard.Contact, existing []*vcard.Contact) []vcard.Contact {
+	known := make(map[string]bool, 2*len(existing))
+	for _, c := range existing {
+		for _, id := range []string{c.UID, c.ObjectID} {
+			if id != "" {
+				known[id] = true
+			}
+		}
+	}
+
+	var missing []vcard.Contact
+	for _, c := range archived {
+		uid := c.UID
+		if uid == "" {
+			uid = c.ObjectID
+		}
+		if known[uid] || (c.ObjectID != "" && known[c.ObjectID]) {
+			continue
+		}
+		if uid != "" {
+			known[uid] = true
+		}
+		c.UID = uid
+		c.ObjectID = ""
+		c.LastModified = ""
+		missing = append(missing, c)
+	}
+	return missing
+}
@@ -0,0 +1,446 @@
+// Package browse implements the state and rendering of the interactive
+// contact browser. It performs no I/O: the caller feeds it keys and
+// carries out the actions it returns.
+package browse
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// Mode is what the browser is currently reading keys for
+type Mode int
+
+const (
+	ModeList         Mode = iota // Moving through the list
+	ModeSearch                   // Typing a search query
+	ModeEditField                // Typing the name of the field to edit
+	ModeEditValue                // Typing the new field value
+	ModeConfirmMerge             // Waiting for y/n to merge the marked contact
+)
+
+// ActionKind is a side effect requested by Update
+type ActionKind int
+
+const (
+	ActionNone  ActionKind = iota
+	ActionQuit             // Leave the browser
+	ActionOpen             // Open Contact in Anytype
+	ActionSave             // Store the edited Contact
+	ActionMerge            // Merge Duplicate into Contact and delete Duplicate
+)
+
+// Action is returned by Update for the caller to carry out
+type Action struct {
+	Kind      ActionKind
+	Contact   *vcard.Contact
+	Duplicate *vcard.Contact
+}
+
+// Field is a contact field that can be edited in the browser
+type Field struct {
+	Name string
+	Get  func(c *vcard.Contact) string
+	Set  func(c *vcard.Contact, v string)
+}
+
+// Fields lists the editable fields. Email and phone edit the first value.
+var Fields = []Field{
+	{"name", func(c *vcard.Contact) string { return c.FormattedName }, func(c *vcard.Contact, v string) { c.FormattedName = v }},
+	{"given_name", func(c *vcard.Contact) string { return c.GivenName }, func(c *vcard.Contact, v string) { c.GivenName = v }},
+	{"family_name", func(c *vcard.Contact) string { return c.FamilyName }, func(c *vcard.Contact, v string) { c.FamilyName = v }},
+	{"organization", func(c *vcard.Contact) string { return c.Organization }, func(c *vcard.Contact, v string) { c.Organization = v }},
+	{"title", func(c *vcard.Contact) string { return c.Title }, func(c *vcard.Contact, v string) { c.Title = v }},
+	{"email", func(c *vcard.Contact) string { return first(c.Emails) }, func(c *vcard.Contact, v string) { c.Emails = setFirst(c.Emails, v) }},
+	{"phone", func(c *vcard.Contact) string { return first(c.Phones) }, func(c *vcard.Contact, v string) { c.Phones = setFirst(c.Phones, v) }},
+	{"birthday", func(c *vcard.Contact) string { return c.Birthday }, func(c *vcard.Contact, v string) { c.Birthday = v }},
+	{"note", func(c *vcard.Contact) string { return c.Note }, func(c *vcard.Contact, v string) { c.Note = v }},
+}
+
+// Model is the browser state
+type Model struct {
+	Mode    Mode
+	Query   string
+	Input   string // Text typed at the prompt
+	Status  string // One-line message shown at the bottom
+	Cursor  int    // Index into Visible
+	Offset  int    // First visible row of the list
+	Visible []*vcard.Contact
+	Marked  *vcard.Contact // Contact marked as a duplicate
+
+	all   []*vcard.Contact
+	field *Field
+}
+
+// New creates a browser over contacts
+func New(contacts []*vcard.Contact) *Model {
+	m := &Model{all: contacts}
+	m.filter()
+	return m
+}
+
+// Selected returns the contact under the cursor, or nil when the list is empty
+func (m *Model) Selected() *vcard.Contact {
+	if m.Cursor < 0 || m.Cursor >= len(m.Visible) {
+		return nil
+	}
+	return m.Visible[m.Cursor]
+}
+
+// Replace swaps the contact with the same ObjectID for c, e.g. after a save
+func (m *Model) Replace(c *vcard.Contact) {
+	for i, existing := range m.all {
+		if existing.ObjectID == c.ObjectID {
+			m.all[i] = c
+		}
+	}
+	if m.Marked != nil && m.Marked.ObjectID == c.ObjectID {
+		m.Marked = c
+	}
+	m.filter()
+}
+
+// Remove drops the contact with the given ObjectID, e.g. after a merge
+func (m *Model) Remove(objectID string) {
+	m.all = slices.DeleteFunc(m.all, func(c *vcard.Contact) bool { return c.ObjectID == objectID })
+	if m.Marked != nil && m.Marked.ObjectID == objectID {
+		m.Marked = nil
+	}
+	m.filter()
+}
+
+// Update applies a key press and returns the action it triggers
+func (m *Model) Update(k Key) Action {
+	if k.Code == KeyCtrlC {
+		return Action{Kind: ActionQuit}
+	}
+	switch m.Mode {
+	case ModeSearch:
+		return m.updateSearch(k)
+	case ModeEditField, ModeEditValue:
+		return m.updateEdit(k)
+	case ModeConfirmMerge:
+		return m.updateConfirm(k)
+	}
+	return m.updateList(k)
+}
+
+func (m *Model) updateList(k Key) Action {
+	m.Status = ""
+	switch {
+	case k.Code == KeyUp || k.Rune == 'k':
+		m.move(-1)
+	case k.Code == KeyDown || k.Rune == 'j':
+		m.move(1)
+	case k.Code == KeyPageUp:
+		m.move(-10)
+	case k.Code == KeyPageDown:
+		m.move(10)
+	case k.Code == KeyHome || k.Rune == 'g':
+		m.move(-len(m.Visible))
+	case k.Code == KeyEnd || k.Rune == 'G':
+		m.move(len(m.Visible))
+	case k.Rune == 'q' || k.Code == KeyEsc:
+		return Action{Kind: ActionQuit}
+	case k.Rune == '/':
+		m.Mode = ModeSearch
+	case m.Selected() == nil:
+	case k.Rune == 'o' || k.Code == KeyEnter:
+		return Action{Kind: ActionOpen, Contact: m.Selected()}
+	case k.Rune == 'e':
+		m.Mode = ModeEditField
+		m.Input = ""
+	case k.Rune == 'd':
+		m.markDuplicate()
+	}
+	return Action{}
+}
+
+func (m *Model) updateSearch(k Key) Action {
+	switch k.Code {
+	case KeyEnter:
+		m.Mode = ModeList
+	case KeyEsc:
+		m.Mode = ModeList
+		m.Query = ""
+		m.filter()
+	case KeyBackspace:
+		m.Query = dropLastRune(m.Query)
+		m.filter()
+	default:
+		if k.Rune != 0 {
+			m.Query += string(k.Rune)
+			m.filter()
+		}
+	}
+	return Action{}
+}
+
+func (m *Model) updateEdit(k Key) Action {
+	switch k.Code {
+	case KeyEsc:
+		m.Mode = ModeList
+		m.Input = ""
+		return Action{}
+	case KeyBackspace:
+		m.Input = dropLastRune(m.Input)
+		return Action{}
+	case KeyEnter:
+	default:
+		if k.Rune != 0 {
+			m.Input += string(k.Rune)
+		}
+		return Action{}
+	}
+
+	if m.Mode == ModeEditField {
+		name := strings.TrimSpace(m.Input)
+		i := slices.IndexFunc(Fields, func(f Field) bool { return f.Name == name })
+		if i < 0 {
+			m.Mode = ModeList
+			m.Status = fmt.Sprintf("Unknown field %q", name)
+			return Action{}
+		}
+		m.field = &Fields[i]
+		m.Mode = ModeEditValue
+		m.Input = m.field.Get(m.Selected())
+		return Action{}
+	}
+
+	edited := *m.Selected()
+	m.field.Set(&edited, strings.TrimSpace(m.Input))
+	m.Mode = ModeList
+	m.Input = ""
+	return Action{Kind: ActionSave, Contact: &edited}
+}
+
+func (m *Model) markDuplicate() {
+	c := m.Selected()
+	switch {
+	case m.Marked == nil:
+		m.Marked = c
+		m.Status = fmt.Sprintf("Marked %s as a duplicate; press d on the contact to keep", c.DisplayName())
+	case m.Marked == c:
+		m.Marked = nil
+		m.Status = "Unmarked"
+	default:
+		m.Mode = ModeConfirmMerge
+	}
+}
+
+func (m *Model) updateConfirm(k Key) Action {
+	m.Mode = ModeList
+	if k.Rune != 'y' && k.Rune != 'Y' {
+		m.Status = "Merge cancelled"
+		return Action{}
+	}
+	return Action{Kind: ActionMerge, Contact: m.Selected(), Duplicate: m.Marked}
+}
+
+func (m *Model) move(delta int) {
+	m.Cursor = max(0, min(m.Cursor+delta, len(m.Visible)-1))
+}
+
+// filter recomputes Visible from the query, keeping the selection if it
+// is still visible
+func (m *Model) filter() {
+	var selectedID string
+	if c := m.Selected(); c != nil {
+		selectedID = c.ObjectID
+	}
+	m.Visible = m.Visible[:0]
+	for _, c := range m.all {
+		if matches(c, m.Query) {
+			m.Visible = append(m.Visible, c)
+		}
+	}
+	m.Cursor = max(0, slices.IndexFunc(m.Visible, func(c *vcard.Contact) bool { return c.ObjectID == selectedID }))
+	m.move(0)
+}
+
+// matches reports whether the name, organization, an email or a phone of
+// c contains query, ignoring case
+func matches(c *vcard.Contact, query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return true
+	}
+	fields := append([]string{c.DisplayName(), c.Organization}, c.Emails...)
+	fields = append(fields, c.Phones...)
+	for _, f := range fields {
+		if strings.Contains(strings.ToLower(f), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// Help is the key summary shown in the footer
+const Help = "↑/↓ move  / search  e edit  o open in Anytype  d mark duplicate  q quit"
+
+// Render draws the browser as height lines of at most width columns: a
+// header, the list and detail panes, and a status or prompt line
+func (m *Model) Render(width, height int) []string {
+	if width < 20 || height < 4 {
+		return []string{truncate("Terminal too small", width)}
+	}
+	rows := height - 2
+	if m.Cursor < m.Offset {
+		m.Offset = m.Cursor
+	}
+	if m.Cursor >= m.Offset+rows {
+		m.Offset = m.Cursor - rows + 1
+	}
+
+	header := fmt.Sprintf("Contacts %d/%d", len(m.Visible), len(m.all))
+	if m.Query != "" {
+		header += fmt.Sprintf("  filter: %s", m.Query)
+	}
+	lines := []string{reverse(pad(header, width))}
+
+	listWidth := width * 2 / 5
+	detail := m.detail()
+	for row := range rows {
+		left := ""
+		if i := m.Offset + row; i < len(m.Visible) {
+			c := m.Visible[i]
+			mark := "  "
+			if c == m.Marked {
+				mark = "* "
+			}
+			left = pad(mark+c.DisplayName(), listWidth)
+			if i == m.Cursor {
+				left = reverse(left)
+			}
+		} else {
+			left = pad("", listWidth)
+		}
+		right := ""
+		if row < len(detail) {
+			right = detail[row]
+		}
+		lines = append(lines, left+" │ "+truncate(right, width-listWidth-3))
+	}
+
+	lines = append(lines, truncate(m.footer(), width))
+	return lines
+}
+
+func (m *Model) footer() string {
+	switch m.Mode {
+	case ModeSearch:
+		return "/" + m.Query
+	case ModeEditField:
+		names := make([]string, len(Fields))
+		for i, f := range Fields {
+			names[i] = f.Name
+		}
+		return fmt.Sprintf("Field (%s): %s", strings.Join(names, ", "), m.Input)
+	case ModeEditValue:
+		return fmt.Sprintf("%s: %s", m.field.Name, m.Input)
+	case ModeConfirmMerge:
+		return fmt.Sprintf("Merge %s into %s and delete it? (y/n)", m.Marked.DisplayName(), m.Selected().DisplayName())
+	}
+	if m.Status != "" {
+		return m.Status
+	}
+	return Help
+}
+
+// detail returns the detail pane lines of the selected contact
+func (m *Model) detail() []string {
+	c := m.Selected()
+	if c == nil {
+		return []string{"No contacts"}
+	}
+	lines := []string{c.DisplayName(), ""}
+	add := func(label string, values ...string) {
+		for _, v := range values {
+			if v = strings.TrimSpace(v); v != "" {
+				lines = append(lines, fmt.Sprintf("%-13s %s", label+":", v))
+			}
+		}
+	}
+	add("Organization", c.Organization)
+	add("Title", c.Title)
+	add("Role", c.Role)
+	add("Email", c.Emails...)
+	add("Phone", c.Phones...)
+	add("Birthday", c.Birthday)
+	for _, a := range c.Addresses {
+		parts := nonEmpty(a.Street, a.City, a.Region, a.PostalCode, a.Country)
+		if len(parts) == 0 {
+			parts = []string{a.Full}
+		}
+		add("Address", strings.Join(parts, ", "))
+	}
+	add("URL", c.URLs...)
+	add("Tags", strings.Join(c.Categories, ", "))
+	for _, l := range strings.Split(c.Note, "\n") {
+		add("Note", l)
+	}
+	add("Object ID", c.ObjectID)
+	return lines
+}
+
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func setFirst(values []string, v string) []string {
+	values = slices.Clone(values)
+	switch {
+	case len(values) == 0 && v != "":
+		return []string{v}
+	case len(values) == 0:
+		return nil
+	case v == "":
+		return values[1:]
+	}
+	values[0] = v
+	return values
+}
+
+func dropLastRune(s string) string {
+	_, size := utf8.DecodeLastRuneInString(s)
+	return s[:len(s)-size]
+}
+
+// truncate cuts s to at most width runes
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:width-1]) + "…"
+}
+
+// pad truncates s and fills it with spaces to exactly width runes
+func pad(s string, width int) string {
+	s = truncate(s, width)
+	return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
+
+func reverse(s string) string {
+	return "\x1b[7m" + s + "\x1b[0m"
+}
@@ -0,0 +1,180 @@
+package browse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+func testContacts() []*vcard.Contact {
+	return []*vcard.Contact{
+		{ObjectID: "obj1", FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}, Organization: "Acme"},
+		{ObjectID: "obj2", FormattedName: "John Roe", Phones: []string{"+34 600 000 000"}},
+		{ObjectID: "obj3", FormattedName: "J. Doe", Emails: []string{"jdoe@example.com"}},
+	}
+}
+
+func typeKeys(m *Model, s string) Action {
+	var last Action
+	for _, k := range ParseKeys([]byte(s)) {
+		last = m.Update(k)
+	}
+	return last
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", 3},
+		{"doe", 2},
+		{"ACME", 1},
+		{"600", 1},
+		{"nobody", 0},
+	}
+	for _, tt := range tests {
+		m := New(testContacts())
+		typeKeys(m, "/"+tt.query+"\r")
+		if len(m.Visible) != tt.want {
+			t.Errorf("search %q shows %d contact(s), want %d", tt.query, len(m.Visible), tt.want)
+		}
+		if m.Mode != ModeList {
+			t.Errorf("search %q left mode %v", tt.query, m.Mode)
+		}
+	}
+
+	m := New(testContacts())
+	typeKeys(m, "/doe\x7f\x7f\x7f")
+	if len(m.Visible) != 3 {
+		t.Errorf("backspacing the query shows %d contact(s), want 3", len(m.Visible))
+	}
+}
+
+func TestMove(t *testing.T) {
+	m := New(testContacts())
+	typeKeys(m, "jjjj")
+	if m.Selected().ObjectID != "obj3" {
+		t.Errorf("moved past the end to %s", m.Selected().ObjectID)
+	}
+	typeKeys(m, "\x1b[A")
+	if m.Selected().ObjectID != "obj2" {
+		t.Errorf("up arrow selected %s", m.Selected().ObjectID)
+	}
+	typeKeys(m, "/roe\r")
+	if m.Selected().ObjectID != "obj2" {
+		t.Errorf("filter lost the selection: %s", m.Selected().ObjectID)
+	}
+	if a := typeKeys(m, "q"); a.Kind != ActionQuit {
+		t.Errorf("q = %v, want quit", a.Kind)
+	}
+}
+
+func TestEdit(t *testing.T) {
+	m := New(testContacts())
+	typeKeys(m, "/doe\r")
+	a := typeKeys(m, "eorganization\r")
+	if m.Mode != ModeEditValue || m.Input != "Acme" {
+		t.Fatalf("mode %v input %q, want the current value to edit", m.Mode, m.Input)
+	}
+	a = typeKeys(m, "\x7f\x7f\x7f\x7fInitech\r")
+	if a.Kind != ActionSave || a.Contact.Organization != "Initech" {
+		t.Fatalf("action = %+v, want save with new organization", a)
+	}
+	if m.Visible[0].Organization != "Acme" {
+		t.Error("edit changed the listed contact before it was saved")
+	}
+	typeKeys(m, "\x1b[B")
+	m.Replace(a.Contact)
+	if m.Selected().ObjectID != "obj3" {
+		t.Errorf("Replace() moved the selection to %s", m.Selected().ObjectID)
+	}
+	if m.Visible[0].Organization != "Initech" {
+		t.Error("Replace() did not update the list")
+	}
+
+	typeKeys(m, "enickname\r")
+	if m.Mode != ModeList || !strings.Contains(m.Status, "Unknown field") {
+		t.Errorf("unknown field: mode %v status %q", m.Mode, m.Status)
+	}
+
+	a = typeKeys(m, "eemail\r\x1b")
+	if a.Kind != ActionNone || m.Mode != ModeList {
+		t.Errorf("escape: action %v mode %v", a.Kind, m.Mode)
+	}
+}
+
+func TestMarkDuplicate(t *testing.T) {
+	m := New(testContacts())
+	typeKeys(m, "jjd")
+	if m.Marked == nil || m.Marked.ObjectID != "obj3" {
+		t.Fatalf("Marked = %v", m.Marked)
+	}
+	typeKeys(m, "ggd")
+	if m.Mode != ModeConfirmMerge {
+		t.Fatalf("mode = %v, want confirm", m.Mode)
+	}
+	a := typeKeys(m, "y")
+	if a.Kind != ActionMerge || a.Contact.ObjectID != "obj1" || a.Duplicate.ObjectID != "obj3" {
+		t.Fatalf("action = %+v", a)
+	}
+	m.Remove("obj3")
+	if m.Marked != nil || len(m.Visible) != 2 {
+		t.Errorf("after merge: marked %v, %d visible", m.Marked, len(m.Visible))
+	}
+
+	typeKeys(m, "dd")
+	if m.Marked != nil {
+		t.Error("pressing d twice on the same contact did not unmark it")
+	}
+	typeKeys(m, "djdn")
+	if m.Mode != ModeList || m.Status != "Merge cancelled" {
+		t.Errorf("cancel: mode %v status %q", m.Mode, m.Status)
+	}
+}
+
+func TestRender(t *testing.T) {
+	m := New(testContacts())
+	lines := m.Render(60, 8)
+	if len(lines) != 8 {
+		t.Fatalf("Render() = %d lines, want 8", len(lines))
+	}
+	out := strings.Join(lines, "\n")
+	for _, want := range []string{"Contacts 3/3", "Jane Doe", "jane@example.com", "Acme", Help[:10]} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Render() missing %q:\n%s", want, out)
+		}
+	}
+
+	m = New(nil)
+	if out := strings.Join(m.Render(60, 8), "\n"); !strings.Contains(out, "No contacts") {
+		t.Errorf("empty Render() = %s", out)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []Key
+	}{
+		{"a", []Key{{Rune: 'a'}}},
+		{"é", []Key{{Rune: 'é'}}},
+		{"\x1b", []Key{{Code: KeyEsc}}},
+		{"\x1b[A\x1b[6~", []Key{{Code: KeyUp}, {Code: KeyPageDown}}},
+		{"\r\x7f\x03", []Key{{Code: KeyEnter}, {Code: KeyBackspace}, {Code: KeyCtrlC}}},
+		{"\x1b[99Z", []Key{{Code: KeyUnknown}}},
+	}
+	for _, tt := range tests {
+		got := ParseKeys([]byte(tt.in))
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseKeys(%q) = %v, want %v", tt.in, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("ParseKeys(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		}
+	}
+}
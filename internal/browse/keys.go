@@ -0,0 +1,97 @@
+package browse
+
+import "unicode/utf8"
+
+// KeyCode identifies a special key
+type KeyCode int
+
+const (
+	KeyRune KeyCode = iota // A printable character in Key.Rune
+	KeyUp
+	KeyDown
+	KeyPageUp
+	KeyPageDown
+	KeyHome
+	KeyEnd
+	KeyEnter
+	KeyEsc
+	KeyBackspace
+	KeyCtrlC
+	KeyUnknown
+)
+
+// Key is a decoded key press
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+var escapeSequences = map[string]KeyCode{
+	"\x1b[A":  KeyUp,
+	"\x1b[B":  KeyDown,
+	"\x1bOA":  KeyUp,
+	"\x1bOB":  KeyDown,
+	"\x1b[5~": KeyPageUp,
+	"\x1b[6~": KeyPageDown,
+	"\x1b[H":  KeyHome,
+	"\x1b[F":  KeyEnd,
+	"\x1b[1~": KeyHome,
+	"\x1b[4~": KeyEnd,
+}
+
+// ParseKeys decodes the bytes of one read from a terminal in raw mode
+func ParseKeys(b []byte) []Key {
+	var keys []Key
+	for len(b) > 0 {
+		if b[0] == 0x1b {
+			if len(b) == 1 {
+				return append(keys, Key{Code: KeyEsc})
+			}
+			n := sequenceLength(b)
+			code, ok := escapeSequences[string(b[:n])]
+			switch {
+			case n == 1:
+				code = KeyEsc
+			case !ok:
+				code = KeyUnknown
+			}
+			keys = append(keys, Key{Code: code})
+			b = b[n:]
+			continue
+		}
+
+		switch b[0] {
+		case '\r', '\n':
+			keys = append(keys, Key{Code: KeyEnter})
+		case 0x7f, 0x08:
+			keys = append(keys, Key{Code: KeyBackspace})
+		case 0x03:
+			keys = append(keys, Key{Code: KeyCtrlC})
+		default:
+			r, size := utf8.DecodeRune(b)
+			if r < 0x20 || r == utf8.RuneError {
+				keys = append(keys, Key{Code: KeyUnknown})
+			} else {
+				keys = append(keys, Key{Rune: r})
+			}
+			b = b[size:]
+			continue
+		}
+		b = b[1:]
+	}
+	return keys
+}
+
+// sequenceLength returns the length of the escape sequence at the start
+// of b: ESC, an optional [ or O, parameters, and a final letter or ~
+func sequenceLength(b []byte) int {
+	if b[1] != '[' && b[1] != 'O' {
+		return 1
+	}
+	for i := 2; i < len(b); i++ {
+		if c := b[i]; c >= 0x40 && c <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(b)
+}
@@ -0,0 +1,76 @@
+// Package config loads the optional any-vcard configuration file, a JSON
+// document with the settings too structured for command line flags, such
+// as the per-domain email normalization rules and the import blocklist:
+//
+//	{
+//	  "email": {
+//	    "keep_tags": false,
+//	    "domains": {
+//	      "example.com": {"ignore_dots": true},
+//	      "fastmail.com": {"tag_separators": "+-"}
+//	    },
+//	    "aliases": {"oldcorp.com": "newcorp.com"},
+//	    "role_accounts": ["frontdesk"],
+//	    "disposable_domains": ["burner.example"]
+//	  },
+//	  "blocklist": {
+//	    "phones": ["+34 900 123 456"],
+//	    "emails": ["@spam.example"],
+//	    "names": ["^Spam Risk"]
+//	  }
+//	}
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// Config is the content of the configuration file
+type Config struct {
+	Email     vcard.EmailRules `json:"email"`     // How email addresses are compared for dedup
+	Blocklist vcard.Blocklist  `json:"blocklist"` // Contacts import drops
+}
+
+// DefaultPath returns the configuration file location under the user
+// config dir
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "any-vcard", "config.json"), nil
+}
+
+// Load reads the configuration file at path. A missing file is an empty
+// configuration unless required is set.
+func Load(path string, required bool) (*Config, error) {
+	cfg := &Config{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	// Unknown keys are most likely typos, which would silently do nothing
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if err := cfg.Email.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if err := cfg.Blocklist.Compile(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
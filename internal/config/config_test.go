@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	cfg, err := Load(write("config.json", `{"email": {"keep_tags": true, "domains": {"example.com": {"ignore_dots": true}}}}`), true)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.Email.KeepTags || !cfg.Email.Domains["example.com"].IgnoreDots {
+		t.Errorf("Load() = %+v", cfg)
+	}
+
+	cfg, err = Load(write("blocklist.json", `{"blocklist": {"names": ["^spam"]}}`), true)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.Blocklist.Blocks(&vcard.Contact{FormattedName: "SPAM Risk"}, vcard.DedupOptions{}) {
+		t.Errorf("Load() blocklist doesn't block a matching name: %+v", cfg.Blocklist)
+	}
+
+	missing := filepath.Join(dir, "missing.json")
+	if cfg, err := Load(missing, false); err != nil || cfg.Email.KeepTags || len(cfg.Email.Domains) != 0 {
+		t.Errorf("Load() of a missing optional file = %+v, %v", cfg, err)
+	}
+	if _, err := Load(missing, true); err == nil {
+		t.Error("Load() of a missing required file succeeded")
+	}
+
+	for name, content := range map[string]string{
+		"typo.json":      `{"email": {"keep_tag": true}}`,
+		"invalid.json":   `{"email": {"domains": {"example.com": {"tag_separators": "."}}}}`,
+		"malformed.json": `{"email": `,
+		"pattern.json":   `{"blocklist": {"names": ["Spam ("]}}`,
+	} {
+		if _, err := Load(write(name, content), true); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("Load(%s) error = %v, want one naming the file", name, err)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Package dryrun wraps an Anytype client so commands can preview their
+// changes: reads reach the server, while every mutation (creating spaces,
+// types, properties and objects, updating and deleting objects) is printed
+// instead of sent and answered with a made-up response.
+package dryrun
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rubiojr/anytype-go"
+)
+
+// IDPrefix starts the IDs of the objects, types and spaces a dry run
+// pretends to create
+const IDPrefix = "dry-run-"
+
+// Client is an anytype.Client printing mutations to W instead of making
+// them
+type Client struct {
+	anytype.Client
+	w io.Writer
+
+	mu   sync.Mutex
+	next int // Number of the next made-up ID
+}
+
+// Wrap returns client printing the mutations it would make to w
+func Wrap(client anytype.Client, w io.Writer) *Client {
+	return &Client{Client: client, w: w}
+}
+
+// printf writes a planned mutation
+func (c *Client) printf(format string, args ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fmt.Fprintf(c.w, "[dry-run] "+format+"\n", args...)
+}
+
+// newID returns a made-up ID
+func (c *Client) newID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.next++
+	return fmt.Sprintf("%s%d", IDPrefix, c.next)
+}
+
+// Spaces implements anytype.Client
+func (c *Client) Spaces() anytype.SpacesClient {
+	return &spaces{SpacesClient: c.Client.Spaces(), c: c}
+}
+
+// Space implements anytype.Client
+func (c *Client) Space(id string) anytype.SpaceClient {
+	return &space{SpaceClient: c.Client.Space(id), c: c, id: id}
+}
+
+type spaces struct {
+	anytype.SpacesClient
+	c *Client
+}
+
+func (s *spaces) Create(ctx context.Context, req anytype.CreateSpaceRequest) (*anytype.SpaceResponse, error) {
+	s.c.printf("create space %q", req.Name)
+	return &anytype.SpaceResponse{Space: anytype.Space{ID: s.c.newID(), Name: req.Name, Description: req.Description}}, nil
+}
+
+type space struct {
+	anytype.SpaceClient
+	c  *Client
+	id string
+}
+
+func (s *space) Properties() anytype.PropertiesClient {
+	return &properties{PropertiesClient: s.SpaceClient.Properties(), s: s}
+}
+
+func (s *space) Types() anytype.TypesClient {
+	return &types{TypesClient: s.SpaceClient.Types(), s: s}
+}
+
+func (s *space) Objects() anytype.ObjectsClient {
+	return &objects{ObjectsClient: s.SpaceClient.Objects(), s: s}
+}
+
+func (s *space) Object(id string) anytype.ObjectClient {
+	return &object{ObjectClient: s.SpaceClient.Object(id), s: s, id: id}
+}
+
+type properties struct {
+	anytype.PropertiesClient
+	s *space
+}
+
+func (p *properties) Create(ctx context.Context, req anytype.CreatePropertyRequest) (*anytype.PropertyResponse, error) {
+	p.s.c.printf("create property %q (key %s, %s) in space %s", req.Name, req.Key, req.Format, p.s.id)
+	return &anytype.PropertyResponse{Property: anytype.Property{Key: req.Key, Name: req.Name, Format: req.Format}}, nil
+}
+
+type types struct {
+	anytype.TypesClient
+	s *space
+}
+
+func (t *types) Create(ctx context.Context, req anytype.CreateTypeRequest) (*anytype.TypeResponse, error) {
+	t.s.c.printf("create type %q (key %s) with properties %s in space %s", req.Name, req.Key, definitionKeys(req.Properties), t.s.id)
+	return &anytype.TypeResponse{Type: anytype.Type{ID: t.s.c.newID(), Key: req.Key, Name: req.Name, PropertyDefinitions: req.Properties}}, nil
+}
+
+type objects struct {
+	anytype.ObjectsClient
+	s *space
+}
+
+func (o *objects) Create(ctx context.Context, req anytype.CreateObjectRequest) (*anytype.ObjectResponse, error) {
+	o.s.c.printf("create %s object %q with %s in space %s", req.TypeKey, req.Name, propertyKeys(req.Properties), o.s.id)
+	return &anytype.ObjectResponse{Object: anytype.Object{ID: o.s.c.newID(), Name: req.Name, SpaceID: o.s.id, Icon: req.Icon}}, nil
+}
+
+type object struct {
+	anytype.ObjectClient
+	s  *space
+	id string
+}
+
+func (o *object) Update(ctx context.Context, req anytype.UpdateObjectRequest) error {
+	what := propertyKeys(req.Properties)
+	if req.Name != "" {
+		what = fmt.Sprintf("name %q, %s", req.Name, what)
+	}
+	o.s.c.printf("update object %s: %s", o.id, what)
+	return nil
+}
+
+func (o *object) Delete(ctx context.Context) error {
+	o.s.c.printf("delete object %s", o.id)
+	return nil
+}
+
+// propertyKeys lists the keys of property values, sorted
+func propertyKeys(props []map[string]any) string {
+	var keys []string
+	for _, p := range props {
+		if key, ok := p["key"].(string); ok {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return "no properties"
+	}
+	slices.Sort(keys)
+	return "properties " + strings.Join(keys, ", ")
+}
+
+// definitionKeys lists the keys of property definitions
+func definitionKeys(defs []anytype.PropertyDefinition) string {
+	keys := make([]string, len(defs))
+	for i, d := range defs {
+		keys[i] = d.Key
+	}
+	return strings.Join(keys, ", ")
+}
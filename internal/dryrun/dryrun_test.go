@@ -0,0 +1,120 @@
+package dryrun
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rubiojr/anytype-go"
+)
+
+// fakeClient fails the test on any call reaching the server but Get
+type fakeClient struct {
+	anytype.Client
+	t *testing.T
+}
+
+func (f *fakeClient) Space(id string) anytype.SpaceClient { return &fakeSpace{t: f.t} }
+func (f *fakeClient) Spaces() anytype.SpacesClient        { return &fakeSpaces{t: f.t} }
+
+type fakeSpaces struct {
+	anytype.SpacesClient
+	t *testing.T
+}
+
+func (f *fakeSpaces) Create(ctx context.Context, req anytype.CreateSpaceRequest) (*anytype.SpaceResponse, error) {
+	f.t.Error("space created")
+	return nil, nil
+}
+
+type fakeSpace struct {
+	anytype.SpaceClient
+	t *testing.T
+}
+
+func (f *fakeSpace) Objects() anytype.ObjectsClient        { return &fakeObjects{t: f.t} }
+func (f *fakeSpace) Object(id string) anytype.ObjectClient { return &fakeObject{t: f.t, id: id} }
+func (f *fakeSpace) Properties() anytype.PropertiesClient  { return &fakeProperties{t: f.t} }
+
+type fakeObjects struct {
+	anytype.ObjectsClient
+	t *testing.T
+}
+
+func (f *fakeObjects) Create(ctx context.Context, req anytype.CreateObjectRequest) (*anytype.ObjectResponse, error) {
+	f.t.Error("object created")
+	return nil, nil
+}
+
+type fakeObject struct {
+	anytype.ObjectClient
+	t  *testing.T
+	id string
+}
+
+func (f *fakeObject) Get(ctx context.Context) (*anytype.ObjectResponse, error) {
+	return &anytype.ObjectResponse{Object: anytype.Object{ID: f.id, Name: "Jane Doe"}}, nil
+}
+
+func (f *fakeObject) Update(ctx context.Context, req anytype.UpdateObjectRequest) error {
+	f.t.Error("object updated")
+	return nil
+}
+
+func (f *fakeObject) Delete(ctx context.Context) error {
+	f.t.Error("object deleted")
+	return nil
+}
+
+type fakeProperties struct {
+	anytype.PropertiesClient
+	t *testing.T
+}
+
+func (f *fakeProperties) Create(ctx context.Context, req anytype.CreatePropertyRequest) (*anytype.PropertyResponse, error) {
+	f.t.Error("property created")
+	return nil, nil
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	var out strings.Builder
+	client := Wrap(&fakeClient{t: t}, &out)
+
+	resp, err := client.Space("sp").Object("obj1").Get(ctx)
+	if err != nil || resp.Object.Name != "Jane Doe" {
+		t.Fatalf("Get() = %v, %v; reads must reach the server", resp, err)
+	}
+
+	created, err := client.Space("sp").Objects().Create(ctx, anytype.CreateObjectRequest{
+		TypeKey:    "contact",
+		Name:       "John Doe",
+		Properties: []map[string]any{{"key": "phone", "phone": "1"}, {"key": "email", "email": "j@x"}},
+	})
+	if err != nil || !strings.HasPrefix(created.Object.ID, IDPrefix) {
+		t.Errorf("Create() = %v, %v", created, err)
+	}
+	if err := client.Space("sp").Object("obj1").Update(ctx, anytype.UpdateObjectRequest{Properties: []map[string]any{{"key": "title", "text": "CTO"}}}); err != nil {
+		t.Error(err)
+	}
+	if err := client.Space("sp").Object("obj2").Delete(ctx); err != nil {
+		t.Error(err)
+	}
+	prop, err := client.Space("sp").Properties().Create(ctx, anytype.CreatePropertyRequest{Key: "phone2", Name: "Phone 2", Format: "phone"})
+	if err != nil || prop.Property.Key != "phone2" {
+		t.Errorf("Properties().Create() = %v, %v", prop, err)
+	}
+	if _, err := client.Spaces().Create(ctx, anytype.CreateSpaceRequest{Name: "Contacts"}); err != nil {
+		t.Error(err)
+	}
+
+	want := `[dry-run] create contact object "John Doe" with properties email, phone in space sp
+[dry-run] update object obj1: properties title
+[dry-run] delete object obj2
+[dry-run] create property "Phone 2" (key phone2, phone) in space sp
+[dry-run] create space "Contacts"
+`
+	if out.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,96 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Cache keeps the results of every source on disk so repeated imports
+// don't query the public services again. Lookups finding nothing are
+// cached too.
+type Cache struct {
+	path    string
+	entries map[string]*Result // By source name and key
+	dirty   bool
+}
+
+// DefaultCachePath returns the cache file location under the user cache dir
+func DefaultCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "any-vcard", "enrich.json"), nil
+}
+
+// NewCache loads the cache file at path, if present
+func NewCache(path string) (*Cache, error) {
+	c := &Cache{path: path, entries: make(map[string]*Result)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read enrichment cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse enrichment cache %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Wrap returns source consulting the cache first
+func (c *Cache) Wrap(name string, source Source) Source {
+	return cachedSource{c, name, source}
+}
+
+type cachedSource struct {
+	cache  *Cache
+	name   string
+	source Source
+}
+
+// Lookup implements Source
+func (s cachedSource) Lookup(ctx context.Context, key string) (*Result, error) {
+	k := s.name + ":" + key
+	if res, ok := s.cache.entries[k]; ok {
+		return res, nil
+	}
+	res, err := s.source.Lookup(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.entries[k] = res
+	s.cache.dirty = true
+	return res, nil
+}
+
+// Save writes the cache back to disk if it changed
+func (c *Cache) Save() error {
+	if !c.dirty {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write enrichment cache: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to write enrichment cache: %w", err)
+	}
+	c.dirty = false
+	return nil
+}
@@ -0,0 +1,132 @@
+// Package enrich fills in the details sparse contacts lack from public
+// sources: Gravatar profiles, the logo of an organization's email domain
+// and the registrant organization of that domain in WHOIS (RDAP).
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// Source names
+const (
+	SourceGravatar = "gravatar" // Photo, organization and title of the profile of an email
+	SourceLogo     = "logo"     // Favicon of the email domain of organization contacts
+	SourceWhois    = "whois"    // Registrant organization of the email domain
+)
+
+// Sources lists the supported sources
+var Sources = []string{SourceGravatar, SourceLogo, SourceWhois}
+
+// userAgent identifies any-vcard to the public services
+const userAgent = "any-vcard (https://github.com/rubiojr/any-vcard)"
+
+// Result is what a source knows about an email address or a domain
+type Result struct {
+	Photo        string `json:"photo,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	Title        string `json:"title,omitempty"`
+}
+
+// Source looks up an email address or a domain. Implementations return
+// (nil, nil) when they know nothing about it.
+type Source interface {
+	Lookup(ctx context.Context, key string) (*Result, error)
+}
+
+// NewSource returns the source registered under name
+func NewSource(name string) (Source, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	switch strings.ToLower(name) {
+	case SourceGravatar:
+		return &Gravatar{BaseURL: DefaultGravatarURL, HTTPClient: client}, nil
+	case SourceLogo:
+		return &Logo{BaseURL: DefaultLogoURL, HTTPClient: client}, nil
+	case SourceWhois:
+		return &RDAP{BaseURL: DefaultRDAPURL, HTTPClient: client}, nil
+	default:
+		return nil, fmt.Errorf("unknown enrichment source %q (supported: %s)", name, strings.Join(Sources, ", "))
+	}
+}
+
+// Enricher fills in the empty fields of contacts from its sources, by
+// source name. Existing values are never replaced.
+type Enricher struct {
+	Sources map[string]Source
+	Email   vcard.EmailRules // Role accounts and disposable domains not looked up
+}
+
+// Enrich looks up the contact in every source that can fill in one of its
+// empty fields and records each value filled in, and where it came from,
+// in Enriched. Lookup errors are returned joined, after the other sources
+// had their chance.
+func (e *Enricher) Enrich(ctx context.Context, c *vcard.Contact) error {
+	var errs []error
+	lookup := func(name, key string, apply func(*Result)) {
+		source := e.Sources[name]
+		if source == nil || key == "" {
+			return
+		}
+		res, err := source.Lookup(ctx, key)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s lookup of %s: %w", name, key, err))
+			return
+		}
+		if res != nil {
+			apply(res)
+		}
+	}
+	fill := func(field string, dst *string, value, origin string) {
+		if *dst != "" || value == "" {
+			return
+		}
+		*dst = value
+		c.Enriched = append(c.Enriched, vcard.Enrichment{Field: field, Value: value, Origin: origin})
+	}
+
+	if c.Photo == "" || c.Organization == "" || c.Title == "" {
+		for _, email := range c.Emails {
+			if e.Email.IsRole(email) || e.Email.IsDisposable(email) {
+				continue
+			}
+			found := false
+			lookup(SourceGravatar, strings.ToLower(strings.TrimSpace(email)), func(r *Result) {
+				origin := "Gravatar " + email
+				fill("photo", &c.Photo, r.Photo, origin)
+				fill("organization", &c.Organization, r.Organization, origin)
+				fill("title", &c.Title, r.Title, origin)
+				found = true
+			})
+			if found {
+				break
+			}
+		}
+	}
+
+	domain := organizationDomain(c)
+	if c.Kind == vcard.KindOrganization && c.Photo == "" {
+		lookup(SourceLogo, domain, func(r *Result) { fill("photo", &c.Photo, r.Photo, "logo of "+domain) })
+	}
+	if c.Organization == "" {
+		lookup(SourceWhois, domain, func(r *Result) { fill("organization", &c.Organization, r.Organization, "WHOIS "+domain) })
+	}
+	return errors.Join(errs...)
+}
+
+// organizationDomain returns the domain of the first email of the contact
+// at an organization rather than a mailbox provider, or ""
+func organizationDomain(c *vcard.Contact) string {
+	i := slices.IndexFunc(c.Emails, func(email string) bool { return strings.Contains(email, "@") && !vcard.IsProviderEmail(email) })
+	if i == -1 {
+		return ""
+	}
+	_, domain, _ := strings.Cut(strings.ToLower(strings.TrimSpace(c.Emails[i])), "@")
+	return domain
+}
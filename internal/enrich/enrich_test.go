@@ -0,0 +1,169 @@
+package enrich
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// fakeSource returns fixed results by key and counts calls
+type fakeSource struct {
+	results map[string]*Result
+	calls   int
+}
+
+func (s *fakeSource) Lookup(ctx context.Context, key string) (*Result, error) {
+	s.calls++
+	return s.results[key], nil
+}
+
+func TestGravatar_Lookup(t *testing.T) {
+	// SHA-256 of "jane@example.com"
+	const hash = "8c87b489ce35cf2e2f39f80e282cb2e804932a56a213983eeeb428407d43b52d"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/profiles/"+hash {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"avatar_url":"https://gravatar.com/avatar/x","company":"Acme","job_title":"CTO"}`)
+	}))
+	defer srv.Close()
+
+	g := &Gravatar{BaseURL: srv.URL, HTTPClient: srv.Client()}
+	res, err := g.Lookup(context.Background(), " Jane@Example.com")
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if res == nil || res.Photo != "https://gravatar.com/avatar/x" || res.Organization != "Acme" || res.Title != "CTO" {
+		t.Errorf("Lookup() = %+v", res)
+	}
+
+	res, err = g.Lookup(context.Background(), "nobody@example.com")
+	if err != nil || res != nil {
+		t.Errorf("Lookup(nobody) = %+v, %v; want nil, nil", res, err)
+	}
+}
+
+func TestRDAP_Lookup(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/domain/acme.com":
+			fmt.Fprint(w, `{"entities":[{"roles":["registrar"],"entities":[
+				{"roles":["registrant"],"vcardArray":["vcard",[["version",{},"text","4.0"],["org",{},"text","Acme Inc."]]]}]}]}`)
+		case "/domain/private.com":
+			fmt.Fprint(w, `{"entities":[{"roles":["registrant"],"vcardArray":["vcard",[["org",{},"text","REDACTED FOR PRIVACY"]]]}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	r := &RDAP{BaseURL: srv.URL, HTTPClient: srv.Client()}
+	tests := []struct {
+		domain string
+		want   string
+	}{
+		{"acme.com", "Acme Inc."},
+		{"private.com", ""},
+		{"unknown.com", ""},
+	}
+	for _, tt := range tests {
+		res, err := r.Lookup(context.Background(), tt.domain)
+		if err != nil {
+			t.Fatalf("Lookup(%s) error = %v", tt.domain, err)
+		}
+		got := ""
+		if res != nil {
+			got = res.Organization
+		}
+		if got != tt.want {
+			t.Errorf("Lookup(%s) = %q, want %q", tt.domain, got, tt.want)
+		}
+	}
+}
+
+func TestEnricher_Enrich(t *testing.T) {
+	gravatar := &fakeSource{results: map[string]*Result{
+		"jane@acme.com": {Photo: "https://gravatar.com/avatar/jane", Organization: "Acme Gravatar", Title: "CTO"},
+	}}
+	logo := &fakeSource{results: map[string]*Result{"acme.com": {Photo: "https://icons.example/acme.ico"}}}
+	whois := &fakeSource{results: map[string]*Result{"acme.com": {Organization: "Acme Inc."}}}
+	e := &Enricher{Sources: map[string]Source{SourceGravatar: gravatar, SourceLogo: logo, SourceWhois: whois}}
+
+	jane := vcard.Contact{FormattedName: "Jane", Title: "Engineer", Emails: []string{"info@acme.com", "jane@acme.com"}}
+	if err := e.Enrich(context.Background(), &jane); err != nil {
+		t.Fatal(err)
+	}
+	if jane.Photo != "https://gravatar.com/avatar/jane" || jane.Organization != "Acme Gravatar" || jane.Title != "Engineer" {
+		t.Errorf("Enrich() = %+v", jane)
+	}
+	if len(jane.Enriched) != 2 || jane.Enriched[1] != (vcard.Enrichment{Field: "organization", Value: "Acme Gravatar", Origin: "Gravatar jane@acme.com"}) {
+		t.Errorf("Enriched = %+v", jane.Enriched)
+	}
+	if whois.calls != 0 || logo.calls != 0 {
+		t.Errorf("looked up %d whois and %d logo after Gravatar filled the fields", whois.calls, logo.calls)
+	}
+
+	acme := vcard.Contact{FormattedName: "Acme", Kind: vcard.KindOrganization, Emails: []string{"sales@acme.com"}}
+	if err := e.Enrich(context.Background(), &acme); err != nil {
+		t.Fatal(err)
+	}
+	if acme.Photo != "https://icons.example/acme.ico" || acme.Organization != "Acme Inc." {
+		t.Errorf("Enrich() = %+v", acme)
+	}
+
+	personal := vcard.Contact{FormattedName: "Joe", Emails: []string{"joe@gmail.com"}}
+	if err := e.Enrich(context.Background(), &personal); err != nil {
+		t.Fatal(err)
+	}
+	if personal.Organization != "" || len(personal.Enriched) != 0 {
+		t.Errorf("Enrich() looked up a mailbox provider domain: %+v", personal)
+	}
+}
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "enrich.json")
+	source := &fakeSource{results: map[string]*Result{"acme.com": {Organization: "Acme Inc."}}}
+
+	cache, err := NewCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached := cache.Wrap(SourceWhois, source)
+	for range 2 {
+		cached.Lookup(context.Background(), "acme.com")
+		cached.Lookup(context.Background(), "unknown.com")
+	}
+	if source.calls != 2 {
+		t.Errorf("source called %d times, want 2", source.calls)
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, _ := reloaded.Wrap(SourceWhois, source).Lookup(context.Background(), "acme.com")
+	if res == nil || res.Organization != "Acme Inc." || source.calls != 2 {
+		t.Errorf("reloaded cache Lookup() = %+v after %d calls", res, source.calls)
+	}
+}
+
+func TestNewSource(t *testing.T) {
+	for _, name := range Sources {
+		if _, err := NewSource(name); err != nil {
+			t.Errorf("NewSource(%s) error = %v", name, err)
+		}
+	}
+	if _, err := NewSource("clearbit"); err == nil || !strings.Contains(err.Error(), "gravatar") {
+		t.Errorf("NewSource(clearbit) error = %v", err)
+	}
+}
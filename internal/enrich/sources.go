@@ -0,0 +1,174 @@
+package enrich
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Default endpoints of the public sources
+const (
+	DefaultGravatarURL = "https://api.gravatar.com"
+	DefaultLogoURL     = "https://icons.duckduckgo.com"
+	DefaultRDAPURL     = "https://rdap.org"
+)
+
+// get fetches url, returning nil without an error when the resource
+// doesn't exist
+func get(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, nil
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s returned status %d", resp.Request.URL.Host, resp.StatusCode)
+	}
+}
+
+// getJSON decodes the JSON document at url into v, reporting whether it
+// exists
+func getJSON(ctx context.Context, client *http.Client, url string, v any) (bool, error) {
+	resp, err := get(ctx, client, url)
+	if err != nil || resp == nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return false, fmt.Errorf("failed to decode %s response: %w", resp.Request.URL.Host, err)
+	}
+	return true, nil
+}
+
+// Gravatar looks up the public Gravatar profile of an email address
+type Gravatar struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+type gravatarProfile struct {
+	AvatarURL string `json:"avatar_url"`
+	Company   string `json:"company"`
+	JobTitle  string `json:"job_title"`
+}
+
+// Lookup implements Source
+func (g *Gravatar) Lookup(ctx context.Context, email string) (*Result, error) {
+	hash := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	var p gravatarProfile
+	found, err := getJSON(ctx, g.HTTPClient, g.BaseURL+"/v3/profiles/"+hex.EncodeToString(hash[:]), &p)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &Result{Photo: p.AvatarURL, Organization: strings.TrimSpace(p.Company), Title: strings.TrimSpace(p.JobTitle)}, nil
+}
+
+// Logo looks up the favicon of a domain
+type Logo struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// Lookup implements Source
+func (l *Logo) Lookup(ctx context.Context, domain string) (*Result, error) {
+	u := l.BaseURL + "/ip3/" + url.PathEscape(domain) + ".ico"
+	resp, err := get(ctx, l.HTTPClient, u)
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	return &Result{Photo: u}, nil
+}
+
+// RDAP looks up the registrant organization of a domain through RDAP,
+// the JSON successor of WHOIS
+type RDAP struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+type rdapEntity struct {
+	Roles      []string     `json:"roles"`
+	VCardArray []any        `json:"vcardArray"` // ["vcard", [[name, params, type, value], ...]]
+	Entities   []rdapEntity `json:"entities"`
+}
+
+// redacted are fragments of the placeholders registrars publish instead
+// of the registrant's details
+var redacted = []string{"redacted", "privacy", "not disclosed", "withheld", "data protected", "proxy"}
+
+// Lookup implements Source
+func (r *RDAP) Lookup(ctx context.Context, domain string) (*Result, error) {
+	var resp struct {
+		Entities []rdapEntity `json:"entities"`
+	}
+	found, err := getJSON(ctx, r.HTTPClient, r.BaseURL+"/domain/"+url.PathEscape(domain), &resp)
+	if err != nil || !found {
+		return nil, err
+	}
+	if org := registrantOrg(resp.Entities); org != "" {
+		return &Result{Organization: org}, nil
+	}
+	return nil, nil
+}
+
+// registrantOrg returns the organization of the registrant among entities,
+// unless it is redacted
+func registrantOrg(entities []rdapEntity) string {
+	for _, e := range entities {
+		if slices.Contains(e.Roles, "registrant") {
+			org := vcardValue(e.VCardArray, "org")
+			lower := strings.ToLower(org)
+			if org != "" && !slices.ContainsFunc(redacted, func(r string) bool { return strings.Contains(lower, r) }) {
+				return org
+			}
+		}
+		if org := registrantOrg(e.Entities); org != "" {
+			return org
+		}
+	}
+	return ""
+}
+
+// vcardValue returns the text value of a property of a jCard
+func vcardValue(card []any, name string) string {
+	if len(card) != 2 {
+		return ""
+	}
+	props, _ := card[1].([]any)
+	for _, p := range props {
+		prop, _ := p.([]any)
+		if len(prop) < 4 || prop[0] != name {
+			continue
+		}
+		switch v := prop[3].(type) {
+		case string:
+			return strings.TrimSpace(v)
+		case []any: // Structured ORG: organization name first
+			if len(v) > 0 {
+				s, _ := v[0].(string)
+				return strings.TrimSpace(s)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Cache wraps a Provider with an on-disk JSON cache so repeated imports
+// don't hit the provider again. Unresolvable addresses are cached too.
+type Cache struct {
+	provider Provider
+	path     string
+	entries  map[string]*Location
+	dirty    bool
+}
+
+// DefaultCachePath returns the cache file location under the user cache dir
+func DefaultCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "any-vcard", "geocode.json"), nil
+}
+
+// NewCache loads the cache file at path (if present) in front of provider
+func NewCache(provider Provider, path string) (*Cache, error) {
+	c := &Cache{
+		provider: provider,
+		path:     path,
+		entries:  make(map[string]*Location),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read geocode cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse geocode cache %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Geocode implements Provider, consulting the cache first
+func (c *Cache) Geocode(ctx context.Context, query string) (*Location, error) {
+	key := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	if loc, ok := c.entries[key]; ok {
+		return loc, nil
+	}
+
+	loc, err := c.provider.Geocode(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	c.entries[key] = loc
+	c.dirty = true
+	return loc, nil
+}
+
+// Save writes the cache back to disk if it changed
+func (c *Cache) Save() error {
+	if !c.dirty {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write geocode cache: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to write geocode cache: %w", err)
+	}
+	c.dirty = false
+	return nil
+}
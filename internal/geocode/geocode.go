@@ -0,0 +1,49 @@
+package geocode
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Location is a resolved pair of coordinates
+type Location struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// MapURL returns an OpenStreetMap link centered on the location
+func (l Location) MapURL() string {
+	q := url.Values{}
+	q.Set("mlat", fmt.Sprintf("%.6f", l.Lat))
+	q.Set("mlon", fmt.Sprintf("%.6f", l.Lon))
+	return "https://www.openstreetmap.org/?" + q.Encode() + fmt.Sprintf("#map=16/%.6f/%.6f", l.Lat, l.Lon)
+}
+
+// Provider resolves a free-form address to coordinates.
+// Implementations return (nil, nil) when the address cannot be found.
+type Provider interface {
+	Geocode(ctx context.Context, query string) (*Location, error)
+}
+
+// NewProvider returns the provider registered under name
+func NewProvider(name, baseURL string) (Provider, error) {
+	switch strings.ToLower(name) {
+	case "", "nominatim":
+		return NewNominatim(baseURL), nil
+	default:
+		return nil, fmt.Errorf("unknown geocoding provider %q (supported: nominatim)", name)
+	}
+}
+
+// Query builds a provider query from address components, skipping empty ones
+func Query(parts ...string) string {
+	var nonEmpty []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, ", ")
+}
@@ -0,0 +1,113 @@
+package geocode
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// countingProvider returns a fixed location and counts calls
+type countingProvider struct {
+	calls int
+	loc   *Location
+}
+
+func (p *countingProvider) Geocode(ctx context.Context, query string) (*Location, error) {
+	p.calls++
+	return p.loc, nil
+}
+
+func TestQuery(t *testing.T) {
+	got := Query("123 Main St", "", " Springfield ", "IL", "")
+	if got != "123 Main St, Springfield, IL" {
+		t.Errorf("Query() = %q", got)
+	}
+}
+
+func TestNominatim_Geocode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") == "" {
+			t.Error("missing User-Agent header")
+		}
+		if r.URL.Query().Get("q") == "nowhere" {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		fmt.Fprint(w, `[{"lat":"40.4168","lon":"-3.7038"}]`)
+	}))
+	defer srv.Close()
+
+	n := NewNominatim(srv.URL)
+	n.Interval = 0
+
+	loc, err := n.Geocode(context.Background(), "Madrid, Spain")
+	if err != nil {
+		t.Fatalf("Geocode() error = %v", err)
+	}
+	if loc == nil || loc.Lat != 40.4168 || loc.Lon != -3.7038 {
+		t.Errorf("Geocode() = %+v", loc)
+	}
+
+	loc, err = n.Geocode(context.Background(), "nowhere")
+	if err != nil || loc != nil {
+		t.Errorf("Geocode(nowhere) = %+v, %v; want nil, nil", loc, err)
+	}
+}
+
+func TestNominatim_RateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	}))
+	defer srv.Close()
+
+	n := NewNominatim(srv.URL)
+	n.Interval = 50 * time.Millisecond
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := n.Geocode(context.Background(), "x"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("3 calls took %v, expected at least 100ms between them", elapsed)
+	}
+}
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "geocode.json")
+	provider := &countingProvider{loc: &Location{Lat: 1, Lon: 2}}
+
+	cache, err := NewCache(provider, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range []string{"Madrid", "madrid", "  MADRID "} {
+		if _, err := cache.Geocode(context.Background(), q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if provider.calls != 1 {
+		t.Errorf("provider called %d times, want 1", provider.calls)
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reload from disk: no provider calls needed
+	reloaded, err := NewCache(provider, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc, err := reloaded.Geocode(context.Background(), "Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider.calls != 1 || loc == nil || loc.Lat != 1 {
+		t.Errorf("expected cached location, got %+v after %d calls", loc, provider.calls)
+	}
+}
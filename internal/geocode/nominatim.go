@@ -0,0 +1,110 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultNominatimURL is the public OpenStreetMap Nominatim endpoint
+const DefaultNominatimURL = "https://nominatim.openstreetmap.org"
+
+// nominatimInterval is the minimum delay between requests required by the
+// public Nominatim usage policy (max 1 request per second)
+const nominatimInterval = time.Second
+
+// Nominatim geocodes addresses using an OpenStreetMap Nominatim server
+type Nominatim struct {
+	BaseURL    string
+	UserAgent  string
+	HTTPClient *http.Client
+	Interval   time.Duration
+
+	mu       sync.Mutex
+	lastCall time.Time
+}
+
+// NewNominatim creates a Nominatim provider. An empty baseURL uses the public server.
+func NewNominatim(baseURL string) *Nominatim {
+	if baseURL == "" {
+		baseURL = DefaultNominatimURL
+	}
+	return &Nominatim{
+		BaseURL:    baseURL,
+		UserAgent:  "any-vcard (https://github.com/rubiojr/any-vcard)",
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		Interval:   nominatimInterval,
+	}
+}
+
+type nominatimResult struct {
+	Lat string `json:"lat"`
+	Lon string `json:"lon"`
+}
+
+// Geocode implements Provider
+func (n *Nominatim) Geocode(ctx context.Context, query string) (*Location, error) {
+	if err := n.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	q := url.Values{}
+	q.Set("q", query)
+	q.Set("format", "jsonv2")
+	q.Set("limit", "1")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.BaseURL+"/search?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", n.UserAgent)
+
+	resp, err := n.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("nominatim request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("nominatim returned status %d", resp.StatusCode)
+	}
+
+	var results []nominatimResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to decode nominatim response: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latitude %q: %w", results[0].Lat, err)
+	}
+	lon, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid longitude %q: %w", results[0].Lon, err)
+	}
+	return &Location{Lat: lat, Lon: lon}, nil
+}
+
+// wait blocks until the rate limit interval since the previous call has passed
+func (n *Nominatim) wait(ctx context.Context) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if delay := n.Interval - time.Since(n.lastCall); delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	n.lastCall = time.Now()
+	return nil
+}
@@ -0,0 +1,105 @@
+// Package hook runs user-provided executables around an import.
+//
+// Import hooks run once before and after the import with details passed as
+// ANYVCARD_* environment variables. Contact hooks run for every contact,
+// receive it as JSON on stdin and may print a modified contact on stdout;
+// exiting non-zero rejects the contact.
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// ErrRejected is returned when a contact hook rejects a contact
+var ErrRejected = errors.New("rejected by hook")
+
+// secretVars are the credentials of any-vcard, kept out of the environment
+// of hooks
+var secretVars = []string{"ANYTYPE_APP_KEY", "ANYVCARD_SERVER_TOKEN", "GOOGLE_CLIENT_SECRET", "LDAP_BIND_PASSWORD"}
+
+// Hook is an executable run at a point of the import
+type Hook struct {
+	Path   string
+	Stdout io.Writer // Output of import hooks, os.Stdout when nil
+	Stderr io.Writer // Errors of import hooks, os.Stderr when nil
+}
+
+// Run executes an import hook with the given KEY=value variables added to
+// its environment
+func (h Hook) Run(ctx context.Context, env map[string]string) error {
+	cmd := exec.CommandContext(ctx, h.Path)
+	cmd.Env = environ(env)
+	cmd.Stdout = h.Stdout
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
+	cmd.Stderr = h.Stderr
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("hook %s failed: %w", h.Path, err)
+	}
+	return nil
+}
+
+// Contact runs a contact hook on c, replacing it with the contact printed by
+// the hook, if any. Rejections wrap ErrRejected with the hook's stderr.
+func (h Hook) Contact(ctx context.Context, c *vcard.Contact, env map[string]string) error {
+	input, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode contact: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, h.Path)
+	cmd.Env = environ(env)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return fmt.Errorf("hook %s failed: %w", h.Path, err)
+		}
+		if reason := strings.TrimSpace(stderr.String()); reason != "" {
+			return fmt.Errorf("%w: %s", ErrRejected, reason)
+		}
+		return fmt.Errorf("%w (exit status %d)", ErrRejected, exitErr.ExitCode())
+	}
+
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return nil
+	}
+	var modified vcard.Contact
+	if err := json.Unmarshal(stdout.Bytes(), &modified); err != nil {
+		return fmt.Errorf("hook %s printed an invalid contact: %w", h.Path, err)
+	}
+	*c = modified
+	return nil
+}
+
+// environ returns the environment of the process without secretVars, plus
+// the KEY=value pairs of env
+func environ(env map[string]string) []string {
+	vars := slices.DeleteFunc(os.Environ(), func(kv string) bool {
+		name, _, _ := strings.Cut(kv, "=")
+		return slices.Contains(secretVars, name)
+	})
+	for k, v := range env {
+		vars = append(vars, k+"="+v)
+	}
+	return vars
+}
@@ -0,0 +1,108 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// script writes an executable shell script into a temporary directory
+func script(t *testing.T, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not executable on windows")
+	}
+	path := filepath.Join(t.TempDir(), "hook.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestHook_Run(t *testing.T) {
+	var out bytes.Buffer
+	h := Hook{Path: script(t, `echo "$ANYVCARD_HOOK $ANYVCARD_CONTACT_COUNT"`), Stdout: &out}
+	if err := h.Run(context.Background(), map[string]string{"ANYVCARD_HOOK": "pre-import", "ANYVCARD_CONTACT_COUNT": "3"}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := strings.TrimSpace(out.String()); got != "pre-import 3" {
+		t.Errorf("output = %q", got)
+	}
+
+	// Credentials are not passed on to hooks
+	t.Setenv("ANYTYPE_APP_KEY", "secret")
+	out.Reset()
+	h = Hook{Path: script(t, `echo "key=$ANYTYPE_APP_KEY path=${PATH:+set}"`), Stdout: &out}
+	if err := h.Run(context.Background(), nil); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := strings.TrimSpace(out.String()); got != "key= path=set" {
+		t.Errorf("output = %q, want the app key left out and the rest of the environment kept", got)
+	}
+
+	h = Hook{Path: script(t, "exit 2"), Stderr: &out}
+	if err := h.Run(context.Background(), nil); err == nil {
+		t.Error("Run() error = nil, want failure")
+	}
+}
+
+func TestHook_Contact(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantName string
+		wantErr  string
+		rejected bool
+	}{
+		{
+			name:     "unchanged",
+			body:     "cat >/dev/null",
+			wantName: "Jane Doe",
+		},
+		{
+			name:     "modified",
+			body:     `sed 's/Jane Doe/Jane Smith/'`,
+			wantName: "Jane Smith",
+		},
+		{
+			name:     "rejected with reason",
+			body:     "echo 'no email' >&2; exit 1",
+			wantErr:  "no email",
+			rejected: true,
+		},
+		{
+			name:    "invalid output",
+			body:    "echo nope",
+			wantErr: "invalid contact",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &vcard.Contact{FormattedName: "Jane Doe"}
+			err := Hook{Path: script(t, tt.body)}.Contact(context.Background(), c, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Contact() error = %v, want %q", err, tt.wantErr)
+				}
+				if errors.Is(err, ErrRejected) != tt.rejected {
+					t.Errorf("errors.Is(err, ErrRejected) = %v, want %v", !tt.rejected, tt.rejected)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Contact() error = %v", err)
+			}
+			if c.FormattedName != tt.wantName {
+				t.Errorf("FormattedName = %q, want %q", c.FormattedName, tt.wantName)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// Package htmlreport writes groups of contacts as a self-contained HTML
+// page, one collapsible section per group with the fields side by side.
+package htmlreport
+
+import (
+	"html/template"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// Member is a contact of a Cluster
+type Member struct {
+	Contact *vcard.Contact
+	Label   string   // Role in the cluster, e.g. "survivor" or "duplicate"
+	Details []string // Match reasons, conflicts and other notes
+}
+
+// Cluster is a group of contacts reviewed together. Fields of the other
+// members that differ from the first one are highlighted.
+type Cluster struct {
+	Title   string
+	Members []Member
+}
+
+// field is a row of the comparison table
+type field struct {
+	name   string
+	values func(c *vcard.Contact) []string
+}
+
+func one(v string) []string {
+	if v == "" {
+		return nil
+	}
+	return []string{v}
+}
+
+var fields = []field{
+	{"Name", func(c *vcard.Contact) []string { return one(c.DisplayName()) }},
+	{"Given name", func(c *vcard.Contact) []string { return one(c.GivenName) }},
+	{"Family name", func(c *vcard.Contact) []string { return one(c.FamilyName) }},
+	{"Middle name", func(c *vcard.Contact) []string { return one(c.MiddleName) }},
+	{"Nickname", func(c *vcard.Contact) []string { return one(c.Nickname) }},
+	{"Organization", func(c *vcard.Contact) []string { return one(c.Organization) }},
+	{"Department", func(c *vcard.Contact) []string { return one(c.Department) }},
+	{"Title", func(c *vcard.Contact) []string { return one(c.Title) }},
+	{"Role", func(c *vcard.Contact) []string { return one(c.Role) }},
+	{"Emails", func(c *vcard.Contact) []string { return c.Emails }},
+	{"Phones", func(c *vcard.Contact) []string { return c.Phones }},
+	{"URLs", func(c *vcard.Contact) []string { return c.URLs }},
+	{"Addresses", func(c *vcard.Contact) []string {
+		var addrs []string
+		for _, a := range c.Addresses {
+			parts := slices.DeleteFunc([]string{a.Street, a.City, a.Region, a.PostalCode, a.Country}, func(s string) bool { return s == "" })
+			if len(parts) > 0 {
+				addrs = append(addrs, strings.Join(parts, ", "))
+			}
+		}
+		return addrs
+	}},
+	{"Birthday", func(c *vcard.Contact) []string { return one(c.Birthday) }},
+	{"Tags", func(c *vcard.Contact) []string { return c.Categories }},
+	{"Note", func(c *vcard.Contact) []string { return one(c.Note) }},
+	{"Object ID", func(c *vcard.Contact) []string { return one(c.ObjectID) }},
+}
+
+// value is a field value, marked when the first member lacks it
+type value struct {
+	Text  string
+	Added bool
+}
+
+// cell holds the values of a field of one member. Differs marks cells
+// whose values are not the first member's.
+type cell struct {
+	Values  []value
+	Differs bool
+}
+
+type row struct {
+	Name  string
+	Cells []cell
+}
+
+type cluster struct {
+	Title   string
+	Members []Member
+	Rows    []row
+	Differs int // Rows where some member differs from the first
+}
+
+// Write writes the clusters as an HTML page titled title
+func Write(w io.Writer, title string, clusters []Cluster) error {
+	data := struct {
+		Title    string
+		Clusters []cluster
+	}{Title: title}
+	for _, c := range clusters {
+		data.Clusters = append(data.Clusters, buildCluster(c))
+	}
+	return page.Execute(w, data)
+}
+
+func buildCluster(c Cluster) cluster {
+	out := cluster{Title: c.Title, Members: c.Members}
+	if len(c.Members) == 0 {
+		return out
+	}
+	for _, f := range fields {
+		first := f.values(c.Members[0].Contact)
+		r := row{Name: f.name}
+		empty, differs := true, false
+		for i, m := range c.Members {
+			values := f.values(m.Contact)
+			cl := cell{Differs: i > 0 && !sameValues(first, values)}
+			for _, v := range values {
+				cl.Values = append(cl.Values, value{Text: v, Added: i > 0 && !slices.ContainsFunc(first, func(s string) bool { return strings.EqualFold(s, v) })})
+			}
+			empty = empty && len(values) == 0
+			differs = differs || cl.Differs
+			r.Cells = append(r.Cells, cl)
+		}
+		if !empty {
+			out.Rows = append(out.Rows, r)
+		}
+		if differs {
+			out.Differs++
+		}
+	}
+	return out
+}
+
+// sameValues compares value lists ignoring case and order
+func sameValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, v := range b {
+		if !slices.ContainsFunc(a, func(s string) bool { return strings.EqualFold(s, v) }) {
+			return false
+		}
+	}
+	return true
+}
+
+var page = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; color: #222; }
+details { border: 1px solid #ccc; border-radius: 6px; margin: 0.6em 0; padding: 0.4em 0.8em; }
+summary { cursor: pointer; font-weight: 600; }
+summary .count { font-weight: normal; color: #666; }
+table { border-collapse: collapse; margin: 0.8em 0; }
+th, td { border: 1px solid #ddd; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+th { background: #f5f5f5; }
+td.differs { background: #fff4d6; }
+.added { background: #d9f5dc; border-radius: 3px; padding: 0 2px; }
+.label { font-size: 0.85em; color: #666; font-weight: normal; }
+ul.details { margin: 0; padding-left: 1.2em; font-size: 0.85em; font-weight: normal; color: #555; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{len .Clusters}} group(s). <span class="added">Green</span> values are missing from the first contact; <span style="background:#fff4d6">yellow</span> cells differ from it.</p>
+<p><button onclick="document.querySelectorAll('details').forEach(d => d.open = true)">Expand all</button>
+<button onclick="document.querySelectorAll('details').forEach(d => d.open = false)">Collapse all</button></p>
+{{range .Clusters}}<details>
+<summary>{{.Title}} <span class="count">({{len .Members}} contacts, {{.Differs}} differing field(s))</span></summary>
+<table>
+<tr><th></th>{{range .Members}}<th>{{.Label}}{{with .Details}}<ul class="details">{{range .}}<li>{{.}}</li>{{end}}</ul>{{end}}</th>{{end}}</tr>
+{{range .Rows}}<tr><th>{{.Name}}</th>{{range .Cells}}<td{{if .Differs}} class="differs"{{end}}>{{range $i, $v := .Values}}{{if $i}}<br>{{end}}{{if $v.Added}}<span class="added">{{$v.Text}}</span>{{else}}{{$v.Text}}{{end}}{{end}}</td>{{end}}</tr>
+{{end}}</table>
+</details>
+{{end}}</body>
+</html>
+`))
@@ -0,0 +1,39 @@
+package htmlreport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+func TestWrite(t *testing.T) {
+	clusters := []Cluster{{
+		Title: "Jane <Smith>",
+		Members: []Member{
+			{Contact: &vcard.Contact{GivenName: "Jane", Emails: []string{"jane@example.com"}, Organization: "Acme"}, Label: "survivor"},
+			{Contact: &vcard.Contact{GivenName: "Jane", Emails: []string{"JANE@example.com", "j@globex.com"}, Organization: "Acme"}, Label: "duplicate", Details: []string{"email jane@example.com"}},
+		},
+	}}
+
+	var b strings.Builder
+	if err := Write(&b, "Duplicates", clusters); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	html := b.String()
+
+	for _, want := range []string{
+		"<details>",
+		"Jane &lt;Smith&gt;",
+		"(2 contacts, 1 differing field(s))",
+		`<td class="differs">JANE@example.com<br><span class="added">j@globex.com</span></td>`,
+		"<li>email jane@example.com</li>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report lacks %q", want)
+		}
+	}
+	if strings.Contains(html, "Phones") {
+		t.Error("report has a row for a field no contact has")
+	}
+}
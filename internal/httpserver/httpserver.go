@@ -0,0 +1,213 @@
+// Package httpserver exposes the contact service as a small JSON REST API.
+package httpserver
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/internal/service"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// MaxUploadSize is the largest request body accepted, in bytes
+const MaxUploadSize = 32 << 20
+
+// New returns the API handler. When token is set, requests need an
+// "Authorization: Bearer <token>" header. Without one, POST requests are
+// refused when they come from a web page (they carry an Origin header) or
+// have a body a page could send without a CORS preflight: bodies must be
+// application/json, multipart/form-data or text/vcard.
+//
+//	GET  /health             liveness check
+//	GET  /contacts?q=&limit= search contacts
+//	GET  /contacts/{id}      get a contact
+//	POST /contacts           create a contact from JSON (?on_duplicate=merge|skip|create)
+//	POST /import             import a text/vcard body or multipart "file" (?on_duplicate=)
+//	POST /dedupe             preview duplicate contacts, merging them with ?apply=true (?min_score=0.9)
+func New(svc *service.Service, token string) http.Handler {
+	h := &handlers{svc: svc}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", h.health)
+	mux.HandleFunc("GET /contacts", h.search)
+	mux.HandleFunc("GET /contacts/{id}", h.get)
+	mux.HandleFunc("POST /contacts", h.create)
+	mux.HandleFunc("POST /import", h.importVCards)
+	mux.HandleFunc("POST /dedupe", h.dedupe)
+	if token == "" {
+		return rejectCrossSite(mux)
+	}
+	return requireToken(mux, token)
+}
+
+// postBodyTypes are the media types POST bodies may have. Browsers only
+// send multipart/form-data cross-origin with an Origin header, and the
+// others after a preflight this server doesn't answer.
+var postBodyTypes = []string{"application/json", "multipart/form-data", "text/vcard"}
+
+// rejectCrossSite refuses the POST requests a web page open in the user's
+// browser could make, as nothing else authenticates them
+func rejectCrossSite(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.Header.Get("Origin") != "" {
+			writeError(w, http.StatusForbidden, errors.New("cross-origin requests are not allowed"))
+			return
+		}
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if r.ContentLength != 0 && !slices.Contains(postBodyTypes, mediaType) {
+			writeError(w, http.StatusUnsupportedMediaType, fmt.Errorf("unsupported Content-Type %q (expected %s)", r.Header.Get("Content-Type"), strings.Join(postBodyTypes, ", ")))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func requireToken(next http.Handler, token string) http.Handler {
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type handlers struct {
+	svc *service.Service
+}
+
+func (h *handlers) health(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (h *handlers) search(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", s))
+			return
+		}
+		limit = n
+	}
+	contacts, err := h.svc.Search(r.Context(), r.URL.Query().Get("q"), limit)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	if contacts == nil {
+		contacts = []*vcard.Contact{}
+	}
+	writeJSON(w, http.StatusOK, contacts)
+}
+
+func (h *handlers) get(w http.ResponseWriter, r *http.Request) {
+	c, err := h.svc.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+func (h *handlers) create(w http.ResponseWriter, r *http.Request) {
+	var c vcard.Contact
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxUploadSize)).Decode(&c); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid contact: %w", err))
+		return
+	}
+	outcome, contact, weak, err := h.svc.Create(r.Context(), &c, r.URL.Query().Get("on_duplicate"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	status := http.StatusOK
+	if outcome == service.Created {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, map[string]any{"outcome": outcome, "contact": contact, "weak": weak})
+}
+
+func (h *handlers) importVCards(w http.ResponseWriter, r *http.Request) {
+	body, name, err := uploadedFile(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	defer body.Close()
+
+	contacts, err := vcard.Parse(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("failed to parse vCard: %w", err))
+		return
+	}
+	for i := range contacts {
+		contacts[i].Source = name
+	}
+	res, err := h.svc.Import(r.Context(), contacts, r.URL.Query().Get("on_duplicate"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// uploadedFile returns the "file" part of a multipart form, or the raw
+// request body otherwise, and the name of the upload
+func uploadedFile(w http.ResponseWriter, r *http.Request) (io.ReadCloser, string, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxUploadSize)
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "multipart/form-data" {
+		return r.Body, "HTTP upload", nil
+	}
+	if err := r.ParseMultipartForm(MaxUploadSize); err != nil {
+		return nil, "", fmt.Errorf("invalid upload: %w", err)
+	}
+	f, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, "", fmt.Errorf("missing \"file\" form field: %w", err)
+	}
+	return f, header.Filename, nil
+}
+
+func (h *handlers) dedupe(w http.ResponseWriter, r *http.Request) {
+	// Merging deletes objects, so it has to be asked for
+	apply, _ := strconv.ParseBool(r.URL.Query().Get("apply"))
+	dryRun := !apply
+	svc := *h.svc
+	if v := r.URL.Query().Get("min_score"); v != "" {
+		score, err := strconv.ParseFloat(v, 64)
+		if err != nil || score < 0 || score > 1 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid min_score %q (expected 0-1)", v))
+			return
+		}
+		svc.MinScore = score
+	}
+	groups, weak, err := svc.Dedupe(r.Context(), dryRun)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"dry_run": dryRun, "groups": groups, "weak": weak})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,196 @@
+package httpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rubiojr/any-vcard/internal/service"
+	"github.com/rubiojr/any-vcard/internal/service/servicetest"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+const testVCF = `BEGIN:VCARD
+VERSION:3.0
+FN:Jane Doe
+EMAIL:jane@example.com
+TEL:+34 600 000 000
+END:VCARD
+BEGIN:VCARD
+VERSION:3.0
+FN:John Roe
+EMAIL:john@example.com
+END:VCARD
+`
+
+func newTestServer(token string) (*httptest.Server, *servicetest.MemStore) {
+	store := servicetest.New(
+		vcard.Contact{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}},
+		vcard.Contact{FormattedName: "J. Doe", Emails: []string{"JANE@example.com"}},
+	)
+	return httptest.NewServer(New(&service.Service{Store: store}, token)), store
+}
+
+func TestAPI(t *testing.T) {
+	srv, store := newTestServer("")
+	defer srv.Close()
+
+	tests := []struct {
+		name        string
+		method, url string
+		contentType string
+		body        string
+		wantStatus  int
+		wantBody    string
+	}{
+		{"health", "GET", "/health", "", "", http.StatusOK, `"ok"`},
+		{"search", "GET", "/contacts?q=jane", "", "", http.StatusOK, `"Jane Doe"`},
+		{"bad limit", "GET", "/contacts?limit=x", "", "", http.StatusBadRequest, "invalid limit"},
+		{"get", "GET", "/contacts/obj1", "", "", http.StatusOK, `"object_id":"obj1"`},
+		{"get missing", "GET", "/contacts/nope", "", "", http.StatusNotFound, "not found"},
+		{"create", "POST", "/contacts", "application/json", `{"formatted_name":"Alice","emails":["alice@example.com"]}`, http.StatusCreated, `"created"`},
+		{"create duplicate", "POST", "/contacts?on_duplicate=skip", "application/json", `{"formatted_name":"Alice","emails":["alice@example.com"]}`, http.StatusOK, `"skipped"`},
+		{"create invalid", "POST", "/contacts", "application/json", `{`, http.StatusBadRequest, "invalid contact"},
+		{"import", "POST", "/import", "text/vcard", testVCF, http.StatusOK, `"created":1,"merged":1`},
+		{"dedupe preview", "POST", "/dedupe", "", "", http.StatusOK, `"reasons":["email jane@example.com"]`},
+		{"dedupe threshold", "POST", "/dedupe?min_score=0.95", "", "", http.StatusOK, `"groups":[]`},
+		{"bad threshold", "POST", "/dedupe?min_score=high", "", "", http.StatusBadRequest, "invalid min_score"},
+		{"threshold out of range", "POST", "/dedupe?min_score=1.5", "", "", http.StatusBadRequest, "invalid min_score"},
+		{"wrong method", "DELETE", "/contacts/obj1", "", "", http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, srv.URL+tt.url, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			var buf bytes.Buffer
+			buf.ReadFrom(resp.Body)
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body %s)", resp.StatusCode, tt.wantStatus, buf.String())
+			}
+			if !strings.Contains(buf.String(), tt.wantBody) {
+				t.Errorf("body = %s, want %s", buf.String(), tt.wantBody)
+			}
+		})
+	}
+
+	if len(store.Contacts) != 4 {
+		t.Errorf("store has %d contact(s) before dedupe, want 4", len(store.Contacts))
+	}
+	// Without apply dedupe only previews
+	for _, query := range []string{"", "?apply=true"} {
+		resp, err := http.Post(srv.URL+"/dedupe"+query, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if query == "" && len(store.Contacts) != 4 {
+			t.Errorf("store has %d contact(s) after a dedupe without apply, want 4", len(store.Contacts))
+		}
+	}
+	if len(store.Contacts) != 3 {
+		t.Errorf("store has %d contact(s) after dedupe, want 3", len(store.Contacts))
+	}
+}
+
+func TestImportMultipart(t *testing.T) {
+	srv, _ := newTestServer("")
+	defer srv.Close()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("file", "contacts.vcf")
+	fw.Write([]byte(testVCF))
+	mw.Close()
+
+	resp, err := http.Post(srv.URL+"/import", mw.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var res service.ImportResult
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Created != 1 || res.Merged != 1 {
+		t.Errorf("import = %+v, want 1 created and 1 merged", res)
+	}
+}
+
+func TestCrossSiteRejected(t *testing.T) {
+	srv, store := newTestServer("")
+	defer srv.Close()
+
+	tests := []struct {
+		name, url, origin, contentType, body string
+		want                                 int
+	}{
+		{"origin", "/dedupe?apply=true", "https://evil.example", "", "", http.StatusForbidden},
+		{"origin with JSON", "/contacts", "https://evil.example", "application/json", `{"formatted_name":"Eve"}`, http.StatusForbidden},
+		{"text/plain body", "/import", "", "text/plain", testVCF, http.StatusUnsupportedMediaType},
+		{"form body", "/import", "", "application/x-www-form-urlencoded", testVCF, http.StatusUnsupportedMediaType},
+		{"untyped body", "/contacts", "", "", `{"formatted_name":"Eve"}`, http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", srv.URL+tt.url, strings.NewReader(tt.body))
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+	if len(store.Contacts) != 2 {
+		t.Errorf("store has %d contact(s), want the 2 it started with", len(store.Contacts))
+	}
+}
+
+func TestToken(t *testing.T) {
+	srv, _ := newTestServer("s3cret")
+	defer srv.Close()
+
+	tests := []struct {
+		path, auth string
+		want       int
+	}{
+		{"/contacts", "", http.StatusUnauthorized},
+		{"/contacts", "Bearer wrong", http.StatusUnauthorized},
+		{"/contacts", "Bearer s3cret", http.StatusOK},
+		{"/health", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest("GET", srv.URL+tt.path, nil)
+		if tt.auth != "" {
+			req.Header.Set("Authorization", tt.auth)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.want {
+			t.Errorf("GET %s with %q = %d, want %d", tt.path, tt.auth, resp.StatusCode, tt.want)
+		}
+	}
+}
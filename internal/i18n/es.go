@@ -0,0 +1,91 @@
+package i18n
+
+// spanish is the Spanish catalog
+var spanish = map[string]string{
+	"✓ Archived %d file(s) to %s\n":                                                            "✓ %d archivo(s) archivado(s) en %s\n",
+	"⚠ Quarantined %d file(s) that failed in %s\n":                                             "⚠ %d archivo(s) con fallos en cuarentena en %s\n",
+	"\n%s has columns %s doesn't recognize. Assign each column to a contact field.\n":          "\n%s tiene columnas que %s no reconoce. Asigna cada columna a un campo del contacto.\n",
+	"✓ Saved mapping to %s; reuse it with --csv-mapping %s\n\n":                                "✓ Correspondencia guardada en %s; reutilízala con --csv-mapping %s\n\n",
+	"Enriching contacts...\n":                                                                  "Completando contactos...\n",
+	"✓ Enriched %d contact(s)\n":                                                               "✓ %d contacto(s) completado(s)\n",
+	"\nOpen this URL in your browser to grant read access to your Google contacts:\n\n%s\n\n":  "\nAbre esta URL en el navegador para dar acceso de lectura a tus contactos de Google:\n\n%s\n\n",
+	"Waiting for authorization...\n":                                                           "Esperando la autorización...\n",
+	"Nothing to import, the files were imported before\n":                                      "Nada que importar, los archivos ya se importaron\n",
+	"Importing writes %d contact(s) to %d space(s)":                                            "La importación escribe %d contacto(s) en %d espacio(s)",
+	"Import cancelled, nothing was written\n":                                                  "Importación cancelada, no se escribió nada\n",
+	"\n=== Space %s ===\n":                                                                     "\n=== Espacio %s ===\n",
+	"✓ Dropped %d blocklisted contact(s)\n":                                                    "✓ %d contacto(s) bloqueado(s) descartado(s)\n",
+	"✓ Replaced %d contact(s) matched by UID\n":                                                "✓ %d contacto(s) reemplazado(s) por UID\n",
+	"✓ Linked the assistant or manager of %d contact(s)\n":                                     "✓ Vinculado el asistente o responsable de %d contacto(s)\n",
+	"Geocoding addresses...\n":                                                                 "Geocodificando direcciones...\n",
+	"✓ Geocoded %d address(es)\n":                                                              "✓ %d dirección(es) geocodificada(s)\n",
+	"Checking email domains...\n":                                                              "Comprobando los dominios de correo...\n",
+	"✓ Checked %d email domain(s)\n":                                                           "✓ %d dominio(s) de correo comprobado(s)\n",
+	"\n⚠ Email issues (%d):\n":                                                                 "\n⚠ Problemas de correo (%d):\n",
+	"✓ Parsed %d contact(s) from %s\n":                                                         "✓ %d contacto(s) leído(s) de %s\n",
+	"✓ Fetched %d contact(s) from Google\n":                                                    "✓ %d contacto(s) obtenido(s) de Google\n",
+	"✓ Fetched %d contact(s) from Microsoft\n":                                                 "✓ %d contacto(s) obtenido(s) de Microsoft\n",
+	"✓ Fetched %d contact(s) from %s\n":                                                        "✓ %d contacto(s) obtenido(s) de %s\n",
+	"✓ Found %d h-card(s) on %s\n":                                                             "✓ %d h-card(s) encontrada(s) en %s\n",
+	"\nDry run mode - would import %d contact(s):\n":                                           "\nModo de prueba - se importarían %d contacto(s):\n",
+	"   Email: %s\n":                                                                           "   Correo: %s\n",
+	"   Phone: %s\n":                                                                           "   Teléfono: %s\n",
+	"Checking for existing contacts...\n":                                                      "Buscando contactos existentes...\n",
+	"\r  fetched %d contacts...":                                                               "\r  %d contactos obtenidos...",
+	"✓ Found %d existing contacts\n":                                                           "✓ %d contactos existentes encontrados\n",
+	"Looking up existing contacts (%d searches)...\n":                                          "Buscando contactos existentes (%d búsquedas)...\n",
+	"✓ Found %d possibly matching contacts\n":                                                  "✓ %d contactos posiblemente coincidentes encontrados\n",
+	"\nTo grant read access to your Microsoft contacts, open %s\nand enter the code: %s\n\n":   "\nPara dar acceso de lectura a tus contactos de Microsoft, abre %s\ne introduce el código: %s\n\n",
+	"Queued %d contact(s) for %d space(s); the next import with --queue-offline writes them\n": "%d contacto(s) en cola para %d espacio(s); la próxima importación con --queue-offline los escribirá\n",
+	"Replaying %d contact(s) queued while Anytype was unreachable\n":                           "Reenviando %d contacto(s) en cola mientras Anytype no estaba disponible\n",
+	"⚠ Anytype became unreachable, queued %d contact(s) for space %s\n":                        "⚠ Anytype dejó de responder, %d contacto(s) en cola para el espacio %s\n",
+	"Looking up contacts to replace...\n":                                                      "Buscando contactos que reemplazar...\n",
+	"↻ Replaced: %s\n":                                                                         "↻ Reemplazado: %s\n",
+	"  Created property: %s (key: %s)\n":                                                       "  Propiedad creada: %s (clave: %s)\n",
+	"  Waiting for properties to be available...\n":                                            "  Esperando a que las propiedades estén disponibles...\n",
+	"  Properties ready.\n":                                                                    "  Propiedades listas.\n",
+	"✓ Found existing Contact type with key: %s\n":                                             "✓ Tipo Contact existente encontrado con clave: %s\n",
+	"Creating Contact object type...\n":                                                        "Creando el tipo de objeto Contact...\n",
+	"✓ Created Contact type with key: %s\n":                                                    "✓ Tipo Contact creado con clave: %s\n",
+	"✓ Found existing Company type with key: %s\n":                                             "✓ Tipo Company existente encontrado con clave: %s\n",
+	"Creating Company object type...\n":                                                        "Creando el tipo de objeto Company...\n",
+	"✓ Created Company type with key: %s\n":                                                    "✓ Tipo Company creado con clave: %s\n",
+	"  %.1f contacts/s, %d API calls, %s uploaded in %s\n":                                     "  %.1f contactos/s, %d llamadas a la API, %s subidos en %s\n",
+	"✓ Found %d contacts in space %s\n":                                                        "✓ %d contactos encontrados en el espacio %s\n",
+	"\nImporting %d contact(s)...\n":                                                           "\nImportando %d contacto(s)...\n",
+	"  ⚠ Conflict merging %s: %s\n":                                                            "  ⚠ Conflicto al fusionar %s: %s\n",
+	"⊕ Merged: %s → %s (%s)\n":                                                                 "⊕ Fusionado: %s → %s (%s)\n",
+	"✓ Imported: %s\n":                                                                         "✓ Importado: %s\n",
+	"  ≈ also in space %s: %s\n":                                                               "  ≈ también en el espacio %s: %s\n",
+	"\n✓ Successfully imported %d/%d contacts":                                                 "\n✓ %d/%d contactos importados correctamente",
+	" (merged %d)":                                      " (%d fusionados)",
+	" (skipped %d duplicates)":                          " (%d duplicados omitidos)",
+	" (%d also in other spaces)":                        " (%d también en otros espacios)",
+	" (%d conflicting values discarded)":                " (%d valores en conflicto descartados)",
+	" (%d possible duplicates to review)":               " (%d posibles duplicados por revisar)",
+	"  ≈ %s may duplicate %s, not merged: %s\n":         "  ≈ %s puede duplicar a %s, no se fusiona: %s\n",
+	"✓ Exported %d contact(s) to %s\n":                  "✓ %d contacto(s) exportado(s) a %s\n",
+	"✓ Converted %d contact(s) to %s\n":                 "✓ %d contacto(s) convertido(s) a %s\n",
+	"✓ Backed up %d contact(s) and %d photo(s) to %s\n": "✓ Copia de seguridad de %d contacto(s) y %d foto(s) en %s\n",
+	"Backup of space %s taken %s: %d contact(s)\n":      "Copia de seguridad del espacio %s hecha el %s: %d contacto(s)\n",
+	"\nWould restore %d contact(s) (%d still exist)\n":  "\nSe restaurarían %d contacto(s) (%d siguen existiendo)\n",
+	"✓ Restored: %s\n":                                  "✓ Restaurado: %s\n",
+	"  ⚠ photo not restored\n":                          "  ⚠ foto no restaurada\n",
+	"⚠ %d photo(s) were not restored: Anytype's API can't upload files, they stay in the backup\n": "⚠ %d foto(s) no se restauraron: la API de Anytype no puede subir archivos, siguen en la copia de seguridad\n",
+	"\n✓ Restored %d/%d contact(s)":                                                           "\n✓ %d/%d contacto(s) restaurado(s)",
+	" (skipped %d that still exist)":                                                          " (%d omitidos porque siguen existiendo)",
+	"Found %d contact(s) to copy from space %s\n":                                             "%d contacto(s) que copiar del espacio %s\n",
+	"      ⚠ conflict %s\n":                                                                   "      ⚠ conflicto %s\n",
+	"\nWeak matches, not merged; review them with diff:\n":                                    "\nCoincidencias débiles, no fusionadas; revísalas con diff:\n",
+	"\nDry run: would merge %d duplicate(s) into %d contact(s)\n":                             "\nPrueba: se fusionarían %d duplicado(s) en %d contacto(s)\n",
+	"✓ Merged %d duplicate(s) into %d contact(s)\n":                                           "✓ %d duplicado(s) fusionado(s) en %d contacto(s)\n",
+	"✓ Wrote %d duplicate cluster(s) to %s\n":                                                 "✓ %d grupo(s) de duplicados escrito(s) en %s\n",
+	"✓ Sorted %s by %s\n":                                                                     "✓ %s ordenado por %s\n",
+	"✓ Refreshed birthday fields for %d/%d contacts\n":                                        "✓ Campos de cumpleaños actualizados en %d/%d contactos\n",
+	"Continue? [y/N] ":                                                                        "¿Continuar? [s/N] ",
+	"Merging deletes %d duplicate contact object(s) and overwrites %d contact(s) in space %s": "La fusión elimina %d objeto(s) de contacto duplicado(s) y sobrescribe %d contacto(s) en el espacio %s",
+	"--replace deletes and re-creates %d contact object(s) in space %s":                       "--replace elimina y vuelve a crear %d objeto(s) de contacto en el espacio %s",
+	"Input of %s is too large for a %s memory budget: skipping embedded photos":               "La entrada de %s es demasiado grande para un límite de memoria de %s: se omiten las fotos incrustadas",
+	" and keeping the duplicate index on disk":                                                " y el índice de duplicados se guarda en disco",
+	"✓ Wrote %d dropped value(s) to %s\n":                                                     "✓ %d valor(es) descartado(s) escritos en %s\n",
+}
@@ -0,0 +1,99 @@
+// Package i18n translates the messages the CLI prints. Messages are written
+// in English in the code and looked up, format string and all, in the
+// catalog of the selected language; those missing from it print in English.
+package i18n
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// English is the language of the messages in the code
+const English = "en"
+
+// catalogs holds the translations of each language by English format string
+var catalogs = map[string]map[string]string{
+	"es": spanish,
+}
+
+// Languages lists the supported languages
+var Languages = []string{English, "es"}
+
+// lang is the selected language
+var lang = English
+
+// output is where Printf writes
+var output io.Writer = os.Stdout
+
+// SetOutput makes Printf write to w, for commands whose standard output
+// carries something else
+func SetOutput(w io.Writer) {
+	output = w
+}
+
+// Output returns the writer of the messages, standard output unless
+// SetOutput changed it
+func Output() io.Writer {
+	return output
+}
+
+// SetLanguage selects the language of the messages from a language code or
+// a locale such as es_ES.UTF-8
+func SetLanguage(code string) error {
+	base := Base(code)
+	if !slices.Contains(Languages, base) {
+		return fmt.Errorf("unsupported language %q (supported: %s)", code, strings.Join(Languages, ", "))
+	}
+	lang = base
+	return nil
+}
+
+// Language returns the selected language
+func Language() string {
+	return lang
+}
+
+// Base returns the language of a language code or locale: "es" for es,
+// es-MX or es_ES.UTF-8
+func Base(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_.@"); i != -1 {
+		code = code[:i]
+	}
+	return code
+}
+
+// SystemLanguage returns the supported language of the environment
+// (LC_ALL, LC_MESSAGES or LANG), English when it isn't supported
+func SystemLanguage() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(env); value != "" {
+			if base := Base(value); slices.Contains(Languages, base) {
+				return base
+			}
+			return English
+		}
+	}
+	return English
+}
+
+// T returns the translation of a message
+func T(msg string) string {
+	if translated, ok := catalogs[lang][msg]; ok {
+		return translated
+	}
+	return msg
+}
+
+// Sprintf formats the translation of format
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Printf prints the translation of format to Output
+func Printf(format string, args ...any) {
+	fmt.Fprintf(output, T(format), args...)
+}
@@ -0,0 +1,85 @@
+package i18n
+
+import (
+	"bytes"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+// verbs matches the formatting verbs of a format string
+var verbs = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestCatalogs(t *testing.T) {
+	for code, catalog := range catalogs {
+		for msg, translated := range catalog {
+			if !slices.Equal(verbs.FindAllString(msg, -1), verbs.FindAllString(translated, -1)) {
+				t.Errorf("%s: %q translates %q with other verbs", code, translated, msg)
+			}
+		}
+	}
+}
+
+func TestSetLanguage(t *testing.T) {
+	defer SetLanguage(English)
+
+	tests := []struct {
+		code    string
+		want    string
+		wantErr bool
+	}{
+		{"es", "es", false},
+		{"es_ES.UTF-8", "es", false},
+		{"es-MX", "es", false},
+		{"en", "en", false},
+		{"fr", "", true},
+	}
+	for _, tt := range tests {
+		err := SetLanguage(tt.code)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("SetLanguage(%q) error = %v, wantErr %v", tt.code, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && Language() != tt.want {
+			t.Errorf("SetLanguage(%q) selected %q, want %q", tt.code, Language(), tt.want)
+		}
+	}
+
+	SetLanguage("es")
+	if got := Sprintf("✓ Imported: %s\n", "Jane"); got != "✓ Importado: Jane\n" {
+		t.Errorf("Sprintf() = %q", got)
+	}
+	if got := Sprintf("Not translated %d", 1); got != "Not translated 1" {
+		t.Errorf("Sprintf() untranslated = %q", got)
+	}
+}
+
+func TestSetOutput(t *testing.T) {
+	defer SetOutput(Output())
+
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	Printf("✓ Imported: %s\n", "Jane")
+	if got := buf.String(); got != "✓ Imported: Jane\n" {
+		t.Errorf("Printf() wrote %q", got)
+	}
+}
+
+func TestSystemLanguage(t *testing.T) {
+	tests := []struct {
+		lcAll, lang string
+		want        string
+	}{
+		{"", "es_ES.UTF-8", "es"},
+		{"fr_FR.UTF-8", "es_ES.UTF-8", "en"},
+		{"", "", "en"},
+	}
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_MESSAGES", "")
+		t.Setenv("LANG", tt.lang)
+		if got := SystemLanguage(); got != tt.want {
+			t.Errorf("SystemLanguage() with LC_ALL=%q LANG=%q = %q, want %q", tt.lcAll, tt.lang, got, tt.want)
+		}
+	}
+}
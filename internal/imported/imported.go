@@ -0,0 +1,115 @@
+// Package imported remembers the files imported into each space by the
+// hash of their content, so importing the same file again is noticed
+// before running the whole import.
+package imported
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Record is a file imported into a space
+type Record struct {
+	SpaceID  string    `json:"space_id"`
+	Hash     string    `json:"sha256"`
+	Path     string    `json:"path"` // Where the file was when imported
+	Contacts int       `json:"contacts"`
+	Imported time.Time `json:"imported"`
+}
+
+// Log holds the records of the imported files
+type Log struct {
+	path    string
+	records []Record
+	dirty   bool
+}
+
+// DefaultPath returns the log file location under the user cache dir
+func DefaultPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "any-vcard", "imported.json"), nil
+}
+
+// Load reads the log file at path, if present
+func Load(path string) (*Log, error) {
+	l := &Log{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read imported files log: %w", err)
+	}
+	if err := json.Unmarshal(data, &l.records); err != nil {
+		return nil, fmt.Errorf("failed to parse imported files log %s: %w", path, err)
+	}
+	return l, nil
+}
+
+// HashFile returns the hex SHA-256 of the content of the file at path
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Find returns the record of the file with the hash imported into spaceID
+func (l *Log) Find(spaceID, hash string) (Record, bool) {
+	i := slices.IndexFunc(l.records, func(r Record) bool { return r.SpaceID == spaceID && r.Hash == hash })
+	if i == -1 {
+		return Record{}, false
+	}
+	return l.records[i], true
+}
+
+// Add records an imported file, replacing the previous import of the same
+// content into the same space
+func (l *Log) Add(r Record) {
+	l.records = slices.DeleteFunc(l.records, func(old Record) bool { return old.SpaceID == r.SpaceID && old.Hash == r.Hash })
+	l.records = append(l.records, r)
+	l.dirty = true
+}
+
+// Save writes the log back to disk if it changed
+func (l *Log) Save() error {
+	if !l.dirty {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(l.records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write imported files log: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return fmt.Errorf("failed to write imported files log: %w", err)
+	}
+	l.dirty = false
+	return nil
+}
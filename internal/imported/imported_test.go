@@ -0,0 +1,63 @@
+package imported
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHashFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	a, err := HashFile(write("a.vcf", "BEGIN:VCARD\nEND:VCARD\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := HashFile(write("renamed.vcf", "BEGIN:VCARD\nEND:VCARD\n"))
+	c, _ := HashFile(write("c.vcf", "BEGIN:VCARD\nFN:Jane\nEND:VCARD\n"))
+	if a != b || a == c {
+		t.Errorf("HashFile() = %s, %s, %s; want the same hash only for the same content", a, b, c)
+	}
+	if _, err := HashFile(filepath.Join(dir, "missing.vcf")); err == nil {
+		t.Error("HashFile() of a missing file succeeded")
+	}
+}
+
+func TestLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "imported.json")
+	l, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := l.Find("space1", "abc"); ok {
+		t.Error("Find() in an empty log succeeded")
+	}
+
+	first := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	l.Add(Record{SpaceID: "space1", Hash: "abc", Path: "contacts.vcf", Contacts: 3, Imported: first})
+	l.Add(Record{SpaceID: "space1", Hash: "abc", Path: "copy.vcf", Contacts: 3, Imported: first.AddDate(0, 0, 1)})
+	if err := l.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, ok := l.Find("space1", "abc")
+	if !ok || r.Path != "copy.vcf" || !r.Imported.Equal(first.AddDate(0, 0, 1)) || len(l.records) != 1 {
+		t.Errorf("Find() = %+v, %v with %d records", r, ok, len(l.records))
+	}
+	if _, ok := l.Find("space2", "abc"); ok {
+		t.Error("Find() matched a file imported into another space")
+	}
+}
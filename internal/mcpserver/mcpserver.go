@@ -0,0 +1,130 @@
+// Package mcpserver exposes the contact service as Model Context Protocol
+// tools.
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rubiojr/any-vcard/internal/service"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// New creates an MCP server with tools backed by svc
+func New(svc *service.Service, name, version string) *server.MCPServer {
+	s := server.NewMCPServer(name, version, server.WithToolCapabilities(false))
+	h := &handlers{svc: svc}
+
+	s.AddTool(mcp.NewTool("search_contacts",
+		mcp.WithDescription("Search contacts by full-text query. Returns the matching contacts as JSON."),
+		mcp.WithString("query", mcp.Description("Text to search for; empty lists all contacts")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of contacts to return (default 20)")),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), h.search)
+
+	s.AddTool(mcp.NewTool("get_contact",
+		mcp.WithDescription("Get a contact by its object ID."),
+		mcp.WithString("id", mcp.Required(), mcp.Description("Object ID of the contact")),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), h.get)
+
+	s.AddTool(mcp.NewTool("create_contact",
+		mcp.WithDescription("Create a contact. Duplicates of existing contacts (same email, phone or name) are merged by default."),
+		mcp.WithString("formatted_name", mcp.Description("Full display name")),
+		mcp.WithString("given_name", mcp.Description("First name")),
+		mcp.WithString("family_name", mcp.Description("Last name")),
+		mcp.WithArray("emails", mcp.WithStringItems(), mcp.Description("Email addresses")),
+		mcp.WithArray("phones", mcp.WithStringItems(), mcp.Description("Phone numbers")),
+		mcp.WithString("organization", mcp.Description("Company or organization")),
+		mcp.WithString("title", mcp.Description("Job title")),
+		mcp.WithString("birthday", mcp.Description("Birthday (YYYY-MM-DD or --MM-DD)")),
+		mcp.WithString("note", mcp.Description("Free-form notes")),
+		mcp.WithArray("categories", mcp.WithStringItems(), mcp.Description("Groups or labels")),
+		mcp.WithString("on_duplicate",
+			mcp.Enum(service.OnDuplicateMerge, service.OnDuplicateSkip, service.OnDuplicateCreate),
+			mcp.Description("What to do when the contact already exists (default merge)")),
+	), h.create)
+
+	s.AddTool(mcp.NewTool("merge_contacts",
+		mcp.WithDescription("Merge the source contact into the target contact, then delete the source."),
+		mcp.WithString("target_id", mcp.Required(), mcp.Description("Object ID of the contact to keep")),
+		mcp.WithString("source_id", mcp.Required(), mcp.Description("Object ID of the contact merged and deleted")),
+		mcp.WithDestructiveHintAnnotation(true),
+	), h.merge)
+
+	return s
+}
+
+// ServeStdio serves the MCP server over stdin/stdout
+func ServeStdio(s *server.MCPServer) error {
+	return server.ServeStdio(s)
+}
+
+type handlers struct {
+	svc *service.Service
+}
+
+func (h *handlers) search(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	contacts, err := h.svc.Search(ctx, req.GetString("query", ""), req.GetInt("limit", 20))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("search failed", err), nil
+	}
+	if contacts == nil {
+		contacts = []*vcard.Contact{}
+	}
+	return jsonResult(contacts)
+}
+
+func (h *handlers) get(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, err := req.RequireString("id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	c, err := h.svc.Get(ctx, id)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return jsonResult(c)
+}
+
+func (h *handlers) create(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var c vcard.Contact
+	if err := req.BindArguments(&c); err != nil {
+		return mcp.NewToolResultErrorFromErr("invalid contact", err), nil
+	}
+	outcome, contact, weak, err := h.svc.Create(ctx, &c, req.GetString("on_duplicate", ""))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return jsonResult(struct {
+		Outcome string                  `json:"outcome"`
+		Contact *vcard.Contact          `json:"contact"`
+		Weak    []service.WeakDuplicate `json:"weak,omitempty"`
+	}{outcome, contact, weak})
+}
+
+func (h *handlers) merge(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	targetID, err := req.RequireString("target_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	sourceID, err := req.RequireString("source_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	c, err := h.svc.Merge(ctx, targetID, sourceID)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return jsonResult(c)
+}
+
+func jsonResult(v any) (*mcp.CallToolResult, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
@@ -0,0 +1,91 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/rubiojr/any-vcard/internal/service"
+	"github.com/rubiojr/any-vcard/internal/service/servicetest"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// callTool sends a tools/call request and returns the text result
+func callTool(t *testing.T, store *servicetest.MemStore, tool string, args map[string]any) (string, bool) {
+	t.Helper()
+	s := New(&service.Service{Store: store}, "any-vcard", "test")
+
+	msg, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": tool, "arguments": args},
+	})
+	resp := s.HandleMessage(context.Background(), msg)
+
+	data, _ := json.Marshal(resp)
+	var out struct {
+		Result mcp.CallToolResult `json:"result"`
+		Error  any                `json:"error"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("decode response %s: %v", data, err)
+	}
+	if out.Error != nil {
+		t.Fatalf("%s returned JSON-RPC error %v", tool, out.Error)
+	}
+	if len(out.Result.Content) == 0 {
+		t.Fatalf("%s returned no content: %s", tool, data)
+	}
+	text, ok := mcp.AsTextContent(out.Result.Content[0])
+	if !ok {
+		t.Fatalf("%s returned non-text content", tool)
+	}
+	return text.Text, out.Result.IsError
+}
+
+func TestTools(t *testing.T) {
+	store := servicetest.New(
+		vcard.Contact{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}},
+		vcard.Contact{FormattedName: "John Roe"},
+	)
+
+	text, isErr := callTool(t, store, "search_contacts", map[string]any{"query": "jane"})
+	if isErr || !strings.Contains(text, "jane@example.com") || strings.Contains(text, "John") {
+		t.Errorf("search_contacts = %s", text)
+	}
+
+	text, isErr = callTool(t, store, "create_contact", map[string]any{
+		"formatted_name": "Jane Doe",
+		"emails":         []string{"jane@example.com"},
+		"phones":         []string{"+34 600 000 000"},
+	})
+	if isErr || !strings.Contains(text, `"outcome":"merged"`) || len(store.Contacts) != 2 {
+		t.Errorf("create_contact duplicate = %s (%d contacts)", text, len(store.Contacts))
+	}
+
+	text, isErr = callTool(t, store, "create_contact", map[string]any{
+		"given_name":  "Alice",
+		"family_name": "Smith",
+		"emails":      []string{"alice@example.com"},
+	})
+	if isErr || !strings.Contains(text, `"outcome":"created"`) || len(store.Contacts) != 3 {
+		t.Errorf("create_contact new = %s", text)
+	}
+
+	text, isErr = callTool(t, store, "get_contact", map[string]any{"id": "obj3"})
+	if isErr || !strings.Contains(text, "alice@example.com") {
+		t.Errorf("get_contact = %s", text)
+	}
+
+	text, isErr = callTool(t, store, "merge_contacts", map[string]any{"target_id": "obj1", "source_id": "obj2"})
+	if isErr || len(store.Contacts) != 2 {
+		t.Errorf("merge_contacts = %s (%d contacts)", text, len(store.Contacts))
+	}
+
+	if text, isErr = callTool(t, store, "get_contact", map[string]any{}); !isErr {
+		t.Errorf("get_contact without id = %s, want tool error", text)
+	}
+}
@@ -0,0 +1,351 @@
+// Package mirror keeps a local SQLite copy of the contact objects of
+// spaces, so read-heavy commands skip fetching every object from the API
+// and keep working while it is unreachable. Refreshes are incremental:
+// only objects whose modification date changed are rewritten, and objects
+// gone from the space are dropped. Every change a refresh finds is
+// recorded in a journal, for exports of what changed since a point in time.
+package mirror
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rubiojr/anytype-go"
+)
+
+// DefaultTTL is how long a mirrored space is trusted before a refresh
+const DefaultTTL = 10 * time.Minute
+
+// ErrUnavailable is returned by Open in builds without SQLite, see sqlite.go
+var ErrUnavailable = errors.New("the local mirror is not available in this build: rebuild with CGO_ENABLED=1 and without the nomirror tag")
+
+const schema = `
+CREATE TABLE IF NOT EXISTS objects (
+	space_id      TEXT NOT NULL,
+	id            TEXT NOT NULL,
+	last_modified TEXT NOT NULL,
+	data          BLOB NOT NULL,
+	PRIMARY KEY (space_id, id)
+);
+CREATE TABLE IF NOT EXISTS spaces (
+	space_id  TEXT PRIMARY KEY,
+	type_key  TEXT NOT NULL,
+	synced_at INTEGER NOT NULL -- Unix nanoseconds
+);
+CREATE TABLE IF NOT EXISTS changes (
+	seq       INTEGER PRIMARY KEY AUTOINCREMENT,
+	space_id  TEXT NOT NULL,
+	object_id TEXT NOT NULL,
+	op        TEXT NOT NULL,
+	at        INTEGER NOT NULL -- Unix nanoseconds
+);
+CREATE INDEX IF NOT EXISTS changes_space_at ON changes (space_id, at);
+CREATE TABLE IF NOT EXISTS exports (
+	space_id TEXT PRIMARY KEY,
+	at       INTEGER NOT NULL -- Snapshot of the last incremental export
+);`
+
+// Journal operations
+const (
+	Created = "created"
+	Updated = "updated"
+	Deleted = "deleted"
+)
+
+// Change is a journal entry: what happened to an object, as seen by the
+// refresh at At
+type Change struct {
+	ObjectID string
+	Op       string
+	At       time.Time
+}
+
+// Mirror is a local copy of the contact objects of spaces
+type Mirror struct {
+	db *sql.DB
+}
+
+// Stats counts what a Refresh changed
+type Stats struct {
+	Added, Updated, Removed, Unchanged int
+}
+
+// DefaultPath returns the mirror database location under the user cache dir
+func DefaultPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "any-vcard", "mirror.db"), nil
+}
+
+// Open opens the mirror database at path, creating it if needed
+func Open(path string) (*Mirror, error) {
+	if !available {
+		return nil, ErrUnavailable
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mirror: %w", err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open mirror %s: %w", path, err)
+	}
+	return &Mirror{db: db}, nil
+}
+
+// Close closes the database
+func (m *Mirror) Close() error {
+	return m.db.Close()
+}
+
+// Synced returns the contact type key spaceID was last refreshed with and
+// when. ok is false when the space was never mirrored.
+func (m *Mirror) Synced(spaceID string) (typeKey string, at time.Time, ok bool, err error) {
+	var unix int64
+	err = m.db.QueryRow(`SELECT type_key, synced_at FROM spaces WHERE space_id = ?`, spaceID).Scan(&typeKey, &unix)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", time.Time{}, false, nil
+	}
+	if err != nil {
+		return "", time.Time{}, false, fmt.Errorf("failed to read mirror: %w", err)
+	}
+	return typeKey, time.Unix(0, unix), true, nil
+}
+
+// Refresh brings the mirror of spaceID in line with objects, the current
+// contact objects of type typeKey in the space
+func (m *Mirror) Refresh(spaceID, typeKey string, objects []anytype.Object, now time.Time) (stats Stats, err error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return stats, fmt.Errorf("failed to refresh mirror: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			err = fmt.Errorf("failed to refresh mirror: %w", err)
+		}
+	}()
+
+	type stored struct {
+		modified string
+		data     []byte
+	}
+	known := make(map[string]stored)
+	rows, err := tx.Query(`SELECT id, last_modified, data FROM objects WHERE space_id = ?`, spaceID)
+	if err != nil {
+		return stats, err
+	}
+	for rows.Next() {
+		var id string
+		var st stored
+		if err := rows.Scan(&id, &st.modified, &st.data); err != nil {
+			rows.Close()
+			return stats, err
+		}
+		known[id] = st
+	}
+	if err := rows.Close(); err != nil {
+		return stats, err
+	}
+
+	for _, obj := range objects {
+		modified := lastModified(obj)
+		prev, seen := known[obj.ID]
+		delete(known, obj.ID)
+		if seen && modified != "" && prev.modified == modified {
+			stats.Unchanged++
+			continue
+		}
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return stats, err
+		}
+		// Objects without a modification date are compared in full
+		if seen && bytes.Equal(prev.data, data) {
+			stats.Unchanged++
+			continue
+		}
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO objects (space_id, id, last_modified, data) VALUES (?, ?, ?, ?)`,
+			spaceID, obj.ID, modified, data); err != nil {
+			return stats, err
+		}
+		op := Created
+		if seen {
+			op = Updated
+			stats.Updated++
+		} else {
+			stats.Added++
+		}
+		if err := journal(tx, spaceID, obj.ID, op, now); err != nil {
+			return stats, err
+		}
+	}
+	for id := range known {
+		if _, err := tx.Exec(`DELETE FROM objects WHERE space_id = ? AND id = ?`, spaceID, id); err != nil {
+			return stats, err
+		}
+		if err := journal(tx, spaceID, id, Deleted, now); err != nil {
+			return stats, err
+		}
+		stats.Removed++
+	}
+
+	if _, err := tx.Exec(`INSERT OR REPLACE INTO spaces (space_id, type_key, synced_at) VALUES (?, ?, ?)`,
+		spaceID, typeKey, now.UnixNano()); err != nil {
+		return stats, err
+	}
+	return stats, tx.Commit()
+}
+
+// Objects returns the mirrored objects of spaceID. A non-empty query keeps
+// the objects containing all its words in their name or property values,
+// ignoring case.
+func (m *Mirror) Objects(spaceID, query string) ([]anytype.Object, error) {
+	rows, err := m.db.Query(`SELECT data FROM objects WHERE space_id = ? ORDER BY rowid`, spaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mirror: %w", err)
+	}
+	defer rows.Close()
+
+	words := strings.Fields(strings.ToLower(query))
+	var objects []anytype.Object
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read mirror: %w", err)
+		}
+		var obj anytype.Object
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return nil, fmt.Errorf("failed to read mirror: %w", err)
+		}
+		if matches(obj, words) {
+			objects = append(objects, obj)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read mirror: %w", err)
+	}
+	return objects, nil
+}
+
+// Invalidate marks spaceID stale, so the next read refreshes it, after
+// contacts were written to the space
+func (m *Mirror) Invalidate(spaceID string) error {
+	if _, err := m.db.Exec(`UPDATE spaces SET synced_at = 0 WHERE space_id = ?`, spaceID); err != nil {
+		return fmt.Errorf("failed to invalidate mirror: %w", err)
+	}
+	return nil
+}
+
+// Changes returns the last change of every object of spaceID changed
+// after since, in the order they last changed
+func (m *Mirror) Changes(spaceID string, since time.Time) ([]Change, error) {
+	rows, err := m.db.Query(`SELECT object_id, op, at FROM changes WHERE space_id = ? AND at > ? ORDER BY seq`,
+		spaceID, since.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mirror journal: %w", err)
+	}
+	defer rows.Close()
+
+	var all []Change
+	for rows.Next() {
+		var c Change
+		var at int64
+		if err := rows.Scan(&c.ObjectID, &c.Op, &at); err != nil {
+			return nil, fmt.Errorf("failed to read mirror journal: %w", err)
+		}
+		c.At = time.Unix(0, at)
+		all = append(all, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read mirror journal: %w", err)
+	}
+
+	// Keep the last change of each object, walking back from the newest
+	var changes []Change
+	index := make(map[string]int)
+	for i := len(all) - 1; i >= 0; i-- {
+		c := all[i]
+		j, ok := index[c.ObjectID]
+		if !ok {
+			index[c.ObjectID] = len(changes)
+			changes = append(changes, c)
+			continue
+		}
+		// Created, then updated, is still new to whoever reads the journal
+		if c.Op == Created && changes[j].Op == Updated {
+			changes[j].Op = Created
+		}
+	}
+	slices.Reverse(changes)
+	return changes, nil
+}
+
+// LastExport returns the snapshot time recorded by MarkExported for
+// spaceID; ok is false when there is none
+func (m *Mirror) LastExport(spaceID string) (at time.Time, ok bool, err error) {
+	var unix int64
+	err = m.db.QueryRow(`SELECT at FROM exports WHERE space_id = ?`, spaceID).Scan(&unix)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to read mirror: %w", err)
+	}
+	return time.Unix(0, unix), true, nil
+}
+
+// MarkExported records that the changes of spaceID up to the snapshot at
+// were exported
+func (m *Mirror) MarkExported(spaceID string, at time.Time) error {
+	if _, err := m.db.Exec(`INSERT OR REPLACE INTO exports (space_id, at) VALUES (?, ?)`, spaceID, at.UnixNano()); err != nil {
+		return fmt.Errorf("failed to record export: %w", err)
+	}
+	return nil
+}
+
+func journal(tx *sql.Tx, spaceID, objectID, op string, at time.Time) error {
+	_, err := tx.Exec(`INSERT INTO changes (space_id, object_id, op, at) VALUES (?, ?, ?, ?)`,
+		spaceID, objectID, op, at.UnixNano())
+	return err
+}
+
+func lastModified(obj anytype.Object) string {
+	for _, p := range obj.Properties {
+		if p.Key == "last_modified_date" {
+			return p.Date
+		}
+	}
+	return ""
+}
+
+// matches reports whether every word appears in the searchable text of obj
+func matches(obj anytype.Object, words []string) bool {
+	if len(words) == 0 {
+		return true
+	}
+	texts := []string{obj.Name, obj.Snippet}
+	for _, p := range obj.Properties {
+		texts = append(texts, p.Text, p.Email, p.Phone, p.URL)
+	}
+	text := strings.ToLower(strings.Join(texts, " "))
+	for _, w := range words {
+		if !strings.Contains(text, w) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,192 @@
+//go:build cgo && !nomirror
+
+package mirror
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rubiojr/anytype-go"
+)
+
+func object(id, name, email, modified string) anytype.Object {
+	return anytype.Object{
+		ID:   id,
+		Name: name,
+		Properties: []anytype.Property{
+			{Key: "email", Format: "email", Email: email},
+			{Key: "last_modified_date", Format: "date", Date: modified},
+		},
+	}
+}
+
+func TestMirror_Refresh(t *testing.T) {
+	m, err := Open(filepath.Join(t.TempDir(), "mirror.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer m.Close()
+
+	if _, _, ok, err := m.Synced("space1"); ok || err != nil {
+		t.Fatalf("Synced() of an empty mirror = %v, %v", ok, err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	stats, err := m.Refresh("space1", "contact", []anytype.Object{
+		object("a", "Jane Smith", "jane@example.com", "2024-01-01T00:00:00Z"),
+		object("b", "John Doe", "john@example.com", "2024-01-01T00:00:00Z"),
+		object("c", "Ana García", "ana@example.com", "2024-01-01T00:00:00Z"),
+	}, now)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if stats != (Stats{Added: 3}) {
+		t.Errorf("first Refresh() = %+v", stats)
+	}
+
+	// b changed, c was deleted, d is new
+	stats, err = m.Refresh("space1", "contact", []anytype.Object{
+		object("a", "Jane Smith", "jane@example.com", "2024-01-01T00:00:00Z"),
+		object("b", "John Doe", "john.doe@example.com", "2024-02-01T00:00:00Z"),
+		object("d", "Li Wei", "li@example.com", "2024-02-01T00:00:00Z"),
+	}, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if stats != (Stats{Added: 1, Updated: 1, Removed: 1, Unchanged: 1}) {
+		t.Errorf("second Refresh() = %+v", stats)
+	}
+
+	typeKey, at, ok, err := m.Synced("space1")
+	if err != nil || !ok || typeKey != "contact" || !at.Equal(now.Add(time.Hour)) {
+		t.Errorf("Synced() = %q, %v, %v, %v", typeKey, at, ok, err)
+	}
+
+	objects, err := m.Objects("space1", "")
+	if err != nil {
+		t.Fatalf("Objects() error = %v", err)
+	}
+	var ids []string
+	for _, o := range objects {
+		ids = append(ids, o.ID)
+	}
+	if len(ids) != 3 || ids[0] != "a" || ids[1] != "b" || ids[2] != "d" {
+		t.Errorf("Objects() ids = %v, want [a b d]", ids)
+	}
+	if objects[1].Properties[0].Email != "john.doe@example.com" {
+		t.Errorf("updated object = %+v", objects[1])
+	}
+
+	if other, _ := m.Objects("space2", ""); len(other) != 0 {
+		t.Errorf("Objects(space2) = %d object(s), want none", len(other))
+	}
+
+	if err := m.Invalidate("space1"); err != nil {
+		t.Fatalf("Invalidate() error = %v", err)
+	}
+	if _, at, ok, _ := m.Synced("space1"); !ok || at.Unix() != 0 {
+		t.Errorf("Synced() after Invalidate = %v, %v", at, ok)
+	}
+}
+
+func TestMirror_Search(t *testing.T) {
+	m, err := Open(filepath.Join(t.TempDir(), "mirror.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer m.Close()
+	if _, err := m.Refresh("space1", "contact", []anytype.Object{
+		object("a", "Jane Smith", "jane@acme.com", ""),
+		object("b", "John Smith", "john@example.com", ""),
+	}, time.Now()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", 2},
+		{"smith", 2},
+		{"SMITH jane", 1},
+		{"acme", 1},
+		{"nobody", 0},
+	}
+	for _, tt := range tests {
+		got, err := m.Objects("space1", tt.query)
+		if err != nil {
+			t.Fatalf("Objects(%q) error = %v", tt.query, err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("Objects(%q) = %d object(s), want %d", tt.query, len(got), tt.want)
+		}
+	}
+}
+
+func TestMirror_Changes(t *testing.T) {
+	m, err := Open(filepath.Join(t.TempDir(), "mirror.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer m.Close()
+
+	t0 := time.Unix(1700000000, 0)
+	refresh := func(at time.Time, objects ...anytype.Object) {
+		t.Helper()
+		if _, err := m.Refresh("space1", "contact", objects, at); err != nil {
+			t.Fatalf("Refresh() error = %v", err)
+		}
+	}
+	refresh(t0,
+		object("a", "Jane Smith", "jane@example.com", "1"),
+		object("b", "John Doe", "john@example.com", "1"))
+	// Unchanged objects without a modification date are not journaled
+	refresh(t0.Add(time.Minute),
+		object("a", "Jane Smith", "jane@example.com", "1"),
+		object("b", "John Doe", "john@example.com", "1"),
+		object("c", "Ana García", "ana@example.com", ""))
+	refresh(t0.Add(2*time.Minute),
+		object("a", "Jane Smith", "jane@example.com", "2"),
+		object("c", "Ana García", "ana@example.com", ""))
+	refresh(t0.Add(3*time.Minute),
+		object("a", "Jane Smith", "jane@example.com", "2"),
+		object("c", "Ana García", "ana@acme.com", ""))
+
+	tests := []struct {
+		name  string
+		since time.Time
+		want  []string
+	}{
+		{"everything", time.Time{}, []string{"a:created", "b:deleted", "c:created"}},
+		{"after the first refresh", t0, []string{"a:updated", "b:deleted", "c:created"}},
+		{"after the third refresh", t0.Add(2 * time.Minute), []string{"c:updated"}},
+		{"after the last refresh", t0.Add(3 * time.Minute), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := m.Changes("space1", tt.since)
+			if err != nil {
+				t.Fatalf("Changes() error = %v", err)
+			}
+			var got []string
+			for _, c := range changes {
+				got = append(got, c.ObjectID+":"+c.Op)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("Changes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, ok, err := m.LastExport("space1"); ok || err != nil {
+		t.Errorf("LastExport() before any export = %v, %v", ok, err)
+	}
+	if err := m.MarkExported("space1", t0.Add(2*time.Minute)); err != nil {
+		t.Fatalf("MarkExported() error = %v", err)
+	}
+	if at, ok, err := m.LastExport("space1"); !ok || err != nil || !at.Equal(t0.Add(2*time.Minute)) {
+		t.Errorf("LastExport() = %v, %v, %v", at, ok, err)
+	}
+}
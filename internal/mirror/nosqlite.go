@@ -0,0 +1,7 @@
+//go:build !cgo || nomirror
+
+package mirror
+
+// available reports whether the build includes SQLite. The driver needs
+// cgo, and the nomirror build tag leaves it out.
+const available = false
@@ -0,0 +1,15 @@
+//go:build !cgo || nomirror
+
+package mirror
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenUnavailable(t *testing.T) {
+	if _, err := Open(filepath.Join(t.TempDir(), "mirror.db")); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Open() error = %v, want ErrUnavailable", err)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build cgo && !nomirror
+
+package mirror
+
+import _ "github.com/mattn/go-sqlite3" // Needs cgo
+
+// available reports whether the build includes SQLite
+const available = true
@@ -0,0 +1,144 @@
+// Package mxcheck tells whether email domains can receive mail, that is
+// whether they have MX records or, failing that, an address record mail
+// is delivered to (RFC 5321 implicit MX).
+package mxcheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds the lookups of a domain
+const DefaultTimeout = 5 * time.Second
+
+// DefaultConcurrency is the number of domains looked up at once
+const DefaultConcurrency = 16
+
+// Resolver is the subset of net.Resolver the checker uses
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// Status is the outcome of checking a domain
+type Status int
+
+const (
+	StatusOK      Status = iota // The domain accepts mail
+	StatusDead                  // The domain doesn't exist or has no MX or address records
+	StatusNoMail                // The domain declares it accepts no mail (null MX, RFC 7505)
+	StatusUnknown               // The lookups failed or timed out
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusOK:
+		return "ok"
+	case StatusDead:
+		return "has no MX or A records"
+	case StatusNoMail:
+		return "accepts no mail"
+	default:
+		return "could not be checked"
+	}
+}
+
+// Checker looks up email domains, each once per Checker
+type Checker struct {
+	Resolver    Resolver
+	Timeout     time.Duration
+	Concurrency int
+
+	mu    sync.Mutex
+	cache map[string]Status
+}
+
+// New creates a Checker using the system resolver
+func New() *Checker {
+	return &Checker{Resolver: net.DefaultResolver, Timeout: DefaultTimeout, Concurrency: DefaultConcurrency}
+}
+
+// Check looks up the domains concurrently and returns the status of each,
+// by lowercase domain
+func (c *Checker) Check(ctx context.Context, domains []string) map[string]Status {
+	c.mu.Lock()
+	if c.cache == nil {
+		c.cache = make(map[string]Status)
+	}
+	var pending []string
+	seen := make(map[string]bool)
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(d), "."))
+		if _, ok := c.cache[d]; !ok && d != "" && !seen[d] {
+			pending = append(pending, d)
+			seen[d] = true
+		}
+	}
+	c.mu.Unlock()
+
+	sem := make(chan struct{}, max(c.Concurrency, 1))
+	var wg sync.WaitGroup
+	for _, d := range pending {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			status := c.lookup(ctx, d)
+			c.mu.Lock()
+			c.cache[d] = status
+			c.mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	statuses := make(map[string]Status, len(domains))
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(d), "."))
+		if status, ok := c.cache[d]; ok {
+			statuses[d] = status
+		}
+	}
+	return statuses
+}
+
+// lookup checks a domain: its MX records, else its address records
+func (c *Checker) lookup(ctx context.Context, domain string) Status {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	mxs, err := c.Resolver.LookupMX(ctx, domain)
+	switch {
+	case err == nil && len(mxs) == 1 && (mxs[0].Host == "." || mxs[0].Host == ""):
+		return StatusNoMail
+	case err == nil && len(mxs) > 0:
+		return StatusOK
+	case err != nil && !notFound(err):
+		return StatusUnknown
+	}
+
+	addrs, err := c.Resolver.LookupHost(ctx, domain)
+	switch {
+	case err == nil && len(addrs) > 0:
+		return StatusOK
+	case err == nil || notFound(err):
+		return StatusDead
+	default:
+		return StatusUnknown
+	}
+}
+
+// notFound reports whether err says the name or its records don't exist,
+// as opposed to the lookup failing
+func notFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
@@ -0,0 +1,77 @@
+package mxcheck
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeResolver answers from fixed records and counts MX lookups
+type fakeResolver struct {
+	mx    map[string][]*net.MX
+	hosts map[string][]string
+	slow  map[string]bool
+
+	mu      sync.Mutex
+	lookups int
+}
+
+func (r *fakeResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	r.mu.Lock()
+	r.lookups++
+	r.mu.Unlock()
+	if r.slow[name] {
+		<-ctx.Done()
+		return nil, &net.DNSError{Err: "timeout", Name: name, IsTimeout: true}
+	}
+	if mx, ok := r.mx[name]; ok {
+		return mx, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if addrs, ok := r.hosts[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestChecker_Check(t *testing.T) {
+	r := &fakeResolver{
+		mx: map[string][]*net.MX{
+			"acme.com":   {{Host: "mx.acme.com.", Pref: 10}},
+			"nomail.com": {{Host: ".", Pref: 0}},
+		},
+		hosts: map[string][]string{"implicit.com": {"192.0.2.1"}},
+		slow:  map[string]bool{"slow.com": true},
+	}
+	c := &Checker{Resolver: r, Timeout: 20 * time.Millisecond, Concurrency: 2}
+
+	got := c.Check(context.Background(), []string{"acme.com", "ACME.com", "nomail.com", "implicit.com", "defunct.com", "slow.com"})
+	want := map[string]Status{
+		"acme.com":     StatusOK,
+		"nomail.com":   StatusNoMail,
+		"implicit.com": StatusOK,
+		"defunct.com":  StatusDead,
+		"slow.com":     StatusUnknown,
+	}
+	if len(got) != len(want) {
+		t.Errorf("Check() = %v, want %v", got, want)
+	}
+	for domain, status := range want {
+		if got[domain] != status {
+			t.Errorf("Check()[%s] = %v, want %v", domain, got[domain], status)
+		}
+	}
+	if r.lookups != 5 {
+		t.Errorf("%d MX lookups, want one per domain", r.lookups)
+	}
+
+	c.Check(context.Background(), []string{"acme.com", "defunct.com"})
+	if r.lookups != 5 {
+		t.Errorf("cached domains looked up again: %d MX lookups", r.lookups)
+	}
+}
@@ -0,0 +1,87 @@
+// Package notify reports finished runs to a webhook.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Run statuses
+const (
+	StatusOK     = "ok"
+	StatusFailed = "failed"
+)
+
+// Summary describes a finished run. Text is a one-line description so chat
+// webhooks (Slack, Mattermost, ntfy) display something readable.
+type Summary struct {
+	Text       string    `json:"text"`
+	SessionID  string    `json:"session_id"`
+	Command    string    `json:"command"`
+	SpaceID    string    `json:"space_id"`
+	Status     string    `json:"status"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Contacts   int       `json:"contacts"`
+	Imported   int       `json:"imported"`
+	Merged     int       `json:"merged"`
+	Skipped    int       `json:"skipped"`
+	Blocked    int       `json:"blocked,omitempty"`
+	Failed     int       `json:"failed"`
+	Errors     []string  `json:"errors,omitempty"`
+
+	ContactsPerSecond float64 `json:"contacts_per_second"`
+	APICalls          int64   `json:"api_calls"`
+	BytesUploaded     int64   `json:"bytes_uploaded"`
+}
+
+// NewSessionID returns a random identifier for a run
+func NewSessionID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Post sends the summary as JSON to url, filling in Text when empty
+func Post(ctx context.Context, client *http.Client, url string, s Summary) error {
+	if s.Text == "" {
+		s.Text = s.describe()
+	}
+	body, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s Summary) describe() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "any-vcard %s %s: %d imported, %d merged, %d skipped, %d failed of %d contact(s)",
+		s.Command, s.Status, s.Imported, s.Merged, s.Skipped, s.Failed, s.Contacts)
+	if s.Status == StatusFailed && len(s.Errors) > 0 {
+		fmt.Fprintf(&b, " (%s)", s.Errors[len(s.Errors)-1])
+	}
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPost(t *testing.T) {
+	var got Summary
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	s := Summary{
+		SessionID: "abc",
+		Command:   "import",
+		Status:    StatusFailed,
+		Contacts:  10,
+		Imported:  7,
+		Failed:    3,
+		Errors:    []string{"contact 4: boom"},
+	}
+	if err := Post(context.Background(), srv.Client(), srv.URL, s); err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	if got.SessionID != "abc" || got.Imported != 7 || len(got.Errors) != 1 {
+		t.Errorf("posted summary = %+v", got)
+	}
+	if !strings.Contains(got.Text, "import failed: 7 imported") || !strings.Contains(got.Text, "boom") {
+		t.Errorf("Text = %q", got.Text)
+	}
+}
+
+func TestPost_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	if err := Post(context.Background(), srv.Client(), srv.URL, Summary{}); err == nil {
+		t.Error("Post() error = nil, want status error")
+	}
+}
+
+func TestNewSessionID(t *testing.T) {
+	a, b := NewSessionID(), NewSessionID()
+	if len(a) != 16 || a == b {
+		t.Errorf("NewSessionID() = %q, %q", a, b)
+	}
+}
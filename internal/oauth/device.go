@@ -0,0 +1,99 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultPollInterval is used when the provider does not send an interval
+var defaultPollInterval = 5 * time.Second
+
+// DeviceCode is the device authorization response (RFC 8628 section 3.2)
+type DeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+	Message         string `json:"message"`
+}
+
+// DeviceFlow runs the device authorization grant. prompt is called with the
+// code the user must enter at the verification URI; the token endpoint is
+// then polled until the user approves, denies or the code expires.
+func (c *Config) DeviceFlow(ctx context.Context, prompt func(code *DeviceCode)) (*Token, error) {
+	code, err := c.requestDeviceCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prompt(code)
+
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	expires := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+
+	values := url.Values{}
+	values.Set("grant_type", "urn:ietf:params:oauth:grant-type:device_code")
+	values.Set("device_code", code.DeviceCode)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		if code.ExpiresIn > 0 && time.Now().After(expires) {
+			return nil, fmt.Errorf("device code expired before authorization completed")
+		}
+
+		tok, tr, err := c.requestToken(ctx, values)
+		if err == nil {
+			return tok, nil
+		}
+		if tr == nil {
+			return nil, err
+		}
+		switch tr.Error {
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		default:
+			return nil, err
+		}
+	}
+}
+
+func (c *Config) requestDeviceCode(ctx context.Context) (*DeviceCode, error) {
+	values := url.Values{}
+	values.Set("client_id", c.ClientID)
+	values.Set("scope", strings.Join(c.Scopes, " "))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.DeviceAuthURL, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("device authorization request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("device authorization request failed with status %d", resp.StatusCode)
+	}
+	var code DeviceCode
+	if err := json.NewDecoder(resp.Body).Decode(&code); err != nil {
+		return nil, fmt.Errorf("failed to decode device authorization response: %w", err)
+	}
+	return &code, nil
+}
@@ -0,0 +1,99 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// LoopbackFlow runs the authorization code flow with PKCE, receiving the
+// redirect on a temporary local HTTP server. openURL is called with the
+// URL the user must visit to grant access.
+func (c *Config) LoopbackFlow(ctx context.Context, openURL func(authURL string)) (*Token, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start local callback server: %w", err)
+	}
+	defer listener.Close()
+	redirectURI := "http://" + listener.Addr().String() + "/callback"
+
+	verifier, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+	state, err := randomString(16)
+	if err != nil {
+		return nil, err
+	}
+	challenge := sha256.Sum256([]byte(verifier))
+
+	q := url.Values{}
+	q.Set("client_id", c.ClientID)
+	q.Set("redirect_uri", redirectURI)
+	q.Set("response_type", "code")
+	q.Set("scope", strings.Join(c.Scopes, " "))
+	q.Set("state", state)
+	q.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	q.Set("code_challenge_method", "S256")
+	q.Set("access_type", "offline") // Ask Google for a refresh token
+	openURL(c.AuthURL + "?" + q.Encode())
+
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/callback" {
+			http.NotFound(w, r)
+			return
+		}
+		params := r.URL.Query()
+		switch {
+		case params.Get("state") != state:
+			results <- result{err: fmt.Errorf("authorization failed: state mismatch")}
+		case params.Get("error") != "":
+			results <- result{err: fmt.Errorf("authorization failed: %s", params.Get("error"))}
+		default:
+			results <- result{code: params.Get("code")}
+		}
+		fmt.Fprintln(w, "Authorization complete. You can close this window and return to the terminal.")
+	})}
+	go srv.Serve(listener)
+	defer srv.Close()
+
+	var res result
+	select {
+	case res = <-results:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+
+	values := url.Values{}
+	values.Set("grant_type", "authorization_code")
+	values.Set("code", res.code)
+	values.Set("redirect_uri", redirectURI)
+	values.Set("code_verifier", verifier)
+
+	tok, _, err := c.requestToken(ctx, values)
+	return tok, err
+}
+
+// randomString returns n random bytes encoded as unpadded base64url
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
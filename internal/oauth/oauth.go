@@ -0,0 +1,222 @@
+// Package oauth implements the OAuth 2.0 flows used to pull contacts from
+// online address books: the loopback redirect flow with PKCE for desktop
+// apps, the device authorization grant, token refresh, and an on-disk
+// token store.
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Config describes an OAuth 2.0 client and provider endpoints
+type Config struct {
+	ClientID      string
+	ClientSecret  string
+	AuthURL       string
+	TokenURL      string
+	DeviceAuthURL string
+	Scopes        []string
+	HTTPClient    *http.Client
+}
+
+// Token is an OAuth 2.0 access token with its refresh token
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+}
+
+// Valid reports whether the access token can still be used
+func (t *Token) Valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	// Treat tokens about to expire as expired to avoid mid-request failures
+	return t.Expiry.IsZero() || time.Until(t.Expiry) > time.Minute
+}
+
+// tokenResponse is the token endpoint response (RFC 6749 section 5.1)
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (c *Config) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// requestToken posts form values to the token endpoint
+func (c *Config) requestToken(ctx context.Context, values url.Values) (*Token, *tokenResponse, error) {
+	values.Set("client_id", c.ClientID)
+	if c.ClientSecret != "" {
+		values.Set("client_secret", c.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tr tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if tr.Error != "" {
+		return nil, &tr, fmt.Errorf("token request failed: %s: %s", tr.Error, tr.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK || tr.AccessToken == "" {
+		return nil, &tr, fmt.Errorf("token request failed with status %d", resp.StatusCode)
+	}
+
+	tok := &Token{
+		AccessToken:  tr.AccessToken,
+		RefreshToken: tr.RefreshToken,
+		TokenType:    tr.TokenType,
+	}
+	if tr.ExpiresIn > 0 {
+		tok.Expiry = time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	}
+	return tok, &tr, nil
+}
+
+// Refresh exchanges a refresh token for a new access token
+func (c *Config) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	values := url.Values{}
+	values.Set("grant_type", "refresh_token")
+	values.Set("refresh_token", refreshToken)
+
+	tok, _, err := c.requestToken(ctx, values)
+	if err != nil {
+		return nil, err
+	}
+	// Providers may omit the refresh token when it is unchanged
+	if tok.RefreshToken == "" {
+		tok.RefreshToken = refreshToken
+	}
+	return tok, nil
+}
+
+// AuthorizeFunc runs an interactive flow to obtain a new token
+type AuthorizeFunc func(ctx context.Context) (*Token, error)
+
+// Client returns an HTTP client authorized with a token from store. Expired
+// tokens are refreshed; when no usable token exists authorize is called.
+// New tokens are saved back to the store.
+func (c *Config) Client(ctx context.Context, store *FileStore, authorize AuthorizeFunc) (*http.Client, error) {
+	tok, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if !tok.Valid() && tok != nil && tok.RefreshToken != "" {
+		refreshed, err := c.Refresh(ctx, tok.RefreshToken)
+		if err == nil {
+			tok = refreshed
+			if err := store.Save(tok); err != nil {
+				return nil, err
+			}
+		} else {
+			tok = nil
+		}
+	}
+
+	if !tok.Valid() {
+		if tok, err = authorize(ctx); err != nil {
+			return nil, err
+		}
+		if err := store.Save(tok); err != nil {
+			return nil, err
+		}
+	}
+
+	return &http.Client{
+		Transport: &bearerTransport{token: tok.AccessToken, base: c.httpClient().Transport},
+	}, nil
+}
+
+// bearerTransport adds the access token to every request
+type bearerTransport struct {
+	token string
+	base  http.RoundTripper
+}
+
+func (t *bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return base.RoundTrip(req)
+}
+
+// FileStore persists a token as JSON on disk
+type FileStore struct {
+	Path string
+}
+
+// DefaultStore returns a token store under the user config dir
+func DefaultStore(name string) (*FileStore, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return &FileStore{Path: filepath.Join(dir, "any-vcard", name+"-token.json")}, nil
+}
+
+// Load reads the stored token. Returns nil without error when none is stored.
+func (s *FileStore) Load() (*Token, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token: %w", err)
+	}
+	var tok Token
+	if err := json.Unmarshal(data, &tok); err != nil {
+		return nil, fmt.Errorf("failed to parse token %s: %w", s.Path, err)
+	}
+	return &tok, nil
+}
+
+// Save writes the token, readable only by the current user
+func (s *FileStore) Save(tok *Token) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o700); err != nil {
+		return fmt.Errorf("failed to create token directory: %w", err)
+	}
+	data, err := json.MarshalIndent(tok, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.Path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+	return nil
+}
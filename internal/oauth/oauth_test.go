@@ -0,0 +1,158 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestClient_RefreshesExpiredToken(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			if err := r.ParseForm(); err != nil {
+				t.Fatal(err)
+			}
+			if r.PostForm.Get("grant_type") != "refresh_token" || r.PostForm.Get("refresh_token") != "refresh-1" {
+				t.Errorf("unexpected token request: %v", r.PostForm)
+			}
+			fmt.Fprint(w, `{"access_token":"access-2","expires_in":3600,"token_type":"Bearer"}`)
+		case "/api":
+			gotAuth = r.Header.Get("Authorization")
+		}
+	}))
+	defer srv.Close()
+
+	store := &FileStore{Path: filepath.Join(t.TempDir(), "token.json")}
+	expired := &Token{AccessToken: "access-1", RefreshToken: "refresh-1", Expiry: time.Now().Add(-time.Hour)}
+	if err := store.Save(expired); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &Config{ClientID: "id", TokenURL: srv.URL + "/token"}
+	client, err := config.Client(context.Background(), store, func(ctx context.Context) (*Token, error) {
+		t.Fatal("authorize should not be called when refresh succeeds")
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Client() error = %v", err)
+	}
+
+	resp, err := client.Get(srv.URL + "/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if gotAuth != "Bearer access-2" {
+		t.Errorf("Authorization = %q, want Bearer access-2", gotAuth)
+	}
+
+	saved, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.AccessToken != "access-2" || saved.RefreshToken != "refresh-1" {
+		t.Errorf("saved token = %+v, want refreshed token keeping refresh-1", saved)
+	}
+}
+
+func TestClient_AuthorizesWithoutToken(t *testing.T) {
+	store := &FileStore{Path: filepath.Join(t.TempDir(), "token.json")}
+	config := &Config{ClientID: "id"}
+
+	called := false
+	_, err := config.Client(context.Background(), store, func(ctx context.Context) (*Token, error) {
+		called = true
+		return &Token{AccessToken: "new"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !called {
+		t.Error("expected authorize to be called")
+	}
+	if tok, _ := store.Load(); tok == nil || tok.AccessToken != "new" {
+		t.Errorf("token not saved: %+v", tok)
+	}
+}
+
+func TestToken_Valid(t *testing.T) {
+	var nilToken *Token
+	if nilToken.Valid() {
+		t.Error("nil token should be invalid")
+	}
+	if (&Token{AccessToken: "x", Expiry: time.Now().Add(30 * time.Second)}).Valid() {
+		t.Error("token about to expire should be invalid")
+	}
+	if !(&Token{AccessToken: "x"}).Valid() {
+		t.Error("token without expiry should be valid")
+	}
+}
+
+func TestDeviceFlow(t *testing.T) {
+	defaultPollInterval = 10 * time.Millisecond
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		switch r.URL.Path {
+		case "/devicecode":
+			fmt.Fprint(w, `{"device_code":"dev-1","user_code":"ABCD-EFGH","verification_uri":"https://example.com/device","expires_in":60,"interval":0}`)
+		case "/token":
+			if r.PostForm.Get("device_code") != "dev-1" {
+				t.Errorf("unexpected device_code %q", r.PostForm.Get("device_code"))
+			}
+			polls++
+			if polls < 2 {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":"authorization_pending"}`)
+				return
+			}
+			fmt.Fprint(w, `{"access_token":"access-1","refresh_token":"refresh-1","expires_in":3600}`)
+		}
+	}))
+	defer srv.Close()
+
+	config := &Config{ClientID: "id", TokenURL: srv.URL + "/token", DeviceAuthURL: srv.URL + "/devicecode"}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var userCode string
+	tok, err := config.DeviceFlow(ctx, func(code *DeviceCode) { userCode = code.UserCode })
+	if err != nil {
+		t.Fatalf("DeviceFlow() error = %v", err)
+	}
+	if userCode != "ABCD-EFGH" {
+		t.Errorf("prompted user code = %q", userCode)
+	}
+	if tok.AccessToken != "access-1" || polls != 2 {
+		t.Errorf("token = %+v after %d polls", tok, polls)
+	}
+}
+
+func TestDeviceFlow_Denied(t *testing.T) {
+	defaultPollInterval = 10 * time.Millisecond
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/devicecode":
+			fmt.Fprint(w, `{"device_code":"dev-1","user_code":"X","expires_in":60}`)
+		case "/token":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"access_denied"}`)
+		}
+	}))
+	defer srv.Close()
+
+	config := &Config{ClientID: "id", TokenURL: srv.URL + "/token", DeviceAuthURL: srv.URL + "/devicecode"}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, err := config.DeviceFlow(ctx, func(*DeviceCode) {}); err == nil {
+		t.Error("expected error when the user denies access")
+	}
+}
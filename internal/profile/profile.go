@@ -0,0 +1,153 @@
+// Package profile measures where the time of a run goes. Code wraps its
+// phases in Start calls, which cost nothing until Enable is called.
+package profile
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Phases of a run. Each call is timed under one of them and they don't
+// overlap, so their durations add up to at most the run's.
+const (
+	Parse  = "parse"  // Reading and parsing the input
+	Photos = "photos" // Downloading and encoding photos
+	Dedup  = "dedup"  // Indexing contacts and finding duplicates
+	API    = "api"    // Anytype API requests
+	Wait   = "wait"   // Waiting for the server to apply schema changes
+)
+
+// phases lists the phases in report order
+var phases = []string{Parse, Photos, Dedup, API, Wait}
+
+type phase struct {
+	total time.Duration
+	calls int
+}
+
+// Counters kept even when timing is disabled, for run summaries
+var (
+	apiCalls      atomic.Int64
+	bytesUploaded atomic.Int64
+)
+
+var (
+	enabled atomic.Bool
+	mu      sync.Mutex
+	started time.Time
+	timings = make(map[string]*phase)
+)
+
+// Enable starts recording the phases
+func Enable() {
+	mu.Lock()
+	defer mu.Unlock()
+	started = time.Now()
+	clear(timings)
+	enabled.Store(true)
+}
+
+// Start begins timing a call of a phase. Call the returned function when
+// it ends. API calls are counted even when timing is disabled.
+func Start(name string) func() {
+	if name == API {
+		apiCalls.Add(1)
+	}
+	if !enabled.Load() {
+		return func() {}
+	}
+	t := time.Now()
+	return func() {
+		d := time.Since(t)
+		mu.Lock()
+		defer mu.Unlock()
+		p, ok := timings[name]
+		if !ok {
+			p = &phase{}
+			timings[name] = p
+		}
+		p.total += d
+		p.calls++
+	}
+}
+
+// APICalls returns the number of API requests started so far
+func APICalls() int64 {
+	return apiCalls.Load()
+}
+
+// AddUploaded counts n bytes sent to the API
+func AddUploaded(n int) {
+	bytesUploaded.Add(int64(n))
+}
+
+// BytesUploaded returns the number of bytes sent to the API so far
+func BytesUploaded() int64 {
+	return bytesUploaded.Load()
+}
+
+// Report writes the time spent in each phase since Enable. Time not
+// spent in any phase is reported as "other".
+func Report(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	total := time.Since(started)
+	fmt.Fprintf(w, "\nProfile (%s total):\n", total.Round(time.Millisecond))
+	other := total
+	for _, name := range phases {
+		p, ok := timings[name]
+		if !ok {
+			continue
+		}
+		other -= p.total
+		fmt.Fprintf(w, "  %-7s %10s %6.1f%% %8d calls\n", name, p.total.Round(time.Millisecond), share(p.total, total), p.calls)
+	}
+	fmt.Fprintf(w, "  %-7s %10s %6.1f%%\n", "other", max(other, 0).Round(time.Millisecond), share(max(other, 0), total))
+}
+
+func share(d, total time.Duration) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(d) / float64(total) * 100
+}
+
+// StartPprof writes a CPU profile to cpu.pprof in dir until the returned
+// function is called, which also writes a heap profile to heap.pprof
+func StartPprof(dir string) (stop func() error, err error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	cpu, err := os.Create(filepath.Join(dir, "cpu.pprof"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+	}
+	if err := pprof.StartCPUProfile(cpu); err != nil {
+		cpu.Close()
+		return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+	}
+
+	return func() error {
+		pprof.StopCPUProfile()
+		if err := cpu.Close(); err != nil {
+			return fmt.Errorf("failed to write CPU profile: %w", err)
+		}
+		heap, err := os.Create(filepath.Join(dir, "heap.pprof"))
+		if err != nil {
+			return fmt.Errorf("failed to create heap profile: %w", err)
+		}
+		runtime.GC() // Up-to-date statistics
+		if err := pprof.WriteHeapProfile(heap); err != nil {
+			heap.Close()
+			return fmt.Errorf("failed to write heap profile: %w", err)
+		}
+		return heap.Close()
+	}, nil
+}
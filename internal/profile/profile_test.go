@@ -0,0 +1,41 @@
+package profile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReport(t *testing.T) {
+	calls := APICalls()
+	Start(API)() // Counted, but not timed before Enable
+	if got := APICalls() - calls; got != 1 {
+		t.Errorf("APICalls() grew by %d, want 1", got)
+	}
+
+	Enable()
+	defer enabled.Store(false)
+	for range 2 {
+		stop := Start(API)
+		time.Sleep(time.Millisecond)
+		stop()
+	}
+	Start(Parse)()
+
+	var buf bytes.Buffer
+	Report(&buf)
+	out := buf.String()
+
+	for _, want := range []string{"parse", "1 calls", "api", "2 calls", "other"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "dedup") {
+		t.Errorf("report lists a phase without calls:\n%s", out)
+	}
+	if strings.Index(out, "parse") > strings.Index(out, "api") {
+		t.Errorf("phases out of order:\n%s", out)
+	}
+}
@@ -0,0 +1,82 @@
+// Package progress streams the progress of a run as newline-delimited JSON
+// events, so programs wrapping the CLI can show it live instead of parsing
+// the human output.
+package progress
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Event kinds
+const (
+	Parsed  = "parsed"  // Contacts were read from a source
+	Deduped = "deduped" // A contact matched an existing one and was merged or skipped
+	Created = "created" // A contact was written as a new object
+	Failed  = "failed"  // A contact could not be written
+	Done    = "done"    // A space finished importing
+)
+
+// Event is a line of the stream. Fields not relevant to the kind are left
+// out.
+type Event struct {
+	Event    string    `json:"event"`
+	Time     time.Time `json:"time"`
+	SpaceID  string    `json:"space_id,omitempty"`
+	Source   string    `json:"source,omitempty"`
+	Contact  string    `json:"contact,omitempty"`   // Display name
+	ObjectID string    `json:"object_id,omitempty"` // Created or merged into
+	Action   string    `json:"action,omitempty"`    // merged or skipped, for deduped
+	Match    string    `json:"match,omitempty"`     // Why a contact is a duplicate
+	Count    int       `json:"count,omitempty"`     // Contacts parsed
+	Error    string    `json:"error,omitempty"`
+	Totals   *Totals   `json:"totals,omitempty"`
+}
+
+// Totals counts the outcomes of a space, in done events
+type Totals struct {
+	Contacts int `json:"contacts"`
+	Imported int `json:"imported"`
+	Merged   int `json:"merged"`
+	Replaced int `json:"replaced"`
+	Skipped  int `json:"skipped"`
+	Failed   int `json:"failed"`
+}
+
+var (
+	mu  sync.Mutex
+	enc *json.Encoder // Nil when not streaming
+)
+
+// SetOutput streams the events to w, or stops streaming when w is nil
+func SetOutput(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	enc = nil
+	if w != nil {
+		enc = json.NewEncoder(w)
+	}
+}
+
+// Enabled reports whether events are streamed
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enc != nil
+}
+
+// Emit writes e, stamped with the current time when it has none. It does
+// nothing when not streaming.
+func Emit(e Event) {
+	mu.Lock()
+	defer mu.Unlock()
+	if enc == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	enc.Encode(e) // Progress is best effort, a closed reader mustn't fail the run
+}
@@ -0,0 +1,46 @@
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestEmit(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(nil)
+
+	Emit(Event{Event: Parsed, Source: "contacts.vcf", Count: 2})
+	Emit(Event{Event: Deduped, Contact: "Jane Doe", Action: "skipped", Match: "email"})
+	Emit(Event{Event: Done, SpaceID: "sp", Totals: &Totals{Contacts: 2, Imported: 1, Skipped: 1}})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+	var kinds []string
+	for _, line := range lines {
+		var e Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("invalid line %q: %v", line, err)
+		}
+		if e.Time.IsZero() {
+			t.Errorf("event %q has no time", e.Event)
+		}
+		kinds = append(kinds, e.Event)
+	}
+	if got := strings.Join(kinds, ","); got != "parsed,deduped,done" {
+		t.Errorf("events = %s, want parsed,deduped,done", got)
+	}
+	if strings.Contains(lines[0], `"contact"`) || strings.Contains(lines[0], `"totals"`) {
+		t.Errorf("parsed event has unrelated fields: %s", lines[0])
+	}
+
+	SetOutput(nil)
+	Emit(Event{Event: Failed})
+	if Enabled() || len(strings.Split(strings.TrimSpace(buf.String()), "\n")) != 3 {
+		t.Error("Emit() wrote after SetOutput(nil)")
+	}
+}
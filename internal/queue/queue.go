@@ -0,0 +1,119 @@
+// Package queue keeps contacts that could not be imported because the
+// Anytype API was unreachable in an on-disk JSON journal, so a later run
+// can replay them instead of losing the cycle.
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// Entry is a batch of contacts queued for a space
+type Entry struct {
+	SpaceID  string          `json:"space_id"`
+	Queued   time.Time       `json:"queued"`
+	Contacts []vcard.Contact `json:"contacts"`
+}
+
+// Queue holds the queued batches, oldest first
+type Queue struct {
+	path    string
+	entries []Entry
+	dirty   bool
+}
+
+// DefaultPath returns the queue file location under the user cache dir
+func DefaultPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "any-vcard", "queue.json"), nil
+}
+
+// Load reads the queue file at path, if present
+func Load(path string) (*Queue, error) {
+	q := &Queue{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read offline queue: %w", err)
+	}
+	// Unlike the caches, a broken queue is not dropped: it holds contacts
+	// that were never written anywhere else
+	if err := json.Unmarshal(data, &q.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse offline queue %s: %w", path, err)
+	}
+	return q, nil
+}
+
+// Add queues contacts for spaceID
+func (q *Queue) Add(spaceID string, contacts []vcard.Contact) {
+	if len(contacts) == 0 {
+		return
+	}
+	q.entries = append(q.entries, Entry{SpaceID: spaceID, Queued: time.Now(), Contacts: contacts})
+	q.dirty = true
+}
+
+// Contacts returns the contacts queued for spaceID, oldest first
+func (q *Queue) Contacts(spaceID string) []vcard.Contact {
+	var contacts []vcard.Contact
+	for _, e := range q.entries {
+		if e.SpaceID == spaceID {
+			contacts = append(contacts, e.Contacts...)
+		}
+	}
+	return contacts
+}
+
+// Drop removes the contacts queued for spaceID, once they are imported
+func (q *Queue) Drop(spaceID string) {
+	n := len(q.entries)
+	q.entries = slices.DeleteFunc(q.entries, func(e Entry) bool { return e.SpaceID == spaceID })
+	q.dirty = q.dirty || len(q.entries) != n
+}
+
+// Save writes the queue back to disk if it changed, removing the file
+// once nothing is left in it
+func (q *Queue) Save() error {
+	if !q.dirty {
+		return nil
+	}
+	if len(q.entries) == 0 {
+		if err := os.Remove(q.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove offline queue: %w", err)
+		}
+		q.dirty = false
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(q.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(q.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Contacts are personal data, keep them private
+	tmp := q.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write offline queue: %w", err)
+	}
+	if err := os.Rename(tmp, q.path); err != nil {
+		return fmt.Errorf("failed to write offline queue: %w", err)
+	}
+	q.dirty = false
+	return nil
+}
@@ -0,0 +1,64 @@
+package queue
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+func TestQueue_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+	q, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	q.Add("space1", []vcard.Contact{{FormattedName: "Jane Smith"}})
+	q.Add("space2", []vcard.Contact{{FormattedName: "John Doe"}})
+	q.Add("space1", []vcard.Contact{{FormattedName: "Ana García", Emails: []string{"ana@example.com"}}})
+	q.Add("space1", nil)
+	if err := q.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	q, err = Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got := q.Contacts("space1")
+	if len(got) != 2 || got[0].FormattedName != "Jane Smith" || got[1].Emails[0] != "ana@example.com" {
+		t.Errorf("Contacts(space1) = %+v", got)
+	}
+	if got := q.Contacts("space3"); len(got) != 0 {
+		t.Errorf("Contacts(space3) = %+v, want none", got)
+	}
+
+	q.Drop("space1")
+	if err := q.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	q, _ = Load(path)
+	if len(q.Contacts("space1")) != 0 || len(q.Contacts("space2")) != 1 {
+		t.Errorf("after Drop(space1): space1 %d, space2 %d contact(s)", len(q.Contacts("space1")), len(q.Contacts("space2")))
+	}
+
+	// An empty queue leaves no file behind
+	q.Drop("space2")
+	if err := q.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("queue file still exists: %v", err)
+	}
+}
+
+func TestLoad_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() of a corrupt queue succeeded, want error")
+	}
+}
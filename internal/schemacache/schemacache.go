@@ -0,0 +1,192 @@
+// Package schemacache keeps the object types and properties of spaces in
+// an on-disk JSON file, so routine runs skip the metadata round trips.
+// Entries expire after a TTL and are dropped when the schema is changed.
+package schemacache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rubiojr/any-vcard/internal/profile"
+	"github.com/rubiojr/anytype-go"
+)
+
+// DefaultTTL is how long cached metadata is trusted
+const DefaultTTL = 10 * time.Minute
+
+// Cache holds the types and properties of spaces. It is safe for
+// concurrent use.
+type Cache struct {
+	path   string
+	server string // API URL, as space IDs are only unique within a server
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu     sync.Mutex
+	spaces map[string]*space // By key
+}
+
+// space is the cached metadata of one space
+type space struct {
+	Types             []anytype.Type     `json:"types,omitempty"`
+	TypesFetched      time.Time          `json:"types_fetched,omitzero"`
+	Properties        []anytype.Property `json:"properties,omitempty"`
+	PropertiesFetched time.Time          `json:"properties_fetched,omitzero"`
+}
+
+// DefaultPath returns the cache file location under the user cache dir
+func DefaultPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "any-vcard", "schema.json"), nil
+}
+
+// Load reads the cache file at path, if present, for the spaces of the API
+// at server. A ttl of zero or less disables caching.
+func Load(path, server string, ttl time.Duration) (*Cache, error) {
+	c := &Cache{path: path, server: server, ttl: ttl, now: time.Now, spaces: make(map[string]*space)}
+	if ttl <= 0 {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.spaces); err != nil {
+		// A broken cache only costs the round trips it would have saved
+		c.spaces = make(map[string]*space)
+	}
+	return c, nil
+}
+
+// Check reports whether the cache file at path, if present, can be read
+// and parsed. Load ignores broken files, so this is the only way to find
+// them.
+func Check(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read schema cache: %w", err)
+	}
+	var spaces map[string]*space
+	if err := json.Unmarshal(data, &spaces); err != nil {
+		return fmt.Errorf("schema cache %s is corrupt: %w", path, err)
+	}
+	return nil
+}
+
+// Cached returns the cached types and properties of a space, fresh or
+// not, without fetching anything. ok is false when nothing is cached.
+func (c *Cache) Cached(spaceID string) (types []anytype.Type, properties []anytype.Property, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.spaces[c.key(spaceID)]
+	if s == nil {
+		return nil, nil, false
+	}
+	return s.Types, s.Properties, true
+}
+
+// Types returns the object types of the space, from the cache when fresh
+func (c *Cache) Types(ctx context.Context, client anytype.Client, spaceID string) ([]anytype.Type, error) {
+	return cached(c, spaceID, func(s *space) (*[]anytype.Type, *time.Time) {
+		return &s.Types, &s.TypesFetched
+	}, func() ([]anytype.Type, error) {
+		defer profile.Start(profile.API)()
+		return client.Space(spaceID).Types().List(ctx)
+	})
+}
+
+// Properties returns the properties of the space, from the cache when
+// fresh
+func (c *Cache) Properties(ctx context.Context, client anytype.Client, spaceID string) ([]anytype.Property, error) {
+	return cached(c, spaceID, func(s *space) (*[]anytype.Property, *time.Time) {
+		return &s.Properties, &s.PropertiesFetched
+	}, func() ([]anytype.Property, error) {
+		defer profile.Start(profile.API)()
+		return client.Space(spaceID).Properties().List(ctx)
+	})
+}
+
+// cached returns the list entry selects from the cached space, calling
+// fetch and caching its result when missing or expired
+func cached[T any](c *Cache, spaceID string, entry func(*space) (*[]T, *time.Time), fetch func() ([]T, error)) ([]T, error) {
+	c.mu.Lock()
+	if s := c.spaces[c.key(spaceID)]; s != nil {
+		if list, fetched := entry(s); c.fresh(*fetched) {
+			defer c.mu.Unlock()
+			return *list, nil
+		}
+	}
+	c.mu.Unlock()
+
+	list, err := fetch()
+	if err != nil || c.ttl <= 0 {
+		return list, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.spaces[c.key(spaceID)]
+	if !ok {
+		s = &space{}
+		c.spaces[c.key(spaceID)] = s
+	}
+	cachedList, fetched := entry(s)
+	*cachedList, *fetched = list, c.now()
+	c.save()
+	return list, nil
+}
+
+// Invalidate drops the cached metadata of a space, after changing its
+// types or properties
+func (c *Cache) Invalidate(spaceID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.spaces[c.key(spaceID)]; !ok {
+		return
+	}
+	delete(c.spaces, c.key(spaceID))
+	c.save()
+}
+
+// key returns the key the metadata of a space is cached under
+func (c *Cache) key(spaceID string) string {
+	return c.server + " " + spaceID
+}
+
+func (c *Cache) fresh(fetched time.Time) bool {
+	return c.ttl > 0 && c.now().Sub(fetched) < c.ttl
+}
+
+// save writes the cache file. Failures are ignored: the cache is only an
+// optimization and the next run fetches the metadata again.
+func (c *Cache) save() {
+	data, err := json.Marshal(c.spaces)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return
+	}
+	os.Rename(tmp, c.path)
+}
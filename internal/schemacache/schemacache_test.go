@@ -0,0 +1,107 @@
+package schemacache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rubiojr/anytype-go"
+)
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.json")
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	load := func(ttl time.Duration) *Cache {
+		c, err := Load(path, "http://localhost:31009", ttl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.now = func() time.Time { return now }
+		return c
+	}
+	fetches := 0
+	get := func(c *Cache, spaceID string) {
+		types, err := cached(c, spaceID, func(s *space) (*[]anytype.Type, *time.Time) {
+			return &s.Types, &s.TypesFetched
+		}, func() ([]anytype.Type, error) {
+			fetches++
+			return []anytype.Type{{Key: "contact"}}, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(types) != 1 || types[0].Key != "contact" {
+			t.Fatalf("types = %v", types)
+		}
+	}
+
+	c := load(time.Minute)
+	get(c, "space1")
+	get(load(time.Minute), "space1") // Read back from the file
+	if fetches != 1 {
+		t.Errorf("fetches = %d, want 1 within the TTL", fetches)
+	}
+
+	now = now.Add(2 * time.Minute)
+	get(c, "space1")
+	if fetches != 2 {
+		t.Errorf("fetches = %d, want 2 after the TTL", fetches)
+	}
+
+	c.Invalidate("space1")
+	get(c, "space1")
+	if fetches != 3 {
+		t.Errorf("fetches = %d, want 3 after Invalidate", fetches)
+	}
+
+	// Disabled caches always fetch
+	c = load(0)
+	get(c, "space1")
+	get(c, "space1")
+	if fetches != 5 {
+		t.Errorf("fetches = %d, want 5 with caching disabled", fetches)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if err := Check(path); err != nil {
+		t.Errorf("Check() of a missing file = %v", err)
+	}
+
+	c, err := Load(path, "http://localhost:31009", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := c.Cached("space1"); ok {
+		t.Error("Cached() of an empty cache reported an entry")
+	}
+	cached(c, "space1", func(s *space) (*[]anytype.Type, *time.Time) {
+		return &s.Types, &s.TypesFetched
+	}, func() ([]anytype.Type, error) {
+		return []anytype.Type{{Key: "contact"}}, nil
+	})
+	if types, _, ok := c.Cached("space1"); !ok || len(types) != 1 {
+		t.Errorf("Cached() = %v, %v", types, ok)
+	}
+	// Space IDs are only unique within a server
+	other, err := Load(path, "http://example.com:31009", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := other.Cached("space1"); ok {
+		t.Error("Cached() returned the entry of another server")
+	}
+	if err := Check(path); err != nil {
+		t.Errorf("Check() of a saved cache = %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Check(path); err == nil {
+		t.Error("Check() accepted a corrupt file")
+	}
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+
+	"github.com/rubiojr/any-vcard/internal/sink"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/rubiojr/anytype-go"
+)
+
+// Anytype is a Store backed by the contact objects of a space
+type Anytype struct {
+	Client anytype.Client
+	Sink   *sink.Anytype // Writes contacts; its SpaceID and TypeKey select the contacts
+}
+
+// Search implements Store
+func (a *Anytype) Search(ctx context.Context, query string) ([]*vcard.Contact, error) {
+	return vcard.SearchContacts(ctx, a.Client, a.Sink.SpaceID, a.Sink.TypeKey, query)
+}
+
+// Get implements Store
+func (a *Anytype) Get(ctx context.Context, id string) (*vcard.Contact, error) {
+	resp, err := a.Client.Space(a.Sink.SpaceID).Object(id).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return vcard.FromObject(resp.Object), nil
+}
+
+// Write implements Store
+func (a *Anytype) Write(ctx context.Context, c *vcard.Contact) error {
+	return a.Sink.Write(ctx, c)
+}
+
+// Delete implements Store
+func (a *Anytype) Delete(ctx context.Context, id string) error {
+	return a.Client.Space(a.Sink.SpaceID).Object(id).Delete(ctx)
+}
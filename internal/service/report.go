@@ -0,0 +1,50 @@
+package service
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// ReportHeader is the header row of WriteDedupeReport
+var ReportHeader = []string{
+	"cluster", "role", "object_id", "name", "emails", "phones", "organization",
+	"match_score", "match_strength", "match_reasons", "conflicts",
+}
+
+// WriteDedupeReport writes the groups as CSV, one row per contact. Each
+// cluster starts with a "survivor" row showing the proposed merged record,
+// followed by a "duplicate" row per contact that would be merged into it.
+func WriteDedupeReport(w io.Writer, groups []DedupeGroup) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(ReportHeader); err != nil {
+		return err
+	}
+	row := func(cluster int, role string, c *vcard.Contact, match ...string) []string {
+		r := []string{
+			strconv.Itoa(cluster), role, c.ObjectID, c.DisplayName(),
+			strings.Join(c.Emails, "; "), strings.Join(c.Phones, "; "), c.Organization,
+		}
+		return append(r, append(match, "", "", "", "")[:4]...)
+	}
+	for i, g := range groups {
+		if err := cw.Write(row(i+1, "survivor", g.Contact)); err != nil {
+			return err
+		}
+		for _, d := range g.Duplicates {
+			score := strconv.FormatFloat(d.Score, 'f', 2, 64)
+			conflicts := make([]string, len(d.Conflicts))
+			for j, c := range d.Conflicts {
+				conflicts[j] = c.String()
+			}
+			if err := cw.Write(row(i+1, "duplicate", d.Contact, score, d.Strength, strings.Join(d.Reasons, "; "), strings.Join(conflicts, "; "))); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,451 @@
+// Package service implements the contact operations exposed by the
+// server modes on top of a contact store.
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// Store persists contacts
+type Store interface {
+	// Search returns the contacts matching a full-text query, or all
+	// contacts when query is empty
+	Search(ctx context.Context, query string) ([]*vcard.Contact, error)
+	// Get returns the contact stored in the object with the given ID
+	Get(ctx context.Context, id string) (*vcard.Contact, error)
+	// Write creates a contact, or updates it when it has an ObjectID
+	Write(ctx context.Context, c *vcard.Contact) error
+	// Delete removes the object with the given ID
+	Delete(ctx context.Context, id string) error
+}
+
+// Duplicate handling when creating contacts
+const (
+	OnDuplicateMerge  = "merge"  // Merge new fields into the existing contact
+	OnDuplicateSkip   = "skip"   // Leave the existing contact untouched
+	OnDuplicateCreate = "create" // Create the contact anyway
+)
+
+// DefaultContactTypes are the type keys of the Contact and Company types
+// any-vcard creates
+var DefaultContactTypes = []string{"contact", "company"}
+
+// Outcomes of Create
+const (
+	Created = "created"
+	Merged  = "merged"
+	Skipped = "skipped"
+)
+
+// Service runs dedup-aware contact operations against a Store
+type Service struct {
+	Store Store
+	// ContactTypes are the type keys of contact objects, the only ones
+	// Merge merges and deletes (DefaultContactTypes when empty)
+	ContactTypes []string
+	// MinScore is the vcard.ScoreContacts confidence below which
+	// candidates are not treated as duplicates (0 accepts every candidate)
+	MinScore float64
+	// Phonetic also matches names that sound alike, see
+	// vcard.DedupIndex.EnablePhonetic
+	Phonetic bool
+	// Dedup is how contacts are matched as duplicates
+	Dedup vcard.DedupOptions
+	// NoteConflicts appends the values Dedupe discards to the notes of
+	// the surviving contact
+	NoteConflicts bool
+	// History records every Dedupe merge in the History of the surviving
+	// contact
+	History bool
+	// Where limits Dedupe to the contacts matching the query (nil
+	// considers every contact)
+	Where *vcard.Query
+}
+
+// Search returns up to limit contacts matching query (no limit when limit <= 0)
+func (s *Service) Search(ctx context.Context, query string, limit int) ([]*vcard.Contact, error) {
+	contacts, err := s.Store.Search(ctx, strings.TrimSpace(query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search contacts: %w", err)
+	}
+	if limit > 0 && len(contacts) > limit {
+		contacts = contacts[:limit]
+	}
+	return contacts, nil
+}
+
+// Get returns a contact by object ID
+func (s *Service) Get(ctx context.Context, id string) (*vcard.Contact, error) {
+	c, err := s.Store.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contact %s: %w", id, err)
+	}
+	return c, nil
+}
+
+// Resolve finds a contact by object ID or by name. A name matching
+// several contacts is an error listing them, unless exactly one has that
+// name (ignoring case).
+func (s *Service) Resolve(ctx context.Context, ref string) (*vcard.Contact, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, fmt.Errorf("contact name or ID required")
+	}
+	if !strings.ContainsAny(ref, " \t") {
+		if c, err := s.Store.Get(ctx, ref); err == nil {
+			return c, nil
+		}
+	}
+
+	found, err := s.Search(ctx, ref, 0)
+	if err != nil {
+		return nil, err
+	}
+	var exact []*vcard.Contact
+	for _, c := range found {
+		if strings.EqualFold(c.DisplayName(), ref) {
+			exact = append(exact, c)
+		}
+	}
+	if len(exact) > 0 {
+		found = exact
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no contact matches %q", ref)
+	case 1:
+		return found[0], nil
+	}
+	const maxListed = 5
+	var names []string
+	for _, c := range found[:min(len(found), maxListed)] {
+		names = append(names, fmt.Sprintf("%s (%s)", c.DisplayName(), c.ObjectID))
+	}
+	if len(found) > maxListed {
+		names = append(names, "...")
+	}
+	return nil, fmt.Errorf("%d contacts match %q, pass an object ID: %s", len(found), ref, strings.Join(names, ", "))
+}
+
+// Save stores changes to an existing contact
+func (s *Service) Save(ctx context.Context, c *vcard.Contact) error {
+	if c.ObjectID == "" {
+		return fmt.Errorf("contact %s has no object ID", c.DisplayName())
+	}
+	if err := s.Store.Write(ctx, c); err != nil {
+		return fmt.Errorf("failed to save %s: %w", c.DisplayName(), err)
+	}
+	return nil
+}
+
+// Create stores a new contact unless it duplicates an existing one, in
+// which case onDuplicate decides what happens. Only matches that
+// vcard.Joins count as duplicates, as in Dedupe: a contact that only
+// matches weakly is created, and the weak matches are returned for
+// review. It returns the outcome and the contact that was created or
+// matched.
+func (s *Service) Create(ctx context.Context, c *vcard.Contact, onDuplicate string) (string, *vcard.Contact, []WeakDuplicate, error) {
+	if err := checkOnDuplicate(onDuplicate); err != nil {
+		return "", nil, nil, err
+	}
+	idx, err := s.index(ctx, onDuplicate)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return s.create(ctx, idx, c, onDuplicate)
+}
+
+// ImportResult reports what Import did with each contact
+type ImportResult struct {
+	Created int             `json:"created"`
+	Merged  int             `json:"merged"`
+	Skipped int             `json:"skipped"`
+	Failed  int             `json:"failed"`
+	Errors  []string        `json:"errors,omitempty"`
+	Weak    []WeakDuplicate `json:"weak,omitempty"` // Created contacts that only match an existing one weakly
+}
+
+// Import creates contacts like Create, loading the existing contacts once.
+// Contacts that fail are counted and reported without stopping the import.
+func (s *Service) Import(ctx context.Context, contacts []vcard.Contact, onDuplicate string) (ImportResult, error) {
+	var res ImportResult
+	if err := checkOnDuplicate(onDuplicate); err != nil {
+		return res, err
+	}
+	idx, err := s.index(ctx, onDuplicate)
+	if err != nil {
+		return res, err
+	}
+
+	for i := range contacts {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		outcome, _, weak, err := s.create(ctx, idx, &contacts[i], onDuplicate)
+		res.Weak = append(res.Weak, weak...)
+		switch {
+		case err != nil:
+			res.Failed++
+			res.Errors = append(res.Errors, fmt.Sprintf("%s: %v", contacts[i].DisplayName(), err))
+		case outcome == Created:
+			res.Created++
+		case outcome == Merged:
+			res.Merged++
+		default:
+			res.Skipped++
+		}
+	}
+	return res, nil
+}
+
+func checkOnDuplicate(onDuplicate string) error {
+	switch onDuplicate {
+	case "", OnDuplicateMerge, OnDuplicateSkip, OnDuplicateCreate:
+		return nil
+	}
+	return fmt.Errorf("unsupported duplicate policy %q (supported: merge, skip, create)", onDuplicate)
+}
+
+// index loads the existing contacts for duplicate detection. It returns
+// nil when duplicates are created anyway.
+func (s *Service) index(ctx context.Context, onDuplicate string) (*vcard.DedupIndex, error) {
+	if onDuplicate == OnDuplicateCreate {
+		return nil, nil
+	}
+	existing, err := s.Store.Search(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load existing contacts: %w", err)
+	}
+	return s.newIndex(existing), nil
+}
+
+func (s *Service) newIndex(contacts []*vcard.Contact) *vcard.DedupIndex {
+	idx := vcard.NewDedupIndexWith(vcard.NewMemoryIndexStore(), s.Dedup, contacts)
+	if s.Phonetic {
+		idx.EnablePhonetic()
+	}
+	return idx
+}
+
+func (s *Service) create(ctx context.Context, idx *vcard.DedupIndex, c *vcard.Contact, onDuplicate string) (string, *vcard.Contact, []WeakDuplicate, error) {
+	if c.DisplayName() == "Unnamed Contact" && len(c.Emails) == 0 && len(c.Phones) == 0 {
+		return "", nil, nil, fmt.Errorf("contact needs a name, email or phone")
+	}
+	c.ObjectID = ""
+
+	var matches []vcard.Match
+	if idx != nil {
+		matches = s.findMatches(idx, c)
+		if i := slices.IndexFunc(matches, vcard.Joins); i >= 0 {
+			match := matches[i].Contact
+			if onDuplicate == OnDuplicateSkip || !s.merge(match, c) {
+				return Skipped, match, nil, nil
+			}
+			if err := s.Store.Write(ctx, match); err != nil {
+				return "", nil, nil, fmt.Errorf("failed to merge into %s: %w", match.DisplayName(), err)
+			}
+			return Merged, match, nil, nil
+		}
+	}
+
+	if err := s.Store.Write(ctx, c); err != nil {
+		return "", nil, nil, fmt.Errorf("failed to create contact: %w", err)
+	}
+	if idx != nil {
+		idx.Add(c)
+	}
+	weak := make([]WeakDuplicate, len(matches))
+	for i, m := range matches {
+		weak[i] = weakDuplicate(c, m)
+	}
+	return Created, c, weak, nil
+}
+
+// weakDuplicate describes the weak match m of c
+func weakDuplicate(c *vcard.Contact, m vcard.Match) WeakDuplicate {
+	return WeakDuplicate{
+		Contact:  c,
+		Match:    m.Contact,
+		Score:    m.Score,
+		Strength: m.Strength.String(),
+		Reasons:  m.Reasons,
+	}
+}
+
+// Merge merges the source contact into the target and deletes the source
+func (s *Service) Merge(ctx context.Context, targetID, sourceID string) (*vcard.Contact, error) {
+	if targetID == sourceID {
+		return nil, fmt.Errorf("cannot merge a contact into itself")
+	}
+	target, err := s.Get(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	src, err := s.Get(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range []*vcard.Contact{target, src} {
+		if err := s.checkContact(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.merge(target, src) {
+		if err := s.Store.Write(ctx, target); err != nil {
+			return nil, fmt.Errorf("failed to update %s: %w", target.DisplayName(), err)
+		}
+	}
+	if err := s.Store.Delete(ctx, sourceID); err != nil {
+		return nil, fmt.Errorf("merged into %s but failed to delete %s: %w", target.DisplayName(), src.DisplayName(), err)
+	}
+	return target, nil
+}
+
+// checkContact returns an error unless c was read from an object of one
+// of the ContactTypes, so other objects are never merged or deleted
+func (s *Service) checkContact(c *vcard.Contact) error {
+	types := s.ContactTypes
+	if len(types) == 0 {
+		types = DefaultContactTypes
+	}
+	if !slices.Contains(types, c.TypeKey) {
+		return fmt.Errorf("%s (%s) is not a contact", c.ObjectID, c.DisplayName())
+	}
+	return nil
+}
+
+// merge merges src into dst like vcard.MergeContacts, comparing emails
+// and phones as Dedup says
+func (s *Service) merge(dst, src *vcard.Contact) bool {
+	return vcard.MergeContactsWith(dst, src, vcard.MergeOptions{Provenance: vcard.DefaultProvenance, Dedup: s.Dedup})
+}
+
+// findMatches returns the duplicates of c in idx scoring at least
+// MinScore, best first
+func (s *Service) findMatches(idx *vcard.DedupIndex, c *vcard.Contact) []vcard.Match {
+	matches := idx.FindMatches(c)
+	n := 0
+	for _, m := range matches {
+		if m.Score >= s.MinScore {
+			matches[n] = m
+			n++
+		}
+	}
+	return matches[:n]
+}
+
+// DedupeGroup is a cluster of duplicate contacts
+type DedupeGroup struct {
+	Contact    *vcard.Contact `json:"contact"` // The surviving contact, with the duplicates merged in
+	Duplicates []Duplicate    `json:"duplicates"`
+}
+
+// Duplicate is a contact merged into the surviving contact of its group
+type Duplicate struct {
+	Contact  *vcard.Contact `json:"contact"`
+	Score    float64        `json:"score"`    // vcard.ScoreContacts confidence against the survivor
+	Strength string         `json:"strength"` // vcard.MatchStrength against the survivor
+	Reasons  []string       `json:"reasons"`  // What they share
+	// Via is the object ID of the duplicate this contact matched when it
+	// doesn't match the survivor directly
+	Via string `json:"via,omitempty"`
+
+	// Conflicts are the values of this contact the merge discarded
+	Conflicts []vcard.Conflict `json:"conflicts,omitempty"`
+}
+
+// WeakDuplicate is a pair of contacts that only match weakly, e.g. by name
+// alone. Dedupe, Create and Import report them for review but never merge
+// them.
+type WeakDuplicate struct {
+	Contact  *vcard.Contact `json:"contact"`
+	Match    *vcard.Contact `json:"match"`
+	Score    float64        `json:"score"`
+	Strength string         `json:"strength"`
+	Reasons  []string       `json:"reasons"`
+}
+
+// Dedupe merges each cluster of duplicate contacts already in the store
+// into its first contact and deletes the rest. With dryRun it only reports
+// the groups. Weak duplicates are only reported.
+func (s *Service) Dedupe(ctx context.Context, dryRun bool) ([]DedupeGroup, []WeakDuplicate, error) {
+	groups, weak, err := s.DedupeGroups(ctx)
+	if err != nil || dryRun {
+		return groups, weak, err
+	}
+	groups, err = s.ApplyDedupe(ctx, groups)
+	return groups, weak, err
+}
+
+// DedupeGroups finds the clusters of duplicate contacts in the store and
+// merges each into its first contact, in memory only. The weak duplicates
+// are left out of the clusters and returned apart.
+func (s *Service) DedupeGroups(ctx context.Context) ([]DedupeGroup, []WeakDuplicate, error) {
+	contacts, err := s.Store.Search(ctx, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load contacts: %w", err)
+	}
+	if s.Where != nil {
+		contacts = slices.DeleteFunc(contacts, func(c *vcard.Contact) bool { return !s.Where.Match(c) })
+	}
+
+	// Whole clusters, so duplicates linked through another duplicate
+	// (A↔B by phone, B↔C by email) are merged together
+	clusters, links := vcard.ClusterDuplicates(contacts, s.Dedup, s.Phonetic, func(m vcard.Match) bool { return m.Score >= s.MinScore })
+	groups := make([]DedupeGroup, len(clusters))
+	for i, cl := range clusters {
+		keep := cl.Contacts[0]
+		groups[i].Contact = keep
+		for j, c := range cl.Contacts[1:] {
+			link := cl.Links[j+1]
+			d := Duplicate{
+				Contact:  c,
+				Score:    link.Score,
+				Strength: link.Strength.String(),
+				Reasons:  link.Reasons,
+			}
+			if link.Contact != keep {
+				d.Via = link.Contact.ObjectID
+			}
+			vcard.MergeContactsWith(keep, c, vcard.MergeOptions{
+				Provenance:    vcard.DefaultProvenance,
+				OnConflict:    func(cf vcard.Conflict) { d.Conflicts = append(d.Conflicts, cf) },
+				NoteConflicts: s.NoteConflicts,
+				History:       s.History,
+				Dedup:         s.Dedup,
+			})
+			groups[i].Duplicates = append(groups[i].Duplicates, d)
+		}
+	}
+	weak := make([]WeakDuplicate, len(links))
+	for i, l := range links {
+		weak[i] = weakDuplicate(l.Contact, l.Match)
+	}
+	return groups, weak, nil
+}
+
+// ApplyDedupe writes the merged contacts of groups found by DedupeGroups
+// and deletes their duplicates. It returns the groups applied.
+func (s *Service) ApplyDedupe(ctx context.Context, groups []DedupeGroup) ([]DedupeGroup, error) {
+	result := make([]DedupeGroup, 0, len(groups))
+	for _, g := range groups {
+		keep := g.Contact
+		if err := s.Store.Write(ctx, keep); err != nil {
+			return result, fmt.Errorf("failed to update %s: %w", keep.DisplayName(), err)
+		}
+		for _, d := range g.Duplicates {
+			if err := s.Store.Delete(ctx, d.Contact.ObjectID); err != nil {
+				return result, fmt.Errorf("merged into %s but failed to delete %s: %w", keep.DisplayName(), d.Contact.DisplayName(), err)
+			}
+		}
+		result = append(result, g)
+	}
+	return result, nil
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rubiojr/any-vcard/internal/service/servicetest"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+func TestService_Create(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name        string
+		contact     vcard.Contact
+		onDuplicate string
+		wantOutcome string
+		wantCount   int
+		wantErr     bool
+	}{
+		{"new contact", vcard.Contact{FormattedName: "John Roe", Emails: []string{"john@example.com"}}, "", Created, 2, false},
+		{"duplicate merged", vcard.Contact{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}, Phones: []string{"+34 600 000 000"}}, "", Merged, 1, false},
+		{"duplicate skipped", vcard.Contact{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}, Phones: []string{"+34 600 000 000"}}, OnDuplicateSkip, Skipped, 1, false},
+		{"duplicate created", vcard.Contact{FormattedName: "Jane Doe"}, OnDuplicateCreate, Created, 2, false},
+		{"nothing to merge", vcard.Contact{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}}, OnDuplicateMerge, Skipped, 1, false},
+		{"weak match created", vcard.Contact{FormattedName: "Jane Doe"}, OnDuplicateMerge, Created, 2, false},
+		{"empty contact", vcard.Contact{}, "", "", 1, true},
+		{"bad policy", vcard.Contact{FormattedName: "X"}, "replace", "", 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := servicetest.New(vcard.Contact{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}})
+			svc := &Service{Store: store}
+			c := tt.contact
+			outcome, got, weak, err := svc.Create(ctx, &c, tt.onDuplicate)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if outcome != tt.wantOutcome {
+				t.Errorf("Create() outcome = %q, want %q", outcome, tt.wantOutcome)
+			}
+			if len(store.Contacts) != tt.wantCount {
+				t.Errorf("store has %d contact(s), want %d", len(store.Contacts), tt.wantCount)
+			}
+			if err == nil && got.ObjectID == "" {
+				t.Error("Create() returned a contact without ObjectID")
+			}
+			if wantWeak := tt.name == "weak match created"; (len(weak) > 0) != wantWeak {
+				t.Errorf("Create() weak = %+v, want weak matches %v", weak, wantWeak)
+			}
+		})
+	}
+
+	store := servicetest.New(vcard.Contact{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}})
+	c := vcard.Contact{FormattedName: "J. Doe", Emails: []string{"jane@example.com"}, Phones: []string{"+34 600 000 000"}}
+	if _, _, _, err := (&Service{Store: store}).Create(ctx, &c, ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.Contacts["obj1"].Phones) != 1 {
+		t.Errorf("merged contact = %+v, want phone added", store.Contacts["obj1"])
+	}
+
+	// Sounding alike is never enough to merge, whatever MinScore
+	store = servicetest.New(vcard.Contact{FormattedName: "Stephen Smith", Emails: []string{"stephen@example.com"}})
+	c = vcard.Contact{FormattedName: "Steven Smith", Phones: []string{"+34 600 000 000"}}
+	outcome, _, weak, err := (&Service{Store: store, Phonetic: true}).Create(ctx, &c, "")
+	if err != nil || outcome != Created || len(weak) != 1 || len(store.Contacts) != 2 {
+		t.Errorf("phonetic Create() = %q, %d weak, %v; %d contact(s), want created with 1 weak match", outcome, len(weak), err, len(store.Contacts))
+	}
+}
+
+func TestService_Merge(t *testing.T) {
+	ctx := context.Background()
+	store := servicetest.New(
+		vcard.Contact{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}},
+		vcard.Contact{FormattedName: "J. Doe", Phones: []string{"+34 600 000 000"}},
+	)
+	svc := &Service{Store: store}
+
+	merged, err := svc.Merge(ctx, "obj1", "obj2")
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if len(merged.Phones) != 1 || merged.ObjectID != "obj1" {
+		t.Errorf("Merge() = %+v", merged)
+	}
+	if _, ok := store.Contacts["obj2"]; ok {
+		t.Error("source contact was not deleted")
+	}
+	if len(store.Contacts["obj1"].Phones) != 1 {
+		t.Error("target contact was not updated")
+	}
+
+	if _, err := svc.Merge(ctx, "obj1", "obj1"); err == nil {
+		t.Error("Merge() into itself error = nil")
+	}
+	if _, err := svc.Merge(ctx, "obj1", "missing"); err == nil {
+		t.Error("Merge() with missing source error = nil")
+	}
+}
+
+func TestService_MergeNonContact(t *testing.T) {
+	ctx := context.Background()
+	store := servicetest.New(
+		vcard.Contact{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}},
+		vcard.Contact{FormattedName: "Meeting notes", TypeKey: "page"},
+		vcard.Contact{FormattedName: "Acme", TypeKey: "company"},
+	)
+	svc := &Service{Store: store}
+
+	if _, err := svc.Merge(ctx, "obj1", "obj2"); err == nil {
+		t.Error("Merge() of a page error = nil")
+	}
+	if _, err := svc.Merge(ctx, "obj2", "obj1"); err == nil {
+		t.Error("Merge() into a page error = nil")
+	}
+	if _, ok := store.Contacts["obj2"]; !ok {
+		t.Error("page was deleted")
+	}
+	if _, err := svc.Merge(ctx, "obj1", "obj3"); err != nil {
+		t.Errorf("Merge() of a company error = %v", err)
+	}
+
+	svc.ContactTypes = []string{"person"}
+	if _, err := svc.Merge(ctx, "obj1", "obj2"); err == nil {
+		t.Error("Merge() outside ContactTypes error = nil")
+	}
+}
+
+func TestService_Search(t *testing.T) {
+	store := servicetest.New(vcard.Contact{FormattedName: "Jane Doe"}, vcard.Contact{FormattedName: "John Doe"}, vcard.Contact{FormattedName: "Alice"})
+	svc := &Service{Store: store}
+
+	got, err := svc.Search(context.Background(), " doe ", 0)
+	if err != nil || len(got) != 2 {
+		t.Fatalf("Search() = %d contact(s), err %v; want 2", len(got), err)
+	}
+	if got, _ := svc.Search(context.Background(), "", 1); len(got) != 1 {
+		t.Errorf("Search() with limit returned %d contact(s)", len(got))
+	}
+}
+
+func TestService_Resolve(t *testing.T) {
+	ctx := context.Background()
+	svc := &Service{Store: servicetest.New(
+		vcard.Contact{FormattedName: "Jane Smith"},
+		vcard.Contact{FormattedName: "Jane Smithson"},
+		vcard.Contact{FormattedName: "John Roe"},
+	)}
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{"obj3", "John Roe", false},
+		{"john", "John Roe", false},
+		{"jane smith", "Jane Smith", false}, // Exact name among several matches
+		{"Jane", "", true},
+		{"Nobody", "", true},
+		{" ", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := svc.Resolve(ctx, tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.DisplayName() != tt.want {
+				t.Errorf("Resolve() = %s, want %s", got.DisplayName(), tt.want)
+			}
+		})
+	}
+}
+
+func TestService_Import(t *testing.T) {
+	store := servicetest.New(vcard.Contact{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}})
+	svc := &Service{Store: store}
+
+	res, err := svc.Import(context.Background(), []vcard.Contact{
+		{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}, Phones: []string{"+34 600 000 000"}},
+		{FormattedName: "John Roe", Emails: []string{"john@example.com"}},
+		{FormattedName: "John Roe", Emails: []string{"john@example.com"}},
+		{FormattedName: "John Roe", Phones: []string{"+34 611 111 111"}},
+		{},
+	}, "")
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	want := ImportResult{Created: 2, Merged: 1, Skipped: 1, Failed: 1}
+	if res.Created != want.Created || res.Merged != want.Merged || res.Skipped != want.Skipped || res.Failed != want.Failed {
+		t.Errorf("Import() = %+v, want %+v", res, want)
+	}
+	if len(res.Errors) != 1 {
+		t.Errorf("Import() errors = %v", res.Errors)
+	}
+	if len(res.Weak) != 1 || res.Weak[0].Match.Emails[0] != "john@example.com" {
+		t.Errorf("Import() weak = %+v, want the second John Roe matching the first by name", res.Weak)
+	}
+	if len(store.Contacts) != 3 {
+		t.Errorf("store has %d contact(s), want 3", len(store.Contacts))
+	}
+
+	if _, err := svc.Import(context.Background(), nil, "replace"); err == nil {
+		t.Error("Import() with bad policy error = nil")
+	}
+}
+
+func TestService_Dedupe(t *testing.T) {
+	newStore := func() *servicetest.MemStore {
+		return servicetest.New(
+			vcard.Contact{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}},
+			vcard.Contact{FormattedName: "Alice"},
+			vcard.Contact{FormattedName: "Jane Doe", Phones: []string{"+34 600 000 000"}},
+			vcard.Contact{FormattedName: "J. Doe", Emails: []string{"Jane@Example.com"}},
+		)
+	}
+
+	store := newStore()
+	groups, weak, err := (&Service{Store: store}).Dedupe(context.Background(), true)
+	if err != nil {
+		t.Fatalf("Dedupe() error = %v", err)
+	}
+	if len(groups) != 1 || groups[0].Contact.ObjectID != "obj1" || len(groups[0].Duplicates) != 1 {
+		t.Fatalf("Dedupe() = %+v", groups)
+	}
+	if d := groups[0].Duplicates[0]; d.Contact.ObjectID != "obj4" || d.Score != 0.9 || d.Strength != "strong" || strings.Join(d.Reasons, ",") != "email jane@example.com" {
+		t.Errorf("duplicate = %+v", d)
+	}
+	// The name alone is reported, not merged
+	if len(weak) != 1 || weak[0].Contact.ObjectID != "obj1" || weak[0].Match.ObjectID != "obj3" || weak[0].Score != 0.5 || weak[0].Strength != "weak" {
+		t.Errorf("weak = %+v, want obj1 ~ obj3 by name", weak)
+	}
+
+	if len(store.Contacts) != 4 {
+		t.Errorf("dry run changed the store: %d contact(s)", len(store.Contacts))
+	}
+
+	store = newStore()
+	if _, _, err := (&Service{Store: store}).Dedupe(context.Background(), false); err != nil {
+		t.Fatalf("Dedupe() error = %v", err)
+	}
+	if len(store.Contacts) != 3 || store.Contacts["obj3"] == nil {
+		t.Errorf("store has %d contact(s), want 3 with the weak duplicate kept", len(store.Contacts))
+	}
+
+	store = newStore()
+	groups, weak, _ = (&Service{Store: store, MinScore: 0.8}).Dedupe(context.Background(), false)
+	if len(groups) != 1 || len(groups[0].Duplicates) != 1 || len(weak) != 0 || len(store.Contacts) != 3 {
+		t.Errorf("with MinScore: %d group(s), %d weak, %d contact(s) left; want only the email match merged", len(groups), len(weak), len(store.Contacts))
+	}
+}
+
+func TestService_DedupeTransitive(t *testing.T) {
+	store := servicetest.New(
+		vcard.Contact{FormattedName: "Jane Doe", Phones: []string{"+34 612 345 678"}},
+		vcard.Contact{FormattedName: "J. Doe", Phones: []string{"612345678"}, Emails: []string{"jane@example.com"}},
+		vcard.Contact{FormattedName: "Jane", Emails: []string{"jane@example.com"}},
+	)
+	groups, _, err := (&Service{Store: store}).Dedupe(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 || len(groups[0].Duplicates) != 2 {
+		t.Fatalf("Dedupe() = %+v, want one cluster of three", groups)
+	}
+	if d := groups[0].Duplicates[1]; d.Contact.ObjectID != "obj3" || d.Via != "obj2" {
+		t.Errorf("second duplicate = %+v, want obj3 via obj2", d)
+	}
+	if len(store.Contacts) != 1 {
+		t.Errorf("store has %d contact(s), want 1", len(store.Contacts))
+	}
+}
+
+func TestWriteDedupeReport(t *testing.T) {
+	store := servicetest.New(
+		vcard.Contact{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}},
+		vcard.Contact{FormattedName: "Jane D.", Emails: []string{"jane@example.com"}, Phones: []string{"+34 600 000 000"}},
+	)
+	groups, _, err := (&Service{Store: store}).Dedupe(context.Background(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf strings.Builder
+	if err := WriteDedupeReport(&buf, groups); err != nil {
+		t.Fatal(err)
+	}
+	want := `cluster,role,object_id,name,emails,phones,organization,match_score,match_strength,match_reasons,conflicts
+1,survivor,obj1,Jane Doe,jane@example.com,+34 600 000 000,,,,,
+1,duplicate,obj2,Jane D.,jane@example.com,+34 600 000 000,,0.90,strong,email jane@example.com,"name: kept ""Jane Doe"", discarded ""Jane D."""
+`
+	if buf.String() != want {
+		t.Errorf("WriteDedupeReport() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestService_DedupePhonetic(t *testing.T) {
+	store := servicetest.New(vcard.Contact{FormattedName: "Stephen Smith"}, vcard.Contact{FormattedName: "Steven Smith"})
+
+	groups, weak, _ := (&Service{Store: store}).Dedupe(context.Background(), true)
+	if len(groups) != 0 || len(weak) != 0 {
+		t.Errorf("Dedupe() = %d group(s), %d weak without Phonetic, want none", len(groups), len(weak))
+	}
+	// Sounding alike is only weak evidence
+	groups, weak, _ = (&Service{Store: store, Phonetic: true}).Dedupe(context.Background(), true)
+	if len(groups) != 0 || len(weak) != 1 || weak[0].Match.FormattedName != "Steven Smith" {
+		t.Errorf("Dedupe() with Phonetic = %+v, weak %+v", groups, weak)
+	}
+}
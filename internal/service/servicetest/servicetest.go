@@ -0,0 +1,74 @@
+// Package servicetest provides an in-memory contact store for the tests of
+// the service and the servers built on it.
+package servicetest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// MemStore is an in-memory service.Store. Objects get the IDs obj1, obj2,
+// ... in the order they are created and the type "contact" unless the
+// contact has one; updates keep the type, like Anytype does.
+type MemStore struct {
+	Contacts map[string]*vcard.Contact
+	nextID   int
+}
+
+// New returns a MemStore holding contacts
+func New(contacts ...vcard.Contact) *MemStore {
+	s := &MemStore{Contacts: make(map[string]*vcard.Contact)}
+	for i := range contacts {
+		s.Write(context.Background(), &contacts[i])
+	}
+	return s
+}
+
+// Search implements service.Store, matching query against display names
+func (s *MemStore) Search(ctx context.Context, query string) ([]*vcard.Contact, error) {
+	var found []*vcard.Contact
+	for i := 1; i <= s.nextID; i++ {
+		c, ok := s.Contacts[fmt.Sprintf("obj%d", i)]
+		if !ok || !strings.Contains(strings.ToLower(c.DisplayName()), strings.ToLower(query)) {
+			continue
+		}
+		cp := *c
+		found = append(found, &cp)
+	}
+	return found, nil
+}
+
+// Get implements service.Store
+func (s *MemStore) Get(ctx context.Context, id string) (*vcard.Contact, error) {
+	c, ok := s.Contacts[id]
+	if !ok {
+		return nil, fmt.Errorf("object not found")
+	}
+	cp := *c
+	return &cp, nil
+}
+
+// Write implements service.Store
+func (s *MemStore) Write(ctx context.Context, c *vcard.Contact) error {
+	if existing, ok := s.Contacts[c.ObjectID]; ok {
+		c.TypeKey = existing.TypeKey
+	} else {
+		s.nextID++
+		c.ObjectID = fmt.Sprintf("obj%d", s.nextID)
+		if c.TypeKey == "" {
+			c.TypeKey = "contact"
+		}
+	}
+	cp := *c
+	s.Contacts[c.ObjectID] = &cp
+	return nil
+}
+
+// Delete implements service.Store
+func (s *MemStore) Delete(ctx context.Context, id string) error {
+	delete(s.Contacts, id)
+	return nil
+}
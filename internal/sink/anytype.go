@@ -0,0 +1,122 @@
+package sink
+
+import (
+	"context"
+	"strings"
+	"text/template"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/rubiojr/anytype-go"
+)
+
+// Anytype writes contacts as objects in an Anytype space. Contacts with an
+// ObjectID update the existing object; others are created and get the ID
+// of the new object.
+type Anytype struct {
+	Client         anytype.Client
+	SpaceID        string
+	TypeKey        string
+	CompanyTypeKey string // Type of company cards (KIND:org), TypeKey when empty
+	PhoneKeys      []string
+	EmailKeys      []string
+	TemplateID     string
+	UIDKey         string             // Text property storing the contact UID, not stored when empty
+	NotesTemplate  *template.Template // Renders the notes property instead of vcard.BuildNotes
+	NoteLinks      bool               // Writes the emails and phones in the notes as mailto:/tel: links
+	NameFormat     *template.Template // Renders object names instead of Contact.DisplayName
+	Description    *template.Template // Renders the object description, not set when nil
+	SnapshotKey    string             // Text property storing the imported vCard for three-way merges, not stored when empty
+	HistoryKey     string             // Text property storing Contact.History, not stored when empty
+
+	// Refresh, when set, is called the first time the API rejects the
+	// properties of a write, as their keys may come from an outdated schema
+	// cache. It may change the keys of s; the write is retried when it
+	// returns nil.
+	Refresh   func(ctx context.Context, s *Anytype) error
+	refreshed bool
+}
+
+// Write implements Sink
+func (s *Anytype) Write(ctx context.Context, c *vcard.Contact) error {
+	err := s.write(ctx, c)
+	if err == nil || s.Refresh == nil || s.refreshed || !strings.Contains(strings.ToLower(err.Error()), "propert") {
+		return err
+	}
+	s.refreshed = true
+	if rerr := s.Refresh(ctx, s); rerr != nil {
+		return err
+	}
+	return s.write(ctx, c)
+}
+
+func (s *Anytype) write(ctx context.Context, c *vcard.Contact) error {
+	props := vcard.BuildProperties(*c, s.PhoneKeys, s.EmailKeys)
+	if s.UIDKey != "" && c.UID != "" {
+		props = vcard.SetProperty(props, s.UIDKey, map[string]any{"text": c.UID})
+	}
+	if s.SnapshotKey != "" {
+		if c.ObjectID == "" && c.Snapshot == "" {
+			snapshot, err := vcard.EncodeSnapshot(*c)
+			if err != nil {
+				return err
+			}
+			c.Snapshot = snapshot
+		}
+		if c.Snapshot != "" {
+			props = vcard.SetProperty(props, s.SnapshotKey, map[string]any{"text": c.Snapshot})
+		}
+	}
+	if s.HistoryKey != "" && c.History != "" {
+		props = vcard.SetProperty(props, s.HistoryKey, map[string]any{"text": c.History})
+	}
+	if s.NotesTemplate != nil {
+		notes, err := vcard.RenderNotes(s.NotesTemplate, *c)
+		if err != nil {
+			return err
+		}
+		props = vcard.SetProperty(props, "notes", map[string]any{"text": notes})
+	} else if s.NoteLinks {
+		if notes := vcard.BuildLinkedNotes(*c); notes != "" {
+			props = vcard.SetProperty(props, "notes", map[string]any{"text": notes})
+		}
+	}
+
+	if s.Description != nil {
+		description, err := vcard.FormatDescription(s.Description, *c)
+		if err != nil {
+			return err
+		}
+		// Keep descriptions written in Anytype when there's nothing to say
+		if description != "" {
+			props = vcard.SetProperty(props, vcard.DescriptionKey, map[string]any{"text": description})
+		}
+	}
+
+	name := c.DisplayName()
+	if s.NameFormat != nil {
+		var err error
+		if name, err = vcard.FormatName(s.NameFormat, *c); err != nil {
+			return err
+		}
+		props = vcard.SetProperty(props, "name", map[string]any{"text": name})
+	}
+
+	if c.ObjectID != "" {
+		return vcard.UpdateObject(ctx, s.Client, s.SpaceID, c.ObjectID, props)
+	}
+	typeKey := s.TypeKey
+	if c.IsOrganization() && s.CompanyTypeKey != "" {
+		typeKey = s.CompanyTypeKey
+	}
+	id, err := vcard.CreateObject(ctx, s.Client, s.SpaceID, typeKey, name, vcard.Icon(*c), props, s.TemplateID)
+	if err != nil {
+		return err
+	}
+	c.ObjectID = id
+	return nil
+}
+
+// Close implements Sink
+func (s *Anytype) Close() error {
+	return nil
+}
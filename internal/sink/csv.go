@@ -0,0 +1,67 @@
+package sink
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// csvHeader lists the columns written by the CSV sink. Multi-valued
+// fields are joined with csvMultiSeparator.
+var csvHeader = []string{
+	"name", "given_name", "family_name", "middle_name", "prefix", "suffix",
+	"emails", "phones", "street", "city", "region", "postal_code", "country",
+	"organization", "title", "role", "urls", "birthday", "notes",
+}
+
+const csvMultiSeparator = "; "
+
+// CSV writes contacts as rows of a CSV file
+type CSV struct {
+	w           io.WriteCloser
+	csv         *csv.Writer
+	wroteHeader bool
+}
+
+// NewCSV creates a Sink writing CSV rows to w. Close flushes and closes w.
+func NewCSV(w io.WriteCloser) *CSV {
+	return &CSV{w: w, csv: csv.NewWriter(w)}
+}
+
+// Write implements Sink
+func (s *CSV) Write(ctx context.Context, c *vcard.Contact) error {
+	if !s.wroteHeader {
+		if err := s.csv.Write(csvHeader); err != nil {
+			return err
+		}
+		s.wroteHeader = true
+	}
+
+	var addr vcard.Address
+	if len(c.Addresses) > 0 {
+		addr = c.Addresses[0]
+	}
+
+	return s.csv.Write([]string{
+		c.DisplayName(), c.GivenName, c.FamilyName, c.MiddleName, c.Prefix, c.Suffix,
+		strings.Join(c.Emails, csvMultiSeparator),
+		strings.Join(c.Phones, csvMultiSeparator),
+		addr.Street, addr.City, addr.Region, addr.PostalCode, addr.Country,
+		c.Organization, c.Title, c.Role,
+		strings.Join(c.URLs, csvMultiSeparator),
+		c.Birthday, c.Note,
+	})
+}
+
+// Close implements Sink
+func (s *CSV) Close() error {
+	s.csv.Flush()
+	if err := s.csv.Error(); err != nil {
+		s.w.Close()
+		return err
+	}
+	return s.w.Close()
+}
@@ -0,0 +1,103 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	govcard "github.com/emersion/go-vcard"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// DefaultNameTemplate names per-contact files after the display name
+const DefaultNameTemplate = "{{.DisplayName}}.vcf"
+
+// Dir writes every contact as its own .vcf file in a directory, the layout
+// expected by vdirsyncer and other directory-based sync tools
+type Dir struct {
+	path    string
+	name    *template.Template
+	version string
+	used    map[string]bool
+}
+
+// NewDir creates a Sink writing one vCard file per contact into dir. File
+// names come from a Go template executed with the contact.
+func NewDir(dir, nameTemplate, version string) (*Dir, error) {
+	if err := checkVersion(version); err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New("name").Parse(nameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name template: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	return &Dir{path: dir, name: tmpl, version: version, used: make(map[string]bool)}, nil
+}
+
+// Write implements Sink
+func (s *Dir) Write(ctx context.Context, c *vcard.Contact) error {
+	var buf bytes.Buffer
+	if err := s.name.Execute(&buf, c); err != nil {
+		return fmt.Errorf("failed to name file for %s: %w", c.DisplayName(), err)
+	}
+	name := uniqueName(s.used, sanitizeFileName(buf.String()))
+
+	card, err := vcard.EncodeVersion(*c, s.version)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(filepath.Join(s.path, name))
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if err := govcard.NewEncoder(file).Encode(card); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to encode %s: %w", c.DisplayName(), err)
+	}
+	return file.Close()
+}
+
+// uniqueName appends a counter when several contacts map to the same file
+func uniqueName(used map[string]bool, name string) string {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	candidate := name
+	for i := 2; used[strings.ToLower(candidate)]; i++ {
+		candidate = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+// sanitizeFileName replaces characters that are invalid in file names on
+// common file systems and ensures a .vcf extension
+func sanitizeFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+
+	ext := filepath.Ext(name)
+	base := strings.Trim(strings.TrimSuffix(name, ext), " ._")
+	if base == "" {
+		base = "contact"
+	}
+	if ext == "" {
+		ext = ".vcf"
+	}
+	return base + ext
+}
+
+// Close implements Sink
+func (s *Dir) Close() error {
+	return nil
+}
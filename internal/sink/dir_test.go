@@ -0,0 +1,79 @@
+package sink
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+func TestDir_Write(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "contacts")
+	dst, err := NewDir(dir, "{{.FamilyName}}_{{.GivenName}}.vcf", vcard.Version3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contacts := []vcard.Contact{
+		{GivenName: "John", FamilyName: "Doe"},
+		{GivenName: "John", FamilyName: "Doe", Emails: []string{"other@example.com"}},
+		{GivenName: "A/B", FamilyName: "C:D"},
+		{Organization: "Acme"},
+	}
+	for i := range contacts {
+		if err := dst.Write(context.Background(), &contacts[i]); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := dst.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	want := []string{"C_D_A_B.vcf", "Doe_John-2.vcf", "Doe_John.vcf", "contact.vcf"}
+	if len(names) != len(want) {
+		t.Fatalf("files = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("files = %v, want %v", names, want)
+			break
+		}
+	}
+
+	parsed, err := vcard.ParseFile(filepath.Join(dir, "Doe_John-2.vcf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != 1 || len(parsed[0].Emails) != 1 {
+		t.Errorf("unexpected contents: %+v", parsed)
+	}
+}
+
+func TestNewDir_Errors(t *testing.T) {
+	if _, err := NewDir(t.TempDir(), "{{.FamilyName", vcard.Version4); err == nil {
+		t.Error("expected error for invalid template")
+	}
+	if _, err := NewDir(t.TempDir(), DefaultNameTemplate, "2.1"); err == nil {
+		t.Error("expected error for unsupported version")
+	}
+
+	dst, err := NewDir(t.TempDir(), "{{.Nope}}.vcf", vcard.Version4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.Write(context.Background(), &vcard.Contact{FormattedName: "A"}); err == nil {
+		t.Error("expected error for unknown template field")
+	}
+}
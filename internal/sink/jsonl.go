@@ -0,0 +1,36 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// JSONL writes contacts as JSON Lines, one Contact object per line
+type JSONL struct {
+	w   io.WriteCloser
+	enc *json.Encoder
+}
+
+// NewJSONL creates a Sink writing JSON Lines to w. Close closes w.
+func NewJSONL(w io.WriteCloser) *JSONL {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &JSONL{w: w, enc: enc}
+}
+
+// Write implements Sink
+func (s *JSONL) Write(ctx context.Context, c *vcard.Contact) error {
+	if err := s.enc.Encode(c); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", c.DisplayName(), err)
+	}
+	return nil
+}
+
+// Close implements Sink
+func (s *JSONL) Close() error {
+	return s.w.Close()
+}
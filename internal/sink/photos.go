@@ -0,0 +1,53 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// Photos writes the photos of contacts as image files named after them,
+// for targets that want images apart from the vCards
+type Photos struct {
+	path   string
+	client *http.Client
+	used   map[string]bool
+}
+
+// NewPhotos creates a Photos writing into dir. Linked photos are
+// downloaded with client.
+func NewPhotos(dir string, client *http.Client) (*Photos, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	return &Photos{path: dir, client: client, used: make(map[string]bool)}, nil
+}
+
+// Save writes the photo of c, if any, to <display name>.<ext> and returns
+// it as a data URI so linked photos aren't downloaded twice
+func (p *Photos) Save(ctx context.Context, c *vcard.Contact) (string, error) {
+	photo := c.Photo
+	if photo == "" {
+		return "", nil
+	}
+	if vcard.IsPhotoURL(photo) {
+		var err error
+		if photo, err = vcard.FetchPhoto(ctx, p.client, photo); err != nil {
+			return "", err
+		}
+	}
+	data, mediaType, err := vcard.DecodePhoto(photo)
+	if err != nil {
+		return "", err
+	}
+
+	name := uniqueName(p.used, sanitizeFileName(c.DisplayName()+vcard.PhotoExtension(mediaType)))
+	if err := os.WriteFile(filepath.Join(p.path, name), data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write photo: %w", err)
+	}
+	return photo, nil
+}
@@ -0,0 +1,40 @@
+package sink
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+func TestPhotos_Save(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "photos")
+	photos, err := NewPhotos(dir, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contacts := []vcard.Contact{
+		{FormattedName: "Jane Doe", Photo: "aGVsbG8="},
+		{FormattedName: "Jane Doe", Photo: "data:image/png;base64,aGVsbG8="},
+		{FormattedName: "John Doe"},
+	}
+	for i := range contacts {
+		if _, err := photos.Save(context.Background(), &contacts[i]); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	for _, name := range []string{"Jane Doe.jpg", "Jane Doe.png"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(data) != "hello" {
+			t.Errorf("%s = %q, %v", name, data, err)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("got %d files, want 2", len(entries))
+	}
+}
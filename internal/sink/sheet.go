@@ -0,0 +1,164 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// HTMLSheet writes a printable address book page: contacts sorted by
+// family name, grouped under their initial letter, with photos. Names are
+// sorted and grouped by the alphabet of vcard.Locale. Contacts are
+// buffered and the page is written on Close.
+type HTMLSheet struct {
+	w        io.WriteCloser
+	contacts []vcard.Contact
+}
+
+// NewHTMLSheet creates a Sink writing an HTML contact sheet to w. Close
+// writes the page and closes w.
+func NewHTMLSheet(w io.WriteCloser) *HTMLSheet {
+	return &HTMLSheet{w: w}
+}
+
+// Write implements Sink
+func (s *HTMLSheet) Write(ctx context.Context, c *vcard.Contact) error {
+	s.contacts = append(s.contacts, *c)
+	return nil
+}
+
+// sheetEntry is a contact as laid out on the sheet
+type sheetEntry struct {
+	Name      string
+	Photo     template.URL // Empty when the contact has no usable photo
+	Initials  string
+	Org       string
+	Phones    []string
+	Emails    []string
+	Addresses []string
+	Birthday  string
+}
+
+type sheetGroup struct {
+	Letter  string
+	Entries []sheetEntry
+}
+
+// Close implements Sink
+func (s *HTMLSheet) Close() error {
+	col, err := vcard.NewCollator(vcard.Locale)
+	if err != nil {
+		return err
+	}
+	letters, err := vcard.NewLetters(vcard.Locale)
+	if err != nil {
+		return err
+	}
+	key := func(c vcard.Contact) string {
+		if c.FamilyName != "" {
+			return c.FamilyName + " " + c.GivenName
+		}
+		return c.DisplayName()
+	}
+	slices.SortStableFunc(s.contacts, func(a, b vcard.Contact) int {
+		return col.CompareString(key(a), key(b))
+	})
+
+	var groups []sheetGroup
+	for _, c := range s.contacts {
+		letter := letters.Of(key(c))
+		if len(groups) == 0 || groups[len(groups)-1].Letter != letter {
+			groups = append(groups, sheetGroup{Letter: letter})
+		}
+		g := &groups[len(groups)-1]
+		g.Entries = append(g.Entries, newSheetEntry(c))
+	}
+
+	err = sheetPage.Execute(s.w, struct {
+		Date   string
+		Count  int
+		Groups []sheetGroup
+	}{time.Now().Format("2006-01-02"), len(s.contacts), groups})
+	if closeErr := s.w.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write contact sheet: %w", err)
+	}
+	return nil
+}
+
+func newSheetEntry(c vcard.Contact) sheetEntry {
+	e := sheetEntry{
+		Name:     c.DisplayName(),
+		Org:      strings.Join(slices.DeleteFunc([]string{c.Title, c.Organization}, func(s string) bool { return s == "" }), ", "),
+		Phones:   c.Phones,
+		Emails:   c.Emails,
+		Birthday: c.Birthday,
+	}
+	// Only images; html/template would reject data URIs anyway
+	if strings.HasPrefix(c.Photo, "data:image/") || strings.HasPrefix(c.Photo, "https://") || strings.HasPrefix(c.Photo, "http://") {
+		e.Photo = template.URL(c.Photo)
+	}
+	for _, part := range strings.Fields(e.Name) {
+		if r := []rune(part); unicode.IsLetter(r[0]) && len([]rune(e.Initials)) < 2 {
+			e.Initials += strings.ToUpper(string(r[0]))
+		}
+	}
+	for _, a := range c.Addresses {
+		parts := slices.DeleteFunc([]string{a.Street, a.City, a.PostalCode, a.Region, a.Country}, func(s string) bool { return s == "" })
+		if len(parts) > 0 {
+			e.Addresses = append(e.Addresses, strings.Join(parts, ", "))
+		} else if a.Full != "" {
+			e.Addresses = append(e.Addresses, a.Full)
+		}
+	}
+	return e
+}
+
+var sheetPage = template.Must(template.New("sheet").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Address book</title>
+<style>
+body { font-family: Georgia, serif; margin: 1.5cm; color: #111; font-size: 10pt; }
+header { display: flex; justify-content: space-between; align-items: baseline; border-bottom: 2px solid #111; margin-bottom: 1em; }
+h1 { margin: 0; font-size: 20pt; }
+h2 { font-size: 16pt; border-bottom: 1px solid #999; margin: 1em 0 0.5em; column-span: all; break-after: avoid; }
+main { columns: 2; column-gap: 1.2cm; }
+.contact { display: flex; gap: 0.6em; break-inside: avoid; margin-bottom: 0.8em; }
+.photo { width: 40px; height: 40px; border-radius: 50%; object-fit: cover; flex: none; }
+.initials { width: 40px; height: 40px; border-radius: 50%; flex: none; background: #ddd; display: flex; align-items: center; justify-content: center; font-family: sans-serif; }
+.name { font-weight: bold; }
+.org { font-style: italic; color: #444; }
+.line { font-family: sans-serif; font-size: 9pt; }
+@media print { body { margin: 0; } a { color: inherit; text-decoration: none; } }
+</style>
+</head>
+<body>
+<header><h1>Address book</h1><span>{{.Count}} contact(s), {{.Date}}</span></header>
+<main>
+{{range .Groups}}<h2>{{.Letter}}</h2>
+{{range .Entries}}<div class="contact">
+{{if .Photo}}<img class="photo" src="{{.Photo}}" alt="">{{else}}<div class="initials">{{.Initials}}</div>{{end}}
+<div>
+<div class="name">{{.Name}}</div>
+{{with .Org}}<div class="org">{{.}}</div>{{end}}
+{{range .Phones}}<div class="line">☎ {{.}}</div>{{end}}
+{{range .Emails}}<div class="line">✉ {{.}}</div>{{end}}
+{{range .Addresses}}<div class="line">⌂ {{.}}</div>{{end}}
+{{with .Birthday}}<div class="line">✱ {{.}}</div>{{end}}
+</div>
+</div>
+{{end}}{{end}}</main>
+</body>
+</html>
+`))
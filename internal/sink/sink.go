@@ -0,0 +1,117 @@
+// Package sink defines the Sink interface implemented by every contact
+// destination (Anytype, .vcf and CSV files, ...) so commands can compose
+// a Source with a Sink instead of duplicating pipelines.
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/internal/source"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// Sink receives contacts one at a time. Close flushes pending output.
+type Sink interface {
+	Write(ctx context.Context, c *vcard.Contact) error
+	Close() error
+}
+
+// Create returns the file Sink for a path based on its extension.
+// The path "-" writes vCards to stdout.
+func Create(path string) (Sink, error) {
+	return CreateVersion(path, vcard.Version4)
+}
+
+// CreateVersion is like Create but writes vCards of the given version
+// (3.0 or 4.0). The version is ignored for other formats.
+func CreateVersion(path, version string) (Sink, error) {
+	return CreateFormat(path, "", version)
+}
+
+// formatExtensions maps file extensions to the format written by default
+var formatExtensions = map[string]string{
+	".vcf":    "vcard",
+	".vcard":  "vcard",
+	".csv":    "csv",
+	".jsonl":  "jsonl",
+	".ndjson": "jsonl",
+	".html":   "html-sheet",
+	".htm":    "html-sheet",
+}
+
+// CreateFormat returns the Sink writing path in the given format: "vcard",
+// "csv", "jsonl" or "html-sheet". An empty format is detected from the extension, and
+// stdout ("-") defaults to vCard.
+func CreateFormat(path, format, version string) (Sink, error) {
+	if err := checkVersion(version); err != nil {
+		return nil, err
+	}
+	if format == "" {
+		if path == "-" {
+			format = "vcard"
+		} else if format = formatExtensions[strings.ToLower(filepath.Ext(path))]; format == "" {
+			return nil, fmt.Errorf("unsupported file type %q", filepath.Ext(path))
+		}
+	}
+	switch format {
+	case "vcard", "csv", "jsonl", "html-sheet":
+	default:
+		return nil, fmt.Errorf("unsupported format %q (supported: vcard, csv, jsonl, html-sheet)", format)
+	}
+
+	var w io.WriteCloser = nopWriteCloser{os.Stdout}
+	if path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create file: %w", err)
+		}
+		w = file
+	}
+
+	switch format {
+	case "csv":
+		return NewCSV(w), nil
+	case "jsonl":
+		return NewJSONL(w), nil
+	case "html-sheet":
+		return NewHTMLSheet(w), nil
+	}
+	return NewVCard(w, version), nil
+}
+
+func checkVersion(version string) error {
+	if version != vcard.Version3 && version != vcard.Version4 {
+		return fmt.Errorf("unsupported vCard version %q (supported: %s, %s)", version, vcard.Version3, vcard.Version4)
+	}
+	return nil
+}
+
+// Copy writes every contact from src into dst and returns how many were written
+func Copy(ctx context.Context, dst Sink, src source.Source) (int, error) {
+	var n int
+	for {
+		c, err := src.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		if err := dst.Write(ctx, c); err != nil {
+			return n, err
+		}
+		n++
+	}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,205 @@
+package sink
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rubiojr/any-vcard/internal/source"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+func copyFile(t *testing.T, input, output string) int {
+	t.Helper()
+
+	src, err := source.Open(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+
+	dst, err := Create(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := Copy(context.Background(), dst, src)
+	if err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if err := dst.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestCopy_VCard(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "out.vcf")
+	if n := copyFile(t, "../../examples/sample-contacts.vcf", output); n != 5 {
+		t.Errorf("copied %d contacts, want 5", n)
+	}
+
+	original, err := vcard.ParseFile("../../examples/sample-contacts.vcf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	copied, err := vcard.ParseFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(copied) != len(original) {
+		t.Fatalf("got %d contacts back, want %d", len(copied), len(original))
+	}
+	for i := range original {
+		if copied[i].DisplayName() != original[i].DisplayName() || len(copied[i].Phones) != len(original[i].Phones) {
+			t.Errorf("contact %d mismatch: got %+v, want %+v", i, copied[i], original[i])
+		}
+	}
+}
+
+func TestCopy_CSV(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "out.csv")
+	copyFile(t, "../../examples/sample-contacts.vcf", output)
+
+	f, err := os.Open(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 6 {
+		t.Fatalf("expected header + 5 rows, got %d", len(rows))
+	}
+	if rows[0][0] != "name" || rows[1][0] != "John Doe" {
+		t.Errorf("unexpected rows: %v", rows[:2])
+	}
+	if rows[1][6] != "john.doe@example.com; jdoe@work.com" {
+		t.Errorf("emails column = %q", rows[1][6])
+	}
+}
+
+func TestCopy_CSVRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "out.csv")
+	copyFile(t, "../../examples/sample-contacts.vcf", csvPath)
+
+	src, err := source.Open(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	contacts, err := source.ReadAll(context.Background(), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original, err := vcard.ParseFile("../../examples/sample-contacts.vcf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contacts) != len(original) {
+		t.Fatalf("expected %d contacts, got %d", len(original), len(contacts))
+	}
+	for i := range original {
+		if contacts[i].DisplayName() != original[i].DisplayName() || len(contacts[i].Emails) != len(original[i].Emails) {
+			t.Errorf("contact %d mismatch: got %+v, want %+v", i, contacts[i], original[i])
+		}
+	}
+}
+
+func TestCopy_JSONL(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "out.jsonl")
+	copyFile(t, "../../examples/sample-contacts.vcf", output)
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected 5 lines, got %d", len(lines))
+	}
+
+	var c vcard.Contact
+	if err := json.Unmarshal([]byte(lines[0]), &c); err != nil {
+		t.Fatalf("line is not a JSON contact: %v", err)
+	}
+	if c.FormattedName != "John Doe" || len(c.Emails) != 2 {
+		t.Errorf("unexpected contact: %+v", c)
+	}
+	if !strings.Contains(lines[0], `"formatted_name":"John Doe"`) {
+		t.Errorf("expected snake_case keys, got %s", lines[0])
+	}
+}
+
+func TestCopy_HTMLSheet(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "out.html")
+	copyFile(t, "../../examples/sample-contacts.vcf", output)
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	if !strings.Contains(html, "5 contact(s)") || !strings.Contains(html, "<h2>D</h2>") {
+		t.Errorf("sheet lacks the count or the letter headings:\n%s", html)
+	}
+	if strings.Index(html, "John Doe") > strings.Index(html, "Jane Smith") {
+		t.Error("contacts are not sorted by family name")
+	}
+}
+
+func TestCreateFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.txt")
+	dst, err := CreateFormat(path, "jsonl", vcard.Version4)
+	if err != nil {
+		t.Fatalf("CreateFormat() error = %v", err)
+	}
+	if _, ok := dst.(*JSONL); !ok {
+		t.Errorf("CreateFormat() = %T, want *JSONL", dst)
+	}
+	dst.Close()
+
+	if _, err := CreateFormat(path, "xml", vcard.Version4); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestCreate_UnsupportedExtension(t *testing.T) {
+	if _, err := Create(filepath.Join(t.TempDir(), "out.txt")); err == nil {
+		t.Error("expected error for unsupported extension")
+	}
+}
+
+func TestCreateVersion(t *testing.T) {
+	if _, err := CreateVersion(filepath.Join(t.TempDir(), "out.vcf"), "2.1"); err == nil {
+		t.Error("expected error for unsupported vCard version")
+	}
+
+	path := filepath.Join(t.TempDir(), "out.vcf")
+	dst, err := CreateVersion(path, vcard.Version3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.Write(context.Background(), &vcard.Contact{FormattedName: "Jane"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "VERSION:3.0") {
+		t.Errorf("expected a 3.0 card, got:\n%s", data)
+	}
+}
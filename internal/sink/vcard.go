@@ -0,0 +1,39 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	govcard "github.com/emersion/go-vcard"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// VCard writes contacts as vCards to a stream
+type VCard struct {
+	w       io.WriteCloser
+	enc     *govcard.Encoder
+	version string
+}
+
+// NewVCard creates a Sink writing vCards of the given version to w. Close closes w.
+func NewVCard(w io.WriteCloser, version string) *VCard {
+	return &VCard{w: w, enc: govcard.NewEncoder(w), version: version}
+}
+
+// Write implements Sink
+func (s *VCard) Write(ctx context.Context, c *vcard.Contact) error {
+	card, err := vcard.EncodeVersion(*c, s.version)
+	if err != nil {
+		return err
+	}
+	if err := s.enc.Encode(card); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", c.DisplayName(), err)
+	}
+	return nil
+}
+
+// Close implements Sink
+func (s *VCard) Close() error {
+	return s.w.Close()
+}
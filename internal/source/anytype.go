@@ -0,0 +1,71 @@
+package source
+
+import (
+	"context"
+	"io"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/rubiojr/anytype-go"
+)
+
+// Anytype reads the contacts stored in an Anytype space. Contacts are
+// fetched on the first call to Next.
+type Anytype struct {
+	Client  anytype.Client
+	SpaceID string
+	TypeKey string
+	Query   string // Optional full-text search query
+
+	contacts []*vcard.Contact
+	fetched  bool
+}
+
+// Next implements Source
+func (s *Anytype) Next(ctx context.Context) (*vcard.Contact, error) {
+	if !s.fetched {
+		contacts, err := vcard.SearchContacts(ctx, s.Client, s.SpaceID, s.TypeKey, s.Query)
+		if err != nil {
+			return nil, err
+		}
+		s.contacts = contacts
+		s.fetched = true
+	}
+
+	if len(s.contacts) == 0 {
+		return nil, io.EOF
+	}
+	c := s.contacts[0]
+	s.contacts = s.contacts[1:]
+	return c, nil
+}
+
+// Close implements Source
+func (s *Anytype) Close() error {
+	return nil
+}
+
+// objects yields the contacts of Anytype objects fetched beforehand
+type objects struct {
+	objects []anytype.Object
+}
+
+// Objects returns a Source reading the contacts of objects already
+// fetched, e.g. from a local mirror of the space
+func Objects(objs []anytype.Object) Source {
+	return &objects{objects: objs}
+}
+
+// Next implements Source
+func (s *objects) Next(ctx context.Context) (*vcard.Contact, error) {
+	if len(s.objects) == 0 {
+		return nil, io.EOF
+	}
+	c := vcard.FromObject(s.objects[0])
+	s.objects = s.objects[1:]
+	return c, nil
+}
+
+// Close implements Source
+func (s *objects) Close() error {
+	return nil
+}
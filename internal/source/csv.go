@@ -0,0 +1,220 @@
+package source
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// DefaultCSVMapping reads the columns written by the CSV sink
+func DefaultCSVMapping() Mapping {
+	return Mapping{
+		"name":     {"name"},
+		"given":    {"given_name"},
+		"middle":   {"middle_name"},
+		"family":   {"family_name"},
+		"prefix":   {"prefix"},
+		"suffix":   {"suffix"},
+		"email":    {"emails"},
+		"phone":    {"phones"},
+		"street":   {"street"},
+		"city":     {"city"},
+		"region":   {"region"},
+		"postal":   {"postal_code"},
+		"country":  {"country"},
+		"org":      {"organization"},
+		"title":    {"title"},
+		"role":     {"role"},
+		"url":      {"urls"},
+		"birthday": {"birthday"},
+		"note":     {"notes"},
+	}
+}
+
+// OutlookCSVMapping reads the contacts CSV exported by Outlook desktop.
+// Home, business and other addresses are read in that order.
+func OutlookCSVMapping() Mapping {
+	return Mapping{
+		"name":   {"Display Name"},
+		"given":  {"First Name"},
+		"middle": {"Middle Name"},
+		"family": {"Last Name"},
+		"prefix": {"Title"},
+		"suffix": {"Suffix"},
+		"email":  {"E-mail Address", "E-mail 2 Address", "E-mail 3 Address"},
+		"phone": {
+			"Mobile Phone", "Home Phone", "Home Phone 2", "Business Phone", "Business Phone 2",
+			"Primary Phone", "Company Main Phone", "Other Phone", "Car Phone",
+		},
+		"street":     {"Home Street", "Business Street", "Other Street"},
+		"city":       {"Home City", "Business City", "Other City"},
+		"region":     {"Home State", "Business State", "Other State"},
+		"postal":     {"Home Postal Code", "Business Postal Code", "Other Postal Code"},
+		"country":    {"Home Country/Region", "Business Country/Region", "Other Country/Region", "Home Country", "Business Country"},
+		"org":        {"Company"},
+		"department": {"Department"},
+		"title":      {"Job Title"},
+		"url":        {"Web Page"},
+		"birthday":   {"Birthday"},
+		"note":       {"Notes"},
+		"categories": {"Categories"},
+		"assistant":  {"Assistant's Name"},
+		"manager":    {"Manager's Name"},
+	}
+}
+
+// ThunderbirdCSVMapping reads the CSV address books exported by
+// Thunderbird. Home addresses come before work ones.
+func ThunderbirdCSVMapping() Mapping {
+	return Mapping{
+		"name":        {"Display Name"},
+		"given":       {"First Name"},
+		"family":      {"Last Name"},
+		"nickname":    {"Nickname", "Nick Name"},
+		"email":       {"Primary Email", "Secondary Email"},
+		"phone":       {"Mobile Number", "Home Phone", "Work Phone"},
+		"street":      {"Home Address", "Work Address"},
+		"city":        {"Home City", "Work City"},
+		"region":      {"Home State", "Work State"},
+		"postal":      {"Home ZipCode", "Work ZipCode"},
+		"country":     {"Home Country", "Work Country"},
+		"org":         {"Organization"},
+		"department":  {"Department"},
+		"title":       {"Job Title"},
+		"url":         {"Web Page 1", "Web Page 2"},
+		"note":        {"Notes"},
+		"birth_year":  {"Birth Year"},
+		"birth_month": {"Birth Month"},
+		"birth_day":   {"Birth Day"},
+	}
+}
+
+// CSVFormats maps the --format names of CSV dialects to their mappings
+var CSVFormats = map[string]func() Mapping{
+	"csv":             DefaultCSVMapping,
+	"outlook-csv":     OutlookCSVMapping,
+	"thunderbird-csv": ThunderbirdCSVMapping,
+}
+
+// CSV reads contacts from a CSV file with a header row, mapping columns to
+// contact fields by header name
+type CSV struct {
+	r       io.ReadCloser
+	csv     *csv.Reader
+	mapping Mapping
+	columns map[string]int // lowercased header → column index
+}
+
+// NewCSV creates a Source reading CSV rows from r. Close closes r.
+func NewCSV(r io.ReadCloser, mapping Mapping) *CSV {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	return &CSV{r: r, csv: reader, mapping: mapping}
+}
+
+// Next implements Source
+func (s *CSV) Next(ctx context.Context) (*vcard.Contact, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.columns == nil {
+		if err := s.readHeader(); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		record, err := s.csv.Read()
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		if isBlankRecord(record) {
+			continue
+		}
+
+		return s.mapping.Contact("", func(attr string) []string {
+			i, ok := s.columns[strings.ToLower(attr)]
+			if !ok || i >= len(record) {
+				return nil
+			}
+			return []string{record[i]}
+		}), nil
+	}
+}
+
+func (s *CSV) readHeader() error {
+	header, err := s.csv.Read()
+	if errors.Is(err, io.EOF) {
+		return io.EOF
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	header[0] = strings.TrimPrefix(header[0], "\ufeff") // Excel writes a UTF-8 BOM before the first header
+	s.columns = make(map[string]int, len(header))
+	for i, name := range header {
+		s.columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if s.mapping.Matches(header) {
+		return nil
+	}
+	return fmt.Errorf("CSV header has none of the mapped columns (got %s)", strconv.Quote(strings.Join(header, ",")))
+}
+
+// PeekCSV returns the header and up to n of the first non-blank rows of
+// a CSV file, for showing what its columns hold
+func PeekCSV(path string, n int) (header []string, rows [][]string, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	src := NewCSV(file, nil)
+	if header, err = src.csv.Read(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	for len(rows) < n {
+		record, err := src.csv.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if !isBlankRecord(record) {
+			rows = append(rows, record)
+		}
+	}
+	return header, rows, nil
+}
+
+// isBlankRecord reports whether a row only has empty cells or Outlook's
+// empty birthday placeholder
+func isBlankRecord(record []string) bool {
+	for _, v := range record {
+		if v = strings.TrimSpace(v); v != "" && normalizeBirthday(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Close implements Source
+func (s *CSV) Close() error {
+	return s.r.Close()
+}
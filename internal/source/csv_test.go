@@ -0,0 +1,150 @@
+package source
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const outlookCSV = "\ufeff" + `First Name,Middle Name,Last Name,Title,Suffix,Company,Job Title,Business Street,Business City,Business State,Business Postal Code,Business Country/Region,Home Street,Home City,Home State,Home Postal Code,Home Country/Region,Business Phone,Home Phone,Mobile Phone,Birthday,Categories,E-mail Address,E-mail 2 Address,Notes,Web Page
+John,Q,Doe,Dr.,Jr.,Acme,Developer,1 Main St,Springfield,IL,62701,United States of America,,,,,,+1 555 000 0003,,+1 555 000 0001,3/22/1990,Work;VIP,john@example.com,jdoe@example.org,"Met at
+the conference",https://example.com
+,,,,,,,,,,,,,,,,,,,,0/0/00,,,,,
+Jane,,Smith,,,,,,,,,,,,,,,,,,0/0/00,,jane@example.com,,,
+`
+
+func TestCSV_Outlook(t *testing.T) {
+	src := NewCSV(io.NopCloser(strings.NewReader(outlookCSV)), OutlookCSVMapping())
+	contacts, err := ReadAll(context.Background(), src)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(contacts) != 2 {
+		t.Fatalf("expected 2 contacts (blank row skipped), got %d", len(contacts))
+	}
+
+	john := contacts[0]
+	if john.GivenName != "John" || john.MiddleName != "Q" || john.FamilyName != "Doe" || john.Prefix != "Dr." || john.Suffix != "Jr." {
+		t.Errorf("unexpected names: %+v", john)
+	}
+	if len(john.Emails) != 2 || john.Emails[1] != "jdoe@example.org" {
+		t.Errorf("Emails = %v", john.Emails)
+	}
+	if len(john.Phones) != 2 || john.Phones[0] != "+1 555 000 0001" {
+		t.Errorf("Phones = %v, want mobile first", john.Phones)
+	}
+	if len(john.Addresses) != 1 || john.Addresses[0].City != "Springfield" || john.Addresses[0].PostalCode != "62701" {
+		t.Errorf("Addresses = %+v, want business address", john.Addresses)
+	}
+	if john.Birthday != "1990-03-22" {
+		t.Errorf("Birthday = %q, want 1990-03-22", john.Birthday)
+	}
+	if len(john.Categories) != 2 || john.Categories[1] != "VIP" {
+		t.Errorf("Categories = %v", john.Categories)
+	}
+	if john.Note != "Met at\nthe conference" || john.Organization != "Acme" || john.Title != "Developer" {
+		t.Errorf("unexpected details: %+v", john)
+	}
+	if contacts[1].Birthday != "" {
+		t.Errorf("Birthday = %q, want empty for 0/0/00", contacts[1].Birthday)
+	}
+}
+
+func TestCSV_OutlookAddresses(t *testing.T) {
+	// Home has a street and a country, business a city and a postal code
+	input := "First Name,Home Street,Home City,Home Country,Business Street,Business City,Business Postal Code,Other City\n" +
+		"John,1 Home Rd,,Spain,,Springfield,62701,\n"
+	contacts, err := ReadAll(context.Background(), NewCSV(io.NopCloser(strings.NewReader(input)), OutlookCSVMapping()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := contacts[0].Addresses
+	if len(got) != 2 {
+		t.Fatalf("Addresses = %+v, want home and business", got)
+	}
+	if got[0].Street != "1 Home Rd" || got[0].City != "" || got[0].Country != "Spain" {
+		t.Errorf("home address = %+v", got[0])
+	}
+	if got[1].Street != "" || got[1].City != "Springfield" || got[1].PostalCode != "62701" || got[1].Country != "" {
+		t.Errorf("business address = %+v", got[1])
+	}
+}
+
+func TestCSV_UnknownColumns(t *testing.T) {
+	src := NewCSV(io.NopCloser(strings.NewReader("foo,bar\n1,2\n")), DefaultCSVMapping())
+	if _, err := src.Next(context.Background()); err == nil {
+		t.Error("expected error for a header without mapped columns")
+	}
+}
+
+func TestOpenFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "contacts.txt")
+	if err := os.WriteFile(path, []byte(outlookCSV), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OpenFormat(path, "excel"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+
+	src, err := OpenFormat(path, "outlook-csv")
+	if err != nil {
+		t.Fatalf("OpenFormat() error = %v", err)
+	}
+	defer src.Close()
+	contacts, err := ReadAll(context.Background(), src)
+	if err != nil || len(contacts) != 2 {
+		t.Errorf("ReadAll() = %d contacts, %v", len(contacts), err)
+	}
+}
+
+func TestCustomCSVMapping(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "crm.csv")
+	data := "\ufeffKunde,Mail,Telefon\n,,\nJane Smith,jane@example.com,+1 555 0100\nBob,bob@example.com,\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	header, rows, err := PeekCSV(path, 1)
+	if err != nil {
+		t.Fatalf("PeekCSV() error = %v", err)
+	}
+	if strings.Join(header, ",") != "Kunde,Mail,Telefon" || len(rows) != 1 || rows[0][0] != "Jane Smith" {
+		t.Errorf("PeekCSV() = %q, %q, want the header and first non-blank row", header, rows)
+	}
+	if DefaultCSVMapping().Matches(header) {
+		t.Error("default mapping matches a header it has no columns of")
+	}
+
+	mappingPath := filepath.Join(dir, "crm.mapping.json")
+	if err := (Mapping{"name": {"Kunde"}, "email": {"Mail"}, "phone": {"Telefon"}}).Save(mappingPath); err != nil {
+		t.Fatal(err)
+	}
+	mapping, err := LoadMapping(mappingPath)
+	if err != nil {
+		t.Fatalf("LoadMapping() error = %v", err)
+	}
+	src, err := OpenCSV(path, mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	contacts, err := ReadAll(context.Background(), src)
+	if err != nil || len(contacts) != 2 {
+		t.Fatalf("ReadAll() = %d contacts, %v", len(contacts), err)
+	}
+	if contacts[0].FormattedName != "Jane Smith" || contacts[0].Emails[0] != "jane@example.com" || contacts[0].Phones[0] != "+1 555 0100" {
+		t.Errorf("unexpected contact: %+v", contacts[0])
+	}
+
+	if err := os.WriteFile(mappingPath, []byte(`{"spouse": ["Partner"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadMapping(mappingPath); err == nil {
+		t.Error("LoadMapping() accepted an unknown field")
+	}
+}
@@ -0,0 +1,298 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/rubiojr/any-vcard/internal/oauth"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// GoogleContactsScope grants read-only access to the user's contacts
+const GoogleContactsScope = "https://www.googleapis.com/auth/contacts.readonly"
+
+// GoogleOAuthConfig returns the OAuth configuration for the People API
+func GoogleOAuthConfig(clientID, clientSecret string) *oauth.Config {
+	return &oauth.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		AuthURL:      "https://accounts.google.com/o/oauth2/v2/auth",
+		TokenURL:     "https://oauth2.googleapis.com/token",
+		Scopes:       []string{GoogleContactsScope},
+	}
+}
+
+// googlePersonFields are the People API fields mapped onto Contact
+const googlePersonFields = "names,emailAddresses,phoneNumbers,addresses,organizations,urls,biographies,birthdays,photos,memberships,relations,metadata"
+
+// Google reads contacts from the Google People API. Client must be
+// authorized for GoogleContactsScope.
+type Google struct {
+	Client  *http.Client
+	BaseURL string // Defaults to https://people.googleapis.com
+
+	groups    map[string]string // contactGroups/... → group name
+	page      []googlePerson
+	pageToken string
+	started   bool
+}
+
+// NewGoogle creates a People API source using an authorized client
+func NewGoogle(client *http.Client) *Google {
+	return &Google{Client: client, BaseURL: "https://people.googleapis.com"}
+}
+
+type googlePerson struct {
+	ResourceName string `json:"resourceName"`
+	Names        []struct {
+		DisplayName     string `json:"displayName"`
+		GivenName       string `json:"givenName"`
+		FamilyName      string `json:"familyName"`
+		MiddleName      string `json:"middleName"`
+		HonorificPrefix string `json:"honorificPrefix"`
+		HonorificSuffix string `json:"honorificSuffix"`
+	} `json:"names"`
+	EmailAddresses []struct {
+		Value string `json:"value"`
+	} `json:"emailAddresses"`
+	PhoneNumbers []struct {
+		Value string `json:"value"`
+	} `json:"phoneNumbers"`
+	Addresses []struct {
+		FormattedValue string `json:"formattedValue"`
+		StreetAddress  string `json:"streetAddress"`
+		City           string `json:"city"`
+		Region         string `json:"region"`
+		PostalCode     string `json:"postalCode"`
+		Country        string `json:"country"`
+	} `json:"addresses"`
+	Organizations []struct {
+		Name       string `json:"name"`
+		Department string `json:"department"`
+		Title      string `json:"title"`
+	} `json:"organizations"`
+	URLs []struct {
+		Value string `json:"value"`
+	} `json:"urls"`
+	Biographies []struct {
+		Value string `json:"value"`
+	} `json:"biographies"`
+	Birthdays []struct {
+		Date *struct {
+			Year  int `json:"year"`
+			Month int `json:"month"`
+			Day   int `json:"day"`
+		} `json:"date"`
+		Text string `json:"text"`
+	} `json:"birthdays"`
+	Photos []struct {
+		URL     string `json:"url"`
+		Default bool   `json:"default"`
+	} `json:"photos"`
+	Relations []struct {
+		Person string `json:"person"`
+		Type   string `json:"type"`
+	} `json:"relations"`
+	Memberships []struct {
+		ContactGroupMembership *struct {
+			ContactGroupResourceName string `json:"contactGroupResourceName"`
+		} `json:"contactGroupMembership"`
+	} `json:"memberships"`
+}
+
+// Next implements Source
+func (s *Google) Next(ctx context.Context) (*vcard.Contact, error) {
+	if s.groups == nil {
+		groups, err := s.fetchGroups(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.groups = groups
+	}
+
+	for len(s.page) == 0 {
+		if s.started && s.pageToken == "" {
+			return nil, io.EOF
+		}
+		if err := s.fetchPage(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	p := s.page[0]
+	s.page = s.page[1:]
+	return s.toContact(p), nil
+}
+
+// Close implements Source
+func (s *Google) Close() error {
+	return nil
+}
+
+func (s *Google) fetchPage(ctx context.Context) error {
+	q := url.Values{}
+	q.Set("personFields", googlePersonFields)
+	q.Set("pageSize", "1000")
+	if s.pageToken != "" {
+		q.Set("pageToken", s.pageToken)
+	}
+
+	var resp struct {
+		Connections   []googlePerson `json:"connections"`
+		NextPageToken string         `json:"nextPageToken"`
+	}
+	if err := s.get(ctx, "/v1/people/me/connections?"+q.Encode(), &resp); err != nil {
+		return err
+	}
+
+	s.started = true
+	s.page = resp.Connections
+	s.pageToken = resp.NextPageToken
+	return nil
+}
+
+// fetchGroups maps contact group resource names to display names. Only
+// user-created groups and the starred system group are kept.
+func (s *Google) fetchGroups(ctx context.Context) (map[string]string, error) {
+	groups := make(map[string]string)
+	pageToken := ""
+	for {
+		q := url.Values{}
+		q.Set("pageSize", "1000")
+		if pageToken != "" {
+			q.Set("pageToken", pageToken)
+		}
+
+		var resp struct {
+			ContactGroups []struct {
+				ResourceName  string `json:"resourceName"`
+				FormattedName string `json:"formattedName"`
+				GroupType     string `json:"groupType"`
+			} `json:"contactGroups"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := s.get(ctx, "/v1/contactGroups?"+q.Encode(), &resp); err != nil {
+			return nil, err
+		}
+
+		for _, g := range resp.ContactGroups {
+			if g.GroupType == "USER_CONTACT_GROUP" || g.ResourceName == "contactGroups/starred" {
+				groups[g.ResourceName] = g.FormattedName
+			}
+		}
+		if resp.NextPageToken == "" {
+			return groups, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+func (s *Google) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.BaseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("people API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("people API returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode people API response: %w", err)
+	}
+	return nil
+}
+
+func (s *Google) toContact(p googlePerson) *vcard.Contact {
+	c := &vcard.Contact{UID: p.ResourceName}
+
+	if len(p.Names) > 0 {
+		n := p.Names[0]
+		c.FormattedName = n.DisplayName
+		c.GivenName = n.GivenName
+		c.FamilyName = n.FamilyName
+		c.MiddleName = n.MiddleName
+		c.Prefix = n.HonorificPrefix
+		c.Suffix = n.HonorificSuffix
+	}
+	for _, e := range p.EmailAddresses {
+		if e.Value != "" {
+			c.Emails = append(c.Emails, e.Value)
+		}
+	}
+	for _, ph := range p.PhoneNumbers {
+		if ph.Value != "" {
+			c.Phones = append(c.Phones, ph.Value)
+		}
+	}
+	for _, a := range p.Addresses {
+		street := a.StreetAddress
+		if street == "" && a.City == "" && a.Country == "" {
+			street = a.FormattedValue
+		}
+		c.Addresses = append(c.Addresses, vcard.Address{
+			Street:     street,
+			City:       a.City,
+			Region:     a.Region,
+			PostalCode: a.PostalCode,
+			Country:    a.Country,
+			Full:       a.FormattedValue,
+		})
+	}
+	if len(p.Organizations) > 0 {
+		c.Organization = p.Organizations[0].Name
+		c.Department = p.Organizations[0].Department
+		c.Title = p.Organizations[0].Title
+	}
+	for _, u := range p.URLs {
+		if u.Value != "" {
+			c.URLs = append(c.URLs, u.Value)
+		}
+	}
+	if len(p.Biographies) > 0 {
+		c.Note = p.Biographies[0].Value
+	}
+	if len(p.Birthdays) > 0 {
+		b := p.Birthdays[0]
+		switch {
+		case b.Date != nil && b.Date.Year != 0:
+			c.Birthday = fmt.Sprintf("%04d-%02d-%02d", b.Date.Year, b.Date.Month, b.Date.Day)
+		case b.Date != nil:
+			c.Birthday = fmt.Sprintf("--%02d-%02d", b.Date.Month, b.Date.Day)
+		default:
+			c.Birthday = b.Text
+		}
+	}
+	for _, ph := range p.Photos {
+		if !ph.Default && ph.URL != "" {
+			c.Photo = ph.URL
+			break
+		}
+	}
+	for _, r := range p.Relations {
+		switch vcard.RelationType(r.Type) {
+		case "assistant":
+			c.Assistant = r.Person
+		case "manager":
+			c.Manager = r.Person
+		}
+	}
+	for _, m := range p.Memberships {
+		if m.ContactGroupMembership == nil {
+			continue
+		}
+		if name, ok := s.groups[m.ContactGroupMembership.ContactGroupResourceName]; ok {
+			c.Categories = append(c.Categories, name)
+		}
+	}
+
+	return c
+}
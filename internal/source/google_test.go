@@ -0,0 +1,87 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGoogle_Next(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/contactGroups":
+			fmt.Fprint(w, `{"contactGroups":[
+				{"resourceName":"contactGroups/abc","formattedName":"Family","groupType":"USER_CONTACT_GROUP"},
+				{"resourceName":"contactGroups/myContacts","formattedName":"My Contacts","groupType":"SYSTEM_CONTACT_GROUP"}
+			]}`)
+		case "/v1/people/me/connections":
+			if r.URL.Query().Get("pageToken") == "" {
+				fmt.Fprint(w, `{"connections":[{
+					"resourceName":"people/c1",
+					"names":[{"displayName":"John Doe","givenName":"John","familyName":"Doe"}],
+					"emailAddresses":[{"value":"john@example.com"}],
+					"phoneNumbers":[{"value":"+1 555 123 4567"}],
+					"addresses":[{"city":"Springfield","country":"USA","formattedValue":"Springfield, USA"}],
+					"organizations":[{"name":"Acme","title":"Developer"}],
+					"birthdays":[{"date":{"month":3,"day":15}}],
+					"photos":[{"url":"https://example.com/default.jpg","default":true},{"url":"https://example.com/john.jpg"}],
+					"memberships":[
+						{"contactGroupMembership":{"contactGroupResourceName":"contactGroups/abc"}},
+						{"contactGroupMembership":{"contactGroupResourceName":"contactGroups/myContacts"}}
+					]
+				}],"nextPageToken":"p2"}`)
+				return
+			}
+			fmt.Fprint(w, `{"connections":[{"resourceName":"people/c2","names":[{"displayName":"Jane Smith"}],"birthdays":[{"date":{"year":1990,"month":3,"day":22}}]}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	src := NewGoogle(srv.Client())
+	src.BaseURL = srv.URL
+
+	contacts, err := ReadAll(context.Background(), src)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(contacts) != 2 {
+		t.Fatalf("expected 2 contacts across pages, got %d", len(contacts))
+	}
+
+	john := contacts[0]
+	if john.UID != "people/c1" || john.FormattedName != "John Doe" || john.FamilyName != "Doe" {
+		t.Errorf("unexpected identity: %+v", john)
+	}
+	if len(john.Emails) != 1 || len(john.Phones) != 1 || john.Organization != "Acme" || john.Title != "Developer" {
+		t.Errorf("unexpected details: %+v", john)
+	}
+	if john.Birthday != "--03-15" {
+		t.Errorf("Birthday = %q, want --03-15", john.Birthday)
+	}
+	if john.Photo != "https://example.com/john.jpg" {
+		t.Errorf("Photo = %q", john.Photo)
+	}
+	if len(john.Categories) != 1 || john.Categories[0] != "Family" {
+		t.Errorf("Categories = %v, want [Family]", john.Categories)
+	}
+	if contacts[1].Birthday != "1990-03-22" {
+		t.Errorf("Birthday = %q, want 1990-03-22", contacts[1].Birthday)
+	}
+}
+
+func TestGoogle_HTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	src := NewGoogle(srv.Client())
+	src.BaseURL = srv.URL
+	if _, err := src.Next(context.Background()); err == nil {
+		t.Error("expected error on HTTP 401")
+	}
+}
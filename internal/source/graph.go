@@ -0,0 +1,200 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/rubiojr/any-vcard/internal/oauth"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// GraphContactsScope grants read-only access to the user's contacts.
+// offline_access is required to receive a refresh token.
+const GraphContactsScope = "Contacts.Read"
+
+// GraphOAuthConfig returns the OAuth configuration for Microsoft Graph.
+// tenant is "common", "consumers", "organizations" or a tenant ID.
+func GraphOAuthConfig(clientID, tenant string) *oauth.Config {
+	if tenant == "" {
+		tenant = "common"
+	}
+	base := "https://login.microsoftonline.com/" + tenant + "/oauth2/v2.0"
+	return &oauth.Config{
+		ClientID:      clientID,
+		AuthURL:       base + "/authorize",
+		TokenURL:      base + "/token",
+		DeviceAuthURL: base + "/devicecode",
+		Scopes:        []string{GraphContactsScope, "offline_access"},
+	}
+}
+
+// Graph reads contacts from Microsoft Graph (Outlook.com and Microsoft 365).
+// Client must be authorized for GraphContactsScope.
+type Graph struct {
+	Client  *http.Client
+	BaseURL string // Defaults to https://graph.microsoft.com
+
+	page     []graphContact
+	nextLink string
+	started  bool
+}
+
+// NewGraph creates a Microsoft Graph source using an authorized client
+func NewGraph(client *http.Client) *Graph {
+	return &Graph{Client: client, BaseURL: "https://graph.microsoft.com"}
+}
+
+type graphAddress struct {
+	Street          string `json:"street"`
+	City            string `json:"city"`
+	State           string `json:"state"`
+	CountryOrRegion string `json:"countryOrRegion"`
+	PostalCode      string `json:"postalCode"`
+}
+
+type graphContact struct {
+	ID             string `json:"id"`
+	DisplayName    string `json:"displayName"`
+	GivenName      string `json:"givenName"`
+	MiddleName     string `json:"middleName"`
+	Surname        string `json:"surname"`
+	Title          string `json:"title"`
+	Generation     string `json:"generation"`
+	EmailAddresses []struct {
+		Address string `json:"address"`
+	} `json:"emailAddresses"`
+	MobilePhone      string        `json:"mobilePhone"`
+	HomePhones       []string      `json:"homePhones"`
+	BusinessPhones   []string      `json:"businessPhones"`
+	HomeAddress      *graphAddress `json:"homeAddress"`
+	BusinessAddress  *graphAddress `json:"businessAddress"`
+	OtherAddress     *graphAddress `json:"otherAddress"`
+	CompanyName      string        `json:"companyName"`
+	Department       string        `json:"department"`
+	JobTitle         string        `json:"jobTitle"`
+	AssistantName    string        `json:"assistantName"`
+	Manager          string        `json:"manager"`
+	BusinessHomePage string        `json:"businessHomePage"`
+	PersonalNotes    string        `json:"personalNotes"`
+	Birthday         string        `json:"birthday"`
+	Categories       []string      `json:"categories"`
+}
+
+// Next implements Source
+func (s *Graph) Next(ctx context.Context) (*vcard.Contact, error) {
+	for len(s.page) == 0 {
+		if s.started && s.nextLink == "" {
+			return nil, io.EOF
+		}
+		if err := s.fetchPage(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	c := s.page[0]
+	s.page = s.page[1:]
+	return graphToContact(c), nil
+}
+
+// Close implements Source
+func (s *Graph) Close() error {
+	return nil
+}
+
+func (s *Graph) fetchPage(ctx context.Context) error {
+	// nextLink is an absolute URL carrying the paging state
+	pageURL := s.nextLink
+	if !s.started {
+		pageURL = s.BaseURL + "/v1.0/me/contacts?$top=100"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("graph request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("graph API returned status %d", resp.StatusCode)
+	}
+
+	var page struct {
+		Value    []graphContact `json:"value"`
+		NextLink string         `json:"@odata.nextLink"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return fmt.Errorf("failed to decode graph response: %w", err)
+	}
+
+	s.started = true
+	s.page = page.Value
+	s.nextLink = page.NextLink
+	return nil
+}
+
+// graphToContact maps the typed Graph fields onto Contact. Phones are ordered
+// mobile, home, business and addresses home, business, other so the most
+// personal value lands in the primary property.
+func graphToContact(g graphContact) *vcard.Contact {
+	c := &vcard.Contact{
+		UID:           g.ID,
+		FormattedName: g.DisplayName,
+		GivenName:     g.GivenName,
+		MiddleName:    g.MiddleName,
+		FamilyName:    g.Surname,
+		Prefix:        g.Title,
+		Suffix:        g.Generation,
+		Organization:  g.CompanyName,
+		Department:    g.Department,
+		Title:         g.JobTitle,
+		Assistant:     g.AssistantName,
+		Manager:       g.Manager,
+		Note:          g.PersonalNotes,
+		Categories:    g.Categories,
+	}
+
+	for _, e := range g.EmailAddresses {
+		if e.Address != "" {
+			c.Emails = append(c.Emails, e.Address)
+		}
+	}
+
+	phones := append([]string{g.MobilePhone}, g.HomePhones...)
+	for _, p := range append(phones, g.BusinessPhones...) {
+		if p != "" {
+			c.Phones = append(c.Phones, p)
+		}
+	}
+
+	for _, a := range []*graphAddress{g.HomeAddress, g.BusinessAddress, g.OtherAddress} {
+		if a == nil || *a == (graphAddress{}) {
+			continue
+		}
+		c.Addresses = append(c.Addresses, vcard.Address{
+			Street:     a.Street,
+			City:       a.City,
+			Region:     a.State,
+			PostalCode: a.PostalCode,
+			Country:    a.CountryOrRegion,
+			Full:       a.Street,
+		})
+	}
+
+	if g.BusinessHomePage != "" {
+		c.URLs = append(c.URLs, g.BusinessHomePage)
+	}
+
+	// Graph returns birthdays as midnight UTC timestamps
+	if len(g.Birthday) >= len("2006-01-02") {
+		c.Birthday = g.Birthday[:len("2006-01-02")]
+	}
+
+	return c
+}
@@ -0,0 +1,73 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGraph_Next(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1.0/me/contacts" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("$skip") == "" {
+			fmt.Fprintf(w, `{"value":[{
+				"id":"AAMk1",
+				"displayName":"John Doe","givenName":"John","surname":"Doe","generation":"Jr.",
+				"emailAddresses":[{"name":"John","address":"john@example.com"},{"address":""}],
+				"mobilePhone":"+1 555 000 0001",
+				"homePhones":["+1 555 000 0002"],
+				"businessPhones":["+1 555 000 0003"],
+				"homeAddress":{},
+				"businessAddress":{"street":"1 Main St","city":"Springfield","state":"IL","countryOrRegion":"USA","postalCode":"62701"},
+				"companyName":"Acme","jobTitle":"Developer",
+				"businessHomePage":"https://acme.example.com",
+				"birthday":"1990-03-22T00:00:00Z",
+				"categories":["Work"]
+			}],"@odata.nextLink":"%s/v1.0/me/contacts?$top=100&$skip=100"}`, srv.URL)
+			return
+		}
+		fmt.Fprint(w, `{"value":[{"id":"AAMk2","displayName":"Jane Smith","birthday":null}]}`)
+	}))
+	defer srv.Close()
+
+	src := NewGraph(srv.Client())
+	src.BaseURL = srv.URL
+
+	contacts, err := ReadAll(context.Background(), src)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(contacts) != 2 {
+		t.Fatalf("expected 2 contacts across pages, got %d", len(contacts))
+	}
+
+	john := contacts[0]
+	if john.UID != "AAMk1" || john.FamilyName != "Doe" || john.Suffix != "Jr." {
+		t.Errorf("unexpected identity: %+v", john)
+	}
+	if len(john.Emails) != 1 || john.Emails[0] != "john@example.com" {
+		t.Errorf("Emails = %v", john.Emails)
+	}
+	wantPhones := []string{"+1 555 000 0001", "+1 555 000 0002", "+1 555 000 0003"}
+	if fmt.Sprint(john.Phones) != fmt.Sprint(wantPhones) {
+		t.Errorf("Phones = %v, want %v (mobile, home, business)", john.Phones, wantPhones)
+	}
+	if len(john.Addresses) != 1 || john.Addresses[0].City != "Springfield" || john.Addresses[0].Region != "IL" {
+		t.Errorf("Addresses = %+v, want only the business address", john.Addresses)
+	}
+	if john.Birthday != "1990-03-22" {
+		t.Errorf("Birthday = %q, want 1990-03-22", john.Birthday)
+	}
+	if len(john.URLs) != 1 || len(john.Categories) != 1 || john.Organization != "Acme" {
+		t.Errorf("unexpected details: %+v", john)
+	}
+	if contacts[1].Birthday != "" {
+		t.Errorf("Birthday = %q, want empty", contacts[1].Birthday)
+	}
+}
@@ -0,0 +1,50 @@
+package source
+
+import (
+	"context"
+	"io"
+	"net/url"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// HTML reads the h-card microformats embedded in a web page
+type HTML struct {
+	r        io.ReadCloser
+	base     *url.URL
+	contacts []vcard.Contact
+	read     bool
+}
+
+// NewHTML creates a Source reading h-cards from the HTML in r. Relative
+// photo and URL links are resolved against base when not nil.
+func NewHTML(r io.ReadCloser, base *url.URL) *HTML {
+	return &HTML{r: r, base: base}
+}
+
+// Next implements Source
+func (s *HTML) Next(ctx context.Context) (*vcard.Contact, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if !s.read {
+		contacts, err := vcard.ParseHCards(s.r, s.base)
+		if err != nil {
+			return nil, err
+		}
+		s.contacts = contacts
+		s.read = true
+	}
+
+	if len(s.contacts) == 0 {
+		return nil, io.EOF
+	}
+	c := s.contacts[0]
+	s.contacts = s.contacts[1:]
+	return &c, nil
+}
+
+// Close implements Source
+func (s *HTML) Close() error {
+	return s.r.Close()
+}
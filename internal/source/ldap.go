@@ -0,0 +1,81 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// DefaultLDAPMapping covers inetOrgPerson and Active Directory user entries
+func DefaultLDAPMapping() Mapping {
+	return Mapping{
+		"name":       {"cn", "displayName"},
+		"given":      {"givenName"},
+		"family":     {"sn"},
+		"email":      {"mail"},
+		"phone":      {"telephoneNumber", "mobile", "homePhone"},
+		"org":        {"o", "company"},
+		"department": {"department", "ou"},
+		"title":      {"title"},
+		"note":       {"description"},
+		"url":        {"labeledURI", "wWWHomePage"},
+		"street":     {"street", "streetAddress"},
+		"city":       {"l"},
+		"region":     {"st"},
+		"postal":     {"postalCode"},
+		"country":    {"c", "co"},
+		"uid":        {"entryUUID"},
+	}
+}
+
+// LDAP reads person entries from an LDAP directory such as OpenLDAP or
+// Active Directory. Conn must already be bound when the directory does not
+// allow anonymous searches.
+type LDAP struct {
+	Conn    *ldap.Conn
+	BaseDN  string
+	Filter  string
+	Mapping Mapping
+
+	entries []*ldap.Entry
+	fetched bool
+}
+
+// NewLDAP creates a directory source searching baseDN with filter
+func NewLDAP(conn *ldap.Conn, baseDN, filter string, mapping Mapping) *LDAP {
+	return &LDAP{Conn: conn, BaseDN: baseDN, Filter: filter, Mapping: mapping}
+}
+
+// Next implements Source
+func (s *LDAP) Next(ctx context.Context) (*vcard.Contact, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if !s.fetched {
+		req := ldap.NewSearchRequest(
+			s.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+			s.Filter, s.Mapping.Attributes(), nil,
+		)
+		result, err := s.Conn.SearchWithPaging(req, 500)
+		if err != nil {
+			return nil, fmt.Errorf("ldap search failed: %w", err)
+		}
+		s.entries = result.Entries
+		s.fetched = true
+	}
+
+	if len(s.entries) == 0 {
+		return nil, io.EOF
+	}
+	entry := s.entries[0]
+	s.entries = s.entries[1:]
+	return s.Mapping.Contact(entry.DN, entry.GetEqualFoldAttributeValues), nil
+}
+
+// Close implements Source
+func (s *LDAP) Close() error {
+	return s.Conn.Close()
+}
@@ -0,0 +1,44 @@
+package source
+
+import (
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestLDAPMapping_Contact(t *testing.T) {
+	entry := ldap.NewEntry("uid=jdoe,ou=people,dc=example,dc=com", map[string][]string{
+		"CN":              {"John Doe"},
+		"givenName":       {"John"},
+		"sn":              {"Doe"},
+		"mail":            {"john@example.com"},
+		"telephoneNumber": {"+1 555 000 0001"},
+		"mobile":          {"+1 555 000 0002"},
+		"o":               {"Acme"},
+		"title":           {"Developer"},
+		"labeledURI":      {"https://example.com/~jdoe Home page"},
+		"l":               {"Springfield"},
+		"c":               {"US"},
+	})
+
+	c := DefaultLDAPMapping().Contact(entry.DN, entry.GetEqualFoldAttributeValues)
+
+	if c.FormattedName != "John Doe" || c.GivenName != "John" || c.FamilyName != "Doe" {
+		t.Errorf("unexpected names: %+v", c)
+	}
+	if len(c.Emails) != 1 || len(c.Phones) != 2 {
+		t.Errorf("Emails = %v, Phones = %v", c.Emails, c.Phones)
+	}
+	if c.Organization != "Acme" || c.Title != "Developer" {
+		t.Errorf("Organization = %q, Title = %q", c.Organization, c.Title)
+	}
+	if len(c.URLs) != 1 || c.URLs[0] != "https://example.com/~jdoe" {
+		t.Errorf("URLs = %v, want label stripped", c.URLs)
+	}
+	if len(c.Addresses) != 1 || c.Addresses[0].City != "Springfield" || c.Addresses[0].Country != "US" {
+		t.Errorf("Addresses = %+v", c.Addresses)
+	}
+	if c.UID != entry.DN {
+		t.Errorf("UID = %q, want DN fallback", c.UID)
+	}
+}
@@ -0,0 +1,150 @@
+package source
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// ThunderbirdLDIFMapping reads the LDIF address books exported by
+// Thunderbird. Home addresses come before work ones.
+func ThunderbirdLDIFMapping() Mapping {
+	return Mapping{
+		"name":        {"cn"},
+		"given":       {"givenName"},
+		"family":      {"sn"},
+		"nickname":    {"mozillaNickname", "xmozillanickname"},
+		"email":       {"mail", "mozillaSecondEmail", "xmozillasecondemail"},
+		"phone":       {"mobile", "homePhone", "telephoneNumber"},
+		"street":      {"mozillaHomeStreet", "street"},
+		"city":        {"mozillaHomeLocalityName", "l"},
+		"region":      {"mozillaHomeState", "st"},
+		"postal":      {"mozillaHomePostalCode", "postalCode"},
+		"country":     {"mozillaHomeCountryName", "c"},
+		"org":         {"o", "company"},
+		"department":  {"department", "ou"},
+		"title":       {"title"},
+		"url":         {"mozillaHomeUrl", "mozillaWorkUrl", "homeurl", "workurl"},
+		"note":        {"description"},
+		"birth_year":  {"birthyear"},
+		"birth_month": {"birthmonth"},
+		"birth_day":   {"birthday"},
+	}
+}
+
+// LDIF reads person entries from an LDIF file (RFC 2849). Group entries
+// such as Thunderbird mailing lists are skipped.
+type LDIF struct {
+	r       io.ReadCloser
+	scanner *bufio.Scanner
+	mapping Mapping
+}
+
+// NewLDIF creates a Source reading LDIF entries from r. Close closes r.
+func NewLDIF(r io.ReadCloser, mapping Mapping) *LDIF {
+	scanner := bufio.NewScanner(r)
+	// Photos are inlined as base64 and can exceed the default line limit
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	return &LDIF{r: r, scanner: scanner, mapping: mapping}
+}
+
+// Next implements Source
+func (s *LDIF) Next(ctx context.Context) (*vcard.Contact, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		dn, attrs, err := s.readEntry()
+		if err != nil {
+			return nil, err
+		}
+		if dn == "" || isLDIFGroup(attrs) {
+			continue // version line or mailing list
+		}
+
+		return s.mapping.Contact(dn, func(attr string) []string {
+			return attrs[strings.ToLower(attr)]
+		}), nil
+	}
+}
+
+// readEntry reads the next blank-line separated record. Attribute names are
+// lowercased and options (cn;lang-en) dropped.
+func (s *LDIF) readEntry() (string, map[string][]string, error) {
+	var lines []string
+	for s.scanner.Scan() {
+		line := strings.TrimRight(s.scanner.Text(), "\r")
+		switch {
+		case line == "":
+			if len(lines) > 0 {
+				return parseLDIFEntry(lines)
+			}
+		case strings.HasPrefix(line, " "):
+			if len(lines) > 0 {
+				lines[len(lines)-1] += line[1:]
+			}
+		case strings.HasPrefix(line, "#"):
+		default:
+			lines = append(lines, line)
+		}
+	}
+	if err := s.scanner.Err(); err != nil {
+		return "", nil, fmt.Errorf("failed to read LDIF: %w", err)
+	}
+	if len(lines) > 0 {
+		return parseLDIFEntry(lines)
+	}
+	return "", nil, io.EOF
+}
+
+func parseLDIFEntry(lines []string) (string, map[string][]string, error) {
+	var dn string
+	attrs := make(map[string][]string)
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return "", nil, fmt.Errorf("invalid LDIF line %q", line)
+		}
+		name, _, _ = strings.Cut(strings.ToLower(name), ";")
+
+		switch {
+		case strings.HasPrefix(value, ":"):
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value[1:]))
+			if err != nil {
+				return "", nil, fmt.Errorf("invalid base64 value for %s: %w", name, err)
+			}
+			value = string(decoded)
+		case strings.HasPrefix(value, "<"):
+			continue // External URL values are not fetched
+		default:
+			value = strings.TrimSpace(value)
+		}
+
+		if name == "dn" {
+			dn = value
+			continue
+		}
+		attrs[name] = append(attrs[name], value)
+	}
+	return dn, attrs, nil
+}
+
+func isLDIFGroup(attrs map[string][]string) bool {
+	for _, oc := range attrs["objectclass"] {
+		if strings.EqualFold(oc, "groupOfNames") || strings.EqualFold(oc, "groupOfUniqueNames") {
+			return true
+		}
+	}
+	return false
+}
+
+// Close implements Source
+func (s *LDIF) Close() error {
+	return s.r.Close()
+}
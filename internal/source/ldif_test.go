@@ -0,0 +1,112 @@
+package source
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+const thunderbirdLDIF = `version: 1
+
+dn: cn=John Doe,mail=john@example.com
+objectclass: top
+objectclass: person
+objectclass: inetOrgPerson
+objectclass: mozillaAbPersonAlpha
+givenName: John
+sn: Doe
+cn: John Doe
+mozillaNickname: Johnny
+mail: john@example.com
+mozillaSecondEmail: jdoe@example.org
+telephoneNumber: +1 555 000 0003
+mobile: +1 555 000 0001
+mozillaHomeLocalityName: Springfield
+l: Shelbyville
+mozillaHomeCountryName: USA
+o: Acme
+# comments are ignored
+description: Met at the
+  conference
+birthyear: 1990
+birthmonth: 03
+birthday: 22
+modifytimestamp: 0Z
+
+dn: cn=Friends
+objectclass: top
+objectclass: groupOfNames
+cn: Friends
+member: cn=John Doe,mail=john@example.com
+
+dn:: Y249Sm9zw6k=
+objectclass: person
+cn:: Sm9zw6k=
+birthmonth: 12
+birthday: 1
+`
+
+func TestLDIF_Thunderbird(t *testing.T) {
+	src := NewLDIF(io.NopCloser(strings.NewReader(thunderbirdLDIF)), ThunderbirdLDIFMapping())
+	contacts, err := ReadAll(context.Background(), src)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(contacts) != 2 {
+		t.Fatalf("expected 2 contacts (group skipped), got %d", len(contacts))
+	}
+
+	john := contacts[0]
+	if john.FormattedName != "John Doe" || john.Nickname != "Johnny" {
+		t.Errorf("FormattedName = %q, Nickname = %q", john.FormattedName, john.Nickname)
+	}
+	if len(john.Emails) != 2 || john.Emails[1] != "jdoe@example.org" {
+		t.Errorf("Emails = %v", john.Emails)
+	}
+	if len(john.Phones) != 2 || john.Phones[0] != "+1 555 000 0001" {
+		t.Errorf("Phones = %v, want mobile first", john.Phones)
+	}
+	if len(john.Addresses) != 2 || john.Addresses[0].City != "Springfield" || john.Addresses[1].City != "Shelbyville" || john.Addresses[1].Country != "" {
+		t.Errorf("Addresses = %+v, want home then work city", john.Addresses)
+	}
+	if john.Note != "Met at the conference" {
+		t.Errorf("Note = %q, want folded line joined", john.Note)
+	}
+	if john.Birthday != "1990-03-22" {
+		t.Errorf("Birthday = %q, want 1990-03-22", john.Birthday)
+	}
+
+	jose := contacts[1]
+	if jose.FormattedName != "José" || jose.UID != "cn=José" {
+		t.Errorf("base64 values not decoded: %+v", jose)
+	}
+	if jose.Birthday != "--12-01" {
+		t.Errorf("Birthday = %q, want --12-01", jose.Birthday)
+	}
+}
+
+func TestCSV_Thunderbird(t *testing.T) {
+	data := `First Name,Last Name,Display Name,Nickname,Primary Email,Secondary Email,Work Phone,Home Phone,Mobile Number,Home City,Work City,Organization,Birth Year,Birth Month,Birth Day,Notes
+Jane,Smith,Jane Smith,JS,jane@example.com,,+1 555 000 0003,,,,Springfield,Acme,,7,4,
+`
+	src := NewCSV(io.NopCloser(strings.NewReader(data)), ThunderbirdCSVMapping())
+	contacts, err := ReadAll(context.Background(), src)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(contacts) != 1 {
+		t.Fatalf("expected 1 contact, got %d", len(contacts))
+	}
+
+	c := contacts[0]
+	if c.Nickname != "JS" || len(c.Emails) != 1 || len(c.Phones) != 1 || c.Organization != "Acme" {
+		t.Errorf("unexpected contact: %+v", c)
+	}
+	if len(c.Addresses) != 1 || c.Addresses[0].City != "Springfield" {
+		t.Errorf("Addresses = %+v, want work city fallback", c.Addresses)
+	}
+	if c.Birthday != "--07-04" {
+		t.Errorf("Birthday = %q, want --07-04", c.Birthday)
+	}
+}
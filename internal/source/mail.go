@@ -0,0 +1,278 @@
+package source
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// DefaultMailMinMessages is the number of messages an address must be in
+// to be read from a mail archive
+const DefaultMailMinMessages = 2
+
+// mailHeaders are the headers whose addresses become contacts
+var mailHeaders = []string{"From", "To", "Cc", "Reply-To"}
+
+// Mail reads the people in a mail archive, an mbox file, a single message
+// (.eml) or a Maildir, as contacts: the name and address of every sender
+// and recipient, ranked by the number of messages they appear in. Role
+// accounts such as noreply@ are left out. Experimental.
+type Mail struct {
+	r           io.ReadCloser // Nil for a Maildir
+	dir         string
+	minMessages int
+
+	contacts []vcard.Contact
+	read     bool
+}
+
+// correspondent is an address seen in the archive
+type correspondent struct {
+	email    string
+	names    map[string]int // Display names by times used
+	messages int
+	last     time.Time
+}
+
+// NewMbox creates a Source reading an mbox stream, or a single message,
+// from r. Addresses in fewer than minMessages messages are skipped. Close
+// closes r.
+func NewMbox(r io.ReadCloser, minMessages int) *Mail {
+	return &Mail{r: r, minMessages: minMessages}
+}
+
+// NewMaildir creates a Source reading the messages of the Maildir at dir,
+// in its cur and new subdirectories
+func NewMaildir(dir string, minMessages int) *Mail {
+	return &Mail{dir: dir, minMessages: minMessages}
+}
+
+// OpenMail returns the Source reading the mail archive at path: a Maildir
+// when it is a directory, else an mbox file or a single message
+func OpenMail(path string, minMessages int) (Source, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return NewMaildir(path, minMessages), nil
+	}
+	return openFile(path, func(r io.ReadCloser) Source { return NewMbox(r, minMessages) })
+}
+
+// IsMail reports whether path is read by OpenMail when imported in format:
+// an mbox, eml or maildir format, or with no format a mail archive
+// extension or a directory
+func IsMail(path, format string) bool {
+	switch format {
+	case "mbox", "eml", "maildir":
+		return true
+	case "":
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			return true
+		}
+		f := formatExtensions[strings.ToLower(filepath.Ext(path))]
+		return f == "mbox" || f == "eml"
+	}
+	return false
+}
+
+// Next implements Source
+func (s *Mail) Next(ctx context.Context) (*vcard.Contact, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if !s.read {
+		s.read = true
+		if err := s.scan(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if len(s.contacts) == 0 {
+		return nil, io.EOF
+	}
+	c := s.contacts[0]
+	s.contacts = s.contacts[1:]
+	return &c, nil
+}
+
+// Close implements Source
+func (s *Mail) Close() error {
+	if s.r == nil {
+		return nil
+	}
+	return s.r.Close()
+}
+
+// scan reads the headers of every message and ranks the correspondents
+func (s *Mail) scan(ctx context.Context) error {
+	seen := make(map[string]*correspondent)
+	add := func(header []byte) {
+		msg, err := mail.ReadMessage(bytes.NewReader(append(header, "\r\n"...)))
+		if err != nil {
+			return // Not a message, e.g. an mbox preamble
+		}
+		date, _ := msg.Header.Date()
+		inMessage := make(map[string]bool)
+		for _, key := range mailHeaders {
+			addrs, err := msg.Header.AddressList(key)
+			if err != nil {
+				continue
+			}
+			for _, a := range addrs {
+				email := strings.ToLower(strings.TrimSpace(a.Address))
+				if email == "" || vcard.IsRoleEmail(email) {
+					continue
+				}
+				c := seen[email]
+				if c == nil {
+					c = &correspondent{email: email, names: make(map[string]int)}
+					seen[email] = c
+				}
+				if name := cleanMailName(a.Name, email); name != "" {
+					c.names[name]++
+				}
+				if !inMessage[email] {
+					inMessage[email] = true
+					c.messages++
+				}
+				if date.After(c.last) {
+					c.last = date
+				}
+			}
+		}
+	}
+
+	if s.dir != "" {
+		if err := s.scanMaildir(ctx, add); err != nil {
+			return err
+		}
+	} else if err := scanMbox(ctx, s.r, add); err != nil {
+		return err
+	}
+
+	ranked := make([]*correspondent, 0, len(seen))
+	for _, c := range seen {
+		if c.messages >= s.minMessages {
+			ranked = append(ranked, c)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].messages != ranked[j].messages {
+			return ranked[i].messages > ranked[j].messages
+		}
+		return ranked[i].email < ranked[j].email
+	})
+	for _, c := range ranked {
+		s.contacts = append(s.contacts, c.contact())
+	}
+	return nil
+}
+
+// contact returns the correspondent as a contact named after its most
+// used display name
+func (c *correspondent) contact() vcard.Contact {
+	name := c.email
+	best := 0
+	for n, count := range c.names {
+		if count > best || count == best && n < name {
+			name, best = n, count
+		}
+	}
+	note := fmt.Sprintf("Found in %d email message(s)", c.messages)
+	if !c.last.IsZero() {
+		note += ", last on " + c.last.Format(time.DateOnly)
+	}
+	return vcard.Contact{FormattedName: name, Emails: []string{c.email}, Note: note}
+}
+
+// cleanMailName returns the display name of an address, "" when it is
+// only the address again
+func cleanMailName(name, email string) string {
+	name = strings.Trim(strings.TrimSpace(name), `"'`)
+	if name == "" || strings.EqualFold(name, email) || strings.Contains(name, "@") {
+		return ""
+	}
+	// "Doe, Jane" is how many clients show "Jane Doe"
+	if last, first, ok := strings.Cut(name, ", "); ok && !strings.Contains(first, ",") {
+		name = first + " " + last
+	}
+	return name
+}
+
+// scanMbox calls add with the header of every message of an mbox stream.
+// A stream not starting with a "From " line is a single message.
+func scanMbox(ctx context.Context, r io.Reader, add func(header []byte)) error {
+	br := bufio.NewReader(r)
+	var header []byte
+	inHeader, atStart, prevBlank := true, true, true
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			switch {
+			case bytes.HasPrefix(line, []byte("From ")) && (atStart || prevBlank):
+				if len(header) > 0 {
+					add(header)
+				}
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				header, inHeader = nil, true
+			case inHeader && len(bytes.TrimRight(line, "\r\n")) == 0:
+				inHeader = false
+			case inHeader:
+				header = append(header, line...)
+			}
+			atStart = false
+			prevBlank = len(bytes.TrimRight(line, "\r\n")) == 0
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read mailbox: %w", err)
+		}
+	}
+	if len(header) > 0 {
+		add(header)
+	}
+	return nil
+}
+
+// scanMaildir calls add with the header of every message of the Maildir
+func (s *Mail) scanMaildir(ctx context.Context, add func(header []byte)) error {
+	for _, sub := range []string{"cur", "new"} {
+		entries, err := os.ReadDir(filepath.Join(s.dir, sub))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read maildir: %w", err)
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			f, err := os.Open(filepath.Join(s.dir, sub, e.Name()))
+			if err != nil {
+				return fmt.Errorf("failed to read maildir: %w", err)
+			}
+			err = scanMbox(ctx, f, add)
+			f.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package source
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testMbox = `From jane@example.com Mon Jan  6 10:00:00 2025
+From: "Doe, Jane" <jane@example.com>
+To: Bob <bob@example.org>, noreply@example.com
+Date: Mon, 6 Jan 2025 10:00:00 +0000
+Subject: Lunch
+
+From now on lunch is at noon.
+
+From bob@example.org Tue Jan  7 10:00:00 2025
+From: =?UTF-8?Q?Bob_M=C3=BCller?= <Bob@Example.org>
+To: Jane Doe <jane@example.com>
+Cc: carol@example.net
+Date: Tue, 7 Jan 2025 10:00:00 +0000
+Subject: Re: Lunch
+
+Sounds good.
+
+From jane@example.com Wed Jan  8 10:00:00 2025
+From: Jane Doe <jane@example.com>
+To: Bob <bob@example.org>
+Date: Wed, 8 Jan 2025 10:00:00 +0000
+
+Bye
+`
+
+func TestMbox(t *testing.T) {
+	tests := []struct {
+		name        string
+		minMessages int
+		want        []string // Name <email>, in rank order
+	}{
+		{"all", 1, []string{"Bob <bob@example.org>", "Jane Doe <jane@example.com>", "carol@example.net <carol@example.net>"}},
+		{"frequent", 2, []string{"Bob <bob@example.org>", "Jane Doe <jane@example.com>"}},
+		{"none", 4, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := NewMbox(io.NopCloser(strings.NewReader(testMbox)), tt.minMessages)
+			contacts, err := ReadAll(context.Background(), src)
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			var got []string
+			for _, c := range contacts {
+				got = append(got, c.FormattedName+" <"+c.Emails[0]+">")
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("contacts = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	contacts, _ := ReadAll(context.Background(), NewMbox(io.NopCloser(strings.NewReader(testMbox)), 1))
+	if want := "Found in 3 email message(s), last on 2025-01-08"; contacts[0].Note != want {
+		t.Errorf("Note = %q, want %q", contacts[0].Note, want)
+	}
+}
+
+func TestOpenMail(t *testing.T) {
+	dir := t.TempDir()
+	eml := filepath.Join(dir, "message.eml")
+	if err := os.WriteFile(eml, []byte("From: Jane Doe <jane@example.com>\r\nTo: bob@example.org\r\n\r\nHi\r\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	maildir := filepath.Join(dir, "Mail")
+	if err := os.MkdirAll(filepath.Join(maildir, "cur"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"1", "2"} {
+		msg := "From: Jane Doe <jane@example.com>\nTo: Bob <bob@example.org>\n\nHi " + name + "\n"
+		if err := os.WriteFile(filepath.Join(maildir, "cur", name), []byte(msg), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{eml, 0}, // Every address is in a single message
+		{maildir, 2},
+	}
+	for _, tt := range tests {
+		if !IsMail(tt.path, "") {
+			t.Errorf("IsMail(%q) = false", tt.path)
+		}
+		src, err := OpenFormat(tt.path, "")
+		if err != nil {
+			t.Fatalf("OpenFormat(%q) error = %v", tt.path, err)
+		}
+		contacts, err := ReadAll(context.Background(), src)
+		src.Close()
+		if err != nil {
+			t.Fatalf("ReadAll(%q) error = %v", tt.path, err)
+		}
+		if len(contacts) != tt.want {
+			t.Errorf("%s: got %d contacts, want %d", tt.path, len(contacts), tt.want)
+		}
+	}
+}
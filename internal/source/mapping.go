@@ -0,0 +1,261 @@
+package source
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// Mapping maps Contact fields to the attribute or column names of a
+// record-based source (LDAP entries, CSV rows). Single-valued fields take the
+// first listed attribute present on the record; multi-valued fields (email,
+// phone, url, categories) collect every listed attribute, splitting values
+// on ";". Address fields listing several addresses, such as home and work,
+// in the same order are read as one address each, see addressGroups.
+type Mapping map[string][]string
+
+// addressFields are the Mapping fields of an address
+var addressFields = []string{"street", "city", "region", "postal", "country"}
+
+// MappingFields lists the Contact fields a Mapping can set
+var MappingFields = []string{
+	"name", "given", "middle", "family", "prefix", "suffix", "nickname",
+	"email", "phone", "org", "title", "note", "url", "categories",
+	"birthday", "birth_year", "birth_month", "birth_day",
+	"street", "city", "region", "postal", "country", "uid",
+	"assistant", "manager", "department", "role",
+}
+
+// Set overrides the attributes of a field from a "field=attr1,attr2" spec
+func (m Mapping) Set(spec string) error {
+	field, attrs, ok := strings.Cut(spec, "=")
+	field = strings.TrimSpace(field)
+	if !ok || attrs == "" {
+		return fmt.Errorf("invalid attribute mapping %q (expected field=attribute)", spec)
+	}
+	if !isMappingField(field) {
+		return fmt.Errorf("unknown contact field %q in attribute mapping (fields: %s)", field, strings.Join(MappingFields, ", "))
+	}
+
+	var list []string
+	for _, a := range strings.Split(attrs, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			list = append(list, a)
+		}
+	}
+	m[field] = list
+	return nil
+}
+
+func isMappingField(field string) bool {
+	for _, f := range MappingFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadMapping reads a mapping saved with Save: a JSON object of contact
+// fields to lists of attribute or column names
+func LoadMapping(path string) (Mapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mapping: %w", err)
+	}
+	var m Mapping
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse mapping %s: %w", path, err)
+	}
+	for field := range m {
+		if !isMappingField(field) {
+			return nil, fmt.Errorf("unknown contact field %q in mapping %s (fields: %s)", field, path, strings.Join(MappingFields, ", "))
+		}
+	}
+	return m, nil
+}
+
+// Save writes the mapping as JSON for LoadMapping
+func (m Mapping) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to save mapping: %w", err)
+	}
+	return nil
+}
+
+// Matches reports whether any of the names, e.g. a CSV header, is an
+// attribute of the mapping (case-insensitive)
+func (m Mapping) Matches(names []string) bool {
+	for _, attr := range m.Attributes() {
+		for _, name := range names {
+			if strings.EqualFold(strings.TrimSpace(name), attr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Attributes lists every attribute referenced by the mapping
+func (m Mapping) Attributes() []string {
+	seen := make(map[string]bool)
+	var attrs []string
+	for _, list := range m {
+		for _, a := range list {
+			if !seen[strings.ToLower(a)] {
+				seen[strings.ToLower(a)] = true
+				attrs = append(attrs, a)
+			}
+		}
+	}
+	sort.Strings(attrs)
+	return attrs
+}
+
+// Contact builds a contact from a record. values returns the values of an
+// attribute, matched case-insensitively. id is used as UID when the mapping
+// yields none.
+func (m Mapping) Contact(id string, values func(attr string) []string) *vcard.Contact {
+	first := func(field string) string { return m.first(field, values) }
+	all := func(field string) []string {
+		var out []string
+		for _, attr := range m[field] {
+			for _, v := range values(attr) {
+				for _, part := range strings.Split(v, ";") {
+					if part = strings.TrimSpace(part); part != "" {
+						out = append(out, part)
+					}
+				}
+			}
+		}
+		return out
+	}
+
+	c := &vcard.Contact{
+		FormattedName: first("name"),
+		GivenName:     first("given"),
+		MiddleName:    first("middle"),
+		FamilyName:    first("family"),
+		Prefix:        first("prefix"),
+		Suffix:        first("suffix"),
+		Nickname:      first("nickname"),
+		Emails:        all("email"),
+		Phones:        all("phone"),
+		Organization:  first("org"),
+		Department:    first("department"),
+		Title:         first("title"),
+		Role:          first("role"),
+		Assistant:     first("assistant"),
+		Manager:       first("manager"),
+		Note:          first("note"),
+		Birthday:      normalizeBirthday(first("birthday")),
+		Categories:    all("categories"),
+		UID:           first("uid"),
+	}
+	if c.UID == "" {
+		c.UID = id
+	}
+	if c.Birthday == "" {
+		c.Birthday = birthdayFromParts(first("birth_year"), first("birth_month"), first("birth_day"))
+	}
+
+	// labeledURI values are "URL label"
+	for _, u := range all("url") {
+		c.URLs = append(c.URLs, strings.Fields(u)[0])
+	}
+
+	// One address per group, so a home street isn't paired with a work city
+	for _, group := range m.addressGroups() {
+		addr := vcard.Address{
+			Street:     group.first("street", values),
+			City:       group.first("city", values),
+			Region:     group.first("region", values),
+			PostalCode: group.first("postal", values),
+			Country:    group.first("country", values),
+		}
+		if addr != (vcard.Address{}) {
+			addr.Full = addr.Street
+			c.Addresses = append(c.Addresses, addr)
+		}
+	}
+
+	return c
+}
+
+// addressGroups splits the address attributes into one mapping per
+// address. With n attributes in the shortest address field, attribute i of
+// a field belongs to address i mod n, so longer fields can list other names
+// of the same columns: Outlook's country is "Home Country/Region" or "Home
+// Country". A mapping with a single attribute for a field, such as
+// city=l, has a single address taking the first attribute of each field set.
+func (m Mapping) addressGroups() []Mapping {
+	n := 0
+	for _, field := range addressFields {
+		if l := len(m[field]); l > 0 && (n == 0 || l < n) {
+			n = l
+		}
+	}
+	groups := make([]Mapping, n)
+	for i := range groups {
+		groups[i] = make(Mapping)
+	}
+	for _, field := range addressFields {
+		for i, attr := range m[field] {
+			groups[i%n][field] = append(groups[i%n][field], attr)
+		}
+	}
+	return groups
+}
+
+// first returns the first non-empty value of the attributes of field
+func (m Mapping) first(field string, values func(attr string) []string) string {
+	for _, attr := range m[field] {
+		for _, v := range values(attr) {
+			if v = strings.TrimSpace(v); v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// normalizeBirthday converts the US dates written by desktop address books
+// (3/22/1990) to ISO 8601. Outlook writes 0/0/00 for an empty birthday.
+func normalizeBirthday(s string) string {
+	if s == "" || s == "0/0/00" || s == "0/0/0000" {
+		return ""
+	}
+	if _, ok := vcard.ParseBirthdayDate(s); ok {
+		return s
+	}
+	for _, layout := range []string{"1/2/2006", "1/2/06", "2006/1/2"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	return s
+}
+
+// birthdayFromParts builds an ISO 8601 birthday from separate year, month
+// and day values as stored by Thunderbird. The year is optional.
+func birthdayFromParts(year, month, day string) string {
+	m, errM := strconv.Atoi(month)
+	d, errD := strconv.Atoi(day)
+	if errM != nil || errD != nil || m < 1 || m > 12 || d < 1 || d > 31 {
+		return ""
+	}
+	if y, err := strconv.Atoi(year); err == nil && y > 0 {
+		return fmt.Sprintf("%04d-%02d-%02d", y, m, d)
+	}
+	return fmt.Sprintf("--%02d-%02d", m, d)
+}
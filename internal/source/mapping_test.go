@@ -0,0 +1,40 @@
+package source
+
+import "testing"
+
+func TestMapping_Set(t *testing.T) {
+	m := DefaultLDAPMapping()
+	if err := m.Set("email=mail, proxyAddresses"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got := m["email"]; len(got) != 2 || got[1] != "proxyAddresses" {
+		t.Errorf("email attributes = %v", got)
+	}
+
+	for _, spec := range []string{"email", "email=", "spouse=displayName"} {
+		if err := m.Set(spec); err == nil {
+			t.Errorf("Set(%q) expected error", spec)
+		}
+	}
+}
+
+func TestNormalizeBirthday(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"0/0/00", ""},
+		{"3/22/1990", "1990-03-22"},
+		{"12/1/85", "1985-12-01"},
+		{"1990-03-22", "1990-03-22"},
+		{"--03-22", "--03-22"},
+		{"sometime in March", "sometime in March"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeBirthday(tt.input); got != tt.expected {
+			t.Errorf("normalizeBirthday(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// MeCard reads contacts from MECARD strings, one per line or concatenated,
+// as produced by QR code scanners
+type MeCard struct {
+	r        io.ReadCloser
+	contacts []vcard.Contact
+	read     bool
+}
+
+// NewMeCard creates a Source reading MECARD strings from r. Close closes r.
+func NewMeCard(r io.ReadCloser) *MeCard {
+	return &MeCard{r: r}
+}
+
+// Next implements Source
+func (s *MeCard) Next(ctx context.Context) (*vcard.Contact, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if !s.read {
+		data, err := io.ReadAll(s.r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read MECARD data: %w", err)
+		}
+		s.read = true
+		if s.contacts, err = vcard.ParseMeCards(string(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(s.contacts) == 0 {
+		return nil, io.EOF
+	}
+	c := s.contacts[0]
+	s.contacts = s.contacts[1:]
+	return &c, nil
+}
+
+// Close implements Source
+func (s *MeCard) Close() error {
+	return s.r.Close()
+}
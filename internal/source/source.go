@@ -0,0 +1,120 @@
+// Package source defines the Source interface implemented by every contact
+// provider (vCard files, CSV exports, remote address books, ...) so the
+// import pipeline can consume any of them.
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// Source yields contacts one at a time. Next returns io.EOF when there
+// are no more contacts.
+type Source interface {
+	Next(ctx context.Context) (*vcard.Contact, error)
+	Close() error
+}
+
+// Open returns the Source for a file path based on its extension, the
+// vCard parser for extensions it doesn't know. The path "-" reads vCards
+// from stdin.
+func Open(path string) (Source, error) {
+	return OpenFormat(path, "")
+}
+
+// formatExtensions maps file extensions to the format read by default
+var formatExtensions = map[string]string{
+	"":        "vcard",
+	".vcf":    "vcard",
+	".vcard":  "vcard",
+	".ldif":   "ldif",
+	".ldi":    "ldif",
+	".csv":    "csv",
+	".mecard": "mecard",
+	".html":   "html",
+	".htm":    "html",
+	".mbox":   "mbox",
+	".mbx":    "mbox",
+	".eml":    "eml",
+}
+
+// OpenFormat returns the Source reading path in the given format: "vcard",
+// "ldif", "mecard", "html", "mbox", "eml", "maildir" or one of CSVFormats.
+// An empty format is detected from the extension, vCard when unknown.
+func OpenFormat(path, format string) (Source, error) {
+	if IsMail(path, format) {
+		return OpenMail(path, DefaultMailMinMessages)
+	}
+	if format == "" {
+		ext := strings.ToLower(filepath.Ext(path))
+		if format = formatExtensions[ext]; format == "" {
+			format = "vcard"
+		}
+	}
+
+	newSource, ok := fileSource(format)
+	if !ok {
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+
+	return openFile(path, newSource)
+}
+
+// OpenCSV returns the Source reading the CSV file at path with a custom
+// column mapping, e.g. one loaded with LoadMapping
+func OpenCSV(path string, mapping Mapping) (Source, error) {
+	return openFile(path, func(r io.ReadCloser) Source { return NewCSV(r, mapping) })
+}
+
+// openFile opens path, or stdin for "-", with newSource
+func openFile(path string, newSource func(io.ReadCloser) Source) (Source, error) {
+	if path == "-" {
+		return newSource(io.NopCloser(os.Stdin)), nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return newSource(file), nil
+}
+
+// fileSource returns the constructor of the Source reading format
+func fileSource(format string) (func(io.ReadCloser) Source, bool) {
+	switch format {
+	case "vcard":
+		return func(r io.ReadCloser) Source { return NewVCard(r) }, true
+	case "ldif":
+		return func(r io.ReadCloser) Source { return NewLDIF(r, ThunderbirdLDIFMapping()) }, true
+	case "mecard":
+		return func(r io.ReadCloser) Source { return NewMeCard(r) }, true
+	case "html":
+		return func(r io.ReadCloser) Source { return NewHTML(r, nil) }, true
+	}
+	if mapping, ok := CSVFormats[format]; ok {
+		return func(r io.ReadCloser) Source { return NewCSV(r, mapping()) }, true
+	}
+	return nil, false
+}
+
+// ReadAll drains a source and returns every contact read. On error the
+// contacts read so far are returned alongside it.
+func ReadAll(ctx context.Context, src Source) ([]vcard.Contact, error) {
+	var contacts []vcard.Contact
+	for {
+		c, err := src.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			return contacts, nil
+		}
+		if err != nil {
+			return contacts, err
+		}
+		contacts = append(contacts, *c)
+	}
+}
@@ -0,0 +1,80 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+func TestOpen_VCard(t *testing.T) {
+	src, err := Open("../../examples/sample-contacts.vcf")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer src.Close()
+
+	contacts, err := ReadAll(context.Background(), src)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(contacts) != 5 {
+		t.Errorf("expected 5 contacts, got %d", len(contacts))
+	}
+}
+
+func TestOpen_Errors(t *testing.T) {
+	if _, err := Open(filepath.Join(t.TempDir(), "missing.vcf")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestOpen_UnknownExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "contacts.txt")
+	if err := os.WriteFile(path, []byte("BEGIN:VCARD\nVERSION:3.0\nFN:A\nEND:VCARD\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	src, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer src.Close()
+
+	contacts, err := ReadAll(context.Background(), src)
+	if err != nil || len(contacts) != 1 {
+		t.Errorf("ReadAll() = %d contacts, %v; want 1 read as a vCard", len(contacts), err)
+	}
+}
+
+func TestVCard_CanceledContext(t *testing.T) {
+	src := NewVCard(io.NopCloser(strings.NewReader("BEGIN:VCARD\nVERSION:3.0\nFN:A\nEND:VCARD\n")))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := src.Next(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Next() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestFilterAndMap(t *testing.T) {
+	data := "BEGIN:VCARD\nVERSION:3.0\nFN:A\nEND:VCARD\nBEGIN:VCARD\nVERSION:3.0\nFN:B\nEND:VCARD\n"
+	var src Source = NewVCard(io.NopCloser(strings.NewReader(data)))
+	src = Filter(src, func(c *vcard.Contact) bool { return c.FormattedName == "B" })
+	src = Map(src, func(ctx context.Context, c *vcard.Contact) error {
+		c.Note = "seen"
+		return nil
+	})
+
+	contacts, err := ReadAll(context.Background(), src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contacts) != 1 || contacts[0].FormattedName != "B" || contacts[0].Note != "seen" {
+		t.Errorf("contacts = %+v", contacts)
+	}
+}
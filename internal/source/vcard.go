@@ -0,0 +1,32 @@
+package source
+
+import (
+	"context"
+	"io"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// VCard reads contacts from a vCard (.vcf) stream
+type VCard struct {
+	r   io.ReadCloser
+	dec *vcard.Decoder
+}
+
+// NewVCard creates a Source reading vCards from r. Close closes r.
+func NewVCard(r io.ReadCloser) *VCard {
+	return &VCard{r: r, dec: vcard.NewDecoder(r)}
+}
+
+// Next implements Source
+func (s *VCard) Next(ctx context.Context) (*vcard.Contact, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.dec.Next()
+}
+
+// Close implements Source
+func (s *VCard) Close() error {
+	return s.r.Close()
+}
@@ -0,0 +1,92 @@
+package source
+
+import (
+	"context"
+	"io"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// filtered wraps a Source, skipping contacts rejected by match
+type filtered struct {
+	Source
+	match func(*vcard.Contact) bool
+}
+
+// Filter returns a Source yielding only the contacts of src accepted by match
+func Filter(src Source, match func(*vcard.Contact) bool) Source {
+	return &filtered{Source: src, match: match}
+}
+
+// Next implements Source
+func (s *filtered) Next(ctx context.Context) (*vcard.Contact, error) {
+	for {
+		c, err := s.Source.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if s.match(c) {
+			return c, nil
+		}
+	}
+}
+
+// mapped wraps a Source, passing every contact through fn
+type mapped struct {
+	Source
+	fn func(context.Context, *vcard.Contact) error
+}
+
+// Map returns a Source yielding the contacts of src after fn modifies them.
+// An error from fn stops the source.
+func Map(src Source, fn func(context.Context, *vcard.Contact) error) Source {
+	return &mapped{Source: src, fn: fn}
+}
+
+// Next implements Source
+func (s *mapped) Next(ctx context.Context) (*vcard.Contact, error) {
+	c, err := s.Source.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fn(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// sorted wraps a Source, reading it whole to yield its contacts in order
+type sorted struct {
+	Source
+	by, locale string
+	contacts   []vcard.Contact
+	read       bool
+}
+
+// Sorted returns a Source yielding the contacts of src ordered by a
+// vcard.SortContacts key with the collation rules of locale. src is read
+// whole on the first call to Next.
+func Sorted(src Source, by, locale string) Source {
+	return &sorted{Source: src, by: by, locale: locale}
+}
+
+// Next implements Source
+func (s *sorted) Next(ctx context.Context) (*vcard.Contact, error) {
+	if !s.read {
+		s.read = true
+		contacts, err := ReadAll(ctx, s.Source)
+		if err != nil {
+			return nil, err
+		}
+		if err := vcard.SortContacts(contacts, s.by, s.locale); err != nil {
+			return nil, err
+		}
+		s.contacts = contacts
+	}
+	if len(s.contacts) == 0 {
+		return nil, io.EOF
+	}
+	c := s.contacts[0]
+	s.contacts = s.contacts[1:]
+	return &c, nil
+}
@@ -0,0 +1,235 @@
+// Package transform runs user Starlark scripts over contacts.
+//
+// A script defines transform(contact), where contact is a dict keyed by the
+// contact's JSON field names (given_name, emails, categories, ...). The
+// function may modify the dict in place, return a new dict to replace it,
+// or return False to drop the contact.
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// FuncName is the function every transform script must define
+const FuncName = "transform"
+
+// Script is a loaded transform script
+type Script struct {
+	thread *starlark.Thread
+	fn     starlark.Callable
+}
+
+// Load reads and executes a transform script. Output of print() goes to out.
+func Load(path string, out io.Writer) (*Script, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transform script: %w", err)
+	}
+	return New(path, src, out)
+}
+
+// New executes the transform script src; filename is used in error messages
+func New(filename string, src []byte, out io.Writer) (*Script, error) {
+	thread := &starlark.Thread{
+		Name: "transform",
+		Print: func(_ *starlark.Thread, msg string) {
+			fmt.Fprintln(out, msg)
+		},
+	}
+	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, filename, src, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load transform script: %w", err)
+	}
+	fn, ok := globals[FuncName].(starlark.Callable)
+	if !ok {
+		return nil, fmt.Errorf("transform script %s does not define %s(contact)", filename, FuncName)
+	}
+	return &Script{thread: thread, fn: fn}, nil
+}
+
+// Apply runs the script on c, updating it in place. It returns false when
+// the script drops the contact.
+func (s *Script) Apply(c *vcard.Contact) (bool, error) {
+	dict, err := toDict(c)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := starlark.Call(s.thread, s.fn, starlark.Tuple{dict}, nil)
+	if err != nil {
+		if evalErr, ok := err.(*starlark.EvalError); ok {
+			return false, fmt.Errorf("transform failed for %s: %s", c.DisplayName(), evalErr.Backtrace())
+		}
+		return false, fmt.Errorf("transform failed for %s: %w", c.DisplayName(), err)
+	}
+
+	switch r := result.(type) {
+	case starlark.NoneType:
+	case starlark.Bool:
+		if !r {
+			return false, nil
+		}
+	case *starlark.Dict:
+		dict = r
+	default:
+		return false, fmt.Errorf("%s() must return None, a bool or a dict, got %s", FuncName, result.Type())
+	}
+
+	if err := fromDict(dict, c); err != nil {
+		return false, fmt.Errorf("transform returned an invalid contact for %s: %w", c.DisplayName(), err)
+	}
+	return true, nil
+}
+
+// toDict converts a contact to a Starlark dict through its JSON encoding.
+// Every field is present so scripts can read and append without checks.
+func toDict(c *vcard.Contact) (*starlark.Dict, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var fields map[string]any
+	if err := dec.Decode(&fields); err != nil {
+		return nil, err
+	}
+
+	t := reflect.TypeOf(vcard.Contact{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if _, ok := fields[name]; ok || name == "" || name == "-" {
+			continue
+		}
+		switch t.Field(i).Type.Kind() {
+		case reflect.String:
+			fields[name] = ""
+		case reflect.Slice:
+			fields[name] = []any{}
+		case reflect.Map:
+			fields[name] = map[string]any{}
+		default:
+			fields[name] = nil
+		}
+	}
+
+	v, err := toStarlark(fields)
+	if err != nil {
+		return nil, err
+	}
+	return v.(*starlark.Dict), nil
+}
+
+func fromDict(dict *starlark.Dict, c *vcard.Contact) error {
+	v, err := fromStarlark(dict)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var updated vcard.Contact
+	if err := json.Unmarshal(data, &updated); err != nil {
+		return err
+	}
+	*c = updated
+	return nil
+}
+
+func toStarlark(v any) (starlark.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return starlark.None, nil
+	case bool:
+		return starlark.Bool(v), nil
+	case string:
+		return starlark.String(v), nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return starlark.MakeInt64(i), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return starlark.Float(f), nil
+	case []any:
+		elems := make([]starlark.Value, len(v))
+		for i, e := range v {
+			sv, err := toStarlark(e)
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = sv
+		}
+		return starlark.NewList(elems), nil
+	case map[string]any:
+		dict := starlark.NewDict(len(v))
+		for k, e := range v {
+			sv, err := toStarlark(e)
+			if err != nil {
+				return nil, err
+			}
+			if err := dict.SetKey(starlark.String(k), sv); err != nil {
+				return nil, err
+			}
+		}
+		return dict, nil
+	}
+	return nil, fmt.Errorf("unsupported value %T", v)
+}
+
+func fromStarlark(v starlark.Value) (any, error) {
+	switch v := v.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.Bool:
+		return bool(v), nil
+	case starlark.String:
+		return string(v), nil
+	case starlark.Int:
+		i, ok := v.Int64()
+		if !ok {
+			return nil, fmt.Errorf("integer %s out of range", v)
+		}
+		return i, nil
+	case starlark.Float:
+		return float64(v), nil
+	case starlark.Indexable: // list, tuple
+		elems := make([]any, v.Len())
+		for i := range elems {
+			e, err := fromStarlark(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = e
+		}
+		return elems, nil
+	case *starlark.Dict:
+		m := make(map[string]any, v.Len())
+		for _, item := range v.Items() {
+			k, ok := item[0].(starlark.String)
+			if !ok {
+				return nil, fmt.Errorf("dict key %s is not a string", item[0])
+			}
+			e, err := fromStarlark(item[1])
+			if err != nil {
+				return nil, err
+			}
+			m[string(k)] = e
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("unsupported value of type %s", v.Type())
+}
@@ -0,0 +1,131 @@
+package transform
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+func TestScript_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		contact  vcard.Contact
+		wantKeep bool
+		check    func(t *testing.T, c vcard.Contact)
+	}{
+		{
+			name: "modify in place",
+			script: `
+def transform(c):
+    c["organization"] = c["organization"].replace(" Inc.", "")
+    c["categories"].append("work")
+`,
+			contact:  vcard.Contact{FormattedName: "Jane", Organization: "Acme Inc."},
+			wantKeep: true,
+			check: func(t *testing.T, c vcard.Contact) {
+				if c.Organization != "Acme" {
+					t.Errorf("Organization = %q, want Acme", c.Organization)
+				}
+				if len(c.Categories) != 1 || c.Categories[0] != "work" {
+					t.Errorf("Categories = %v, want [work]", c.Categories)
+				}
+				if c.FormattedName != "Jane" {
+					t.Errorf("FormattedName = %q, want unchanged", c.FormattedName)
+				}
+			},
+		},
+		{
+			name: "drop",
+			script: `
+def transform(c):
+    if not c["emails"]:
+        return False
+`,
+			contact:  vcard.Contact{FormattedName: "No Email"},
+			wantKeep: false,
+		},
+		{
+			name: "return replacement",
+			script: `
+def transform(c):
+    return {"formatted_name": c["formatted_name"].upper(), "emails": [e.lower() for e in c["emails"]]}
+`,
+			contact:  vcard.Contact{FormattedName: "Jane", Emails: []string{"JANE@EXAMPLE.COM"}, Note: "dropped"},
+			wantKeep: true,
+			check: func(t *testing.T, c vcard.Contact) {
+				if c.FormattedName != "JANE" || c.Emails[0] != "jane@example.com" || c.Note != "" {
+					t.Errorf("contact = %+v", c)
+				}
+			},
+		},
+		{
+			name: "nested addresses",
+			script: `
+def transform(c):
+    for a in c["addresses"]:
+        a["country"] = "Spain"
+`,
+			contact:  vcard.Contact{Addresses: []vcard.Address{{City: "Madrid"}}},
+			wantKeep: true,
+			check: func(t *testing.T, c vcard.Contact) {
+				if c.Addresses[0].Country != "Spain" || c.Addresses[0].City != "Madrid" {
+					t.Errorf("Addresses = %+v", c.Addresses)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New("test.star", []byte(tt.script), &bytes.Buffer{})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			c := tt.contact
+			keep, err := s.Apply(&c)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if keep != tt.wantKeep {
+				t.Fatalf("Apply() keep = %v, want %v", keep, tt.wantKeep)
+			}
+			if tt.check != nil {
+				tt.check(t, c)
+			}
+		})
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	if _, err := New("test.star", []byte("x = 1\n"), &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "does not define") {
+		t.Errorf("New() without transform error = %v", err)
+	}
+	if _, err := New("test.star", []byte("def transform(:\n"), &bytes.Buffer{}); err == nil {
+		t.Error("New() with syntax error returned nil error")
+	}
+
+	s, err := New("test.star", []byte("def transform(c):\n    return 42\n"), &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Apply(&vcard.Contact{}); err == nil {
+		t.Error("Apply() returning an int error = nil")
+	}
+}
+
+func TestScript_Print(t *testing.T) {
+	var out bytes.Buffer
+	s, err := New("test.star", []byte("def transform(c):\n    print('seen', c['formatted_name'])\n"), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Apply(&vcard.Contact{FormattedName: "Jane"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(out.String()); got != "seen Jane" {
+		t.Errorf("print output = %q", got)
+	}
+}
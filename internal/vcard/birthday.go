@@ -0,0 +1,146 @@
+package vcard
+
+import (
+	"fmt"
+	"time"
+)
+
+// YearlessBirthdayPlaceholder is the year used for birthdays without a year.
+// 1604 is a leap year and the convention used by Apple Contacts.
+const YearlessBirthdayPlaceholder = 1604
+
+// ValidatePlaceholderYear rejects placeholder years that aren't leap years:
+// Feb 29 birthdays without a year would be written as Mar 1.
+func ValidatePlaceholderYear(year int) error {
+	if time.Date(year, time.February, 29, 0, 0, 0, 0, time.UTC).Month() != time.February {
+		return fmt.Errorf("invalid birthday placeholder year %d (expected a leap year, such as %d)", year, YearlessBirthdayPlaceholder)
+	}
+	return nil
+}
+
+// BirthdayDate is a parsed birthday. Year is 0 when the year is unknown.
+type BirthdayDate struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// HasYear reports whether the birthday includes a real year
+func (b BirthdayDate) HasYear() bool {
+	return b.Year != 0
+}
+
+// Time returns the birthday as a UTC time, using placeholderYear when the year is unknown
+func (b BirthdayDate) Time(placeholderYear int) time.Time {
+	year := b.Year
+	if year == 0 {
+		year = placeholderYear
+	}
+	return time.Date(year, b.Month, b.Day, 0, 0, 0, 0, time.UTC)
+}
+
+// String formats the birthday as YYYY-MM-DD, or --MM-DD without a year
+func (b BirthdayDate) String() string {
+	if b.Year == 0 {
+		return fmt.Sprintf("--%02d-%02d", b.Month, b.Day)
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", b.Year, b.Month, b.Day)
+}
+
+// ParseBirthdayDate parses the birthday formats found in vCards.
+// Handles: 19850615, 1985-06-15, RFC3339, year-less --0615 / --06-15,
+// and the 1604 placeholder year used by Apple for year-less birthdays.
+func ParseBirthdayDate(bday string) (BirthdayDate, bool) {
+	formats := []string{"20060102", "2006-01-02", time.RFC3339, "--0102", "--01-02"}
+	for _, format := range formats {
+		t, err := time.Parse(format, bday)
+		if err != nil {
+			continue
+		}
+		b := BirthdayDate{Year: t.Year(), Month: t.Month(), Day: t.Day()}
+		if format[0] == '-' || b.Year == YearlessBirthdayPlaceholder {
+			b.Year = 0
+		}
+		return b, true
+	}
+
+	// time.Parse rejects Feb 29 without a year; handle it explicitly
+	if bday == "--0229" || bday == "--02-29" {
+		return BirthdayDate{Month: time.February, Day: 29}, true
+	}
+	return BirthdayDate{}, false
+}
+
+// ResolveYearlessBirthday rewrites a birthday without a year. With asText the
+// birthday moves to BirthdayText (--MM-DD) and no date is written; otherwise
+// placeholderYear is used as the year.
+func (c *Contact) ResolveYearlessBirthday(placeholderYear int, asText bool) {
+	b, ok := ParseBirthdayDate(c.Birthday)
+	if !ok || b.HasYear() {
+		return
+	}
+	if asText {
+		c.BirthdayText = b.String()
+		c.Birthday = ""
+		return
+	}
+	c.Birthday = b.Time(placeholderYear).Format("2006-01-02")
+}
+
+// Age returns the completed years at now. The second result is false
+// when the birth year is unknown.
+func (b BirthdayDate) Age(now time.Time) (int, bool) {
+	if !b.HasYear() {
+		return 0, false
+	}
+	age := now.Year() - b.Year
+	if now.Month() < b.Month || (now.Month() == b.Month && now.Day() < b.Day) {
+		age--
+	}
+	return age, true
+}
+
+// Next returns the next occurrence of the birthday on or after now.
+// Feb 29 birthdays fall on Mar 1 in non-leap years.
+func (b BirthdayDate) Next(now time.Time) time.Time {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	next := time.Date(today.Year(), b.Month, b.Day, 0, 0, 0, 0, time.UTC)
+	if next.Before(today) {
+		next = time.Date(today.Year()+1, b.Month, b.Day, 0, 0, 0, 0, time.UTC)
+	}
+	return next
+}
+
+// ComputeBirthdayFields derives Age and NextBirthday from the birthday.
+// Birthdays in placeholderYear, see ResolveYearlessBirthday, have no age.
+func (c *Contact) ComputeBirthdayFields(now time.Time, placeholderYear int) {
+	c.Age = nil
+	c.NextBirthday = ""
+
+	b, ok := ParseBirthdayDate(c.Birthday)
+	if !ok {
+		b, ok = ParseBirthdayDate(c.BirthdayText)
+	}
+	if !ok {
+		return
+	}
+	if b.Year == placeholderYear {
+		b.Year = 0
+	}
+	if age, ok := b.Age(now); ok {
+		c.Age = &age
+	}
+	c.NextBirthday = b.Next(now).Format(time.RFC3339)
+}
+
+// BirthdayFieldProperties builds the age and next_birthday properties
+func BirthdayFieldProperties(contact Contact) []map[string]any {
+	var props []map[string]any
+	if contact.Age != nil {
+		props = append(props, map[string]any{"key": "age", "number": *contact.Age})
+	}
+	if contact.NextBirthday != "" {
+		props = append(props, map[string]any{"key": "next_birthday", "date": contact.NextBirthday})
+	}
+	return props
+}
@@ -0,0 +1,149 @@
+package vcard
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseBirthdayDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected BirthdayDate
+		ok       bool
+	}{
+		{"basic format", "19850615", BirthdayDate{1985, time.June, 15}, true},
+		{"extended format", "1985-06-15", BirthdayDate{1985, time.June, 15}, true},
+		{"RFC3339", "1985-06-15T00:00:00Z", BirthdayDate{1985, time.June, 15}, true},
+		{"year-less basic", "--0315", BirthdayDate{0, time.March, 15}, true},
+		{"year-less extended", "--03-15", BirthdayDate{0, time.March, 15}, true},
+		{"year-less leap day", "--0229", BirthdayDate{0, time.February, 29}, true},
+		{"Apple placeholder", "1604-02-29", BirthdayDate{0, time.February, 29}, true},
+		{"invalid", "sometime in june", BirthdayDate{}, false},
+		{"empty", "", BirthdayDate{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseBirthdayDate(tt.input)
+			if ok != tt.ok || got != tt.expected {
+				t.Errorf("ParseBirthdayDate(%q) = %+v, %v; want %+v, %v", tt.input, got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseBirthday(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1985-06-15", "1985-06-15T00:00:00Z"},
+		{"--0315", "1604-03-15T00:00:00Z"},
+		{"--02-29", "1604-02-29T00:00:00Z"},
+		{"unparseable", "unparseable"},
+	}
+
+	for _, tt := range tests {
+		if got := ParseBirthday(tt.input); got != tt.expected {
+			t.Errorf("ParseBirthday(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestContact_ResolveYearlessBirthday(t *testing.T) {
+	c := Contact{Birthday: "--0315"}
+	c.ResolveYearlessBirthday(1900, false)
+	if c.Birthday != "1900-03-15" || c.BirthdayText != "" {
+		t.Errorf("placeholder mode: Birthday = %q, BirthdayText = %q", c.Birthday, c.BirthdayText)
+	}
+
+	c = Contact{Birthday: "1604-02-29"}
+	c.ResolveYearlessBirthday(1900, true)
+	if c.Birthday != "" || c.BirthdayText != "--02-29" {
+		t.Errorf("text mode: Birthday = %q, BirthdayText = %q", c.Birthday, c.BirthdayText)
+	}
+
+	c = Contact{Birthday: "1985-06-15"}
+	c.ResolveYearlessBirthday(1900, true)
+	if c.Birthday != "1985-06-15" || c.BirthdayText != "" {
+		t.Errorf("full dates must be untouched: Birthday = %q, BirthdayText = %q", c.Birthday, c.BirthdayText)
+	}
+}
+
+func TestValidatePlaceholderYear(t *testing.T) {
+	for _, year := range []int{1604, 1904, 2000} {
+		if err := ValidatePlaceholderYear(year); err != nil {
+			t.Errorf("ValidatePlaceholderYear(%d) = %v, want nil", year, err)
+		}
+	}
+	for _, year := range []int{1900, 1999, 2023} {
+		if err := ValidatePlaceholderYear(year); err == nil {
+			t.Errorf("ValidatePlaceholderYear(%d) = nil, want an error", year)
+		}
+	}
+}
+
+func TestBirthdayDate_AgeAndNext(t *testing.T) {
+	now := time.Date(2025, time.June, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		birthday BirthdayDate
+		wantAge  int
+		wantOK   bool
+		wantNext string
+	}{
+		{"birthday today", BirthdayDate{1985, time.June, 15}, 40, true, "2025-06-15"},
+		{"birthday tomorrow", BirthdayDate{1985, time.June, 16}, 39, true, "2025-06-16"},
+		{"birthday passed", BirthdayDate{1990, time.March, 22}, 35, true, "2026-03-22"},
+		{"year-less", BirthdayDate{0, time.December, 1}, 0, false, "2025-12-01"},
+		{"leap day in non-leap year", BirthdayDate{2000, time.February, 29}, 25, true, "2026-03-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			age, ok := tt.birthday.Age(now)
+			if age != tt.wantAge || ok != tt.wantOK {
+				t.Errorf("Age() = %d, %v; want %d, %v", age, ok, tt.wantAge, tt.wantOK)
+			}
+			if next := tt.birthday.Next(now).Format("2006-01-02"); next != tt.wantNext {
+				t.Errorf("Next() = %s, want %s", next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestContact_ComputeBirthdayFields(t *testing.T) {
+	now := time.Date(2025, time.June, 15, 0, 0, 0, 0, time.UTC)
+
+	c := Contact{Birthday: "1985-06-20"}
+	c.ComputeBirthdayFields(now, YearlessBirthdayPlaceholder)
+	if c.Age == nil || *c.Age != 39 {
+		t.Errorf("Age = %v, want 39", c.Age)
+	}
+	if c.NextBirthday != "2025-06-20T00:00:00Z" {
+		t.Errorf("NextBirthday = %q", c.NextBirthday)
+	}
+	if props := BirthdayFieldProperties(c); len(props) != 2 {
+		t.Errorf("expected age and next_birthday properties, got %v", props)
+	}
+
+	c = Contact{BirthdayText: "--03-15"}
+	c.ComputeBirthdayFields(now, YearlessBirthdayPlaceholder)
+	if c.Age != nil || c.NextBirthday != "2026-03-15T00:00:00Z" {
+		t.Errorf("year-less: Age = %v, NextBirthday = %q", c.Age, c.NextBirthday)
+	}
+
+	c = Contact{Birthday: "2000-03-15"}
+	c.ComputeBirthdayFields(now, 2000)
+	if c.Age != nil || c.NextBirthday != "2026-03-15T00:00:00Z" {
+		t.Errorf("custom placeholder year: Age = %v, NextBirthday = %q", c.Age, c.NextBirthday)
+	}
+
+	c = Contact{}
+	c.ComputeBirthdayFields(now, YearlessBirthdayPlaceholder)
+	if props := BirthdayFieldProperties(c); len(props) != 0 {
+		t.Errorf("no birthday should produce no properties, got %v", props)
+	}
+}
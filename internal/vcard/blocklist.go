@@ -0,0 +1,93 @@
+package vcard
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Blocklist lists contacts never to import: spam callers, defunct
+// services, ...
+type Blocklist struct {
+	Phones []string `json:"phones,omitempty"` // Matched like duplicate phones, see SamePhone
+	Emails []string `json:"emails,omitempty"` // Matched like duplicate emails; "@domain" blocks a whole domain
+	Names  []string `json:"names,omitempty"`  // Regular expressions matched against the display name, ignoring case
+
+	names []*regexp.Regexp
+}
+
+// ParseBlocklist reads a blocklist with an entry per line. Lines with an @
+// are emails, lines of only digits and phone punctuation are phones and
+// any other is a name pattern. Blank lines and lines starting with # are
+// skipped.
+func ParseBlocklist(r io.Reader) (*Blocklist, error) {
+	b := &Blocklist{}
+	phone := regexp.MustCompile(`^[+\d][\d\s().-]*$`)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "", strings.HasPrefix(line, "#"):
+		case strings.Contains(line, "@"):
+			b.Emails = append(b.Emails, line)
+		case phone.MatchString(line):
+			b.Phones = append(b.Phones, line)
+		default:
+			b.Names = append(b.Names, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return b, b.Compile()
+}
+
+// Add appends the entries of other
+func (b *Blocklist) Add(other *Blocklist) error {
+	b.Phones = append(b.Phones, other.Phones...)
+	b.Emails = append(b.Emails, other.Emails...)
+	b.Names = append(b.Names, other.Names...)
+	return b.Compile()
+}
+
+// Compile validates the name patterns, which must be done before Blocks
+func (b *Blocklist) Compile() error {
+	b.names = b.names[:0]
+	for _, pattern := range b.Names {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return fmt.Errorf("invalid blocklist name pattern %q: %w", pattern, err)
+		}
+		b.names = append(b.names, re)
+	}
+	return nil
+}
+
+// Empty reports whether the blocklist blocks nothing
+func (b *Blocklist) Empty() bool {
+	return len(b.Phones) == 0 && len(b.Emails) == 0 && len(b.Names) == 0
+}
+
+// Blocks reports whether c has a blocked phone, email or name, comparing
+// phones and emails as opts say
+func (b *Blocklist) Blocks(c *Contact, opts DedupOptions) bool {
+	for _, p := range c.Phones {
+		if slices.ContainsFunc(b.Phones, func(blocked string) bool { return opts.SamePhone(p, blocked) }) {
+			return true
+		}
+	}
+	for _, e := range c.Emails {
+		key := opts.Email.Normalize(e)
+		for _, blocked := range b.Emails {
+			blockedKey := opts.Email.Normalize(blocked)
+			if blockedKey == key || strings.HasPrefix(blockedKey, "@") && strings.HasSuffix(key, blockedKey) {
+				return true
+			}
+		}
+	}
+	name := c.DisplayName()
+	return slices.ContainsFunc(b.names, func(re *regexp.Regexp) bool { return re.MatchString(name) })
+}
@@ -0,0 +1,60 @@
+package vcard
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseBlocklist(t *testing.T) {
+	b, err := ParseBlocklist(strings.NewReader(`# spam
++34 900 123 456
+
+offers@spam.example
+@defunct.example
+^Spam Risk
+`))
+	if err != nil {
+		t.Fatalf("ParseBlocklist() error = %v", err)
+	}
+	if !slices.Equal(b.Phones, []string{"+34 900 123 456"}) ||
+		!slices.Equal(b.Emails, []string{"offers@spam.example", "@defunct.example"}) ||
+		!slices.Equal(b.Names, []string{"^Spam Risk"}) {
+		t.Errorf("ParseBlocklist() = %+v", b)
+	}
+
+	if _, err := ParseBlocklist(strings.NewReader("Spam (")); err == nil {
+		t.Error("ParseBlocklist() accepted an invalid name pattern")
+	}
+}
+
+func TestBlocklistBlocks(t *testing.T) {
+	b := &Blocklist{
+		Phones: []string{"+34 900 123 456"},
+		Emails: []string{"offers@spam.example", "@defunct.example"},
+		Names:  []string{"^spam risk"},
+	}
+	if err := b.Compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		contact Contact
+		want    bool
+	}{
+		{"phone", Contact{FormattedName: "Caller", Phones: []string{"900-123-456"}}, true},
+		{"email", Contact{FormattedName: "Offers", Emails: []string{"Offers+promo@spam.example"}}, true},
+		{"domain", Contact{FormattedName: "Old Service", Emails: []string{"support@defunct.example"}}, true},
+		{"name", Contact{FormattedName: "Spam Risk 2"}, true},
+		{"other domain", Contact{FormattedName: "Jane", Emails: []string{"jane@notdefunct.example"}}, false},
+		{"clean", Contact{FormattedName: "Jane Doe", Phones: []string{"+34 600 111 222"}, Emails: []string{"jane@example.com"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.Blocks(&tt.contact, DedupOptions{}); got != tt.want {
+				t.Errorf("Blocks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package vcard
+
+// Cluster is a group of contacts linked by duplicate matches
+type Cluster struct {
+	Contacts []*Contact // In input order; the first is the natural survivor
+	// Links[i] is the match that ties Contacts[i] into the cluster: its
+	// best match with an earlier contact, or with any contact of the
+	// cluster when it only matches later ones. Links[0] is unset.
+	Links []Match
+}
+
+// WeakLink is a match too weak to cluster on, such as a shared name alone.
+// It is reported for review and never merged.
+type WeakLink struct {
+	Contact *Contact
+	Match   Match // Against Contact, with the later contact of the pair
+}
+
+// ClusterScore is the score at which a match that isn't MatchStrong still
+// joins a cluster
+const ClusterScore = 0.9
+
+// Joins reports whether m is strong enough to cluster on. Weaker matches
+// would chain: Jane Doe and J. Doe share a name, J. Doe and John Doe
+// another, and all three would be merged.
+func Joins(m Match) bool {
+	return m.Strength == MatchStrong || m.Score >= ClusterScore
+}
+
+// ClusterDuplicates groups contacts that are duplicates of each other.
+// Every contact is matched against all the others, and the matches accept
+// approves (nil approves all) that Join are joined transitively with
+// union-find: when A matches B by phone and B matches C by email, A, B and
+// C form a single cluster even if A and C share nothing, matching as opts
+// say. Contacts without
+// duplicates are left out, and clusters are ordered by their first contact.
+// The accepted matches that don't Join are returned as weak links, once per
+// pair, unless their contacts ended up in the same cluster.
+func ClusterDuplicates(contacts []*Contact, opts DedupOptions, phonetic bool, accept func(Match) bool) ([]Cluster, []WeakLink) {
+	idx := NewDedupIndexWith(NewMemoryIndexStore(), opts, contacts)
+	if phonetic {
+		idx.EnablePhonetic()
+	}
+	position := make(map[*Contact]int, len(contacts))
+	for i, c := range contacts {
+		position[c] = i
+	}
+
+	parent := make([]int, len(contacts))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(i, j int) {
+		ri, rj := find(i), find(j)
+		// The earliest contact stays the root so it leads the cluster
+		if ri > rj {
+			ri, rj = rj, ri
+		}
+		parent[rj] = ri
+	}
+
+	// Matches by contact, in both directions
+	links := make([][]Match, len(contacts))
+	var weak []WeakLink
+	for i, c := range contacts {
+		for _, m := range idx.FindMatches(c) {
+			j, ok := position[m.Contact]
+			if !ok || (accept != nil && !accept(m)) {
+				continue
+			}
+			if !Joins(m) {
+				if i < j { // Each pair is found from both sides
+					weak = append(weak, WeakLink{Contact: c, Match: m})
+				}
+				continue
+			}
+			union(i, j)
+			links[i] = append(links[i], m)
+			// Scored from j's side so its reasons quote its own values
+			reverse := ScoreContacts(contacts[j], c, opts)
+			if phonetic {
+				addPhoneticMatch(&reverse, contacts[j], c)
+			}
+			links[j] = append(links[j], reverse)
+		}
+	}
+
+	var clusters []Cluster
+	byRoot := make(map[int]int)
+	for i, c := range contacts {
+		root := find(i)
+		if len(links[root]) == 0 {
+			continue // No duplicates
+		}
+		k, ok := byRoot[root]
+		if !ok {
+			k = len(clusters)
+			byRoot[root] = k
+			clusters = append(clusters, Cluster{})
+		}
+		cl := &clusters[k]
+		var link Match
+		if len(cl.Contacts) > 0 {
+			link = bestLink(links[i], position, i)
+		}
+		cl.Contacts = append(cl.Contacts, c)
+		cl.Links = append(cl.Links, link)
+	}
+
+	// Contacts clustered through stronger matches are merged anyway
+	n := 0
+	for _, w := range weak {
+		if find(position[w.Contact]) != find(position[w.Match.Contact]) {
+			weak[n] = w
+			n++
+		}
+	}
+	return clusters, weak[:n]
+}
+
+// bestLink picks the highest scoring match of contact i with an earlier
+// contact, falling back to any match; ties go to the earliest contact
+func bestLink(matches []Match, position map[*Contact]int, i int) Match {
+	var best Match
+	bestEarlier := false
+	for _, m := range matches {
+		earlier := position[m.Contact] < i
+		switch {
+		case best.Contact == nil,
+			earlier && !bestEarlier,
+			earlier == bestEarlier && m.Score > best.Score,
+			earlier == bestEarlier && m.Score == best.Score && position[m.Contact] < position[best.Contact]:
+			best, bestEarlier = m, earlier
+		}
+	}
+	return best
+}
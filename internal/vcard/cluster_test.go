@@ -0,0 +1,73 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClusterDuplicates(t *testing.T) {
+	a := &Contact{FormattedName: "Jane Doe", Phones: []string{"+34 612 345 678"}}
+	b := &Contact{FormattedName: "J. Doe", Phones: []string{"612345678"}, Emails: []string{"jane@example.com"}}
+	other := &Contact{FormattedName: "Bob", Emails: []string{"bob@example.com"}}
+	c := &Contact{FormattedName: "Jane", Emails: []string{"Jane@Example.com"}}
+	contacts := []*Contact{a, b, other, c}
+
+	clusters, weak := ClusterDuplicates(contacts, DedupOptions{}, false, nil)
+	if len(clusters) != 1 {
+		t.Fatalf("ClusterDuplicates() = %d cluster(s), want 1", len(clusters))
+	}
+	if len(weak) != 0 {
+		t.Errorf("weak links = %+v, want none", weak)
+	}
+	cl := clusters[0]
+	if len(cl.Contacts) != 3 || cl.Contacts[0] != a || cl.Contacts[1] != b || cl.Contacts[2] != c {
+		t.Fatalf("cluster = %v, want a, b and c in input order", cl.Contacts)
+	}
+	if cl.Links[0].Contact != nil {
+		t.Errorf("Links[0] = %+v, want unset", cl.Links[0])
+	}
+	if cl.Links[1].Contact != a || cl.Links[1].Strength != MatchStrong {
+		t.Errorf("b linked via %+v, want a by phone", cl.Links[1])
+	}
+	if cl.Links[2].Contact != b {
+		t.Errorf("c linked via %+v, want b by email", cl.Links[2])
+	}
+
+	// Rejecting the email link splits c off
+	clusters, _ = ClusterDuplicates(contacts, DedupOptions{}, false, func(m Match) bool { return strings.HasPrefix(strings.Join(m.Reasons, ","), "phone") })
+	if len(clusters) != 1 || len(clusters[0].Contacts) != 2 {
+		t.Errorf("with accept: %+v, want only a and b", clusters)
+	}
+
+	if clusters, _ := ClusterDuplicates([]*Contact{other}, DedupOptions{}, false, nil); len(clusters) != 0 {
+		t.Errorf("ClusterDuplicates() of a single contact = %+v", clusters)
+	}
+}
+
+func TestClusterDuplicatesWeakChain(t *testing.T) {
+	// a and b share a phone; c only shares a's name, d sounds like c and e
+	// shares d's name
+	a := &Contact{FormattedName: "Jane Doe", Phones: []string{"612345678"}}
+	b := &Contact{FormattedName: "J. Doe", Phones: []string{"+34 612 345 678"}}
+	c := &Contact{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}}
+	d := &Contact{FormattedName: "Stephen Smith"}
+	e := &Contact{FormattedName: "Steven Smith"}
+	f := &Contact{FormattedName: "Steven Smith", Emails: []string{"steven@example.com"}}
+
+	clusters, weak := ClusterDuplicates([]*Contact{a, b, c, d, e, f}, DedupOptions{}, true, nil)
+	if len(clusters) != 1 || len(clusters[0].Contacts) != 2 || clusters[0].Contacts[0] != a || clusters[0].Contacts[1] != b {
+		t.Fatalf("clusters = %+v, want only a and b", clusters)
+	}
+
+	var pairs []string
+	for _, w := range weak {
+		if Joins(w.Match) {
+			t.Errorf("weak link %+v joins", w)
+		}
+		pairs = append(pairs, w.Contact.FormattedName+" ~ "+w.Match.Contact.FormattedName)
+	}
+	want := "Jane Doe ~ Jane Doe,Stephen Smith ~ Steven Smith,Stephen Smith ~ Steven Smith,Steven Smith ~ Steven Smith"
+	if strings.Join(pairs, ",") != want {
+		t.Errorf("weak links = %s, want %s", strings.Join(pairs, ","), want)
+	}
+}
@@ -0,0 +1,72 @@
+package vcard
+
+// completenessCriteria are what makes a contact complete, weighted out of
+// 100: a way to reach them matters most
+var completenessCriteria = []struct {
+	name   string
+	weight int
+	has    func(c *Contact) bool
+}{
+	{"phone", 20, func(c *Contact) bool { return len(c.Phones) > 0 }},
+	{"email", 20, func(c *Contact) bool { return len(c.Emails) > 0 }},
+	{"photo", 15, func(c *Contact) bool { return c.Photo != "" }},
+	{"birthday", 15, func(c *Contact) bool { return c.Birthday != "" || c.BirthdayText != "" }},
+	{"address", 15, func(c *Contact) bool {
+		for _, a := range c.Addresses {
+			if a != (Address{Geo: a.Geo}) {
+				return true
+			}
+		}
+		return false
+	}},
+	{"organization", 15, func(c *Contact) bool { return c.Organization != "" }},
+}
+
+// CompletenessCriteria returns the names of the criteria of the
+// completeness score
+func CompletenessCriteria() []string {
+	names := make([]string, len(completenessCriteria))
+	for i, criterion := range completenessCriteria {
+		names[i] = criterion.name
+	}
+	return names
+}
+
+// CompletenessScore rates from 0 to 100 how complete the contact is: it
+// has a phone, an email, a photo, a birthday, an address and an
+// organization
+func (c *Contact) CompletenessScore() int {
+	score := 0
+	for _, criterion := range completenessCriteria {
+		if criterion.has(c) {
+			score += criterion.weight
+		}
+	}
+	return score
+}
+
+// MissingForCompleteness returns the criteria of the completeness score
+// the contact lacks
+func (c *Contact) MissingForCompleteness() []string {
+	var missing []string
+	for _, criterion := range completenessCriteria {
+		if !criterion.has(c) {
+			missing = append(missing, criterion.name)
+		}
+	}
+	return missing
+}
+
+// ComputeCompleteness sets Completeness to the completeness score
+func (c *Contact) ComputeCompleteness() {
+	score := c.CompletenessScore()
+	c.Completeness = &score
+}
+
+// CompletenessProperties builds the completeness property
+func CompletenessProperties(contact Contact) []map[string]any {
+	if contact.Completeness == nil {
+		return nil
+	}
+	return []map[string]any{{"key": "completeness", "number": *contact.Completeness}}
+}
@@ -0,0 +1,52 @@
+package vcard
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompletenessScore(t *testing.T) {
+	full := Contact{
+		FormattedName: "Jane Doe",
+		Phones:        []string{"+34 612 345 678"},
+		Emails:        []string{"jane@example.com"},
+		Photo:         "https://example.com/jane.jpg",
+		BirthdayText:  "--06-01",
+		Addresses:     []Address{{City: "Madrid"}},
+		Organization:  "Acme",
+	}
+
+	tests := []struct {
+		name        string
+		contact     Contact
+		want        int
+		wantMissing []string
+	}{
+		{"complete", full, 100, nil},
+		{"name only", Contact{FormattedName: "Jane"}, 0, CompletenessCriteria()},
+		{"phone and email", Contact{Phones: full.Phones, Emails: full.Emails}, 40, []string{"photo", "birthday", "address", "organization"}},
+		{"geocoded empty address", Contact{Emails: full.Emails, Addresses: []Address{{Geo: &Geo{Lat: 1, Lon: 2}}}}, 20, []string{"phone", "photo", "birthday", "address", "organization"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.contact.CompletenessScore(); got != tt.want {
+				t.Errorf("CompletenessScore() = %d, want %d", got, tt.want)
+			}
+			if got := tt.contact.MissingForCompleteness(); !slices.Equal(got, tt.wantMissing) {
+				t.Errorf("MissingForCompleteness() = %v, want %v", got, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestCompletenessProperties(t *testing.T) {
+	c := Contact{FormattedName: "Jane", Emails: []string{"jane@example.com"}}
+	if props := CompletenessProperties(c); props != nil {
+		t.Errorf("CompletenessProperties() = %v before ComputeCompleteness", props)
+	}
+	c.ComputeCompleteness()
+	props := CompletenessProperties(c)
+	if len(props) != 1 || props[0]["key"] != "completeness" || props[0]["number"] != 20 {
+		t.Errorf("CompletenessProperties() = %v", props)
+	}
+}
@@ -0,0 +1,104 @@
+package vcard
+
+import (
+	"strings"
+)
+
+// CountryFormat selects the canonical form used for address countries
+type CountryFormat string
+
+const (
+	CountryFormatName CountryFormat = "name" // Canonical English name (Germany)
+	CountryFormatISO  CountryFormat = "iso"  // ISO-3166 alpha-2 code (DE)
+)
+
+// country holds the canonical forms and known aliases of a country
+type country struct {
+	ISO     string
+	Name    string
+	Aliases []string
+}
+
+// countries lists the canonical forms and common spellings, abbreviations
+// and native names seen in address book exports
+var countries = []country{
+	{"AR", "Argentina", []string{"arg", "republica argentina"}},
+	{"AT", "Austria", []string{"aut", "osterreich", "oesterreich"}},
+	{"AU", "Australia", []string{"aus"}},
+	{"BE", "Belgium", []string{"bel", "belgie", "belgique", "belgien", "belgica"}},
+	{"BR", "Brazil", []string{"bra", "brasil"}},
+	{"CA", "Canada", []string{"can"}},
+	{"CH", "Switzerland", []string{"che", "schweiz", "suisse", "svizzera", "suiza"}},
+	{"CL", "Chile", []string{"chl"}},
+	{"CN", "China", []string{"chn", "prc", "people's republic of china", "zhongguo"}},
+	{"CO", "Colombia", []string{"col"}},
+	{"CZ", "Czechia", []string{"cze", "czech republic", "cesko", "ceska republika"}},
+	{"DE", "Germany", []string{"deu", "deutschland", "alemania", "allemagne", "germania", "brd"}},
+	{"DK", "Denmark", []string{"dnk", "danmark", "dinamarca"}},
+	{"ES", "Spain", []string{"esp", "espana", "espagne", "spanien", "spagna"}},
+	{"FI", "Finland", []string{"fin", "suomi"}},
+	{"FR", "France", []string{"fra", "francia", "frankreich"}},
+	{"GB", "United Kingdom", []string{"gbr", "uk", "u.k.", "great britain", "britain", "england", "scotland", "wales", "reino unido", "royaume-uni", "vereinigtes konigreich"}},
+	{"GR", "Greece", []string{"grc", "hellas", "ellada", "grecia"}},
+	{"IE", "Ireland", []string{"irl", "eire", "irlanda"}},
+	{"IN", "India", []string{"ind", "bharat"}},
+	{"IT", "Italy", []string{"ita", "italia", "italien", "italie"}},
+	{"JP", "Japan", []string{"jpn", "nippon", "nihon", "japon"}},
+	{"KR", "South Korea", []string{"kor", "korea", "republic of korea"}},
+	{"MX", "Mexico", []string{"mex", "estados unidos mexicanos"}},
+	{"NL", "Netherlands", []string{"nld", "the netherlands", "holland", "nederland", "paises bajos", "pays-bas", "niederlande"}},
+	{"NO", "Norway", []string{"nor", "norge", "noruega"}},
+	{"NZ", "New Zealand", []string{"nzl", "aotearoa"}},
+	{"PE", "Peru", []string{"per"}},
+	{"PL", "Poland", []string{"pol", "polska", "polonia"}},
+	{"PT", "Portugal", []string{"prt"}},
+	{"RU", "Russia", []string{"rus", "russian federation", "rossiya"}},
+	{"SE", "Sweden", []string{"swe", "sverige", "suecia"}},
+	{"US", "United States", []string{"usa", "us", "u.s.", "u.s.a.", "united states of america", "america", "ee.uu.", "eeuu", "estados unidos", "etats-unis", "vereinigte staaten"}},
+	{"UY", "Uruguay", []string{"ury"}},
+	{"VE", "Venezuela", []string{"ven"}},
+}
+
+// countryIndex maps every normalized spelling to its country
+var countryIndex = buildCountryIndex()
+
+func buildCountryIndex() map[string]*country {
+	idx := make(map[string]*country)
+	for i := range countries {
+		c := &countries[i]
+		idx[countryKey(c.ISO)] = c
+		idx[countryKey(c.Name)] = c
+		for _, alias := range c.Aliases {
+			idx[countryKey(alias)] = c
+		}
+	}
+	return idx
+}
+
+// countryKey normalizes a country spelling for lookup.
+// Handles: case, accents, dots (EE.UU.), extra whitespace
+func countryKey(s string) string {
+	s = removeAccents(strings.ToLower(s))
+	s = strings.ReplaceAll(s, ".", "")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// NormalizeCountry returns the canonical form of a country name.
+// Unknown values are returned unchanged.
+func NormalizeCountry(name string, format CountryFormat) string {
+	c, ok := countryIndex[countryKey(name)]
+	if !ok {
+		return name
+	}
+	if format == CountryFormatISO {
+		return c.ISO
+	}
+	return c.Name
+}
+
+// NormalizeCountries rewrites the country of every address in canonical form
+func (c *Contact) NormalizeCountries(format CountryFormat) {
+	for i := range c.Addresses {
+		c.Addresses[i].Country = NormalizeCountry(c.Addresses[i].Country, format)
+	}
+}
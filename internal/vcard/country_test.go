@@ -0,0 +1,45 @@
+package vcard
+
+import (
+	"testing"
+)
+
+func TestNormalizeCountry(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		format   CountryFormat
+		expected string
+	}{
+		// Canonical names
+		{"USA", "USA", CountryFormatName, "United States"},
+		{"United States", "United States", CountryFormatName, "United States"},
+		{"EE.UU.", "EE.UU.", CountryFormatName, "United States"},
+		{"U.S.A.", "U.S.A.", CountryFormatName, "United States"},
+		{"Deutschland", "Deutschland", CountryFormatName, "Germany"},
+		{"España accented", "España", CountryFormatName, "Spain"},
+		{"lowercase", "spain", CountryFormatName, "Spain"},
+		{"extra whitespace", "  united   kingdom ", CountryFormatName, "United Kingdom"},
+		{"ISO input", "DE", CountryFormatName, "Germany"},
+		{"alpha-3 input", "GBR", CountryFormatName, "United Kingdom"},
+
+		// ISO codes
+		{"USA to ISO", "USA", CountryFormatISO, "US"},
+		{"Deutschland to ISO", "Deutschland", CountryFormatISO, "DE"},
+		{"UK to ISO", "UK", CountryFormatISO, "GB"},
+
+		// Unknown values are untouched
+		{"unknown", "Atlantis", CountryFormatName, "Atlantis"},
+		{"unknown ISO", "Atlantis", CountryFormatISO, "Atlantis"},
+		{"empty", "", CountryFormatISO, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NormalizeCountry(tt.input, tt.format)
+			if got != tt.expected {
+				t.Errorf("NormalizeCountry(%q, %q) = %q, want %q", tt.input, tt.format, got, tt.expected)
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package vcard
 
 import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
 	"strings"
 	"unicode"
 
@@ -11,18 +15,31 @@ import (
 
 // DedupIndex provides efficient contact deduplication
 type DedupIndex struct {
-	byPhone map[string][]*Contact
-	byEmail map[string][]*Contact
-	byName  map[string][]*Contact
+	store    IndexStore
+	opts     DedupOptions
+	phonetic bool    // Also index names by PhoneticNameKey, see EnablePhonetic
+	minScore float64 // See SetMinScore
 }
 
-// NewDedupIndex creates an index from a slice of contacts
+// Lookup tables of a DedupIndex
+const (
+	tablePhone    = "phone"
+	tableEmail    = "email"
+	tableName     = "name"
+	tablePhonetic = "phonetic"
+	tableUID      = "uid"
+)
+
+// NewDedupIndex creates an in-memory index from a slice of contacts,
+// matching them with the default options
 func NewDedupIndex(contacts []*Contact) *DedupIndex {
-	idx := &DedupIndex{
-		byPhone: make(map[string][]*Contact),
-		byEmail: make(map[string][]*Contact),
-		byName:  make(map[string][]*Contact),
-	}
+	return NewDedupIndexWith(NewMemoryIndexStore(), DedupOptions{}, contacts)
+}
+
+// NewDedupIndexWith creates an index keeping its lookup tables in store
+// and matching contacts as opts say
+func NewDedupIndexWith(store IndexStore, opts DedupOptions, contacts []*Contact) *DedupIndex {
+	idx := &DedupIndex{store: store, opts: opts}
 
 	for _, c := range contacts {
 		idx.Add(c)
@@ -33,57 +50,129 @@ func NewDedupIndex(contacts []*Contact) *DedupIndex {
 
 // Add indexes a contact for dedup lookups
 func (idx *DedupIndex) Add(c *Contact) {
+	// Index by UID, including the one exports derive from the object ID
+	for _, key := range c.uidKeys() {
+		idx.store.Add(tableUID, key, c)
+	}
+
 	// Index by all phone suffixes
 	for _, phone := range c.Phones {
-		key := NormalizePhoneForDedup(phone)
+		key := idx.opts.NormalizePhone(phone)
 		if key != "" {
-			idx.byPhone[key] = append(idx.byPhone[key], c)
+			idx.store.Add(tablePhone, key, c)
 		}
 	}
 
-	// Index by all normalized emails
+	// Index by all normalized emails, but role and disposable ones
 	for _, email := range c.Emails {
-		key := NormalizeEmailForDedup(email)
+		key := idx.opts.emailKey(email)
 		if key != "" {
-			idx.byEmail[key] = append(idx.byEmail[key], c)
+			idx.store.Add(tableEmail, key, c)
 		}
 	}
 
 	// Index by normalized name
 	key := NormalizeNameForDedup(c.DisplayName())
 	if key != "" {
-		idx.byName[key] = append(idx.byName[key], c)
+		idx.store.Add(tableName, key, c)
+		if idx.phonetic {
+			idx.addPhonetic(c)
+		}
+	}
+}
+
+// Update saves changes made to an indexed contact, such as a merge, so
+// later lookups return them. Its keys are not re-indexed.
+func (idx *DedupIndex) Update(c *Contact) {
+	idx.store.Update(c)
+}
+
+// Err returns the first error the store ran into, for stores that can
+// fail like DiskIndexStore. Lookups after one may miss duplicates.
+func (idx *DedupIndex) Err() error {
+	if s, ok := idx.store.(interface{ Err() error }); ok {
+		return s.Err()
+	}
+	return nil
+}
+
+// Options returns how the index matches contacts
+func (idx *DedupIndex) Options() DedupOptions {
+	return idx.opts
+}
+
+// SetMinScore makes FindMatches leave out matches scoring below score
+func (idx *DedupIndex) SetMinScore(score float64) {
+	idx.minScore = score
+}
+
+// EnablePhonetic makes names that sound alike ("Stephen"/"Steven") match
+// as a weak signal, like identical names do. Contacts already in the
+// index are included.
+func (idx *DedupIndex) EnablePhonetic() {
+	if idx.phonetic {
+		return
+	}
+	idx.phonetic = true
+	idx.store.Each(tableName, idx.addPhonetic)
+}
+
+func (idx *DedupIndex) addPhonetic(c *Contact) {
+	if key := PhoneticNameKey(c.DisplayName()); key != "" {
+		idx.store.Add(tablePhonetic, key, c)
 	}
 }
 
+// sameContact reports whether a and b are the same indexed contact.
+// Stores that don't keep contacts in memory return a new copy on every
+// lookup, told apart by the ID they assign.
+func sameContact(a, b *Contact) bool {
+	if a.indexID != 0 {
+		return a.indexID == b.indexID
+	}
+	return a == b
+}
+
 // FindDuplicates returns contacts that likely match the given contact
 func (idx *DedupIndex) FindDuplicates(c *Contact) []*Contact {
-	seen := make(map[*Contact]struct{})
 	var matches []*Contact
 
 	addMatch := func(candidate *Contact) {
-		if candidate == c {
+		if sameContact(candidate, c) {
 			return
 		}
-		if _, ok := seen[candidate]; ok {
-			return
+		for _, m := range matches {
+			if sameContact(m, candidate) {
+				return
+			}
 		}
-		seen[candidate] = struct{}{}
 		matches = append(matches, candidate)
 	}
 
+	// Strongest match: same UID, the card was exported from the object
+	for _, key := range c.uidKeys() {
+		for _, candidate := range idx.store.Get(tableUID, key) {
+			addMatch(candidate)
+		}
+	}
+
 	// Strong match: same phone (suffix match handles country codes)
 	for _, phone := range c.Phones {
-		key := NormalizePhoneForDedup(phone)
-		for _, candidate := range idx.byPhone[key] {
-			addMatch(candidate)
+		key := idx.opts.NormalizePhone(phone)
+		for _, candidate := range idx.store.Get(tablePhone, key) {
+			if slices.ContainsFunc(candidate.Phones, func(p string) bool { return idx.opts.SamePhone(phone, p) }) {
+				addMatch(candidate)
+			}
 		}
 	}
 
 	// Strong match: same email (after normalization)
 	for _, email := range c.Emails {
-		key := NormalizeEmailForDedup(email)
-		for _, candidate := range idx.byEmail[key] {
+		key := idx.opts.emailKey(email)
+		if key == "" {
+			continue
+		}
+		for _, candidate := range idx.store.Get(tableEmail, key) {
 			addMatch(candidate)
 		}
 	}
@@ -92,9 +181,13 @@ func (idx *DedupIndex) FindDuplicates(c *Contact) []*Contact {
 	nameKey := NormalizeNameForDedup(c.DisplayName())
 	// Skip name matching if name is empty or generic "unnamed contact"
 	if nameKey != "" && nameKey != "unnamed contact" {
-		for _, candidate := range idx.byName[nameKey] {
+		candidates := idx.store.Get(tableName, nameKey)
+		if idx.phonetic {
+			candidates = append(candidates[:len(candidates):len(candidates)], idx.store.Get(tablePhonetic, PhoneticNameKey(c.DisplayName()))...)
+		}
+		for _, candidate := range candidates {
 			// If there's any phone/email overlap, definitely a match
-			if hasAnyOverlap(c, candidate) {
+			if hasAnyOverlap(c, candidate, idx.opts) {
 				addMatch(candidate)
 				continue
 			}
@@ -117,9 +210,53 @@ func (idx *DedupIndex) IsDuplicate(c *Contact) bool {
 	return len(idx.FindDuplicates(c)) > 0
 }
 
-// NormalizePhoneForDedup aggressively normalizes phone for comparison.
-// Uses last 9 digits to handle country code variations (+1, +34, etc.)
+// DefaultPhoneSuffixLength is the number of trailing digits phones are
+// matched by unless DedupOptions set another
+const DefaultPhoneSuffixLength = 9
+
+// PhoneMatching selects how phone numbers are compared for dedup
+type PhoneMatching string
+
+const (
+	PhoneMatchSuffix PhoneMatching = "suffix" // The last PhoneSuffixLength digits
+	PhoneMatchStrict PhoneMatching = "strict" // The full E.164 form
+)
+
+// DedupOptions configure how contacts are matched as duplicates. The zero
+// value compares phones by their last DefaultPhoneSuffixLength digits.
+type DedupOptions struct {
+	PhoneMatch PhoneMatching // How phones are compared, PhoneMatchSuffix when empty
+	// PhoneSuffixLength is the number of trailing digits PhoneMatchSuffix
+	// compares, DefaultPhoneSuffixLength when zero. Lower it for regions
+	// with short national numbers, which a longer suffix would include
+	// the country code of.
+	PhoneSuffixLength int
+	PhoneRegion       string     // Resolves national numbers with PhoneMatchStrict
+	Email             EmailRules // How emails are compared
+}
+
+// NormalizePhoneForDedup is NormalizePhone with the default options
 func NormalizePhoneForDedup(phone string) string {
+	return DedupOptions{}.NormalizePhone(phone)
+}
+
+// NormalizePhone aggressively normalizes phone for comparison.
+// Uses the last PhoneSuffixLength digits to handle country code variations
+// (+1, +34, etc.). With PhoneMatchStrict it is the E.164 form instead, or
+// all the digits of numbers that can't be formatted, so numbers only match
+// when nothing but their formatting differs.
+func (o DedupOptions) NormalizePhone(phone string) string {
+	strict := o.PhoneMatch == PhoneMatchStrict
+	if strict {
+		if e164, err := FormatPhoneE164(phone, o.PhoneRegion); err == nil {
+			return e164
+		}
+	}
+	suffix := o.PhoneSuffixLength
+	if suffix == 0 {
+		suffix = DefaultPhoneSuffixLength
+	}
+
 	// Extract only digits
 	var digits strings.Builder
 	for _, r := range phone {
@@ -130,12 +267,11 @@ func NormalizePhoneForDedup(phone string) string {
 
 	d := digits.String()
 
-	// Use last 9 digits as canonical form
+	// Use the last digits as canonical form
 	// This handles: +1-555-123-4567, 555-123-4567, 5551234567
 	// All normalize to: 551234567
-	const suffixLen = 9
-	if len(d) >= suffixLen {
-		return d[len(d)-suffixLen:]
+	if len(d) >= suffix && !strict {
+		return d[len(d)-suffix:]
 	}
 
 	// Short numbers kept as-is (local/extension numbers)
@@ -146,9 +282,33 @@ func NormalizePhoneForDedup(phone string) string {
 	return ""
 }
 
-// NormalizeEmailForDedup normalizes email for comparison.
-// Handles: case, plus-addressing (user+tag@), googlemail vs gmail
+// SamePhone is DedupOptions.SamePhone with the default options
+func SamePhone(a, b string) bool {
+	return DedupOptions{}.SamePhone(a, b)
+}
+
+// SamePhone reports whether two numbers are the same for dedup: their
+// NormalizePhone keys match and, when both are written with a country
+// code, the codes do too. This keeps short national numbers of different
+// countries that end in the same digits apart.
+func (o DedupOptions) SamePhone(a, b string) bool {
+	key := o.NormalizePhone(a)
+	if key == "" || key != o.NormalizePhone(b) {
+		return false
+	}
+	codeA, codeB := CallingCode(a), CallingCode(b)
+	return codeA == "" || codeB == "" || codeA == codeB
+}
+
+// NormalizeEmailForDedup is EmailRules.Normalize with the built-in rules
 func NormalizeEmailForDedup(email string) string {
+	return EmailRules{}.Normalize(email)
+}
+
+// Normalize normalizes email for comparison.
+// Handles: case, plus-addressing (user+tag@), domain aliases (googlemail
+// vs gmail), and the per-domain rules of r (Gmail ignores dots by default)
+func (r EmailRules) Normalize(email string) string {
 	email = strings.ToLower(strings.TrimSpace(email))
 
 	parts := strings.SplitN(email, "@", 2)
@@ -158,24 +318,25 @@ func NormalizeEmailForDedup(email string) string {
 
 	local, domain := parts[0], parts[1]
 
-	// Strip plus-addressing (user+anything@domain → user@domain)
-	if idx := strings.Index(local, "+"); idx != -1 {
-		local = local[:idx]
-	}
+	// Equivalent domains: gmail variants, renamed companies
+	domain = r.canonicalDomain(domain)
 
-	// Normalize gmail variants
-	if domain == "googlemail.com" {
-		domain = "gmail.com"
-	}
-
-	// Gmail ignores dots in local part
-	if domain == "gmail.com" {
-		local = strings.ReplaceAll(local, ".", "")
-	}
+	// Strip tags (user+anything@domain → user@domain) and dots as the
+	// domain's rule says
+	local = r.rule(domain).normalizeLocal(local)
 
 	return local + "@" + domain
 }
 
+// emailKey is the normalized key duplicates are matched by, "" for role
+// and disposable addresses, which identify nobody
+func (o DedupOptions) emailKey(email string) string {
+	if o.Email.weak(email) {
+		return ""
+	}
+	return o.Email.Normalize(email)
+}
+
 // NormalizeNameForDedup normalizes name for comparison.
 // Handles: case, accents, extra whitespace, common prefixes
 func NormalizeNameForDedup(name string) string {
@@ -209,127 +370,11 @@ func removeAccents(s string) string {
 	return result
 }
 
-// MergeContacts merges missing fields from src into dst.
-// Prefers existing values in dst (only fills in missing data).
-// Returns true if any fields were merged.
-func MergeContacts(dst, src *Contact) bool {
-	merged := false
-
-	// Merge name fields (only if dst is missing them)
-	if dst.FormattedName == "" && src.FormattedName != "" {
-		dst.FormattedName = src.FormattedName
-		merged = true
-	}
-	if dst.GivenName == "" && src.GivenName != "" {
-		dst.GivenName = src.GivenName
-		merged = true
-	}
-	if dst.FamilyName == "" && src.FamilyName != "" {
-		dst.FamilyName = src.FamilyName
-		merged = true
-	}
-	if dst.MiddleName == "" && src.MiddleName != "" {
-		dst.MiddleName = src.MiddleName
-		merged = true
-	}
-	if dst.Prefix == "" && src.Prefix != "" {
-		dst.Prefix = src.Prefix
-		merged = true
-	}
-	if dst.Suffix == "" && src.Suffix != "" {
-		dst.Suffix = src.Suffix
-		merged = true
-	}
-
-	// Merge unique emails
-	existingEmails := make(map[string]struct{})
-	for _, e := range dst.Emails {
-		existingEmails[NormalizeEmailForDedup(e)] = struct{}{}
-	}
-	for _, e := range src.Emails {
-		key := NormalizeEmailForDedup(e)
-		if _, exists := existingEmails[key]; !exists && key != "" {
-			dst.Emails = append(dst.Emails, e)
-			existingEmails[key] = struct{}{}
-			merged = true
-		}
-	}
-
-	// Merge unique phones
-	existingPhones := make(map[string]struct{})
-	for _, p := range dst.Phones {
-		existingPhones[NormalizePhoneForDedup(p)] = struct{}{}
-	}
-	for _, p := range src.Phones {
-		key := NormalizePhoneForDedup(p)
-		if _, exists := existingPhones[key]; !exists && key != "" {
-			dst.Phones = append(dst.Phones, p)
-			existingPhones[key] = struct{}{}
-			merged = true
-		}
-	}
-
-	// Merge unique addresses
-	existingAddrs := make(map[string]struct{})
-	for _, a := range dst.Addresses {
-		existingAddrs[normalizeAddress(a)] = struct{}{}
-	}
-	for _, a := range src.Addresses {
-		key := normalizeAddress(a)
-		if _, exists := existingAddrs[key]; !exists && key != "" {
-			dst.Addresses = append(dst.Addresses, a)
-			existingAddrs[key] = struct{}{}
-			merged = true
-		}
-	}
-
-	// Merge organization and title
-	if dst.Organization == "" && src.Organization != "" {
-		dst.Organization = src.Organization
-		merged = true
-	}
-	if dst.Title == "" && src.Title != "" {
-		dst.Title = src.Title
-		merged = true
-	}
-
-	// Merge unique URLs
-	existingURLs := make(map[string]struct{})
-	for _, u := range dst.URLs {
-		existingURLs[strings.ToLower(u)] = struct{}{}
-	}
-	for _, u := range src.URLs {
-		key := strings.ToLower(u)
-		if _, exists := existingURLs[key]; !exists && key != "" {
-			dst.URLs = append(dst.URLs, u)
-			existingURLs[key] = struct{}{}
-			merged = true
-		}
-	}
-
-	// Merge notes (append if different)
-	if src.Note != "" && dst.Note != src.Note {
-		if dst.Note == "" {
-			dst.Note = src.Note
-		} else {
-			dst.Note = dst.Note + "\n\n---\n\n" + src.Note
-		}
-		merged = true
-	}
-
-	// Merge birthday
-	if dst.Birthday == "" && src.Birthday != "" {
-		dst.Birthday = src.Birthday
-		merged = true
-	}
-
-	// Merge photo
-	if dst.Photo == "" && src.Photo != "" {
-		dst.Photo = src.Photo
-		merged = true
-	}
-
-	return merged
+// companyName returns the company part of an organization, ignoring the
+// departments of values stored before they were split off
+func companyName(org string) string {
+	company, _ := SplitOrganization(org)
+	return company
 }
 
 // normalizeAddress creates a key for address deduplication
@@ -345,14 +390,10 @@ func normalizeAddress(a Address) string {
 }
 
 // hasAnyOverlap checks if two contacts share any phone or email
-func hasAnyOverlap(a, b *Contact) bool {
+func hasAnyOverlap(a, b *Contact, opts DedupOptions) bool {
 	// Check phone overlap
-	aPhones := make(map[string]struct{})
-	for _, p := range a.Phones {
-		aPhones[NormalizePhoneForDedup(p)] = struct{}{}
-	}
 	for _, p := range b.Phones {
-		if _, ok := aPhones[NormalizePhoneForDedup(p)]; ok {
+		if slices.ContainsFunc(a.Phones, func(q string) bool { return opts.SamePhone(q, p) }) {
 			return true
 		}
 	}
@@ -360,10 +401,12 @@ func hasAnyOverlap(a, b *Contact) bool {
 	// Check email overlap
 	aEmails := make(map[string]struct{})
 	for _, e := range a.Emails {
-		aEmails[NormalizeEmailForDedup(e)] = struct{}{}
+		if key := opts.emailKey(e); key != "" {
+			aEmails[key] = struct{}{}
+		}
 	}
 	for _, e := range b.Emails {
-		if _, ok := aEmails[NormalizeEmailForDedup(e)]; ok {
+		if _, ok := aEmails[opts.emailKey(e)]; ok {
 			return true
 		}
 	}
@@ -401,16 +444,29 @@ const (
 	MatchStrong               // Phone or email match
 )
 
+// String returns the lowercase name of the strength
+func (m MatchStrength) String() string {
+	switch m {
+	case MatchWeak:
+		return "weak"
+	case MatchMedium:
+		return "medium"
+	case MatchStrong:
+		return "strong"
+	}
+	return "none"
+}
+
 // CompareContacts returns the match strength between two contacts
-func CompareContacts(a, b *Contact) MatchStrength {
+func CompareContacts(a, b *Contact, opts DedupOptions) MatchStrength {
+	if sharedUID(a, b) != "" {
+		return MatchStrong
+	}
+
 	// Check for phone match (strongest signal)
 	for _, pa := range a.Phones {
-		keyA := NormalizePhoneForDedup(pa)
-		if keyA == "" {
-			continue
-		}
 		for _, pb := range b.Phones {
-			if keyA == NormalizePhoneForDedup(pb) {
+			if opts.SamePhone(pa, pb) {
 				return MatchStrong
 			}
 		}
@@ -418,12 +474,12 @@ func CompareContacts(a, b *Contact) MatchStrength {
 
 	// Check for email match (strong signal)
 	for _, ea := range a.Emails {
-		keyA := NormalizeEmailForDedup(ea)
+		keyA := opts.emailKey(ea)
 		if keyA == "" {
 			continue
 		}
 		for _, eb := range b.Emails {
-			if keyA == NormalizeEmailForDedup(eb) {
+			if keyA == opts.emailKey(eb) {
 				return MatchStrong
 			}
 		}
@@ -440,7 +496,7 @@ func CompareContacts(a, b *Contact) MatchStrength {
 
 	if nameA != "" && nameA == nameB {
 		// Same name - check for any supporting evidence
-		if a.Organization != "" && a.Organization == b.Organization {
+		if org := companyName(a.Organization); org != "" && org == companyName(b.Organization) {
 			return MatchMedium
 		}
 		if a.Birthday != "" && a.Birthday == b.Birthday {
@@ -451,3 +507,133 @@ func CompareContacts(a, b *Contact) MatchStrength {
 
 	return MatchNone
 }
+
+// Weights of the signals combined by ScoreContacts. Each is the confidence
+// that the signal alone identifies the same person.
+const (
+	UIDWeight          = 0.99 // The same card, e.g. exported and imported back
+	PhoneWeight        = 0.9
+	EmailWeight        = 0.9
+	NameWeight         = 0.5
+	OrganizationWeight = 0.2 // Only counted when the names match
+	BirthdayWeight     = 0.3 // Only counted when the names match
+	PhoneticWeight     = 0.3 // Names that only sound alike, see DedupIndex.EnablePhonetic
+)
+
+// Match is a duplicate candidate with a confidence score
+type Match struct {
+	Contact  *Contact
+	Score    float64 // Confidence from 0 to 1 that both are the same person
+	Strength MatchStrength
+	Reasons  []string // What they share, e.g. "phone 612345678", "email jane@example.com", "name jane doe"
+}
+
+// ScoreContacts compares a with the candidate b as opts say. Every shared
+// phone, email, name, organization and birthday is independent evidence,
+// so the score is 1 - (1-w1)(1-w2)... over the weights of the shared signals.
+func ScoreContacts(a, b *Contact, opts DedupOptions) Match {
+	m := Match{Contact: b, Strength: CompareContacts(a, b, opts)}
+	miss := 1.0
+	add := func(weight float64, reason string) {
+		miss *= 1 - weight
+		m.Reasons = append(m.Reasons, reason)
+	}
+
+	if uid := sharedUID(a, b); uid != "" {
+		add(UIDWeight, "uid "+uid)
+	}
+	for _, v := range sharedValues(a.Phones, b.Phones, opts.NormalizePhone, opts.SamePhone) {
+		add(PhoneWeight, "phone "+v)
+	}
+	for _, v := range sharedValues(a.Emails, b.Emails, opts.emailKey, nil) {
+		add(EmailWeight, "email "+v)
+	}
+	name := NormalizeNameForDedup(a.DisplayName())
+	if name != "" && name != "unnamed contact" && name == NormalizeNameForDedup(b.DisplayName()) {
+		add(NameWeight, "name "+name)
+		if org := companyName(a.Organization); org != "" && strings.EqualFold(org, companyName(b.Organization)) {
+			add(OrganizationWeight, "organization "+org)
+		}
+		if a.Birthday != "" && a.Birthday == b.Birthday {
+			add(BirthdayWeight, "birthday "+a.Birthday)
+		}
+	}
+
+	m.Score = math.Round((1-miss)*100) / 100
+	return m
+}
+
+// FindMatches returns the candidates of FindDuplicates scored against c,
+// best first, leaving out those below SetMinScore. With EnablePhonetic,
+// names that only sound alike add PhoneticWeight.
+func (idx *DedupIndex) FindMatches(c *Contact) []Match {
+	duplicates := idx.FindDuplicates(c)
+	matches := make([]Match, 0, len(duplicates))
+	for _, d := range duplicates {
+		m := ScoreContacts(c, d, idx.opts)
+		if idx.phonetic {
+			addPhoneticMatch(&m, c, d)
+		}
+		if m.Score >= idx.minScore {
+			matches = append(matches, m)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	return matches
+}
+
+// addPhoneticMatch adds the phonetic signal to m when the names of a and b
+// differ but sound alike
+func addPhoneticMatch(m *Match, a, b *Contact) {
+	key := PhoneticNameKey(a.DisplayName())
+	if key == "" || key != PhoneticNameKey(b.DisplayName()) ||
+		NormalizeNameForDedup(a.DisplayName()) == NormalizeNameForDedup(b.DisplayName()) {
+		return
+	}
+	m.Score = math.Round((1-(1-m.Score)*(1-PhoneticWeight))*100) / 100
+	m.Reasons = append(m.Reasons, fmt.Sprintf("name sounding like %s (%s)", NormalizeNameForDedup(b.DisplayName()), key))
+	if m.Strength == MatchNone {
+		m.Strength = MatchWeak
+	}
+}
+
+// sharedValues returns the values of b whose normalized key is also in a,
+// followed by the key when it differs: "+34 612 345 678 (normalized 612345678)".
+// When same is not nil, a value of a with the key must also be the same as
+// the value of b.
+func sharedValues(a, b []string, normalize func(string) string, same func(a, b string) bool) []string {
+	inA := make(map[string][]string, len(a))
+	for _, v := range a {
+		if key := normalize(v); key != "" {
+			inA[key] = append(inA[key], v)
+		}
+	}
+	var shared []string
+	for _, v := range b {
+		key := normalize(v)
+		values, ok := inA[key]
+		if !ok || (same != nil && !slices.ContainsFunc(values, func(w string) bool { return same(w, v) })) {
+			continue
+		}
+		delete(inA, key)
+		if v = strings.TrimSpace(v); v != key {
+			v += " (normalized " + key + ")"
+		}
+		shared = append(shared, v)
+	}
+	return shared
+}
+
+// Explain describes the match for logs and reports:
+// matched object ID (Name) via phone +34 612 345 678 (normalized 612345678), score 0.90
+func (m Match) Explain() string {
+	target := m.Contact.DisplayName()
+	if m.Contact.ObjectID != "" {
+		target = fmt.Sprintf("object %s (%s)", m.Contact.ObjectID, target)
+	}
+	reasons := strings.Join(m.Reasons, ", ")
+	if reasons == "" {
+		reasons = "similar details"
+	}
+	return fmt.Sprintf("matched %s via %s, score %.2f", target, reasons, m.Score)
+}
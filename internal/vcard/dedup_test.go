@@ -1,6 +1,7 @@
 package vcard
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -787,7 +788,7 @@ func TestCompareContacts(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := CompareContacts(tt.a, tt.b)
+			got := CompareContacts(tt.a, tt.b, DedupOptions{})
 			if got != tt.expected {
 				t.Errorf("CompareContacts() = %v, want %v", got, tt.expected)
 			}
@@ -795,6 +796,77 @@ func TestCompareContacts(t *testing.T) {
 	}
 }
 
+func TestScoreContacts(t *testing.T) {
+	tests := []struct {
+		name        string
+		a, b        *Contact
+		wantScore   float64
+		wantReasons []string
+	}{
+		{"phone", &Contact{Phones: []string{"612345678"}}, &Contact{Phones: []string{"+34 612 345 678"}}, 0.9, []string{"phone +34 612 345 678 (normalized 612345678)"}},
+		{"email", &Contact{Emails: []string{"John@Example.com"}}, &Contact{Emails: []string{"john@example.com"}}, 0.9, []string{"email john@example.com"}},
+		{"phone and email", &Contact{Phones: []string{"612345678"}, Emails: []string{"a@b.com"}}, &Contact{Phones: []string{"612345678"}, Emails: []string{"a@b.com"}}, 0.99, []string{"phone 612345678", "email a@b.com"}},
+		{"name", &Contact{FormattedName: "José Pérez"}, &Contact{FormattedName: "jose perez"}, 0.5, []string{"name jose perez"}},
+		{"name and org", &Contact{FormattedName: "John Doe", Organization: "Acme"}, &Contact{FormattedName: "John Doe", Organization: "ACME"}, 0.6, []string{"name john doe", "organization Acme"}},
+		{"org without name", &Contact{FormattedName: "John Doe", Organization: "Acme"}, &Contact{FormattedName: "Jane Roe", Organization: "Acme"}, 0, nil},
+		{"unnamed", &Contact{}, &Contact{}, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := ScoreContacts(tt.a, tt.b, DedupOptions{})
+			if m.Score != tt.wantScore {
+				t.Errorf("Score = %v, want %v", m.Score, tt.wantScore)
+			}
+			if strings.Join(m.Reasons, "|") != strings.Join(tt.wantReasons, "|") {
+				t.Errorf("Reasons = %q, want %q", m.Reasons, tt.wantReasons)
+			}
+			if m.Strength != CompareContacts(tt.a, tt.b, DedupOptions{}) || m.Contact != tt.b {
+				t.Errorf("Strength/Contact = %v/%p", m.Strength, m.Contact)
+			}
+		})
+	}
+
+	if MatchStrong.String() != "strong" || MatchNone.String() != "none" {
+		t.Errorf("MatchStrength.String() = %q/%q", MatchStrong, MatchNone)
+	}
+}
+
+func TestMatch_Explain(t *testing.T) {
+	existing := &Contact{ObjectID: "bafy1", FormattedName: "Jane Doe", Phones: []string{"+34 612 345 678"}}
+	got := ScoreContacts(&Contact{Phones: []string{"612345678"}}, existing, DedupOptions{}).Explain()
+	want := "matched object bafy1 (Jane Doe) via phone +34 612 345 678 (normalized 612345678), score 0.90"
+	if got != want {
+		t.Errorf("Explain() = %q, want %q", got, want)
+	}
+
+	got = Match{Contact: &Contact{FormattedName: "Jane Doe"}}.Explain()
+	if got != "matched Jane Doe via similar details, score 0.00" {
+		t.Errorf("Explain() without reasons = %q", got)
+	}
+}
+
+func TestDedupIndex_FindMatches(t *testing.T) {
+	byName := &Contact{FormattedName: "Jane Doe"}
+	byEmail := &Contact{FormattedName: "J. Doe", Emails: []string{"jane@example.com"}}
+	idx := NewDedupIndex([]*Contact{byName, byEmail})
+
+	matches := idx.FindMatches(&Contact{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}})
+	if len(matches) != 2 || matches[0].Contact != byEmail || matches[1].Contact != byName {
+		t.Fatalf("FindMatches() = %+v, want the email match first", matches)
+	}
+	if matches[0].Score <= matches[1].Score {
+		t.Errorf("scores %v, %v not sorted", matches[0].Score, matches[1].Score)
+	}
+
+	// The name alone scores 0.5
+	idx.SetMinScore(0.6)
+	matches = idx.FindMatches(&Contact{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}})
+	if len(matches) != 1 || matches[0].Contact != byEmail {
+		t.Errorf("FindMatches() with SetMinScore = %+v, want only the email match", matches)
+	}
+}
+
 func TestCompareContacts_DetailedStrength(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -878,7 +950,7 @@ func TestCompareContacts_DetailedStrength(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := CompareContacts(tt.a, tt.b)
+			got := CompareContacts(tt.a, tt.b, DedupOptions{})
 			if got != tt.expected {
 				t.Errorf("CompareContacts() = %v, want %v", got, tt.expected)
 			}
@@ -0,0 +1,45 @@
+package vcard
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DescriptionKey is the built-in Anytype property holding the object
+// description
+const DescriptionKey = "description"
+
+// DescriptionPresets are the named object description formats
+var DescriptionPresets = map[string]string{
+	"auto": "{{.Title}}{{if and .Title .Organization}} @ {{end}}{{.Organization}}",
+}
+
+// ParseDescriptionFormat parses an object description format: a preset name
+// from DescriptionPresets or a Go template executed with the Contact, with
+// the functions of notes templates
+func ParseDescriptionFormat(format string) (*template.Template, error) {
+	if preset, ok := DescriptionPresets[format]; ok {
+		format = preset
+	}
+	tmpl, err := template.New("description").Funcs(notesFuncs).Option("missingkey=error").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid description format: %w", err)
+	}
+	return tmpl, nil
+}
+
+// FormatDescription renders the one-line summary of the contact shown in
+// Anytype galleries, e.g. "CTO @ Acme". Whitespace is collapsed, and the
+// summary is empty when it would only repeat the contact name.
+func FormatDescription(tmpl *template.Template, c Contact) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, c); err != nil {
+		return "", fmt.Errorf("failed to render description of %s: %w", c.DisplayName(), err)
+	}
+	description := strings.Join(strings.Fields(b.String()), " ")
+	if strings.EqualFold(description, c.DisplayName()) {
+		return "", nil
+	}
+	return description, nil
+}
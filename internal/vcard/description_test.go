@@ -0,0 +1,35 @@
+package vcard
+
+import "testing"
+
+func TestFormatDescription(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		contact Contact
+		want    string
+	}{
+		{"title and organization", "auto", Contact{GivenName: "Jane", Title: "CTO", Organization: "Acme"}, "CTO @ Acme"},
+		{"organization only", "auto", Contact{GivenName: "Jane", Organization: "Acme"}, "Acme"},
+		{"title only", "auto", Contact{GivenName: "Jane", Title: "CTO"}, "CTO"},
+		{"nothing known", "auto", Contact{GivenName: "Jane"}, ""},
+		{"repeats the name", "auto", Contact{Organization: "Acme", Kind: KindOrganization}, ""},
+		{"custom template", "{{join .Categories \", \"}} {{.Organization}}", Contact{GivenName: "Jane", Organization: "Acme", Categories: []string{"work", "vip"}}, "work, vip Acme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseDescriptionFormat(tt.format)
+			if err != nil {
+				t.Fatalf("ParseDescriptionFormat() error = %v", err)
+			}
+			got, err := FormatDescription(tmpl, tt.contact)
+			if err != nil {
+				t.Fatalf("FormatDescription() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatDescription() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package vcard
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	govcard "github.com/emersion/go-vcard"
+)
+
+// Unmapped is a vCard property value the parser has no contact field for,
+// kept so imports can report what they leave out
+type Unmapped struct {
+	Field string `json:"field"` // Property name, e.g. IMPP or X-SKYPE
+	Value string `json:"value"`
+}
+
+// DroppedValue is a value of a contact an import leaves out
+type DroppedValue struct {
+	Field string
+	Value string
+}
+
+// singleFields are the properties parseCard reads a single value of, the
+// preferred one
+var singleFields = []string{
+	govcard.FieldFormattedName, govcard.FieldName, govcard.FieldTitle, govcard.FieldRole,
+	govcard.FieldNickname, govcard.FieldNote, govcard.FieldBirthday, govcard.FieldPhoto,
+	govcard.FieldOrganization, govcard.FieldAddress,
+}
+
+// mappedFields are the other properties parseCard reads, or that only
+// describe the card itself
+var mappedFields = slices.Concat([]string{
+	govcard.FieldVersion, govcard.FieldProductID, govcard.FieldClientPIDMap,
+	govcard.FieldUID, govcard.FieldRevision, govcard.FieldKind,
+	govcard.FieldEmail, govcard.FieldTelephone, govcard.FieldURL, govcard.FieldCategories,
+	govcard.FieldRelated, "X-ABSHOWAS", "X-ABRELATEDNAMES", "X-ABLABEL",
+}, assistantFields, managerFields, favoriteFields)
+
+// unmappedFields returns the values of card that parseCard leaves out: the
+// properties it doesn't read, and the values beyond the preferred one of
+// those it reads one of
+func unmappedFields(card govcard.Card) []Unmapped {
+	var unmapped []Unmapped
+	for _, name := range slices.Sorted(maps.Keys(card)) {
+		switch {
+		case slices.Contains(mappedFields, name):
+		case slices.Contains(singleFields, name):
+			preferred := card.Preferred(name)
+			for _, f := range card[name] {
+				if f != preferred {
+					unmapped = append(unmapped, Unmapped{Field: name, Value: fieldValue(f)})
+				}
+			}
+		default:
+			for _, f := range card[name] {
+				unmapped = append(unmapped, Unmapped{Field: name, Value: fieldValue(f)})
+			}
+		}
+	}
+	return unmapped
+}
+
+// fieldValue returns the value of a property, structured values such as
+// ADR and N with their empty components left out
+func fieldValue(f *govcard.Field) string {
+	parts := strings.Split(f.Value, ";")
+	return strings.Join(filterEmpty(parts...), ", ")
+}
+
+// DroppedFields describes the values of c that an Anytype object can't
+// hold: BuildProperties writes phoneSlots phones and the first address,
+// and photos are not uploaded. Extra emails and URLs go to the notes, so
+// they are not dropped. vCard properties with no contact field are listed
+// by name.
+func DroppedFields(c Contact, phoneSlots int) []string {
+	var dropped []string
+	for _, v := range DroppedValues(c, phoneSlots) {
+		if !slices.Contains(dropped, v.Field) {
+			dropped = append(dropped, v.Field)
+		}
+	}
+	return dropped
+}
+
+// DroppedValues returns every value of c that an Anytype object can't
+// hold, see DroppedFields
+func DroppedValues(c Contact, phoneSlots int) []DroppedValue {
+	var dropped []DroppedValue
+	if c.Photo != "" {
+		photo := c.Photo
+		if !IsPhotoURL(photo) {
+			photo = "embedded image"
+		}
+		dropped = append(dropped, DroppedValue{"photo", photo})
+	}
+	if len(c.Phones) > phoneSlots {
+		field := fmt.Sprintf("phones beyond the first %d", phoneSlots)
+		for _, phone := range c.Phones[phoneSlots:] {
+			dropped = append(dropped, DroppedValue{field, phone})
+		}
+	}
+	if len(c.Addresses) > 1 {
+		for _, a := range c.Addresses[1:] {
+			value := strings.Join(filterEmpty(a.Street, a.City, a.Region, a.PostalCode, a.Country), ", ")
+			dropped = append(dropped, DroppedValue{"addresses beyond the first", value})
+		}
+	}
+	for _, u := range c.Unmapped {
+		dropped = append(dropped, DroppedValue{u.Field, u.Value})
+	}
+	return dropped
+}
@@ -0,0 +1,49 @@
+package vcard
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDroppedFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		contact Contact
+		want    []string
+	}{
+		{"fits", Contact{Phones: []string{"1", "2", "3"}, Emails: []string{"a", "b", "c", "d"}, Addresses: []Address{{City: "Madrid"}}}, nil},
+		{"photo", Contact{Photo: "https://example.com/jane.jpg"}, []string{"photo"}},
+		{"extra phones", Contact{Phones: []string{"1", "2", "3", "4"}}, []string{"phones beyond the first 3"}},
+		{"extra addresses", Contact{Addresses: []Address{{City: "Madrid"}, {City: "Lisbon"}}}, []string{"addresses beyond the first"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DroppedFields(tt.contact, 3); !slices.Equal(got, tt.want) {
+				t.Errorf("DroppedFields() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDroppedValuesUnmapped(t *testing.T) {
+	card := "BEGIN:VCARD\r\nVERSION:3.0\r\nPRODID:-//Test//EN\r\nFN:Jane Doe\r\n" +
+		"ADR;TYPE=home;TYPE=pref:;;1 Main St;Springfield;;;USA\r\nADR;TYPE=work:;;2 Side St;Shelbyville;;;USA\r\n" +
+		"IMPP:xmpp:jane@example.com\r\nX-SKYPE:jane.doe\r\nCATEGORIES:Friends\r\nX-FAVORITE:true\r\nEND:VCARD\r\n"
+	contacts, err := Parse(strings.NewReader(card))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []DroppedValue{
+		{"ADR", "2 Side St, Shelbyville, USA"},
+		{"IMPP", "xmpp:jane@example.com"},
+		{"X-SKYPE", "jane.doe"},
+	}
+	if got := DroppedValues(contacts[0], 3); !slices.Equal(got, want) {
+		t.Errorf("DroppedValues() = %q, want %q", got, want)
+	}
+	if got, want := DroppedFields(contacts[0], 3), []string{"ADR", "IMPP", "X-SKYPE"}; !slices.Equal(got, want) {
+		t.Errorf("DroppedFields() = %q, want %q", got, want)
+	}
+}
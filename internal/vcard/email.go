@@ -0,0 +1,118 @@
+package vcard
+
+import (
+	"strings"
+	"unicode"
+)
+
+// emailDomainTypos maps common misspellings to the intended provider domain
+var emailDomainTypos = map[string]string{
+	"gamil.com":   "gmail.com",
+	"gmial.com":   "gmail.com",
+	"gmai.com":    "gmail.com",
+	"gmal.com":    "gmail.com",
+	"gnail.com":   "gmail.com",
+	"gmaill.com":  "gmail.com",
+	"gmail.co":    "gmail.com",
+	"gmail.cm":    "gmail.com",
+	"gmail.om":    "gmail.com",
+	"hotmial.com": "hotmail.com",
+	"hotmal.com":  "hotmail.com",
+	"hotmai.com":  "hotmail.com",
+	"homail.com":  "hotmail.com",
+	"hotmail.co":  "hotmail.com",
+	"hotmail.cm":  "hotmail.com",
+	"yaho.com":    "yahoo.com",
+	"yahooo.com":  "yahoo.com",
+	"yhoo.com":    "yahoo.com",
+	"yahoo.co":    "yahoo.com",
+	"outlok.com":  "outlook.com",
+	"outloo.com":  "outlook.com",
+	"outlook.co":  "outlook.com",
+	"iclod.com":   "icloud.com",
+	"icoud.com":   "icloud.com",
+	"icloud.co":   "icloud.com",
+}
+
+// emailProvidersWithoutTLD maps bare provider names to their full domain
+var emailProvidersWithoutTLD = map[string]string{
+	"gmail":   "gmail.com",
+	"hotmail": "hotmail.com",
+	"yahoo":   "yahoo.com",
+	"outlook": "outlook.com",
+	"icloud":  "icloud.com",
+}
+
+// EmailIssue describes a problem found in an email address
+type EmailIssue struct {
+	Original string
+	Fixed    string // Empty when there is no safe automatic correction
+	Reason   string
+}
+
+// CheckEmail validates an email address and detects common typos.
+// Returns nil if the address looks fine.
+func CheckEmail(email string) *EmailIssue {
+	fixed := strings.TrimSpace(email)
+	var reasons []string
+
+	if strings.IndexFunc(fixed, unicode.IsSpace) != -1 {
+		fixed = strings.Join(strings.Fields(fixed), "")
+		reasons = append(reasons, "contains spaces")
+	}
+
+	if strings.Count(fixed, "@") != 1 {
+		return &EmailIssue{Original: email, Reason: "not a valid address"}
+	}
+
+	local, domain, _ := strings.Cut(fixed, "@")
+	if local == "" || domain == "" {
+		return &EmailIssue{Original: email, Reason: "not a valid address"}
+	}
+
+	lower := strings.TrimSuffix(strings.ToLower(strings.ReplaceAll(domain, ",", ".")), ".")
+	if lower != strings.ToLower(domain) {
+		domain = lower
+		reasons = append(reasons, "malformed domain")
+	}
+
+	if correct, ok := emailDomainTypos[lower]; ok {
+		domain = correct
+		reasons = append(reasons, "domain typo")
+	} else if !strings.Contains(lower, ".") {
+		correct, ok := emailProvidersWithoutTLD[lower]
+		if !ok {
+			return &EmailIssue{Original: email, Reason: "missing top-level domain"}
+		}
+		domain = correct
+		reasons = append(reasons, "missing top-level domain")
+	}
+
+	if len(reasons) == 0 {
+		return nil
+	}
+	return &EmailIssue{
+		Original: email,
+		Fixed:    local + "@" + domain,
+		Reason:   strings.Join(reasons, ", "),
+	}
+}
+
+// CheckEmails validates every email of the contact. Surrounding whitespace
+// is always trimmed; when fix is true, addresses with a known correction
+// are rewritten in place too.
+func (c *Contact) CheckEmails(fix bool) []EmailIssue {
+	var issues []EmailIssue
+	for i, email := range c.Emails {
+		c.Emails[i] = strings.TrimSpace(email)
+		issue := CheckEmail(email)
+		if issue == nil {
+			continue
+		}
+		if fix && issue.Fixed != "" {
+			c.Emails[i] = issue.Fixed
+		}
+		issues = append(issues, *issue)
+	}
+	return issues
+}
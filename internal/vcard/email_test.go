@@ -0,0 +1,82 @@
+package vcard
+
+import (
+	"testing"
+)
+
+func TestCheckEmail(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantIssue bool
+		wantFixed string
+	}{
+		// Valid addresses
+		{"valid", "john@example.com", false, ""},
+		{"valid gmail", "john@gmail.com", false, ""},
+		{"uppercase domain", "john@Example.COM", false, ""},
+
+		// Domain typos
+		{"gamil", "john@gamil.com", true, "john@gmail.com"},
+		{"hotmial", "jane@hotmial.com", true, "jane@hotmail.com"},
+		{"typo uppercase", "john@GAMIL.COM", true, "john@gmail.com"},
+		{"gmail.co", "john@gmail.co", true, "john@gmail.com"},
+
+		// Whitespace and punctuation
+		{"inner spaces", "john doe@example.com", true, "johndoe@example.com"},
+		{"comma instead of dot", "john@example,com", true, "john@example.com"},
+		{"trailing dot", "john@example.com.", true, "john@example.com"},
+
+		// Missing TLD
+		{"known provider without TLD", "john@gmail", true, "john@gmail.com"},
+		{"unknown domain without TLD", "john@acme", true, ""},
+
+		// Unfixable
+		{"no @", "john.example.com", true, ""},
+		{"two @", "john@doe@example.com", true, ""},
+		{"empty local", "@example.com", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := CheckEmail(tt.input)
+			if (issue != nil) != tt.wantIssue {
+				t.Fatalf("CheckEmail(%q) issue = %v, want issue %v", tt.input, issue, tt.wantIssue)
+			}
+			if issue != nil && issue.Fixed != tt.wantFixed {
+				t.Errorf("CheckEmail(%q).Fixed = %q, want %q", tt.input, issue.Fixed, tt.wantFixed)
+			}
+		})
+	}
+}
+
+func TestContact_CheckEmails(t *testing.T) {
+	c := Contact{Emails: []string{"a@gamil.com", "b@example.com", "broken"}}
+
+	issues := c.CheckEmails(false)
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(issues))
+	}
+	if c.Emails[0] != "a@gamil.com" {
+		t.Errorf("report-only mode should not rewrite emails, got %q", c.Emails[0])
+	}
+
+	issues = c.CheckEmails(true)
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(issues))
+	}
+	if c.Emails[0] != "a@gmail.com" {
+		t.Errorf("expected corrected email, got %q", c.Emails[0])
+	}
+	if c.Emails[2] != "broken" {
+		t.Errorf("unfixable email should be kept, got %q", c.Emails[2])
+	}
+
+	c = Contact{Emails: []string{" b@example.com\t"}}
+	if issues := c.CheckEmails(false); len(issues) != 0 {
+		t.Errorf("surrounding whitespace is not an issue, got %v", issues)
+	}
+	if c.Emails[0] != "b@example.com" {
+		t.Errorf("expected trimmed email, got %q", c.Emails[0])
+	}
+}
@@ -0,0 +1,193 @@
+package vcard
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// EmailRules configure how addresses are compared for dedup, see
+// EmailRules.Normalize. The zero value applies the built-in rules.
+type EmailRules struct {
+	// KeepTags keeps tagged addresses (user+tag@) distinct on every domain
+	// without its own rule, for orgs using them as separate identities
+	KeepTags bool `json:"keep_tags,omitempty"`
+	// Domains override the rules of single domains, e.g. a custom domain
+	// ignoring dots like Gmail does
+	Domains map[string]EmailDomainRule `json:"domains,omitempty"`
+	// Aliases map domains to the one they are equivalent to, e.g. the old
+	// domain of a renamed company to the new one. The rule of the domain
+	// aliased to applies.
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// RoleAccounts and DisposableDomains extend the local parts of role
+	// accounts and the throwaway domains known to IsRole and IsDisposable
+	RoleAccounts      []string `json:"role_accounts,omitempty"`
+	DisposableDomains []string `json:"disposable_domains,omitempty"`
+}
+
+// EmailDomainRule normalizes the local part of the addresses of a domain
+type EmailDomainRule struct {
+	IgnoreDots    bool   `json:"ignore_dots,omitempty"`    // jane.doe@ is janedoe@
+	TagSeparators string `json:"tag_separators,omitempty"` // Characters starting a tag dropped from the local part, "+" when empty
+	KeepTags      bool   `json:"keep_tags,omitempty"`      // Never drop tags
+}
+
+// defaultEmailDomains are the rules of domains with none configured
+var defaultEmailDomains = map[string]EmailDomainRule{
+	"gmail.com": {IgnoreDots: true},
+}
+
+// defaultEmailAliases are the domains equivalent to another one out of the box
+var defaultEmailAliases = map[string]string{
+	"googlemail.com": "gmail.com",
+}
+
+// Validate reports rules that can't be applied
+func (r EmailRules) Validate() error {
+	for domain, rule := range r.Domains {
+		if domain == "" || strings.Contains(domain, "@") {
+			return fmt.Errorf("invalid email rule domain %q", domain)
+		}
+		if strings.ContainsAny(rule.TagSeparators, "@.") {
+			return fmt.Errorf("invalid tag separators %q for %s", rule.TagSeparators, domain)
+		}
+	}
+	for from, to := range r.Aliases {
+		if from == "" || to == "" || strings.Contains(from+to, "@") || strings.EqualFold(from, to) {
+			return fmt.Errorf("invalid email domain alias %q → %q", from, to)
+		}
+	}
+	return nil
+}
+
+// canonicalDomain returns the domain a lowercase domain is an alias of, or
+// the domain itself
+func (r EmailRules) canonicalDomain(domain string) string {
+	for from, to := range r.Aliases {
+		if strings.EqualFold(from, domain) {
+			return strings.ToLower(to)
+		}
+	}
+	if to, ok := defaultEmailAliases[domain]; ok {
+		return to
+	}
+	return domain
+}
+
+// rule returns the rule of domain: the configured one, else the default
+func (r EmailRules) rule(domain string) EmailDomainRule {
+	if rule, ok := r.Domains[domain]; ok {
+		return rule
+	}
+	rule := defaultEmailDomains[domain]
+	rule.KeepTags = r.KeepTags
+	return rule
+}
+
+// normalizeLocal applies the rule of a domain to the local part of an
+// address
+func (r EmailDomainRule) normalizeLocal(local string) string {
+	if !r.KeepTags {
+		separators := r.TagSeparators
+		if separators == "" {
+			separators = "+"
+		}
+		if i := strings.IndexAny(local, separators); i != -1 {
+			local = local[:i]
+		}
+	}
+	if r.IgnoreDots {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+	return local
+}
+
+// roleAccounts are local parts of addresses reaching a function or a team
+// rather than a person
+var roleAccounts = []string{
+	"abuse", "admin", "administrator", "billing", "careers", "contact",
+	"customerservice", "do-not-reply", "donotreply", "enquiries", "feedback",
+	"hello", "help", "helpdesk", "hostmaster", "hr", "info", "jobs",
+	"mailer-daemon", "marketing", "media", "newsletter", "no-reply", "noreply",
+	"notifications", "office", "orders", "postmaster", "press", "privacy",
+	"reception", "sales", "security", "service", "support", "team",
+	"webmaster",
+}
+
+// disposableDomains are throwaway mailbox providers
+var disposableDomains = []string{
+	"10minutemail.com", "discard.email", "dispostable.com", "emailondeck.com",
+	"fakeinbox.com", "getnada.com", "guerrillamail.com", "guerrillamail.net",
+	"mailinator.com", "maildrop.cc", "mailnesia.com", "mintemail.com",
+	"moakt.com", "sharklasers.com", "spamgourmet.com", "temp-mail.org",
+	"tempmail.com", "tempr.email", "throwawaymail.com", "trashmail.com",
+	"yopmail.com",
+}
+
+// mailProviders are public mailbox providers, whose domains say nothing
+// about the organization of their users
+var mailProviders = []string{
+	"aol.com", "fastmail.com", "gmail.com", "gmx.com", "gmx.de", "gmx.net",
+	"googlemail.com", "hey.com", "hotmail.com", "icloud.com", "live.com",
+	"mac.com", "mail.com", "me.com", "msn.com", "outlook.com", "pm.me",
+	"proton.me", "protonmail.com", "qq.com", "tutanota.com", "web.de",
+	"yahoo.com", "yandex.com", "zoho.com",
+}
+
+// IsProviderEmail reports whether email is at a public mailbox provider
+// such as gmail.com, or a disposable one, rather than at the domain of an
+// organization
+func IsProviderEmail(email string) bool {
+	_, domain, ok := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	if !ok {
+		return false
+	}
+	return slices.Contains(mailProviders, domain) || IsDisposableEmail(email)
+}
+
+// IsRoleEmail is EmailRules.IsRole with the built-in role accounts
+func IsRoleEmail(email string) bool {
+	return EmailRules{}.IsRole(email)
+}
+
+// IsRole reports whether email is a role account such as info@ or
+// noreply@, or one of RoleAccounts
+func (r EmailRules) IsRole(email string) bool {
+	local, _, ok := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	if !ok {
+		return false
+	}
+	if i := strings.Index(local, "+"); i != -1 {
+		local = local[:i]
+	}
+	return slices.Contains(roleAccounts, local) || slices.ContainsFunc(r.RoleAccounts, func(a string) bool { return strings.EqualFold(a, local) })
+}
+
+// IsDisposableEmail is EmailRules.IsDisposable with the built-in domains
+func IsDisposableEmail(email string) bool {
+	return EmailRules{}.IsDisposable(email)
+}
+
+// IsDisposable reports whether email is at a throwaway mailbox provider,
+// or one of DisposableDomains
+func (r EmailRules) IsDisposable(email string) bool {
+	_, domain, ok := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	if !ok {
+		return false
+	}
+	return slices.Contains(disposableDomains, domain) || slices.ContainsFunc(r.DisposableDomains, func(d string) bool { return strings.EqualFold(d, domain) })
+}
+
+// weakEmail reports whether email says little about who a contact is:
+// role accounts are shared by many people and disposable ones by nobody
+// for long, so neither identifies duplicates
+func (r EmailRules) weak(email string) bool {
+	return r.IsRole(email) || r.IsDisposable(email)
+}
+
+// OnlyRoleEmails reports whether the emails of c, all role accounts or
+// disposable addresses as rules say, are all there is to tell it apart:
+// it has at least one email and no phone
+func (c *Contact) OnlyRoleEmails(rules EmailRules) bool {
+	return len(c.Emails) > 0 && len(c.Phones) == 0 && !slices.ContainsFunc(c.Emails, func(e string) bool { return !rules.weak(e) })
+}
@@ -0,0 +1,178 @@
+package vcard
+
+import "testing"
+
+func TestEmailRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules EmailRules
+		email string
+		want  string
+	}{
+		{"defaults", EmailRules{}, "Jane.Doe+news@Gmail.com", "janedoe@gmail.com"},
+		{"keep tags", EmailRules{KeepTags: true}, "jane+work@example.com", "jane+work@example.com"},
+		{"keep tags, gmail still ignores dots", EmailRules{KeepTags: true}, "jane.doe+work@gmail.com", "janedoe+work@gmail.com"},
+		{
+			"own domain ignores dots",
+			EmailRules{Domains: map[string]EmailDomainRule{"example.com": {IgnoreDots: true}}},
+			"jane.doe+x@example.com", "janedoe@example.com",
+		},
+		{
+			"dash tags",
+			EmailRules{Domains: map[string]EmailDomainRule{"fastmail.com": {TagSeparators: "+-"}}},
+			"jane-shop@fastmail.com", "jane@fastmail.com",
+		},
+		{
+			"domain keeps tags",
+			EmailRules{Domains: map[string]EmailDomainRule{"acme.com": {KeepTags: true}}},
+			"jane+support@acme.com", "jane+support@acme.com",
+		},
+		{
+			"other domains unaffected",
+			EmailRules{Domains: map[string]EmailDomainRule{"fastmail.com": {TagSeparators: "-"}}},
+			"jane-doe+x@example.com", "jane-doe@example.com",
+		},
+		{
+			"renamed company",
+			EmailRules{Aliases: map[string]string{"oldcorp.com": "NewCorp.com"}},
+			"Jane@OldCorp.com", "jane@newcorp.com",
+		},
+		{
+			"aliased domain takes the rule of its target",
+			EmailRules{
+				Aliases: map[string]string{"oldcorp.com": "newcorp.com"},
+				Domains: map[string]EmailDomainRule{"newcorp.com": {IgnoreDots: true}},
+			},
+			"jane.doe@oldcorp.com", "janedoe@newcorp.com",
+		},
+		{"googlemail by default", EmailRules{}, "jane.doe@googlemail.com", "janedoe@gmail.com"},
+		{
+			"gmail rule overridden",
+			EmailRules{Domains: map[string]EmailDomainRule{"gmail.com": {}}},
+			"jane.doe@gmail.com", "jane.doe@gmail.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rules.Normalize(tt.email); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.email, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDedupIndex_EmailRules(t *testing.T) {
+	existing := []*Contact{{FormattedName: "Jane", Emails: []string{"jane+work@example.com"}}}
+	c := &Contact{FormattedName: "Jane Doe", Emails: []string{"jane+home@example.com"}}
+	if !NewDedupIndex(existing).IsDuplicate(c) {
+		t.Error("tagged addresses didn't match with the built-in rules")
+	}
+	opts := DedupOptions{Email: EmailRules{KeepTags: true}}
+	if NewDedupIndexWith(NewMemoryIndexStore(), opts, existing).IsDuplicate(c) {
+		t.Error("tagged addresses matched with KeepTags")
+	}
+	if ScoreContacts(c, existing[0], opts).Score != 0 {
+		t.Error("tagged addresses scored as a match with KeepTags")
+	}
+}
+
+func TestEmailRules_Validate(t *testing.T) {
+	for _, rules := range []EmailRules{
+		{Domains: map[string]EmailDomainRule{"jane@example.com": {}}},
+		{Domains: map[string]EmailDomainRule{"example.com": {TagSeparators: "."}}},
+		{Aliases: map[string]string{"oldcorp.com": ""}},
+		{Aliases: map[string]string{"oldcorp.com": "OLDCORP.com"}},
+	} {
+		if err := rules.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want an error", rules)
+		}
+	}
+	ok := EmailRules{Domains: map[string]EmailDomainRule{"fastmail.com": {TagSeparators: "+-"}}}
+	if err := ok.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestRoleAndDisposableEmails(t *testing.T) {
+	rules := EmailRules{RoleAccounts: []string{"Frontdesk"}, DisposableDomains: []string{"burner.example"}}
+
+	tests := []struct {
+		email      string
+		role       bool
+		disposable bool
+	}{
+		{"info@acme.com", true, false},
+		{"No-Reply@acme.com", true, false},
+		{"support+eu@acme.com", true, false},
+		{"frontdesk@acme.com", true, false},
+		{"jane@mailinator.com", false, true},
+		{"jane@burner.example", false, true},
+		{"jane@acme.com", false, false},
+		{"information@acme.com", false, false},
+		{"info", false, false},
+	}
+	for _, tt := range tests {
+		if got := rules.IsRole(tt.email); got != tt.role {
+			t.Errorf("IsRole(%q) = %v, want %v", tt.email, got, tt.role)
+		}
+		if got := rules.IsDisposable(tt.email); got != tt.disposable {
+			t.Errorf("IsDisposable(%q) = %v, want %v", tt.email, got, tt.disposable)
+		}
+	}
+	if IsRoleEmail("frontdesk@acme.com") || IsDisposableEmail("jane@burner.example") {
+		t.Error("configured accounts and domains are known without the rules")
+	}
+}
+
+func TestIsProviderEmail(t *testing.T) {
+	for email, want := range map[string]bool{
+		"jane@Gmail.com":      true,
+		"jane@mailinator.com": true,
+		"jane@acme.com":       false,
+		"gmail.com":           false,
+	} {
+		if got := IsProviderEmail(email); got != want {
+			t.Errorf("IsProviderEmail(%q) = %v, want %v", email, got, want)
+		}
+	}
+}
+
+func TestDedupIndex_IgnoresRoleEmails(t *testing.T) {
+	idx := NewDedupIndex([]*Contact{
+		{FormattedName: "Jane Smith", Emails: []string{"info@acme.com", "jane@acme.com"}},
+		{FormattedName: "Temp", Emails: []string{"x@mailinator.com"}},
+	})
+	if idx.IsDuplicate(&Contact{FormattedName: "John Doe", Emails: []string{"info@acme.com"}}) {
+		t.Error("contacts sharing only a role account matched")
+	}
+	if idx.IsDuplicate(&Contact{FormattedName: "Someone", Emails: []string{"x@mailinator.com"}}) {
+		t.Error("contacts sharing only a disposable address matched")
+	}
+	if !idx.IsDuplicate(&Contact{FormattedName: "J. Smith", Emails: []string{"jane@acme.com"}}) {
+		t.Error("personal email no longer matches")
+	}
+	a := &Contact{FormattedName: "A", Emails: []string{"sales@acme.com"}}
+	b := &Contact{FormattedName: "B", Emails: []string{"sales@acme.com"}}
+	if CompareContacts(a, b, DedupOptions{}) != MatchNone || ScoreContacts(a, b, DedupOptions{}).Score != 0 {
+		t.Error("role account scored as a match")
+	}
+}
+
+func TestContact_OnlyRoleEmails(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Contact
+		want bool
+	}{
+		{"role only", Contact{FormattedName: "Acme", Emails: []string{"info@acme.com"}}, true},
+		{"role and disposable", Contact{Emails: []string{"noreply@acme.com", "a@yopmail.com"}}, true},
+		{"personal email too", Contact{Emails: []string{"info@acme.com", "jane@acme.com"}}, false},
+		{"phone too", Contact{Emails: []string{"info@acme.com"}, Phones: []string{"+34 612 345 678"}}, false},
+		{"no email", Contact{FormattedName: "Jane"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.c.OnlyRoleEmails(EmailRules{}); got != tt.want {
+			t.Errorf("%s: OnlyRoleEmails() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
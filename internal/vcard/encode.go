@@ -0,0 +1,230 @@
+package vcard
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	govcard "github.com/emersion/go-vcard"
+)
+
+// Supported vCard versions for encoding
+const (
+	Version3 = "3.0"
+	Version4 = "4.0"
+)
+
+// Encode serializes a contact as a vCard 4.0 card
+func Encode(c Contact) (govcard.Card, error) {
+	return EncodeVersion(c, Version4)
+}
+
+// EncodeVersion serializes a contact as a vCard of the given version (3.0 or 4.0)
+func EncodeVersion(c Contact, version string) (govcard.Card, error) {
+	if version != Version3 && version != Version4 {
+		return nil, fmt.Errorf("unsupported vCard version %q (supported: %s, %s)", version, Version3, Version4)
+	}
+
+	card := make(govcard.Card)
+	card.SetValue(govcard.FieldVersion, version)
+	card.SetValue(govcard.FieldFormattedName, c.DisplayName())
+	card.SetName(&govcard.Name{
+		FamilyName:      c.FamilyName,
+		GivenName:       c.GivenName,
+		AdditionalName:  c.MiddleName,
+		HonorificPrefix: c.Prefix,
+		HonorificSuffix: c.Suffix,
+	})
+
+	for i, email := range c.Emails {
+		f := &govcard.Field{Value: email, Params: govcard.Params{}}
+		if version == Version3 {
+			f.Params.Add(govcard.ParamType, "INTERNET")
+		}
+		if i == 0 && len(c.Emails) > 1 {
+			setPreferred(f, version)
+		}
+		card.Add(govcard.FieldEmail, f)
+	}
+
+	for i, phone := range c.Phones {
+		f := &govcard.Field{Value: phone, Params: govcard.Params{}}
+		if i == 0 && len(c.Phones) > 1 {
+			setPreferred(f, version)
+		}
+		card.Add(govcard.FieldTelephone, f)
+	}
+
+	for _, addr := range c.Addresses {
+		card.AddAddress(&govcard.Address{
+			StreetAddress: addr.Street,
+			Locality:      addr.City,
+			Region:        addr.Region,
+			PostalCode:    addr.PostalCode,
+			Country:       addr.Country,
+		})
+	}
+	if len(c.Addresses) > 0 && c.Addresses[0].Geo != nil {
+		geo := c.Addresses[0].Geo
+		value := fmt.Sprintf("geo:%f,%f", geo.Lat, geo.Lon)
+		if version == Version3 {
+			value = fmt.Sprintf("%f;%f", geo.Lat, geo.Lon)
+		}
+		card.SetValue(govcard.FieldGeolocation, value)
+	}
+
+	setIfNotEmpty(card, govcard.FieldNickname, c.Nickname)
+	setIfNotEmpty(card, govcard.FieldOrganization, JoinOrganization(c.Organization, c.Department))
+	setIfNotEmpty(card, govcard.FieldTitle, c.Title)
+	setIfNotEmpty(card, govcard.FieldRole, c.Role)
+	setIfNotEmpty(card, "X-MS-ASSISTANT", c.Assistant)
+	setIfNotEmpty(card, "X-MS-MANAGER", c.Manager)
+	setIfNotEmpty(card, govcard.FieldNote, c.Note)
+
+	for _, u := range c.URLs {
+		card.AddValue(govcard.FieldURL, u)
+	}
+
+	if b, ok := ParseBirthdayDate(c.Birthday); ok {
+		card.SetValue(govcard.FieldBirthday, b.String())
+	} else if b, ok := ParseBirthdayDate(c.BirthdayText); ok {
+		card.SetValue(govcard.FieldBirthday, b.String())
+	} else if c.Birthday != "" {
+		card.SetValue(govcard.FieldBirthday, c.Birthday)
+	}
+
+	if c.Photo != "" {
+		card.Set(govcard.FieldPhoto, encodePhoto(c.Photo, version))
+	}
+
+	if len(c.Categories) > 0 {
+		card.SetCategories(c.Categories)
+	}
+	setIfNotEmpty(card, govcard.FieldUID, c.ExportUID())
+	if c.Favorite {
+		card.SetValue("X-FAVORITE", "1")
+	}
+
+	// Company cards and contacts with only an organization name describe
+	// the organization
+	if c.IsOrganization() || c.Organization != "" && c.FormattedName == "" && c.GivenName == "" && c.FamilyName == "" {
+		if version == Version4 {
+			card.SetKind(govcard.KindOrganization)
+		} else {
+			card.SetValue("X-ABSHOWAS", "COMPANY")
+		}
+	}
+
+	if version == Version3 {
+		addCharset(card)
+	}
+
+	return card, nil
+}
+
+// setPreferred marks the preferred value of a multi-valued property:
+// TYPE=PREF in 3.0, PREF=1 in 4.0
+func setPreferred(f *govcard.Field, version string) {
+	if version == Version3 {
+		f.Params.Add(govcard.ParamType, "PREF")
+		return
+	}
+	f.Params.Set(govcard.ParamPreferred, "1")
+}
+
+// charsetFields are the free-text properties that may carry non-ASCII text
+var charsetFields = []string{
+	govcard.FieldFormattedName, govcard.FieldName, govcard.FieldNickname,
+	govcard.FieldAddress, govcard.FieldOrganization, govcard.FieldTitle, govcard.FieldRole,
+	govcard.FieldNote, govcard.FieldCategories,
+}
+
+// addCharset labels non-ASCII text as UTF-8. vCard 4.0 is always UTF-8 and
+// forbids the parameter, but many phones limited to 3.0 misread text without it.
+func addCharset(card govcard.Card) {
+	for _, name := range charsetFields {
+		for _, f := range card[name] {
+			if !isASCII(f.Value) {
+				if f.Params == nil {
+					f.Params = govcard.Params{}
+				}
+				f.Params.Set("CHARSET", "UTF-8")
+			}
+		}
+	}
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// encodePhoto converts the stored photo value (URL, data URI or raw base64)
+// to the representation expected by the vCard version
+func encodePhoto(photo, version string) *govcard.Field {
+	isURL := IsPhotoURL(photo)
+
+	if version == Version4 {
+		if isURL || strings.HasPrefix(photo, "data:") {
+			return &govcard.Field{Value: photo}
+		}
+		return &govcard.Field{Value: "data:image/jpeg;base64," + photo}
+	}
+
+	if isURL {
+		return &govcard.Field{Value: photo, Params: govcard.Params{"VALUE": {"uri"}}}
+	}
+
+	mediaType, data := "JPEG", photo
+	if rest, ok := strings.CutPrefix(photo, "data:"); ok {
+		header, payload, _ := strings.Cut(rest, ",")
+		data = payload
+		if mime, _, _ := strings.Cut(header, ";"); strings.HasPrefix(mime, "image/") {
+			mediaType = strings.ToUpper(strings.TrimPrefix(mime, "image/"))
+		}
+	}
+	return &govcard.Field{
+		Value:  data,
+		Params: govcard.Params{"ENCODING": {"b"}, govcard.ParamType: {mediaType}},
+	}
+}
+
+func setIfNotEmpty(card govcard.Card, field, value string) {
+	if value != "" {
+		card.SetValue(field, value)
+	}
+}
+
+// Write encodes contacts as vCards of the given version to w
+func Write(w io.Writer, contacts []Contact, version string) error {
+	enc := govcard.NewEncoder(w)
+	for _, c := range contacts {
+		card, err := EncodeVersion(c, version)
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(card); err != nil {
+			return fmt.Errorf("failed to encode %s: %w", c.DisplayName(), err)
+		}
+	}
+	return nil
+}
+
+// WriteFile encodes contacts as vCards of the given version into a .vcf file
+func WriteFile(path string, contacts []Contact, version string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	if err := Write(file, contacts, version); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
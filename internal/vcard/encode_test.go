@@ -0,0 +1,184 @@
+package vcard
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	govcard "github.com/emersion/go-vcard"
+)
+
+func TestEncode_RoundTrip(t *testing.T) {
+	original := Contact{
+		FormattedName: "John Doe",
+		GivenName:     "John",
+		FamilyName:    "Doe",
+		MiddleName:    "Michael",
+		Prefix:        "Mr.",
+		Suffix:        "Jr.",
+		Emails:        []string{"john@example.com", "jdoe@work.com"},
+		Phones:        []string{"+1-555-123-4567"},
+		Addresses: []Address{{
+			Street:     "123 Main St",
+			City:       "Springfield",
+			Region:     "IL",
+			PostalCode: "62701",
+			Country:    "USA",
+			Full:       "123 Main St",
+		}},
+		Organization: "Acme Corporation",
+		Department:   "Engineering, Platform",
+		Title:        "Senior Developer",
+		URLs:         []string{"https://johndoe.example.com"},
+		Note:         "Important, prefers email.\nSecond line.",
+		Birthday:     "1985-06-15",
+	}
+
+	for _, version := range []string{Version3, Version4} {
+		t.Run(version, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, []Contact{original}, version); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !strings.Contains(buf.String(), "VERSION:"+version) {
+				t.Errorf("output missing VERSION:%s:\n%s", version, buf.String())
+			}
+			if !strings.Contains(buf.String(), "ORG:Acme Corporation;Engineering;Platform") {
+				t.Errorf("output should keep the ORG units apart:\n%s", buf.String())
+			}
+
+			parsed, err := Parse(&buf)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(parsed) != 1 {
+				t.Fatalf("expected 1 contact, got %d", len(parsed))
+			}
+			if !reflect.DeepEqual(parsed[0], original) {
+				t.Errorf("round trip mismatch:\n got  %+v\n want %+v", parsed[0], original)
+			}
+		})
+	}
+}
+
+func TestEncode_Details(t *testing.T) {
+	c := Contact{
+		FormattedName: "Jane",
+		BirthdayText:  "--03-15",
+		Photo:         "data:image/png;base64,AAAA",
+		Addresses:     []Address{{City: "Madrid", Geo: &Geo{Lat: 40.4168, Lon: -3.7038}}},
+	}
+
+	card, err := EncodeVersion(c, Version3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := card.Value(govcard.FieldBirthday); got != "--03-15" {
+		t.Errorf("BDAY = %q, want --03-15", got)
+	}
+	photo := card.Get(govcard.FieldPhoto)
+	if photo.Value != "AAAA" || photo.Params.Get("ENCODING") != "b" || photo.Params.Get(govcard.ParamType) != "PNG" {
+		t.Errorf("3.0 PHOTO = %+v", photo)
+	}
+	if got := card.Value(govcard.FieldGeolocation); got != "40.416800;-3.703800" {
+		t.Errorf("3.0 GEO = %q", got)
+	}
+
+	card, err = Encode(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := card.Value(govcard.FieldPhoto); got != "data:image/png;base64,AAAA" {
+		t.Errorf("4.0 PHOTO = %q", got)
+	}
+	if got := card.Value(govcard.FieldGeolocation); got != "geo:40.416800,-3.703800" {
+		t.Errorf("4.0 GEO = %q", got)
+	}
+
+	if _, err := EncodeVersion(c, "2.1"); err == nil {
+		t.Error("expected error for unsupported version")
+	}
+}
+
+func TestEncode_VersionDifferences(t *testing.T) {
+	c := Contact{
+		FormattedName: "José Núñez",
+		Emails:        []string{"jose@example.com", "jn@example.org"},
+		Phones:        []string{"+34600000000", "+34910000000"},
+		Note:          "plain ascii",
+	}
+
+	card, err := EncodeVersion(c, Version3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := card.Get(govcard.FieldFormattedName).Params.Get("CHARSET"); got != "UTF-8" {
+		t.Errorf("3.0 FN CHARSET = %q, want UTF-8", got)
+	}
+	if got := card.Get(govcard.FieldNote).Params.Get("CHARSET"); got != "" {
+		t.Errorf("3.0 NOTE CHARSET = %q, want none for ASCII", got)
+	}
+	emails := card[govcard.FieldEmail]
+	if types := emails[0].Params[govcard.ParamType]; !reflect.DeepEqual(types, []string{"INTERNET", "PREF"}) {
+		t.Errorf("3.0 first EMAIL TYPE = %v, want [INTERNET PREF]", types)
+	}
+	if types := emails[1].Params[govcard.ParamType]; !reflect.DeepEqual(types, []string{"INTERNET"}) {
+		t.Errorf("3.0 second EMAIL TYPE = %v", types)
+	}
+
+	card, err = EncodeVersion(c, Version4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := card.Get(govcard.FieldFormattedName).Params.Get("CHARSET"); got != "" {
+		t.Errorf("4.0 must not use CHARSET, got %q", got)
+	}
+	if got := card[govcard.FieldTelephone][0].Params.Get(govcard.ParamPreferred); got != "1" {
+		t.Errorf("4.0 first TEL PREF = %q, want 1", got)
+	}
+	if got := card[govcard.FieldEmail][0].Params.Types(); len(got) != 0 {
+		t.Errorf("4.0 EMAIL TYPE = %v, want none", got)
+	}
+}
+
+func TestEncode_OrganizationKind(t *testing.T) {
+	c := Contact{Organization: "Acme"}
+
+	card, err := EncodeVersion(c, Version4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Kind() != govcard.KindOrganization {
+		t.Errorf("4.0 KIND = %q, want org", card.Kind())
+	}
+
+	card, err = EncodeVersion(c, Version3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Value(govcard.FieldKind) != "" || card.Value("X-ABSHOWAS") != "COMPANY" {
+		t.Errorf("3.0 should use X-ABSHOWAS instead of KIND: %v", card)
+	}
+
+	card, _ = EncodeVersion(Contact{FormattedName: "Jane", Organization: "Acme"}, Version4)
+	if card.Value(govcard.FieldKind) != "" {
+		t.Errorf("KIND = %q, want none for a person", card.Value(govcard.FieldKind))
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.vcf")
+	contacts := []Contact{{FormattedName: "A"}, {FormattedName: "B"}}
+	if err := WriteFile(path, contacts, Version4); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != 2 || parsed[1].FormattedName != "B" {
+		t.Errorf("ParseFile() = %+v", parsed)
+	}
+}
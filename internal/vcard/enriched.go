@@ -0,0 +1,46 @@
+package vcard
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Enrichment records a value filled in from a public source, kept in the
+// notes so it is never mistaken for one from the address book
+type Enrichment struct {
+	Field  string `json:"field"` // photo, organization or title
+	Value  string `json:"value"`
+	Origin string `json:"origin"` // Source and what was looked up, e.g. "WHOIS acme.com"
+}
+
+func (e Enrichment) String() string {
+	return fmt.Sprintf("- %s: %s (%s)", e.Field, e.Value, e.Origin)
+}
+
+// enrichedHeader introduces the enrichments in the notes
+const enrichedHeader = "Enriched from public sources:"
+
+// enrichedNotes renders enrichments for the notes, "" when there are none
+func enrichedNotes(enrichments []Enrichment) string {
+	if len(enrichments) == 0 {
+		return ""
+	}
+	lines := []string{enrichedHeader}
+	for _, e := range enrichments {
+		lines = append(lines, e.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// enrichedValue returns the value of a field enrichment can fill in
+func (c *Contact) enrichedValue(field string) string {
+	switch field {
+	case "photo":
+		return c.Photo
+	case "organization":
+		return c.Organization
+	case "title":
+		return c.Title
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package vcard
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	govcard "github.com/emersion/go-vcard"
+)
+
+// favoriteFields are the vCard extensions address books use to export
+// starred contacts
+var favoriteFields = []string{"X-FAVORITE", "X-APPLE-FAVORITE", "X-STARRED"}
+
+// favoriteCategories are the group names that mark starred contacts, such
+// as Google's "Starred" system group or an Apple "Favorites" group
+var favoriteCategories = []string{"starred", "favorite", "favorites"}
+
+// IsFavoriteCategory reports whether a group name marks starred contacts
+func IsFavoriteCategory(name string) bool {
+	return slices.Contains(favoriteCategories, strings.ToLower(strings.TrimSpace(name)))
+}
+
+// isFavoriteCard reports whether the card carries a favorite marker with
+// a true value
+func isFavoriteCard(card govcard.Card) bool {
+	for _, field := range favoriteFields {
+		switch strings.ToLower(strings.TrimSpace(card.Value(field))) {
+		case "1", "true", "yes":
+			return true
+		}
+	}
+	return false
+}
+
+// MarkFavorites flags the contacts in a starred group and, when re is not
+// nil, those whose name, organization, emails or groups match it. It
+// returns the number of favorites.
+func MarkFavorites(contacts []Contact, re *regexp.Regexp) int {
+	n := 0
+	for i := range contacts {
+		c := &contacts[i]
+		if !c.Favorite && slices.ContainsFunc(c.Categories, IsFavoriteCategory) {
+			c.Favorite = true
+		}
+		if !c.Favorite && re != nil && matchesContact(re, c) {
+			c.Favorite = true
+		}
+		if c.Favorite {
+			n++
+		}
+	}
+	return n
+}
+
+func matchesContact(re *regexp.Regexp, c *Contact) bool {
+	if re.MatchString(c.DisplayName()) || c.Organization != "" && re.MatchString(c.Organization) {
+		return true
+	}
+	return slices.ContainsFunc(c.Emails, re.MatchString) || slices.ContainsFunc(c.Categories, re.MatchString)
+}
@@ -0,0 +1,71 @@
+package vcard
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestParse_Favorite(t *testing.T) {
+	input := `BEGIN:VCARD
+VERSION:3.0
+FN:Starred
+X-FAVORITE:1
+END:VCARD
+BEGIN:VCARD
+VERSION:3.0
+FN:Apple
+X-APPLE-FAVORITE:TRUE
+END:VCARD
+BEGIN:VCARD
+VERSION:3.0
+FN:Plain
+X-FAVORITE:0
+END:VCARD
+`
+	contacts, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []bool{true, true, false}
+	for i, c := range contacts {
+		if c.Favorite != want[i] {
+			t.Errorf("%s: Favorite = %v, want %v", c.FormattedName, c.Favorite, want[i])
+		}
+	}
+}
+
+func TestMarkFavorites(t *testing.T) {
+	contacts := []Contact{
+		{FormattedName: "Google", Categories: []string{"Family", "Starred"}},
+		{FormattedName: "Boss", Organization: "Acme"},
+		{FormattedName: "Client", Emails: []string{"ceo@bigcorp.com"}},
+		{FormattedName: "Nobody", Organization: "Other"},
+	}
+	n := MarkFavorites(contacts, regexp.MustCompile(`(?i)acme|@bigcorp\.com$`))
+	if n != 3 {
+		t.Errorf("MarkFavorites() = %d, want 3", n)
+	}
+	for i, want := range []bool{true, true, true, false} {
+		if contacts[i].Favorite != want {
+			t.Errorf("%s: Favorite = %v, want %v", contacts[i].FormattedName, contacts[i].Favorite, want)
+		}
+	}
+}
+
+func TestBuildProperties_Favorite(t *testing.T) {
+	has := func(c Contact) bool {
+		for _, p := range BuildProperties(c, nil, nil) {
+			if p["key"] == "favorite" {
+				return p["checkbox"] == true
+			}
+		}
+		return false
+	}
+	if !has(Contact{FormattedName: "A", Favorite: true}) {
+		t.Error("expected a favorite checkbox for a favorite contact")
+	}
+	if has(Contact{FormattedName: "B"}) {
+		t.Error("unexpected favorite checkbox for a regular contact")
+	}
+}
@@ -0,0 +1,59 @@
+package vcard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Filter selects contacts by tag, organization, modification time and a
+// query expression. Empty criteria match every contact.
+type Filter struct {
+	Tags          []string  // Matches contacts with any of the tags (case-insensitive)
+	Organization  string    // Case-insensitive substring of the organization
+	ModifiedSince time.Time // Matches contacts modified at or after this time
+	Where         *Query    // Matches contacts satisfying the expression
+}
+
+// Match reports whether the contact satisfies every criterion
+func (f Filter) Match(c *Contact) bool {
+	if len(f.Tags) > 0 && !hasAnyTag(c.Categories, f.Tags) {
+		return false
+	}
+	if f.Organization != "" && !strings.Contains(strings.ToLower(c.Organization), strings.ToLower(f.Organization)) {
+		return false
+	}
+	if !f.ModifiedSince.IsZero() {
+		modified, err := time.Parse(time.RFC3339, c.LastModified)
+		if err != nil || modified.Before(f.ModifiedSince) {
+			return false
+		}
+	}
+	if f.Where != nil && !f.Where.Match(c) {
+		return false
+	}
+	return true
+}
+
+func hasAnyTag(categories, tags []string) bool {
+	for _, c := range categories {
+		for _, t := range tags {
+			if strings.EqualFold(c, t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseFilterTime parses a --modified-since style value: a date
+// (2024-01-01, local midnight) or an RFC3339 timestamp
+func ParseFilterTime(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (expected YYYY-MM-DD or RFC3339)", s)
+}
@@ -0,0 +1,58 @@
+package vcard
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilter_Match(t *testing.T) {
+	c := &Contact{
+		Organization: "Acme Corporation",
+		Categories:   []string{"Family", "VIP"},
+		LastModified: "2024-03-01T12:00:00Z",
+	}
+	since := func(s string) time.Time {
+		t, _ := time.Parse(time.RFC3339, s)
+		return t
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"empty filter", Filter{}, true},
+		{"tag match", Filter{Tags: []string{"family"}}, true},
+		{"any tag", Filter{Tags: []string{"work", "vip"}}, true},
+		{"tag mismatch", Filter{Tags: []string{"work"}}, false},
+		{"org substring", Filter{Organization: "acme"}, true},
+		{"org mismatch", Filter{Organization: "Globex"}, false},
+		{"modified after", Filter{ModifiedSince: since("2024-01-01T00:00:00Z")}, true},
+		{"modified before", Filter{ModifiedSince: since("2024-06-01T00:00:00Z")}, false},
+		{"all criteria", Filter{Tags: []string{"vip"}, Organization: "Acme", ModifiedSince: since("2024-01-01T00:00:00Z")}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(c); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if (Filter{ModifiedSince: since("2024-01-01T00:00:00Z")}).Match(&Contact{}) {
+		t.Error("contact without modification time should not match --modified-since")
+	}
+}
+
+func TestParseFilterTime(t *testing.T) {
+	if _, err := ParseFilterTime("2024-01-01"); err != nil {
+		t.Errorf("date: %v", err)
+	}
+	if _, err := ParseFilterTime("2024-01-01T10:00:00Z"); err != nil {
+		t.Errorf("RFC3339: %v", err)
+	}
+	if _, err := ParseFilterTime("last week"); err == nil {
+		t.Error("expected error for invalid time")
+	}
+}
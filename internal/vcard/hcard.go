@@ -0,0 +1,288 @@
+package vcard
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// hCardProperty describes how an hCard class name maps onto a Contact
+type hCardProperty struct {
+	field string
+	kind  byte // 'p' plain text, 'u' URL, 'd' date-time
+}
+
+// hCardProperties maps microformats2 (p-name) and classic hCard (fn)
+// class names to Contact fields
+var hCardProperties = map[string]hCardProperty{
+	"p-name": {"name", 'p'}, "fn": {"name", 'p'},
+	"p-given-name": {"given", 'p'}, "given-name": {"given", 'p'},
+	"p-family-name": {"family", 'p'}, "family-name": {"family", 'p'},
+	"p-additional-name": {"middle", 'p'}, "additional-name": {"middle", 'p'},
+	"p-honorific-prefix": {"prefix", 'p'}, "honorific-prefix": {"prefix", 'p'},
+	"p-honorific-suffix": {"suffix", 'p'}, "honorific-suffix": {"suffix", 'p'},
+	"p-nickname": {"nickname", 'p'}, "nickname": {"nickname", 'p'},
+	"u-email": {"email", 'u'}, "email": {"email", 'u'},
+	"p-tel": {"tel", 'p'}, "u-tel": {"tel", 'u'}, "tel": {"tel", 'u'},
+	"p-org": {"org", 'p'}, "org": {"org", 'p'},
+	"p-job-title": {"title", 'p'}, "title": {"title", 'p'},
+	"p-role": {"role", 'p'}, "role": {"role", 'p'},
+	"u-url": {"url", 'u'}, "url": {"url", 'u'},
+	"u-photo": {"photo", 'u'}, "photo": {"photo", 'u'},
+	"p-note": {"note", 'p'}, "note": {"note", 'p'},
+	"dt-bday": {"bday", 'd'}, "bday": {"bday", 'd'},
+	"p-street-address": {"street", 'p'}, "street-address": {"street", 'p'},
+	"p-locality": {"city", 'p'}, "locality": {"city", 'p'},
+	"p-region": {"region", 'p'}, "region": {"region", 'p'},
+	"p-postal-code": {"postal", 'p'}, "postal-code": {"postal", 'p'},
+	"p-country-name": {"country", 'p'}, "country-name": {"country", 'p'},
+	"p-category": {"category", 'p'}, "category": {"category", 'p'},
+	"u-uid": {"uid", 'u'}, "uid": {"uid", 'u'},
+}
+
+// ParseHCards extracts every top-level h-card (or classic vcard) from an
+// HTML document. Relative URLs are resolved against base when not nil.
+func ParseHCards(r io.Reader, base *url.URL) ([]Contact, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	var contacts []Contact
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && isHCard(n) {
+			contacts = append(contacts, parseHCard(n, base))
+			return
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+	return contacts, nil
+}
+
+func isHCard(n *html.Node) bool {
+	c := classes(n)
+	return slices.Contains(c, "h-card") || slices.Contains(c, "vcard")
+}
+
+func parseHCard(root *html.Node, base *url.URL) Contact {
+	var contact Contact
+	var addr Address
+
+	set := func(prop hCardProperty, value string) {
+		if value == "" {
+			return
+		}
+		switch prop.field {
+		case "name":
+			contact.FormattedName = firstNonEmpty(contact.FormattedName, value)
+		case "given":
+			contact.GivenName = firstNonEmpty(contact.GivenName, value)
+		case "family":
+			contact.FamilyName = firstNonEmpty(contact.FamilyName, value)
+		case "middle":
+			contact.MiddleName = firstNonEmpty(contact.MiddleName, value)
+		case "prefix":
+			contact.Prefix = firstNonEmpty(contact.Prefix, value)
+		case "suffix":
+			contact.Suffix = firstNonEmpty(contact.Suffix, value)
+		case "nickname":
+			contact.Nickname = firstNonEmpty(contact.Nickname, value)
+		case "email":
+			value = strings.TrimPrefix(value, "mailto:")
+			value, _, _ = strings.Cut(value, "?")
+			contact.Emails = appendUnique(contact.Emails, value)
+		case "tel":
+			contact.Phones = appendUnique(contact.Phones, strings.TrimPrefix(value, "tel:"))
+		case "org":
+			contact.Organization = firstNonEmpty(contact.Organization, value)
+		case "title":
+			contact.Title = firstNonEmpty(contact.Title, value)
+		case "role":
+			contact.Role = firstNonEmpty(contact.Role, value)
+		case "url":
+			contact.URLs = appendUnique(contact.URLs, value)
+		case "photo":
+			contact.Photo = firstNonEmpty(contact.Photo, value)
+		case "note":
+			contact.Note = firstNonEmpty(contact.Note, value)
+		case "bday":
+			contact.Birthday = firstNonEmpty(contact.Birthday, value)
+		case "street":
+			addr.Street = firstNonEmpty(addr.Street, value)
+		case "city":
+			addr.City = firstNonEmpty(addr.City, value)
+		case "region":
+			addr.Region = firstNonEmpty(addr.Region, value)
+		case "postal":
+			addr.PostalCode = firstNonEmpty(addr.PostalCode, value)
+		case "country":
+			addr.Country = firstNonEmpty(addr.Country, value)
+		case "category":
+			contact.Categories = appendUnique(contact.Categories, value)
+		case "uid":
+			contact.UID = firstNonEmpty(contact.UID, value)
+		}
+	}
+
+	var walk func(n *html.Node, isRoot bool)
+	walk = func(n *html.Node, isRoot bool) {
+		if n.Type == html.ElementNode {
+			if !isRoot {
+				for _, class := range classes(n) {
+					if prop, ok := hCardProperties[class]; ok {
+						set(prop, hCardValue(n, prop.kind, base))
+					}
+				}
+			}
+			// Nested cards (p-org h-card, p-author h-card) belong to another
+			// entity: only their own property classes apply
+			if !isRoot && isHCard(n) {
+				return
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child, false)
+		}
+	}
+	walk(root, true)
+
+	// microformats2 implied name: the text of a card without name properties
+	if contact.FormattedName == "" && contact.GivenName == "" && contact.FamilyName == "" {
+		contact.FormattedName = hCardValue(root, 'p', base)
+	}
+	if addr != (Address{}) {
+		addr.Full = addr.Street
+		contact.Addresses = append(contact.Addresses, addr)
+	}
+	return contact
+}
+
+// hCardValue extracts a property value following the microformats parsing
+// rules for its kind
+func hCardValue(n *html.Node, kind byte, base *url.URL) string {
+	if v := valueClassText(n); v != "" {
+		return v
+	}
+
+	switch kind {
+	case 'u':
+		for _, attr := range []string{"href", "src", "data", "value"} {
+			if v := attrValue(n, attr); v != "" {
+				return resolveURL(v, base)
+			}
+		}
+	case 'd':
+		if v := attrValue(n, "datetime"); v != "" {
+			return v
+		}
+	}
+
+	switch n.Data {
+	case "abbr":
+		if v := attrValue(n, "title"); v != "" {
+			return v
+		}
+	case "img", "area":
+		if v := attrValue(n, "alt"); v != "" {
+			return v
+		}
+	case "data", "input":
+		if v := attrValue(n, "value"); v != "" {
+			return v
+		}
+	}
+	return textContent(n)
+}
+
+// valueClassText joins the descendants marked class="value", used by hCard
+// to separate the value from type labels (<span class="type">work</span>)
+func valueClassText(n *html.Node) string {
+	var parts []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			if slices.Contains(classes(child), "value") {
+				v := attrValue(child, "title")
+				if v == "" {
+					v = textContent(child)
+				}
+				parts = append(parts, v)
+				continue
+			}
+			walk(child)
+		}
+	}
+	walk(n)
+	return strings.Join(parts, "")
+}
+
+func resolveURL(ref string, base *url.URL) string {
+	if base == nil {
+		return ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
+
+func classes(n *html.Node) []string {
+	return strings.Fields(attrValue(n, "class"))
+}
+
+func attrValue(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+// textContent returns the text of a node with whitespace collapsed,
+// skipping scripts and styles
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			b.WriteByte(' ')
+			return
+		}
+		if n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style") {
+			return
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func firstNonEmpty(current, value string) string {
+	if current != "" {
+		return current
+	}
+	return value
+}
+
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
@@ -0,0 +1,71 @@
+package vcard
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestParseHCards(t *testing.T) {
+	page := `<html><body>
+<div class="h-card">
+  <img class="u-photo" src="/me.jpg" alt="">
+  <a class="p-name u-url" href="/">Jane Doe</a>
+  <span class="p-nickname">JD</span>
+  <a class="u-email" href="mailto:jane@example.com?subject=hi">email me</a>
+  <span class="p-tel">+1 555 000 0001</span>
+  <span class="p-job-title">CTO</span> at
+  <a class="p-org h-card" href="https://acme.example.com"><span class="p-name">Acme</span></a>
+  <time class="dt-bday" datetime="1990-03-22">March 22</time>
+  <p class="p-adr h-adr"><span class="p-locality">Springfield</span>, <span class="p-country-name">USA</span></p>
+  <script>var fn = "ignored";</script>
+</div>
+<div class="vcard">
+  <span class="fn">John Smith</span>
+  <div class="tel"><span class="type">work</span> <span class="value">+1 555 000 0002</span></div>
+  <a class="email" href="mailto:john@example.com">john@example.com</a>
+</div>
+<p class="h-card">Implied Name</p>
+</body></html>`
+
+	base, _ := url.Parse("https://jane.example.com/about")
+	contacts, err := ParseHCards(strings.NewReader(page), base)
+	if err != nil {
+		t.Fatalf("ParseHCards() error = %v", err)
+	}
+	if len(contacts) != 3 {
+		t.Fatalf("expected 3 top-level cards, got %d", len(contacts))
+	}
+
+	jane := contacts[0]
+	if jane.FormattedName != "Jane Doe" || jane.Nickname != "JD" || jane.Title != "CTO" {
+		t.Errorf("unexpected card: %+v", jane)
+	}
+	if jane.Organization != "Acme" {
+		t.Errorf("Organization = %q, want nested h-card name only", jane.Organization)
+	}
+	if jane.Photo != "https://jane.example.com/me.jpg" {
+		t.Errorf("Photo = %q, want resolved URL", jane.Photo)
+	}
+	if len(jane.URLs) != 1 || jane.URLs[0] != "https://jane.example.com/" {
+		t.Errorf("URLs = %v, want page root only", jane.URLs)
+	}
+	if len(jane.Emails) != 1 || jane.Emails[0] != "jane@example.com" {
+		t.Errorf("Emails = %v", jane.Emails)
+	}
+	if jane.Birthday != "1990-03-22" {
+		t.Errorf("Birthday = %q", jane.Birthday)
+	}
+	if len(jane.Addresses) != 1 || jane.Addresses[0].City != "Springfield" || jane.Addresses[0].Country != "USA" {
+		t.Errorf("Addresses = %+v", jane.Addresses)
+	}
+
+	john := contacts[1]
+	if john.FormattedName != "John Smith" || len(john.Phones) != 1 || john.Phones[0] != "+1 555 000 0002" {
+		t.Errorf("classic hCard not parsed: %+v", john)
+	}
+
+	if contacts[2].FormattedName != "Implied Name" {
+		t.Errorf("FormattedName = %q, want implied name", contacts[2].FormattedName)
+	}
+}
@@ -0,0 +1,58 @@
+package vcard
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxHistory caps the length of Contact.History; the oldest entries are
+// dropped first
+const MaxHistory = 2000
+
+// historyFields are the fields compared by ChangedFields, named as in
+// Query expressions
+var historyFields = []string{
+	"name", "given", "family", "nickname", "org", "department", "title",
+	"role", "email", "phone", "tag", "url", "note", "birthday", "uid", "favorite",
+}
+
+// ChangedFields lists the fields that differ between two versions of a
+// contact
+func ChangedFields(before, after *Contact) []string {
+	var changed []string
+	for _, field := range historyFields {
+		if !slices.Equal(queryValues(before, field), queryValues(after, field)) {
+			changed = append(changed, field)
+		}
+	}
+	if !slices.EqualFunc(before.Addresses, after.Addresses, func(a, b Address) bool {
+		return normalizeAddress(a) == normalizeAddress(b)
+	}) {
+		changed = append(changed, "address")
+	}
+	if before.Photo != after.Photo {
+		changed = append(changed, "photo")
+	}
+	return changed
+}
+
+// RecordHistory appends an entry for a change of fields brought by source
+// to the history of c, e.g. "2024-06-01 contacts.vcf: email, title",
+// dropping the oldest entries past MaxHistory
+func (c *Contact) RecordHistory(date time.Time, source string, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+	if source == "" {
+		source = "unknown source"
+	}
+	entry := fmt.Sprintf("%s %s: %s", date.Format("2006-01-02"), source, strings.Join(fields, ", "))
+	entries := append(strings.Split(c.History, "\n"), entry)
+	entries = slices.DeleteFunc(entries, func(e string) bool { return e == "" })
+	for len(entries) > 1 && len(strings.Join(entries, "\n")) > MaxHistory {
+		entries = entries[1:]
+	}
+	c.History = strings.Join(entries, "\n")
+}
@@ -0,0 +1,85 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChangedFields(t *testing.T) {
+	before := &Contact{
+		FormattedName: "Jane Smith",
+		Emails:        []string{"jane@example.com"},
+		Title:         "Engineer",
+		Addresses:     []Address{{City: "Madrid"}},
+	}
+	tests := []struct {
+		name   string
+		change func(c *Contact)
+		want   string
+	}{
+		{"unchanged", func(c *Contact) {}, ""},
+		{"email added", func(c *Contact) { c.Emails = append(c.Emails, "j@acme.com") }, "email"},
+		{"title and org", func(c *Contact) { c.Title, c.Organization = "CTO", "Acme" }, "org, title"},
+		{"address", func(c *Contact) { c.Addresses = []Address{{City: "Paris"}} }, "address"},
+		{"same address in other case", func(c *Contact) { c.Addresses = []Address{{City: "MADRID"}} }, ""},
+		{"photo", func(c *Contact) { c.Photo = "https://example.com/jane.jpg" }, "photo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := *before
+			after.Emails = append([]string(nil), before.Emails...)
+			tt.change(&after)
+			if got := strings.Join(ChangedFields(before, &after), ", "); got != tt.want {
+				t.Errorf("ChangedFields() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordHistory(t *testing.T) {
+	date := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	var c Contact
+	c.RecordHistory(date, "contacts.vcf", []string{"email", "title"})
+	c.RecordHistory(date.AddDate(0, 1, 0), "", []string{"phone"})
+	c.RecordHistory(date, "ignored.vcf", nil)
+	want := "2024-06-01 contacts.vcf: email, title\n2024-07-01 unknown source: phone"
+	if c.History != want {
+		t.Errorf("History = %q, want %q", c.History, want)
+	}
+
+	// The oldest entries go first once the history is full
+	for i := range 100 {
+		c.RecordHistory(date.AddDate(0, 0, i), "google", []string{"email", "phone", "address"})
+	}
+	if len(c.History) > MaxHistory {
+		t.Errorf("History has %d bytes, want at most %d", len(c.History), MaxHistory)
+	}
+	if strings.Contains(c.History, "contacts.vcf") {
+		t.Error("History kept its oldest entry past the cap")
+	}
+	if !strings.HasSuffix(c.History, "2024-09-08 google: email, phone, address") {
+		t.Errorf("History lost its newest entry: ...%s", c.History[len(c.History)-60:])
+	}
+}
+
+func TestMergeContactsWith_History(t *testing.T) {
+	dst := &Contact{FormattedName: "Jane Smith", Emails: []string{"jane@example.com"}, History: "2024-01-01 old.vcf: phone"}
+	src := &Contact{FormattedName: "Jane Smith", Emails: []string{"jane@acme.com"}, Title: "CTO", Source: "work.vcf"}
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	if !MergeContactsWith(dst, src, MergeOptions{Now: now, History: true}) {
+		t.Fatal("MergeContactsWith() = false, want true")
+	}
+	want := "2024-01-01 old.vcf: phone\n2024-06-01 work.vcf: title, email"
+	if dst.History != want {
+		t.Errorf("History = %q, want %q", dst.History, want)
+	}
+
+	// Nothing merged, nothing recorded
+	if MergeContactsWith(dst, src, MergeOptions{Now: now, History: true}) {
+		t.Error("second MergeContactsWith() = true, want false")
+	}
+	if dst.History != want {
+		t.Errorf("History after a no-op merge = %q", dst.History)
+	}
+}
@@ -0,0 +1,231 @@
+package vcard
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// IndexStore holds the lookup tables of a DedupIndex, each mapping a
+// normalized key (a phone suffix, an email, a name) to the contacts
+// having it
+type IndexStore interface {
+	// Add appends c to the contacts under key in table
+	Add(table, key string, c *Contact)
+	// Get returns the contacts under key in table
+	Get(table, key string) []*Contact
+	// Each calls fn for every contact in table
+	Each(table string, fn func(*Contact))
+	// Update saves the changes made to a contact returned by Get
+	Update(c *Contact)
+}
+
+// MemoryIndexStore keeps the lookup tables in maps of contact pointers.
+// Changes to indexed contacts are seen by later lookups without Update.
+type MemoryIndexStore struct {
+	tables map[string]map[string][]*Contact
+}
+
+// NewMemoryIndexStore creates an empty in-memory store
+func NewMemoryIndexStore() *MemoryIndexStore {
+	return &MemoryIndexStore{tables: make(map[string]map[string][]*Contact)}
+}
+
+// Add implements IndexStore
+func (s *MemoryIndexStore) Add(table, key string, c *Contact) {
+	t, ok := s.tables[table]
+	if !ok {
+		t = make(map[string][]*Contact)
+		s.tables[table] = t
+	}
+	t[key] = append(t[key], c)
+}
+
+// Get implements IndexStore
+func (s *MemoryIndexStore) Get(table, key string) []*Contact {
+	return s.tables[table][key]
+}
+
+// Each implements IndexStore
+func (s *MemoryIndexStore) Each(table string, fn func(*Contact)) {
+	for _, contacts := range s.tables[table] {
+		for _, c := range contacts {
+			fn(c)
+		}
+	}
+}
+
+// Update implements IndexStore
+func (s *MemoryIndexStore) Update(c *Contact) {}
+
+// contactsBucket holds the indexed contacts as JSON by ID
+var contactsBucket = []byte("contacts")
+
+// DiskIndexStore keeps the lookup tables and the indexed contacts in a
+// temporary bbolt database, so only the contacts of a lookup are held in
+// memory. Lookups return new copies of the contacts; changes must be saved
+// with Update. The first error is kept and returned by Err and Close.
+type DiskIndexStore struct {
+	db   *bolt.DB
+	path string
+	err  error
+}
+
+// storedContact adds the fields left out of a contact's JSON
+type storedContact struct {
+	Contact
+	Snapshot string `json:"snapshot,omitempty"`
+}
+
+// NewDiskIndexStore creates a store in a temporary file in dir, or the
+// default temporary directory when dir is empty. Close removes the file.
+func NewDiskIndexStore(dir string) (*DiskIndexStore, error) {
+	f, err := os.CreateTemp(dir, "any-vcard-index-*.db")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create index file: %w", err)
+	}
+	f.Close()
+
+	// The index is rebuilt on every run, so it need not survive a crash
+	db, err := bolt.Open(f.Name(), 0o600, &bolt.Options{NoSync: true, NoFreelistSync: true})
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, fmt.Errorf("failed to open index file: %w", err)
+	}
+	return &DiskIndexStore{db: db, path: f.Name()}, nil
+}
+
+// Add implements IndexStore. The contact is stored, and given an ID, the
+// first time it is added.
+func (s *DiskIndexStore) Add(table, key string, c *Contact) {
+	s.fail(s.db.Update(func(tx *bolt.Tx) error {
+		if c.indexID == 0 {
+			contacts, err := tx.CreateBucketIfNotExists(contactsBucket)
+			if err != nil {
+				return err
+			}
+			id, err := contacts.NextSequence()
+			if err != nil {
+				return err
+			}
+			c.indexID = id
+			if err := putContact(contacts, c); err != nil {
+				return err
+			}
+		}
+
+		b, err := tx.CreateBucketIfNotExists([]byte(table))
+		if err != nil {
+			return err
+		}
+		ids := append(b.Get([]byte(key)), idKey(c.indexID)...)
+		return b.Put([]byte(key), ids)
+	}))
+}
+
+// Get implements IndexStore
+func (s *DiskIndexStore) Get(table, key string) []*Contact {
+	var found []*Contact
+	s.fail(s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(table))
+		if b == nil {
+			return nil
+		}
+		var err error
+		found, err = getContacts(tx, b.Get([]byte(key)))
+		return err
+	}))
+	return found
+}
+
+// Each implements IndexStore
+func (s *DiskIndexStore) Each(table string, fn func(*Contact)) {
+	// Collect the contacts first, fn may Add to the store
+	var ids []byte
+	s.fail(s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(table))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, v []byte) error {
+			ids = append(ids, v...)
+			return nil
+		})
+	}))
+	for len(ids) > 0 {
+		n := min(len(ids), 8*1024)
+		var contacts []*Contact
+		s.fail(s.db.View(func(tx *bolt.Tx) error {
+			var err error
+			contacts, err = getContacts(tx, ids[:n])
+			return err
+		}))
+		for _, c := range contacts {
+			fn(c)
+		}
+		ids = ids[n:]
+	}
+}
+
+// Update implements IndexStore
+func (s *DiskIndexStore) Update(c *Contact) {
+	if c.indexID == 0 {
+		return
+	}
+	s.fail(s.db.Update(func(tx *bolt.Tx) error {
+		return putContact(tx.Bucket(contactsBucket), c)
+	}))
+}
+
+// Err returns the first error the store ran into
+func (s *DiskIndexStore) Err() error {
+	return s.err
+}
+
+// Close closes and removes the database, returning the first error the
+// store ran into
+func (s *DiskIndexStore) Close() error {
+	if err := s.db.Close(); err != nil {
+		s.fail(err)
+	}
+	os.Remove(s.path)
+	return s.err
+}
+
+func (s *DiskIndexStore) fail(err error) {
+	if err != nil && s.err == nil {
+		s.err = fmt.Errorf("dedup index: %w", err)
+	}
+}
+
+func idKey(id uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, id)
+}
+
+func putContact(b *bolt.Bucket, c *Contact) error {
+	data, err := json.Marshal(storedContact{Contact: *c, Snapshot: c.Snapshot})
+	if err != nil {
+		return err
+	}
+	return b.Put(idKey(c.indexID), data)
+}
+
+// getContacts decodes the contacts of a list of IDs
+func getContacts(tx *bolt.Tx, ids []byte) ([]*Contact, error) {
+	b := tx.Bucket(contactsBucket)
+	var found []*Contact
+	for ; len(ids) >= 8; ids = ids[8:] {
+		var sc storedContact
+		if err := json.Unmarshal(b.Get(ids[:8]), &sc); err != nil {
+			return nil, err
+		}
+		c := sc.Contact
+		c.Snapshot = sc.Snapshot
+		c.indexID = binary.BigEndian.Uint64(ids[:8])
+		found = append(found, &c)
+	}
+	return found, nil
+}
@@ -0,0 +1,55 @@
+package vcard
+
+import (
+	"testing"
+)
+
+func TestDiskIndexStore(t *testing.T) {
+	store, err := NewDiskIndexStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	jane := &Contact{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}, Phones: []string{"+34 612 345 678"}}
+	idx := NewDedupIndexWith(store, DedupOptions{}, []*Contact{
+		jane,
+		{FormattedName: "Stephen King"},
+	})
+
+	// A phone and an email match return the contact once
+	matches := idx.FindDuplicates(&Contact{Emails: []string{"Jane@Example.com"}, Phones: []string{"612345678"}})
+	if len(matches) != 1 || matches[0].DisplayName() != "Jane Doe" {
+		t.Fatalf("FindDuplicates = %v, want Jane Doe once", matches)
+	}
+	// An indexed contact is not its own duplicate
+	if got := idx.FindDuplicates(jane); len(got) != 0 {
+		t.Errorf("FindDuplicates(indexed) = %v, want none", got)
+	}
+
+	// Changes are only seen once saved
+	matches[0].Title = "Engineer"
+	idx.Update(matches[0])
+	if got := idx.FindDuplicates(&Contact{Emails: []string{"jane@example.com"}}); len(got) != 1 || got[0].Title != "Engineer" {
+		t.Errorf("after Update got %v, want the new title", got)
+	}
+
+	idx.EnablePhonetic()
+	if got := idx.FindMatches(&Contact{FormattedName: "Steven King"}); len(got) != 1 {
+		t.Errorf("phonetic FindMatches = %v, want Stephen King", got)
+	}
+
+	if err := idx.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	// The index reports what the store ran into
+	idx.Add(&Contact{FormattedName: "John Doe"})
+	if idx.Err() == nil {
+		t.Error("Err() = nil after adding to a closed store")
+	}
+	if err := NewDedupIndex(nil).Err(); err != nil {
+		t.Errorf("Err() of an in-memory index = %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package vcard
+
+import "strings"
+
+// MailtoLink renders an email address as a Markdown mailto: link
+func MailtoLink(email string) string {
+	email = strings.TrimSpace(strings.TrimPrefix(email, "mailto:"))
+	if email == "" {
+		return ""
+	}
+	return "[" + email + "](mailto:" + email + ")"
+}
+
+// TelLink renders a phone number as a Markdown tel: link dialing its
+// digits, e.g. [+34 612 345 678](tel:+34612345678). Numbers without
+// digits are returned as they are.
+func TelLink(phone string) string {
+	phone = strings.TrimSpace(strings.TrimPrefix(phone, "tel:"))
+	number := phone
+	// Extensions (";ext=12", " x12") can't be dialed from the link
+	if i := strings.IndexAny(number, ";xX"); i != -1 {
+		number = number[:i]
+	}
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, number)
+	if digits == "" {
+		return phone
+	}
+	if strings.HasPrefix(number, "+") {
+		digits = "+" + digits
+	}
+	return "[" + phone + "](tel:" + digits + ")"
+}
@@ -0,0 +1,35 @@
+package vcard
+
+import "testing"
+
+func TestTelLink(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  string
+	}{
+		{"+34 612 345 678", "[+34 612 345 678](tel:+34612345678)"},
+		{"(555) 123-4567 x12", "[(555) 123-4567 x12](tel:5551234567)"},
+		{"tel:+44-20-7123-4567", "[+44-20-7123-4567](tel:+442071234567)"},
+		{"unknown", "unknown"},
+	}
+	for _, tt := range tests {
+		if got := TelLink(tt.phone); got != tt.want {
+			t.Errorf("TelLink(%q) = %q, want %q", tt.phone, got, tt.want)
+		}
+	}
+}
+
+func TestBuildLinkedNotes(t *testing.T) {
+	c := Contact{
+		Note:           "Met at FOSDEM",
+		Emails:         []string{"a@x", "b@x", "c@x", "d@x"},
+		OriginalPhones: []string{"612 345 678"},
+	}
+	want := "Met at FOSDEM\n\nAdditional emails: [d@x](mailto:d@x)\n\nOriginal phones: [612 345 678](tel:612345678)"
+	if got := BuildLinkedNotes(c); got != want {
+		t.Errorf("BuildLinkedNotes() = %q, want %q", got, want)
+	}
+	if got := BuildNotes(c); got != "Met at FOSDEM\n\nAdditional emails: d@x\n\nOriginal phones: 612 345 678" {
+		t.Errorf("BuildNotes() = %q", got)
+	}
+}
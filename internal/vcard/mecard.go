@@ -0,0 +1,170 @@
+package vcard
+
+import (
+	"fmt"
+	"strings"
+)
+
+const meCardPrefix = "MECARD:"
+
+// ParseMeCard parses a single MECARD string as encoded in QR codes:
+//
+//	MECARD:N:Doe,John;TEL:+15551234567;EMAIL:john@example.com;;
+//
+// Values use backslash escapes for \ ; : and ,.
+func ParseMeCard(s string) (Contact, error) {
+	s = strings.TrimSpace(s)
+	if !hasMeCardPrefix(s) {
+		return Contact{}, fmt.Errorf("not a MECARD: missing %q prefix", meCardPrefix)
+	}
+
+	var contact Contact
+	for _, field := range splitEscaped(s[len(meCardPrefix):], ';') {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(field, ":")
+		if !ok {
+			return Contact{}, fmt.Errorf("invalid MECARD field %q", field)
+		}
+
+		switch strings.ToUpper(key) {
+		case "N":
+			// Family name first; the given name follows a comma
+			parts := splitEscaped(value, ',')
+			contact.FamilyName = unescapeMeCard(parts[0])
+			if len(parts) > 1 {
+				contact.GivenName = unescapeMeCard(parts[1])
+			}
+		case "NICKNAME":
+			contact.Nickname = unescapeMeCard(value)
+		case "TEL", "TEL-AV":
+			contact.Phones = append(contact.Phones, unescapeMeCard(value))
+		case "EMAIL":
+			contact.Emails = append(contact.Emails, unescapeMeCard(value))
+		case "URL":
+			contact.URLs = append(contact.URLs, unescapeMeCard(value))
+		case "NOTE":
+			contact.Note = unescapeMeCard(value)
+		case "BDAY":
+			contact.Birthday = unescapeMeCard(value)
+		case "ORG":
+			contact.Organization = unescapeMeCard(value)
+		case "TITLE":
+			contact.Title = unescapeMeCard(value)
+		case "ROLE":
+			contact.Role = unescapeMeCard(value)
+		case "ADR":
+			contact.Addresses = append(contact.Addresses, parseMeCardAddress(value))
+		}
+	}
+
+	if contact.FamilyName != "" || contact.GivenName != "" {
+		contact.FormattedName = strings.TrimSpace(contact.GivenName + " " + contact.FamilyName)
+	}
+	return contact, nil
+}
+
+// ParseMeCards parses every MECARD found in data, such as a file with one
+// scanned QR payload per line. A card ends at its ;; terminator, or at the
+// next line starting a card when it has none.
+func ParseMeCards(data string) ([]Contact, error) {
+	var contacts []Contact
+	for i := 0; i < len(data); {
+		if !hasMeCardPrefix(data[i:]) {
+			i++
+			continue
+		}
+		end := meCardEnd(data, i+len(meCardPrefix))
+		contact, err := ParseMeCard(data[i:end])
+		if err != nil {
+			return contacts, err
+		}
+		contacts = append(contacts, contact)
+		i = end
+	}
+	return contacts, nil
+}
+
+// hasMeCardPrefix reports whether s starts with the MECARD: prefix, in any
+// case
+func hasMeCardPrefix(s string) bool {
+	return len(s) >= len(meCardPrefix) && strings.EqualFold(s[:len(meCardPrefix)], meCardPrefix)
+}
+
+// meCardEnd returns the end of the card whose fields start at start in
+// data: after its unescaped ;; terminator, before the next line starting a
+// card, or the end of data
+func meCardEnd(data string, start int) int {
+	for i := start; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case ';':
+			if i+1 < len(data) && data[i+1] == ';' {
+				return i + 2
+			}
+		case '\n':
+			if hasMeCardPrefix(strings.TrimLeft(data[i+1:], " \t\r")) {
+				return i
+			}
+		}
+	}
+	return len(data)
+}
+
+// parseMeCardAddress maps the comma-separated ADR components (PO box,
+// extended address, street, city, region, postal code, country). Addresses
+// without separators are kept as the street.
+func parseMeCardAddress(value string) Address {
+	parts := splitEscaped(value, ',')
+	for i := range parts {
+		parts[i] = strings.TrimSpace(unescapeMeCard(parts[i]))
+	}
+	if len(parts) < 7 {
+		street := strings.Join(filterEmpty(parts...), ", ")
+		return Address{Street: street, Full: street}
+	}
+
+	street := parts[2]
+	if street == "" {
+		street = parts[1]
+	}
+	return Address{
+		Street:     street,
+		City:       parts[3],
+		Region:     parts[4],
+		PostalCode: parts[5],
+		Country:    parts[6],
+		Full:       street,
+	}
+}
+
+// splitEscaped splits s on sep, ignoring separators escaped with a backslash.
+// Escapes are preserved in the returned parts.
+func splitEscaped(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func unescapeMeCard(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,87 @@
+package vcard
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseMeCard(t *testing.T) {
+	c, err := ParseMeCard(`MECARD:N:Doe,John;SOUND:doe,jon;TEL:+15551234567;TEL:03-1234-5678;EMAIL:john@example.com;NOTE:Met at\: the bar\; twice;BDAY:19900322;ADR:,,1-2-3 Chiyoda,Tokyo,,100-0001,Japan;URL:https\://example.com;NICKNAME:Johnny;;`)
+	if err != nil {
+		t.Fatalf("ParseMeCard() error = %v", err)
+	}
+
+	if c.FamilyName != "Doe" || c.GivenName != "John" || c.FormattedName != "John Doe" {
+		t.Errorf("unexpected names: %+v", c)
+	}
+	if len(c.Phones) != 2 || c.Phones[1] != "03-1234-5678" {
+		t.Errorf("Phones = %v", c.Phones)
+	}
+	if len(c.Emails) != 1 || c.Emails[0] != "john@example.com" {
+		t.Errorf("Emails = %v", c.Emails)
+	}
+	if c.Note != "Met at: the bar; twice" {
+		t.Errorf("Note = %q, want escapes resolved", c.Note)
+	}
+	if c.Birthday != "19900322" {
+		t.Errorf("Birthday = %q", c.Birthday)
+	}
+	if len(c.Addresses) != 1 || c.Addresses[0].Street != "1-2-3 Chiyoda" || c.Addresses[0].City != "Tokyo" || c.Addresses[0].Country != "Japan" {
+		t.Errorf("Addresses = %+v", c.Addresses)
+	}
+	if len(c.URLs) != 1 || c.URLs[0] != "https://example.com" || c.Nickname != "Johnny" {
+		t.Errorf("URLs = %v, Nickname = %q", c.URLs, c.Nickname)
+	}
+}
+
+func TestParseMeCard_Invalid(t *testing.T) {
+	for _, input := range []string{"", "BEGIN:VCARD", "MECARD:N"} {
+		if _, err := ParseMeCard(input); err == nil {
+			t.Errorf("ParseMeCard(%q) expected error", input)
+		}
+	}
+}
+
+func TestParseMeCards(t *testing.T) {
+	data := "MECARD:N:Doe,John;TEL:1;;\nMECARD:N:Smith,Jane;ADR:Main St 1, Springfield;;\n"
+	contacts, err := ParseMeCards(data)
+	if err != nil {
+		t.Fatalf("ParseMeCards() error = %v", err)
+	}
+	if len(contacts) != 2 {
+		t.Fatalf("expected 2 contacts, got %d", len(contacts))
+	}
+	if contacts[1].GivenName != "Jane" || contacts[1].Addresses[0].Street != "Main St 1, Springfield" {
+		t.Errorf("unexpected contact: %+v", contacts[1])
+	}
+}
+
+func TestParseMeCards_Terminators(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		names []string
+	}{
+		{"lowercase prefix", "mecard:N:Doe,John;;\nMeCard:N:Smith,Jane;;", []string{"John Doe", "Jane Smith"}},
+		{"several per line", "MECARD:N:Doe,John;;MECARD:N:Smith,Jane;;", []string{"John Doe", "Jane Smith"}},
+		{"prefix in a value", "MECARD:N:Doe,John;NOTE:see MECARD:N:Smith;;", []string{"John Doe"}},
+		{"escaped semicolons", "MECARD:N:Doe,John;NOTE:a\\;\\;b;;MECARD:N:Smith,Jane;;", []string{"John Doe", "Jane Smith"}},
+		{"missing terminator", "MECARD:N:Doe,John;\nMECARD:N:Smith,Jane;", []string{"John Doe", "Jane Smith"}},
+		{"text around cards", "scanned:\nMECARD:N:Doe,John;; end", []string{"John Doe"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contacts, err := ParseMeCards(tt.data)
+			if err != nil {
+				t.Fatalf("ParseMeCards() error = %v", err)
+			}
+			var names []string
+			for _, c := range contacts {
+				names = append(names, c.FormattedName)
+			}
+			if !slices.Equal(names, tt.names) {
+				t.Errorf("ParseMeCards() = %q, want %q", names, tt.names)
+			}
+		})
+	}
+}
@@ -0,0 +1,442 @@
+package vcard
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MergeStrategy decides which value wins when both contacts have a
+// different value for a single-valued field
+type MergeStrategy string
+
+const (
+	MergeFillEmpty    MergeStrategy = "fill-empty"    // Keep dst's values, only fill in missing ones
+	MergePreferSource MergeStrategy = "prefer-src"    // Incoming values win
+	MergePreferNewest MergeStrategy = "prefer-newest" // The most recently revised contact wins
+)
+
+// MergeStrategies lists the supported strategies
+var MergeStrategies = []MergeStrategy{MergeFillEmpty, MergePreferSource, MergePreferNewest}
+
+// FieldPolicy overrides the merge strategy for one field
+type FieldPolicy string
+
+const (
+	FieldFill    FieldPolicy = "fill"    // Only set the field when dst has none
+	FieldReplace FieldPolicy = "replace" // Incoming non-empty values always win
+	FieldKeep    FieldPolicy = "keep"    // Never change the field
+)
+
+// MergeFields lists the fields a FieldPolicy can apply to, named after
+// their Anytype properties
+var MergeFields = []string{
+	"name", "given_name", "family_name", "middle_name", "prefix", "suffix",
+	"nickname", "organization", "department", "title", "role", "assistant",
+	"manager", "birthday", "photo", "notes",
+}
+
+// DefaultProvenance is the header written above notes merged from a
+// contact with a known Source
+const DefaultProvenance = "merged from {source} on {date}"
+
+// MergeOptions controls how contacts are merged. Multi-valued fields
+// (emails, phones, addresses, URLs, groups) are always combined.
+type MergeOptions struct {
+	// Provenance is the header separating merged notes, with {source} and
+	// {date} replaced. Empty, or a source contact without Source, writes a
+	// bare "---" separator.
+	Provenance string
+	Now        time.Time              // Date of the merge, today when zero
+	Strategy   MergeStrategy          // MergeFillEmpty when empty
+	Fields     map[string]FieldPolicy // Per-field overrides of Strategy
+
+	OnConflict    func(Conflict) // Called for every value the merge discards
+	NoteConflicts bool           // Append the discarded values to the notes
+
+	// ThreeWay merges against dst's Snapshot of its last import: values
+	// unchanged in the source since then don't override edits made in
+	// Anytype, and values not edited in Anytype take the incoming change.
+	// Only fields both sides changed follow Strategy. dst's Snapshot is
+	// replaced by src's.
+	ThreeWay bool
+
+	// History records the date, src's Source and the fields a merge
+	// changed in dst's History
+	History bool
+
+	// AdoptUID sets dst's UID to src's when it has none, so later imports
+	// can match the contact by UID
+	AdoptUID bool
+
+	// Dedup is how emails and phones are compared to leave out the ones
+	// dst has
+	Dedup DedupOptions
+}
+
+// Conflict is a single-valued field both contacts had a different value
+// for, of which a merge kept one
+type Conflict struct {
+	Field     string `json:"field"`
+	Kept      string `json:"kept"`
+	Discarded string `json:"discarded"`
+}
+
+// String describes the conflict
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s: kept %q, discarded %q", c.Field, c.Kept, c.Discarded)
+}
+
+// sameValue reports whether two values of field only differ in case or,
+// for birthdays, in format
+func sameValue(field, a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	if field == "birthday" {
+		da, okA := ParseBirthdayDate(a)
+		db, okB := ParseBirthdayDate(b)
+		return okA && okB && da == db
+	}
+	return false
+}
+
+// ParseMergeStrategy validates a strategy name
+func ParseMergeStrategy(s string) (MergeStrategy, error) {
+	if s == "" {
+		return MergeFillEmpty, nil
+	}
+	if !slices.Contains(MergeStrategies, MergeStrategy(s)) {
+		return "", fmt.Errorf("unknown merge strategy %q (supported: fill-empty, prefer-src, prefer-newest)", s)
+	}
+	return MergeStrategy(s), nil
+}
+
+// ParseFieldPolicies parses "field=policy" overrides such as
+// "title=replace" or "birthday=fill"
+func ParseFieldPolicies(specs []string) (map[string]FieldPolicy, error) {
+	policies := make(map[string]FieldPolicy, len(specs))
+	for _, spec := range specs {
+		field, policy, ok := strings.Cut(spec, "=")
+		field, policy = strings.TrimSpace(field), strings.TrimSpace(policy)
+		if !ok {
+			return nil, fmt.Errorf("invalid merge field %q (expected field=policy)", spec)
+		}
+		if !slices.Contains(MergeFields, field) {
+			return nil, fmt.Errorf("unknown merge field %q (fields: %s)", field, strings.Join(MergeFields, ", "))
+		}
+		switch p := FieldPolicy(policy); p {
+		case FieldFill, FieldReplace, FieldKeep:
+			policies[field] = p
+		default:
+			return nil, fmt.Errorf("unknown policy %q for %s (supported: fill, replace, keep)", policy, field)
+		}
+	}
+	return policies, nil
+}
+
+// policy returns how field is merged from src into dst
+func (o MergeOptions) policy(field string, dst, src *Contact) FieldPolicy {
+	if p, ok := o.Fields[field]; ok {
+		return p
+	}
+	switch o.Strategy {
+	case MergePreferSource:
+		return FieldReplace
+	case MergePreferNewest:
+		if o.isNewer(src, dst) {
+			return FieldReplace
+		}
+	}
+	return FieldFill
+}
+
+// isNewer reports whether src was revised after dst. Contacts without a
+// REV count as revised at the time of the merge; objects without a
+// modification time as older than anything.
+func (o MergeOptions) isNewer(src, dst *Contact) bool {
+	srcTime, ok := ParseRevision(src.Revision)
+	if !ok {
+		srcTime = o.now()
+	}
+	dstTime, ok := ParseRevision(dst.LastModified)
+	if !ok {
+		dstTime, ok = ParseRevision(dst.Revision)
+	}
+	return !ok || srcTime.After(dstTime)
+}
+
+func (o MergeOptions) now() time.Time {
+	if o.Now.IsZero() {
+		return time.Now()
+	}
+	return o.Now
+}
+
+// ParseRevision parses a vCard REV timestamp (20240601T120000Z) or an
+// RFC 3339 time
+func ParseRevision(s string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, "20060102T150405Z", "20060102T150405Z0700", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// noteSeparator returns the text placed between dst's note and the note
+// merged from src
+func (o MergeOptions) noteSeparator(src *Contact) string {
+	if o.Provenance == "" || src.Source == "" {
+		return "\n\n---\n\n"
+	}
+	header := strings.NewReplacer("{source}", src.Source, "{date}", o.now().Format("2006-01-02")).Replace(o.Provenance)
+	return "\n\n--- " + header + " ---\n\n"
+}
+
+// containsNote reports whether note is part of notes, ignoring case and
+// differences in whitespace
+func containsNote(notes, note string) bool {
+	normalize := func(s string) string { return strings.ToLower(strings.Join(strings.Fields(s), " ")) }
+	return strings.Contains(normalize(notes), normalize(note))
+}
+
+// photoLabel describes a photo in conflicts: links as they are, embedded
+// images by size
+func photoLabel(photo string) string {
+	if photo == "" || IsPhotoURL(photo) {
+		return photo
+	}
+	return fmt.Sprintf("embedded image (%d bytes)", len(photo))
+}
+
+// MergeContacts merges missing fields from src into dst.
+// Prefers existing values in dst (only fills in missing data).
+// Returns true if any fields were merged.
+func MergeContacts(dst, src *Contact) bool {
+	return MergeContactsWith(dst, src, MergeOptions{Provenance: DefaultProvenance})
+}
+
+// MergeContactsWith merges src into dst following opts. Returns true if
+// any fields were merged.
+func MergeContactsWith(dst, src *Contact, opts MergeOptions) bool {
+	merged := false
+	before := *dst
+
+	// Without a snapshot every field counts as changed on both sides
+	base, threeWay := &Contact{}, false
+	if opts.ThreeWay {
+		if b, ok := ParseSnapshot(dst.Snapshot); ok {
+			base, threeWay = b, true
+		}
+	}
+
+	var conflicts []Conflict
+	conflict := func(field, kept, discarded string) {
+		if field == "photo" {
+			// Embedded images would fill reports and notes with base64
+			kept, discarded = photoLabel(kept), photoLabel(discarded)
+		}
+		c := Conflict{Field: field, Kept: kept, Discarded: discarded}
+		conflicts = append(conflicts, c)
+		if opts.OnConflict != nil {
+			opts.OnConflict(c)
+		}
+	}
+
+	// Single-valued fields follow the strategy and the field overrides
+	scalar := func(field string, value *string, incoming, previous string) {
+		if incoming == "" || *value == incoming {
+			return
+		}
+		if _, override := opts.Fields[field]; threeWay && !override && field != "photo" {
+			switch {
+			case sameValue(field, incoming, previous):
+				return // Unchanged in the source, keep any edit made since
+			case *value == previous || sameValue(field, *value, previous):
+				*value = incoming
+				merged = true
+				return
+			}
+		}
+		differs := *value != "" && !sameValue(field, *value, incoming)
+		switch opts.policy(field, dst, src) {
+		case FieldKeep:
+			if differs {
+				conflict(field, *value, incoming)
+			}
+			return
+		case FieldFill:
+			if *value != "" {
+				if differs {
+					conflict(field, *value, incoming)
+				}
+				return
+			}
+		}
+		if differs {
+			conflict(field, incoming, *value)
+		}
+		*value = incoming
+		merged = true
+	}
+
+	scalar("name", &dst.FormattedName, src.FormattedName, base.FormattedName)
+	scalar("given_name", &dst.GivenName, src.GivenName, base.GivenName)
+	scalar("family_name", &dst.FamilyName, src.FamilyName, base.FamilyName)
+	scalar("middle_name", &dst.MiddleName, src.MiddleName, base.MiddleName)
+	scalar("prefix", &dst.Prefix, src.Prefix, base.Prefix)
+	scalar("suffix", &dst.Suffix, src.Suffix, base.Suffix)
+	scalar("nickname", &dst.Nickname, src.Nickname, base.Nickname)
+
+	// Merge unique emails. With a snapshot, values it already had were
+	// imported before, and are missing from dst because they were removed.
+	existingEmails := make(map[string]struct{})
+	for _, e := range slices.Concat(dst.Emails, base.Emails) {
+		existingEmails[opts.Dedup.Email.Normalize(e)] = struct{}{}
+	}
+	for _, e := range src.Emails {
+		key := opts.Dedup.Email.Normalize(e)
+		if _, exists := existingEmails[key]; !exists && key != "" {
+			dst.Emails = append(dst.Emails, e)
+			existingEmails[key] = struct{}{}
+			merged = true
+		}
+	}
+
+	// Merge unique phones
+	existingPhones := slices.Concat(dst.Phones, base.Phones)
+	for _, p := range src.Phones {
+		exists := slices.ContainsFunc(existingPhones, func(e string) bool { return opts.Dedup.SamePhone(e, p) })
+		if !exists && opts.Dedup.NormalizePhone(p) != "" {
+			dst.Phones = append(dst.Phones, p)
+			existingPhones = append(existingPhones, p)
+			merged = true
+		}
+	}
+
+	// Merge unique addresses
+	existingAddrs := make(map[string]struct{})
+	for _, a := range slices.Concat(dst.Addresses, base.Addresses) {
+		existingAddrs[normalizeAddress(a)] = struct{}{}
+	}
+	for _, a := range src.Addresses {
+		key := normalizeAddress(a)
+		if _, exists := existingAddrs[key]; !exists && key != "" {
+			dst.Addresses = append(dst.Addresses, a)
+			existingAddrs[key] = struct{}{}
+			merged = true
+		}
+	}
+
+	scalar("organization", &dst.Organization, src.Organization, base.Organization)
+	scalar("department", &dst.Department, src.Department, base.Department)
+	scalar("title", &dst.Title, src.Title, base.Title)
+	scalar("role", &dst.Role, src.Role, base.Role)
+	scalar("assistant", &dst.Assistant, src.Assistant, base.Assistant)
+	scalar("manager", &dst.Manager, src.Manager, base.Manager)
+
+	// Merge unique URLs
+	existingURLs := make(map[string]struct{})
+	for _, u := range slices.Concat(dst.URLs, base.URLs) {
+		existingURLs[strings.ToLower(u)] = struct{}{}
+	}
+	for _, u := range src.URLs {
+		key := strings.ToLower(u)
+		if _, exists := existingURLs[key]; !exists && key != "" {
+			dst.URLs = append(dst.URLs, u)
+			existingURLs[key] = struct{}{}
+			merged = true
+		}
+	}
+
+	// Notes are appended unless overridden by a field policy or already
+	// there, so repeated merges don't stack copies of the same note
+	if src.Note != "" && dst.Note != src.Note && (!threeWay || src.Note != base.Note) {
+		switch policy, ok := opts.Fields["notes"]; {
+		case ok && policy == FieldKeep, ok && policy == FieldFill && dst.Note != "":
+		case ok && policy == FieldReplace, dst.Note == "":
+			dst.Note = src.Note
+			merged = true
+		case containsNote(dst.Note, src.Note):
+		default:
+			dst.Note += opts.noteSeparator(src) + src.Note
+			merged = true
+		}
+	}
+
+	scalar("birthday", &dst.Birthday, src.Birthday, base.Birthday)
+	scalar("photo", &dst.Photo, src.Photo, base.Photo)
+
+	// Merge unique categories
+	existingCategories := make(map[string]struct{})
+	for _, cat := range slices.Concat(dst.Categories, base.Categories) {
+		existingCategories[strings.ToLower(cat)] = struct{}{}
+	}
+	for _, cat := range src.Categories {
+		key := strings.ToLower(cat)
+		if _, exists := existingCategories[key]; !exists && key != "" {
+			dst.Categories = append(dst.Categories, cat)
+			existingCategories[key] = struct{}{}
+			merged = true
+		}
+	}
+
+	if !dst.Favorite && src.Favorite && !base.Favorite {
+		dst.Favorite = true
+		merged = true
+	}
+
+	if opts.NoteConflicts && len(conflicts) > 0 {
+		lines := []string{"Discarded on merge:"}
+		for _, c := range conflicts {
+			if c.Field == "photo" {
+				lines = append(lines, "- photo replaced")
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("- %s: %s", c.Field, c.Discarded))
+		}
+		// Merging the same contacts again mustn't repeat the block
+		if block := strings.Join(lines, "\n"); !containsNote(dst.Note, block) {
+			if dst.Note != "" {
+				dst.Note += "\n\n"
+			}
+			dst.Note += block
+			merged = true
+		}
+	}
+
+	// Values dst took from an enriched src keep their provenance
+	var enriched []Enrichment
+	for _, e := range src.Enriched {
+		if dst.enrichedValue(e.Field) == e.Value && !strings.Contains(dst.Note, e.String()) {
+			enriched = append(enriched, e)
+		}
+	}
+	if len(enriched) > 0 {
+		if dst.Note != "" {
+			dst.Note += "\n\n"
+		}
+		dst.Note += enrichedNotes(enriched)
+		merged = true
+	}
+
+	if opts.AdoptUID && dst.UID == "" && src.UID != "" {
+		dst.UID = src.UID
+		merged = true
+	}
+
+	if opts.ThreeWay {
+		if snapshot, err := EncodeSnapshot(*src); err == nil && snapshot != dst.Snapshot {
+			dst.Snapshot = snapshot
+			merged = true
+		}
+	}
+
+	if opts.History && merged {
+		dst.RecordHistory(opts.now(), src.Source, ChangedFields(&before, dst))
+	}
+
+	return merged
+}
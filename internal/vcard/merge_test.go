@@ -0,0 +1,277 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMergeContactsWith_Strategies(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	existing := func() *Contact {
+		return &Contact{
+			FormattedName: "John Doe",
+			Organization:  "Old Corp",
+			Title:         "Engineer",
+			Birthday:      "1980-01-01",
+			LastModified:  "2024-01-01T00:00:00Z",
+		}
+	}
+
+	tests := []struct {
+		name      string
+		opts      MergeOptions
+		src       Contact
+		wantOrg   string
+		wantTitle string
+		wantBday  string
+	}{
+		{
+			name:      "fill empty keeps dst",
+			opts:      MergeOptions{Now: now},
+			src:       Contact{Organization: "New Corp", Title: "Manager", Birthday: "1980-02-02"},
+			wantOrg:   "Old Corp",
+			wantTitle: "Engineer",
+			wantBday:  "1980-01-01",
+		},
+		{
+			name:      "prefer src",
+			opts:      MergeOptions{Now: now, Strategy: MergePreferSource},
+			src:       Contact{Organization: "New Corp", Title: "Manager"},
+			wantOrg:   "New Corp",
+			wantTitle: "Manager",
+			wantBday:  "1980-01-01", // Empty incoming values never clear a field
+		},
+		{
+			name:      "prefer newest with newer REV",
+			opts:      MergeOptions{Now: now, Strategy: MergePreferNewest},
+			src:       Contact{Organization: "New Corp", Revision: "20240301T100000Z"},
+			wantOrg:   "New Corp",
+			wantTitle: "Engineer",
+			wantBday:  "1980-01-01",
+		},
+		{
+			name:      "prefer newest with older REV",
+			opts:      MergeOptions{Now: now, Strategy: MergePreferNewest},
+			src:       Contact{Organization: "New Corp", Revision: "20231201T100000Z"},
+			wantOrg:   "Old Corp",
+			wantTitle: "Engineer",
+			wantBday:  "1980-01-01",
+		},
+		{
+			name: "field overrides",
+			opts: MergeOptions{Now: now, Strategy: MergePreferSource, Fields: map[string]FieldPolicy{
+				"title":    FieldKeep,
+				"birthday": FieldFill,
+			}},
+			src:       Contact{Organization: "New Corp", Title: "Manager", Birthday: "1980-02-02"},
+			wantOrg:   "New Corp",
+			wantTitle: "Engineer",
+			wantBday:  "1980-01-01",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := existing()
+			MergeContactsWith(dst, &tt.src, tt.opts)
+			if dst.Organization != tt.wantOrg || dst.Title != tt.wantTitle || dst.Birthday != tt.wantBday {
+				t.Errorf("got org=%q title=%q birthday=%q, want %q %q %q",
+					dst.Organization, dst.Title, dst.Birthday, tt.wantOrg, tt.wantTitle, tt.wantBday)
+			}
+		})
+	}
+}
+
+func TestParseFieldPolicies(t *testing.T) {
+	got, err := ParseFieldPolicies([]string{"title=replace", " birthday = fill "})
+	if err != nil {
+		t.Fatalf("ParseFieldPolicies() error = %v", err)
+	}
+	if got["title"] != FieldReplace || got["birthday"] != FieldFill {
+		t.Errorf("ParseFieldPolicies() = %v", got)
+	}
+
+	for _, spec := range []string{"title", "shoe_size=replace", "title=sometimes"} {
+		if _, err := ParseFieldPolicies([]string{spec}); err == nil {
+			t.Errorf("ParseFieldPolicies(%q) expected an error", spec)
+		}
+	}
+}
+
+func TestMergeContactsWith_Conflicts(t *testing.T) {
+	dst := &Contact{FormattedName: "John Doe", Organization: "Acme", Birthday: "1980-01-02", Title: "CTO"}
+	src := &Contact{FormattedName: "john doe", Organization: "Globex", Birthday: "19800102", Title: "CEO"}
+
+	var got []Conflict
+	MergeContactsWith(dst, src, MergeOptions{
+		Fields:        map[string]FieldPolicy{"title": FieldReplace},
+		OnConflict:    func(c Conflict) { got = append(got, c) },
+		NoteConflicts: true,
+	})
+
+	// Names differing in case and birthdays in format are not conflicts
+	want := []Conflict{
+		{Field: "organization", Kept: "Acme", Discarded: "Globex"},
+		{Field: "title", Kept: "CEO", Discarded: "CTO"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("conflicts = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("conflict %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if wantNote := "Discarded on merge:\n- organization: Globex\n- title: CTO"; dst.Note != wantNote {
+		t.Errorf("Note = %q, want %q", dst.Note, wantNote)
+	}
+
+	// Merging the same contact again doesn't repeat the block
+	MergeContactsWith(dst, src, MergeOptions{
+		Fields:        map[string]FieldPolicy{"title": FieldReplace},
+		NoteConflicts: true,
+	})
+	if strings.Count(dst.Note, "Discarded on merge:") != 1 {
+		t.Errorf("Note after a second merge = %q", dst.Note)
+	}
+}
+
+func TestMergeContactsWith_PhotoConflict(t *testing.T) {
+	embedded := strings.Repeat("A", 4000)
+	dst := &Contact{FormattedName: "Jane", Photo: embedded}
+	src := &Contact{FormattedName: "Jane", Photo: "https://example.com/jane.jpg"}
+
+	var got []Conflict
+	MergeContactsWith(dst, src, MergeOptions{
+		Fields:        map[string]FieldPolicy{"photo": FieldReplace},
+		OnConflict:    func(c Conflict) { got = append(got, c) },
+		NoteConflicts: true,
+	})
+	want := Conflict{Field: "photo", Kept: "https://example.com/jane.jpg", Discarded: "embedded image (4000 bytes)"}
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("conflicts = %v, want %v", got, want)
+	}
+	if wantNote := "Discarded on merge:\n- photo replaced"; dst.Note != wantNote {
+		t.Errorf("Note = %q, want %q", dst.Note, wantNote)
+	}
+}
+
+func TestMergeContactsWith_ThreeWay(t *testing.T) {
+	imported := Contact{
+		FormattedName: "Jane Doe",
+		Organization:  "Acme",
+		Title:         "Engineer",
+		Emails:        []string{"jane@acme.com", "jane@old.com"},
+	}
+	snapshot, err := EncodeSnapshot(imported)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Edited in Anytype since: new title, one email removed
+	dst := &Contact{
+		FormattedName: "Jane Doe",
+		Organization:  "Acme",
+		Title:         "Staff Engineer",
+		Emails:        []string{"jane@acme.com"},
+		Snapshot:      snapshot,
+	}
+	// Changed in the source since: new organization
+	src := Contact{
+		FormattedName: "Jane Doe",
+		Organization:  "Globex",
+		Title:         "Engineer",
+		Emails:        []string{"jane@acme.com", "jane@old.com"},
+	}
+
+	if !MergeContactsWith(dst, &src, MergeOptions{ThreeWay: true}) {
+		t.Fatal("expected a merge")
+	}
+	if dst.Organization != "Globex" {
+		t.Errorf("Organization = %q, want the source change", dst.Organization)
+	}
+	if dst.Title != "Staff Engineer" {
+		t.Errorf("Title = %q, want the Anytype edit kept", dst.Title)
+	}
+	if len(dst.Emails) != 1 {
+		t.Errorf("Emails = %v, want the removed email to stay removed", dst.Emails)
+	}
+	if want, _ := EncodeSnapshot(src); dst.Snapshot != want {
+		t.Errorf("Snapshot not replaced by the incoming card:\n%s", dst.Snapshot)
+	}
+
+	// Without a snapshot, the strategy decides as usual
+	dst = &Contact{FormattedName: "Jane Doe", Organization: "Acme", Title: "Staff Engineer"}
+	MergeContactsWith(dst, &src, MergeOptions{ThreeWay: true})
+	if dst.Organization != "Acme" || dst.Title != "Staff Engineer" {
+		t.Errorf("got %q, %q; want dst kept", dst.Organization, dst.Title)
+	}
+}
+
+func TestMergeContactsWith_AdoptUID(t *testing.T) {
+	src := &Contact{FormattedName: "Jane Smith", UID: "urn:uuid:1"}
+
+	dst := &Contact{FormattedName: "Jane Smith"}
+	if MergeContactsWith(dst, src, MergeOptions{}) || dst.UID != "" {
+		t.Errorf("UID adopted without AdoptUID: %q", dst.UID)
+	}
+	if !MergeContactsWith(dst, src, MergeOptions{AdoptUID: true}) || dst.UID != "urn:uuid:1" {
+		t.Errorf("UID = %q, want urn:uuid:1", dst.UID)
+	}
+
+	// An existing UID is kept
+	dst = &Contact{FormattedName: "Jane Smith", UID: "urn:uuid:2"}
+	if MergeContactsWith(dst, src, MergeOptions{AdoptUID: true}) || dst.UID != "urn:uuid:2" {
+		t.Errorf("UID = %q, want urn:uuid:2", dst.UID)
+	}
+}
+
+func TestMergeContacts_RepeatedNotes(t *testing.T) {
+	tests := []struct {
+		name string
+		dst  string
+		src  string
+		want string
+	}{
+		{"same note", "Met at FOSDEM", "Met at FOSDEM", "Met at FOSDEM"},
+		{"already merged", "Old\n\n---\n\nMet at FOSDEM", "Met at FOSDEM", "Old\n\n---\n\nMet at FOSDEM"},
+		{"whitespace and case", "Old\n\n---\n\nMet at\n  FOSDEM ", "met at fosdem", "Old\n\n---\n\nMet at\n  FOSDEM "},
+		{"new note", "Old", "Met at FOSDEM", "Old\n\n---\n\nMet at FOSDEM"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := &Contact{FormattedName: "Jane Smith", Note: tt.dst}
+			src := &Contact{FormattedName: "Jane Smith", Note: tt.src}
+			// Merging again must not change anything
+			for range 3 {
+				MergeContactsWith(dst, src, MergeOptions{})
+			}
+			if dst.Note != tt.want {
+				t.Errorf("Note = %q, want %q", dst.Note, tt.want)
+			}
+			if MergeContacts(dst, src) {
+				t.Error("MergeContacts() = true after merging the same note again")
+			}
+		})
+	}
+}
+
+func TestMergeContacts_EnrichedProvenance(t *testing.T) {
+	dst := &Contact{FormattedName: "Jane Smith", Organization: "Acme", Note: "Met at FOSDEM"}
+	src := &Contact{
+		FormattedName: "Jane Smith",
+		Organization:  "Acme Inc.",
+		Title:         "CTO",
+		Enriched: []Enrichment{
+			{Field: "organization", Value: "Acme Inc.", Origin: "WHOIS acme.com"},
+			{Field: "title", Value: "CTO", Origin: "Gravatar jane@acme.com"},
+		},
+	}
+	for range 2 {
+		MergeContacts(dst, src)
+	}
+	want := "Met at FOSDEM\n\nEnriched from public sources:\n- title: CTO (Gravatar jane@acme.com)"
+	if dst.Note != want {
+		t.Errorf("Note = %q, want %q", dst.Note, want)
+	}
+}
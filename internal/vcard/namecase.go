@@ -0,0 +1,88 @@
+package vcard
+
+import (
+	"strings"
+	"unicode"
+)
+
+// nameParticles are lowercase connectors in surnames (van der Berg, de la Cruz)
+var nameParticles = map[string]struct{}{
+	"van": {}, "von": {}, "der": {}, "den": {}, "de": {}, "del": {}, "della": {},
+	"di": {}, "da": {}, "das": {}, "dos": {}, "du": {}, "la": {}, "le": {},
+	"y": {}, "e": {}, "ter": {}, "ten": {}, "bin": {}, "al": {},
+}
+
+// romanNumerals are generational suffixes kept uppercase (John Doe III)
+var romanNumerals = map[string]struct{}{
+	"ii": {}, "iii": {}, "iv": {}, "v": {}, "vi": {}, "vii": {}, "viii": {}, "ix": {},
+}
+
+// FixNameCase title-cases the name fields of a contact that were written
+// entirely in upper or lower case. Mixed-case values are left untouched.
+func (c *Contact) FixNameCase() {
+	c.FormattedName = FixNameCase(c.FormattedName)
+	c.GivenName = FixNameCase(c.GivenName)
+	c.FamilyName = FixNameCase(c.FamilyName)
+	c.MiddleName = FixNameCase(c.MiddleName)
+	c.Prefix = FixNameCase(c.Prefix)
+	c.Suffix = FixNameCase(c.Suffix)
+}
+
+// FixNameCase title-cases a personal name written in ALL CAPS or all lowercase.
+// Handles: Mc/O' prefixes, hyphenated names, particles (van, de), roman
+// numerals. Particles are only lowercase between other words: a name
+// starting or ending with one (De, Van) is capitalized.
+func FixNameCase(name string) string {
+	if !isSingleCase(name) {
+		return name
+	}
+
+	words := strings.Fields(strings.ToLower(name))
+	for i, w := range words {
+		if _, ok := nameParticles[w]; ok && i > 0 && i < len(words)-1 {
+			continue
+		}
+		if _, ok := romanNumerals[strings.TrimSuffix(w, ".")]; ok && i > 0 {
+			words[i] = strings.ToUpper(w)
+			continue
+		}
+		words[i] = titleNamePart(w)
+	}
+	return strings.Join(words, " ")
+}
+
+// isSingleCase reports whether every letter in s has the same case
+func isSingleCase(s string) bool {
+	hasUpper, hasLower := false, false
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			hasUpper = true
+		} else if unicode.IsLower(r) {
+			hasLower = true
+		}
+	}
+	return hasUpper != hasLower
+}
+
+// titleNamePart capitalizes a single lowercase word, including every
+// segment after a hyphen or apostrophe (smith-jones, o'connor)
+func titleNamePart(w string) string {
+	runes := []rune(w)
+	upperNext := true
+	for i, r := range runes {
+		if upperNext && unicode.IsLetter(r) {
+			runes[i] = unicode.ToUpper(r)
+			upperNext = false
+			continue
+		}
+		if r == '-' || r == '\'' || r == '’' {
+			upperNext = true
+		}
+	}
+
+	// Mc prefix: mcdonald → McDonald
+	if len(runes) > 3 && runes[0] == 'M' && runes[1] == 'c' && unicode.IsLetter(runes[2]) {
+		runes[2] = unicode.ToUpper(runes[2])
+	}
+	return string(runes)
+}
@@ -0,0 +1,75 @@
+package vcard
+
+import (
+	"testing"
+)
+
+func TestFixNameCase(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		// Basic casing
+		{"all caps", "JOHN DOE", "John Doe"},
+		{"all lowercase", "john doe", "John Doe"},
+		{"mixed case untouched", "John DOE", "John DOE"},
+		{"already correct", "John Doe", "John Doe"},
+		{"collapse whitespace", "JOHN   DOE", "John Doe"},
+
+		// Prefixes and apostrophes
+		{"Mc prefix", "RONALD MCDONALD", "Ronald McDonald"},
+		{"O' prefix", "sean o'connor", "Sean O'Connor"},
+		{"D' prefix", "MARIO D'ANGELO", "Mario D'Angelo"},
+		{"Mc alone", "mc", "Mc"},
+
+		// Hyphens and particles
+		{"hyphenated", "ANNE SMITH-JONES", "Anne Smith-Jones"},
+		{"van der", "pieter van der berg", "Pieter van der Berg"},
+		{"de la", "MARIA DE LA CRUZ", "Maria de la Cruz"},
+		{"leading particle", "van halen", "Van Halen"},
+		{"trailing particle", "JOHN DE", "John De"},
+		{"particle alone", "van", "Van"},
+
+		// Suffixes and accents
+		{"roman numeral", "JOHN DOE III", "John Doe III"},
+		{"roman numeral V", "henry doe v", "Henry Doe V"},
+		{"roman numeral IX", "LOUIS CAPET IX", "Louis Capet IX"},
+		{"accented", "JOSÉ GARCÍA", "José García"},
+		{"prefix with dot", "DR.", "Dr."},
+
+		// Edge cases
+		{"empty", "", ""},
+		{"no letters", "123", "123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FixNameCase(tt.input)
+			if got != tt.expected {
+				t.Errorf("FixNameCase(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestContact_FixNameCase(t *testing.T) {
+	c := Contact{
+		FormattedName: "JOHN MCDONALD",
+		GivenName:     "JOHN",
+		FamilyName:    "MCDONALD",
+		Prefix:        "mr.",
+		Organization:  "ACME",
+	}
+	c.FixNameCase()
+
+	if c.FormattedName != "John McDonald" {
+		t.Errorf("FormattedName = %q, want %q", c.FormattedName, "John McDonald")
+	}
+	if c.GivenName != "John" || c.FamilyName != "McDonald" || c.Prefix != "Mr." {
+		t.Errorf("N components not fixed: %q %q %q", c.Prefix, c.GivenName, c.FamilyName)
+	}
+	if c.Organization != "ACME" {
+		t.Errorf("Organization should be untouched, got %q", c.Organization)
+	}
+}
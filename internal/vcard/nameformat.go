@@ -0,0 +1,43 @@
+package vcard
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// NameFormatPresets are the named display-name formats
+var NameFormatPresets = map[string]string{
+	"first-last": "{{.Prefix}} {{.GivenName}} {{.MiddleName}} {{.FamilyName}} {{.Suffix}}",
+	"last-first": "{{.FamilyName}}, {{.GivenName}} {{.MiddleName}}",
+}
+
+// ParseNameFormat parses a display-name format: a preset name from
+// NameFormatPresets or a Go template executed with the Contact
+func ParseNameFormat(format string) (*template.Template, error) {
+	if preset, ok := NameFormatPresets[format]; ok {
+		format = preset
+	}
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name format: %w", err)
+	}
+	return tmpl, nil
+}
+
+// FormatName renders the contact name with a name format. Repeated spaces
+// and dangling commas left by empty fields are removed; contacts the format
+// renders empty fall back to DisplayName.
+func FormatName(tmpl *template.Template, c Contact) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, c); err != nil {
+		return "", fmt.Errorf("failed to format name of %s: %w", c.DisplayName(), err)
+	}
+	name := strings.Join(strings.Fields(b.String()), " ")
+	name = strings.ReplaceAll(name, " ,", ",")
+	name = strings.Trim(name, ", ")
+	if name == "" {
+		return c.DisplayName(), nil
+	}
+	return name, nil
+}
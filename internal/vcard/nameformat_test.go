@@ -0,0 +1,44 @@
+package vcard
+
+import "testing"
+
+func TestFormatName(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		contact Contact
+		want    string
+	}{
+		{"last-first", "last-first", Contact{GivenName: "Jane", FamilyName: "Doe"}, "Doe, Jane"},
+		{"last-first with middle", "last-first", Contact{GivenName: "Jane", MiddleName: "Q", FamilyName: "Doe"}, "Doe, Jane Q"},
+		{"last-first family only", "last-first", Contact{FamilyName: "Doe"}, "Doe"},
+		{"last-first given only", "last-first", Contact{GivenName: "Jane"}, "Jane"},
+		{"first-last", "first-last", Contact{Prefix: "Dr.", GivenName: "Jane", FamilyName: "Doe"}, "Dr. Jane Doe"},
+		{"first-last ignores FN", "first-last", Contact{FormattedName: "JD", GivenName: "Jane", FamilyName: "Doe"}, "Jane Doe"},
+		{"falls back to display name", "last-first", Contact{FormattedName: "Acme Support"}, "Acme Support"},
+		{"falls back to organization", "last-first", Contact{Organization: "Acme"}, "Acme"},
+		{"custom template", "{{.FamilyName | printf \"%.1s\"}}. {{.GivenName}}", Contact{GivenName: "Jane", FamilyName: "Doe"}, "D. Jane"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseNameFormat(tt.format)
+			if err != nil {
+				t.Fatalf("ParseNameFormat() error = %v", err)
+			}
+			got, err := FormatName(tmpl, tt.contact)
+			if err != nil {
+				t.Fatalf("FormatName() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNameFormat_Invalid(t *testing.T) {
+	if _, err := ParseNameFormat("{{.FamilyName"); err == nil {
+		t.Error("ParseNameFormat() error = nil, want parse error")
+	}
+}
@@ -0,0 +1,59 @@
+package vcard
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// notesFuncs are available to notes templates in addition to the builtins
+var notesFuncs = template.FuncMap{
+	"join": strings.Join,
+	// rest returns the values after the first n, e.g. the emails that do not
+	// fit in the email properties
+	"rest": func(n int, values []string) []string {
+		if n >= len(values) {
+			return nil
+		}
+		return values[n:]
+	},
+	"mailto": MailtoLink,
+	"tel":    TelLink,
+}
+
+// ParseNotesTemplate parses a Go template rendering the notes property from
+// a Contact. Besides the builtins it provides join, rest, and mailto and
+// tel rendering Markdown links:
+//
+//	{{.Note}}
+//	{{with rest 3 .Emails}}More emails: {{join . ", "}}{{end}}
+//	{{range .Phones}}{{tel .}} {{end}}
+func ParseNotesTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("notes").Funcs(notesFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notes template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// RenderNotes executes a notes template for the contact, trimming
+// surrounding whitespace
+func RenderNotes(tmpl *template.Template, contact Contact) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, contact); err != nil {
+		return "", fmt.Errorf("failed to render notes for %s: %w", contact.DisplayName(), err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// SetProperty replaces the property with the given key, or appends it
+func SetProperty(props []map[string]any, key string, value map[string]any) []map[string]any {
+	value["key"] = key
+	for i, p := range props {
+		if p["key"] == key {
+			props[i] = value
+			return props
+		}
+	}
+	return append(props, value)
+}
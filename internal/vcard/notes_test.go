@@ -0,0 +1,72 @@
+package vcard
+
+import "testing"
+
+func TestRenderNotes(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		contact  Contact
+		want     string
+	}{
+		{
+			name:     "overflow emails",
+			template: "{{.Note}}\n{{with rest 3 .Emails}}More emails: {{join . \", \"}}{{end}}",
+			contact:  Contact{Note: "Met at FOSDEM", Emails: []string{"a@x", "b@x", "c@x", "d@x", "e@x"}},
+			want:     "Met at FOSDEM\nMore emails: d@x, e@x",
+		},
+		{
+			name:     "no overflow",
+			template: "{{with rest 3 .Emails}}More emails: {{join . \", \"}}{{end}}",
+			contact:  Contact{Emails: []string{"a@x"}},
+			want:     "",
+		},
+		{
+			name:     "fields",
+			template: "{{.Organization}} / {{.Title}}\n{{range .Categories}}#{{.}} {{end}}",
+			contact:  Contact{Organization: "Acme", Title: "CTO", Categories: []string{"work", "vip"}},
+			want:     "Acme / CTO\n#work #vip",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseNotesTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("ParseNotesTemplate() error = %v", err)
+			}
+			got, err := RenderNotes(tmpl, tt.contact)
+			if err != nil {
+				t.Fatalf("RenderNotes() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderNotes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNotesTemplate_Invalid(t *testing.T) {
+	if _, err := ParseNotesTemplate("{{.Note"); err == nil {
+		t.Error("ParseNotesTemplate() error = nil, want parse error")
+	}
+	tmpl, err := ParseNotesTemplate("{{.Unknown}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RenderNotes(tmpl, Contact{}); err == nil {
+		t.Error("RenderNotes() with unknown field error = nil")
+	}
+}
+
+func TestSetProperty(t *testing.T) {
+	props := []map[string]any{{"key": "notes", "text": "old"}}
+	props = SetProperty(props, "notes", map[string]any{"text": "new"})
+	if len(props) != 1 || props[0]["text"] != "new" {
+		t.Errorf("SetProperty() replace = %v", props)
+	}
+	props = SetProperty(props, "uid", map[string]any{"text": "1"})
+	if len(props) != 2 || props[1]["key"] != "uid" {
+		t.Errorf("SetProperty() append = %v", props)
+	}
+}
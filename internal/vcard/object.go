@@ -0,0 +1,230 @@
+package vcard
+
+import (
+	"context"
+
+	"github.com/rubiojr/any-vcard/internal/profile"
+	"github.com/rubiojr/anytype-go"
+	"github.com/rubiojr/anytype-go/options"
+)
+
+// FromObject converts an Anytype contact object back to a Contact.
+// Phone and email properties are matched by format so any number of
+// phone/email properties (phone, phone2, ...) are picked up.
+func FromObject(obj anytype.Object) *Contact {
+	c := &Contact{
+		FormattedName: obj.Name,
+		ObjectID:      obj.ID,
+	}
+	if obj.Type != nil {
+		c.TypeKey = obj.Type.Key
+	}
+	// Image icons are served by the Anytype gateway
+	if obj.Icon != nil && obj.Icon.Format == anytype.IconFormatFile {
+		c.Photo = obj.Icon.File
+	}
+
+	address := func() *Address {
+		if len(c.Addresses) == 0 {
+			c.Addresses = append(c.Addresses, Address{})
+		}
+		return &c.Addresses[0]
+	}
+
+	for _, prop := range obj.Properties {
+		if v := propertyValue(prop); v != nil {
+			if c.Properties == nil {
+				c.Properties = make(map[string]any)
+			}
+			c.Properties[prop.Key] = v
+		}
+
+		switch {
+		case prop.Format == "email" || prop.Email != "":
+			if prop.Email != "" {
+				c.Emails = append(c.Emails, prop.Email)
+			}
+			continue
+		case prop.Format == "phone" || prop.Phone != "":
+			if prop.Phone != "" {
+				c.Phones = append(c.Phones, prop.Phone)
+			}
+			continue
+		}
+
+		switch prop.Key {
+		case "given_name":
+			c.GivenName = prop.Text
+		case "family_name":
+			c.FamilyName = prop.Text
+		case "middle_name":
+			c.MiddleName = prop.Text
+		case "prefix":
+			c.Prefix = prop.Text
+		case "suffix":
+			c.Suffix = prop.Text
+		case "organization":
+			c.Organization = prop.Text
+		case "department":
+			c.Department = prop.Text
+		case "title":
+			c.Title = prop.Text
+		case "role":
+			c.Role = prop.Text
+		case "assistant":
+			c.Assistant = prop.Text
+		case "manager":
+			c.Manager = prop.Text
+		case "notes":
+			c.Note = prop.Text
+		case "birthday":
+			c.Birthday = prop.Date
+		case "birthday_text":
+			c.BirthdayText = prop.Text
+		case "tag":
+			for _, tag := range prop.MultiSelect {
+				c.Categories = append(c.Categories, tag.Name)
+			}
+		case "uid":
+			c.UID = prop.Text
+		case "vcard_snapshot":
+			c.Snapshot = prop.Text
+		case "history":
+			c.History = prop.Text
+		case "favorite":
+			c.Favorite = prop.Checkbox
+		case "last_modified_date":
+			c.LastModified = prop.Date
+		case "url":
+			if prop.URL != "" {
+				c.URLs = append(c.URLs, prop.URL)
+			}
+		case "address":
+			if prop.Text != "" {
+				address().Street = prop.Text
+			}
+		case "city":
+			if prop.Text != "" {
+				address().City = prop.Text
+			}
+		case "region":
+			if prop.Text != "" {
+				address().Region = prop.Text
+			}
+		case "postal_code":
+			if prop.Text != "" {
+				address().PostalCode = prop.Text
+			}
+		case "country":
+			if prop.Text != "" {
+				address().Country = prop.Text
+			}
+		}
+	}
+
+	return c
+}
+
+// propertyValue returns the value of a property according to its format,
+// or nil when it is empty
+func propertyValue(prop anytype.Property) any {
+	switch prop.Format {
+	case "number":
+		if prop.Number != nil {
+			return *prop.Number
+		}
+	case "checkbox":
+		return prop.Checkbox
+	case "date":
+		if prop.Date != "" {
+			return prop.Date
+		}
+	case "url":
+		if prop.URL != "" {
+			return prop.URL
+		}
+	case "email":
+		if prop.Email != "" {
+			return prop.Email
+		}
+	case "phone":
+		if prop.Phone != "" {
+			return prop.Phone
+		}
+	case "select":
+		if prop.Select != nil {
+			return prop.Select.Name
+		}
+	case "multi_select":
+		if len(prop.MultiSelect) > 0 {
+			names := make([]string, len(prop.MultiSelect))
+			for i, tag := range prop.MultiSelect {
+				names[i] = tag.Name
+			}
+			return names
+		}
+	case "objects":
+		if len(prop.Objects) > 0 {
+			return prop.Objects
+		}
+	default:
+		if prop.Text != "" {
+			return prop.Text
+		}
+	}
+	return nil
+}
+
+// FetchContacts loads every object of typeKey in the space as contacts
+func FetchContacts(ctx context.Context, client anytype.Client, spaceID, typeKey string) ([]*Contact, error) {
+	return SearchContacts(ctx, client, spaceID, typeKey, "")
+}
+
+// SearchContacts loads the objects of typeKey matching a full-text query.
+// An empty query matches every object.
+func SearchContacts(ctx context.Context, client anytype.Client, spaceID, typeKey, query string) ([]*Contact, error) {
+	var contacts []*Contact
+	searchReq := anytype.SearchRequest{
+		Query: query,
+		Types: []string{typeKey},
+	}
+	err := SearchPages(ctx, client, spaceID, searchReq, func(page []anytype.Object) error {
+		for _, obj := range page {
+			contacts = append(contacts, FromObject(obj))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return contacts, nil
+}
+
+// SearchPageSize is the number of objects SearchPages requests at a time
+const SearchPageSize = 100
+
+// SearchPages runs a search and calls fn with every page of results until
+// all objects are fetched or fn returns an error
+func SearchPages(ctx context.Context, client anytype.Client, spaceID string, req anytype.SearchRequest, fn func(page []anytype.Object) error) error {
+	offset := 0
+	for {
+		stop := profile.Start(profile.API)
+		searchResp, err := client.Space(spaceID).Search(ctx, req,
+			options.WithLimit(SearchPageSize),
+			options.WithOffset(offset),
+		)
+		stop()
+		if err != nil {
+			return err
+		}
+
+		if err := fn(searchResp.Data); err != nil {
+			return err
+		}
+
+		if len(searchResp.Data) < SearchPageSize {
+			return nil // No more pages
+		}
+		offset += SearchPageSize
+	}
+}
@@ -0,0 +1,67 @@
+package vcard
+
+import (
+	"testing"
+
+	"github.com/rubiojr/anytype-go"
+)
+
+func TestFromObject(t *testing.T) {
+	obj := anytype.Object{
+		ID:   "obj1",
+		Name: "John Doe",
+		Icon: &anytype.Icon{Format: anytype.IconFormatFile, File: "http://127.0.0.1:47800/image/bafy123"},
+		Properties: []anytype.Property{
+			{Key: "email", Format: "email", Email: "john@example.com"},
+			{Key: "email2", Format: "email", Email: "jdoe@work.com"},
+			{Key: "email_3", Format: "email"},
+			{Key: "phone", Format: "phone", Phone: "+1-555-123-4567"},
+			{Key: "organization", Format: "text", Text: "Acme"},
+			{Key: "birthday", Format: "date", Date: "1985-06-15T00:00:00Z"},
+			{Key: "city", Format: "text", Text: "Springfield"},
+			{Key: "country", Format: "text", Text: "USA"},
+			{Key: "url", Format: "url", URL: "https://example.com"},
+			{Key: "tag", Format: "multi_select", MultiSelect: []anytype.Tag{{Name: "family"}, {Name: "vip"}}},
+			{Key: "last_modified_date", Format: "date", Date: "2024-05-01T10:00:00Z"},
+			{Key: "uid", Format: "text", Text: "urn:uuid:42"},
+		},
+	}
+
+	c := FromObject(obj)
+	if c.ObjectID != "obj1" || c.FormattedName != "John Doe" {
+		t.Errorf("ObjectID/FormattedName = %q/%q", c.ObjectID, c.FormattedName)
+	}
+	if len(c.Emails) != 2 || c.Emails[1] != "jdoe@work.com" {
+		t.Errorf("Emails = %v", c.Emails)
+	}
+	if len(c.Phones) != 1 {
+		t.Errorf("Phones = %v", c.Phones)
+	}
+	if c.Organization != "Acme" || c.Birthday != "1985-06-15T00:00:00Z" {
+		t.Errorf("Organization/Birthday = %q/%q", c.Organization, c.Birthday)
+	}
+	if len(c.Addresses) != 1 || c.Addresses[0].City != "Springfield" || c.Addresses[0].Country != "USA" {
+		t.Errorf("Addresses = %+v", c.Addresses)
+	}
+	if len(c.URLs) != 1 {
+		t.Errorf("URLs = %v", c.URLs)
+	}
+	if len(c.Categories) != 2 || c.Categories[0] != "family" {
+		t.Errorf("Categories = %v, want tags", c.Categories)
+	}
+	if c.Photo != "http://127.0.0.1:47800/image/bafy123" {
+		t.Errorf("Photo = %q, want icon file URL", c.Photo)
+	}
+	if c.Properties["organization"] != "Acme" || c.Properties["email_3"] != nil {
+		t.Errorf("Properties = %v", c.Properties)
+	}
+	if tags, _ := c.Properties["tag"].([]string); len(tags) != 2 {
+		t.Errorf("Properties[tag] = %v", c.Properties["tag"])
+	}
+	if c.LastModified != "2024-05-01T10:00:00Z" {
+		t.Errorf("LastModified = %q", c.LastModified)
+	}
+	if c.UID != "urn:uuid:42" {
+		t.Errorf("UID = %q", c.UID)
+	}
+}
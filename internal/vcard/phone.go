@@ -0,0 +1,170 @@
+package vcard
+
+import (
+	"fmt"
+	"strings"
+)
+
+// phoneRegion describes how national numbers are dialed in a region
+type phoneRegion struct {
+	CallingCode string
+	TrunkPrefix string // Dropped from national numbers before adding the calling code
+	IntlPrefix  string // Prefix used to dial out of the region
+}
+
+// phoneRegions maps ISO-3166 alpha-2 codes to dialing rules
+var phoneRegions = map[string]phoneRegion{
+	"AR": {"54", "0", "00"},
+	"AT": {"43", "0", "00"},
+	"AU": {"61", "0", "0011"},
+	"BE": {"32", "0", "00"},
+	"BR": {"55", "0", "00"},
+	"CA": {"1", "1", "011"},
+	"CH": {"41", "0", "00"},
+	"CL": {"56", "", "00"},
+	"CN": {"86", "0", "00"},
+	"CO": {"57", "", "00"},
+	"DE": {"49", "0", "00"},
+	"DK": {"45", "", "00"},
+	"ES": {"34", "", "00"},
+	"FI": {"358", "0", "00"},
+	"FR": {"33", "0", "00"},
+	"GB": {"44", "0", "00"},
+	"IE": {"353", "0", "00"},
+	"IN": {"91", "0", "00"},
+	"IT": {"39", "", "00"},
+	"JP": {"81", "0", "010"},
+	"MX": {"52", "", "00"},
+	"NL": {"31", "0", "00"},
+	"NO": {"47", "", "00"},
+	"NZ": {"64", "0", "00"},
+	"PE": {"51", "0", "00"},
+	"PL": {"48", "", "00"},
+	"PT": {"351", "", "00"},
+	"SE": {"46", "0", "00"},
+	"US": {"1", "1", "011"},
+}
+
+// callingCodes are the ITU-T E.164 country calling codes. No code is the
+// prefix of another, so the leading digits of a number match at most one.
+var callingCodes = func() map[string]bool {
+	codes := make(map[string]bool)
+	for _, code := range strings.Fields(`
+		1 7 20 27 30 31 32 33 34 36 39 40 41 43 44 45 46 47 48 49 51 52 53 54 55
+		56 57 58 60 61 62 63 64 65 66 81 82 84 86 90 91 92 93 94 95 98
+		211 212 213 216 218 220 221 222 223 224 225 226 227 228 229 230 231 232
+		233 234 235 236 237 238 239 240 241 242 243 244 245 246 247 248 249 250
+		251 252 253 254 255 256 257 258 260 261 262 263 264 265 266 267 268 269
+		290 291 297 298 299 350 351 352 353 354 355 356 357 358 359 370 371 372
+		373 374 375 376 377 378 379 380 381 382 383 385 386 387 389 420 421 423
+		500 501 502 503 504 505 506 507 508 509 590 591 592 593 594 595 596 597
+		598 599 670 672 673 674 675 676 677 678 679 680 681 682 683 685 686 687
+		688 689 690 691 692 800 808 850 852 853 855 856 870 878 880 881 882 883
+		886 888 960 961 962 963 964 965 966 967 968 970 971 972 973 974 975 976
+		977 979 991 992 993 994 995 996 998`) {
+		codes[code] = true
+	}
+	return codes
+}()
+
+// CallingCode returns the country calling code of a number written in
+// international form (+34 ..., 0034 ...), or "" for national numbers
+func CallingCode(phone string) string {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(phone), "tel:")
+	d := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, trimmed)
+	switch {
+	case strings.HasPrefix(trimmed, "+"):
+	case strings.HasPrefix(trimmed, "00"):
+		d = d[2:]
+	default:
+		return ""
+	}
+	for n := 1; n <= 3 && n <= len(d); n++ {
+		if callingCodes[d[:n]] {
+			return d[:n]
+		}
+	}
+	return ""
+}
+
+// IsSupportedPhoneRegion reports whether region has known dialing rules
+func IsSupportedPhoneRegion(region string) bool {
+	_, ok := phoneRegions[strings.ToUpper(region)]
+	return ok
+}
+
+// FormatPhoneE164 formats a phone number as E.164 (+34612345678).
+// National numbers are resolved using the dialing rules of region.
+func FormatPhoneE164(phone, region string) (string, error) {
+	trimmed := strings.TrimSpace(phone)
+	trimmed = strings.TrimPrefix(trimmed, "tel:")
+
+	// Drop extensions (";ext=12", " x12") before extracting digits
+	if i := strings.IndexAny(trimmed, ";xX"); i != -1 {
+		trimmed = trimmed[:i]
+	}
+
+	var digits strings.Builder
+	for _, r := range trimmed {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	d := digits.String()
+	if d == "" {
+		return "", fmt.Errorf("no digits in phone number %q", phone)
+	}
+
+	switch {
+	case strings.HasPrefix(trimmed, "+"):
+		// Already international
+	case strings.HasPrefix(d, "00"):
+		d = d[2:]
+	default:
+		rules, ok := phoneRegions[strings.ToUpper(region)]
+		if !ok {
+			return "", fmt.Errorf("cannot format national number %q without a known region", phone)
+		}
+		if rules.IntlPrefix != "00" && strings.HasPrefix(d, rules.IntlPrefix) {
+			d = d[len(rules.IntlPrefix):]
+			break
+		}
+		if rules.TrunkPrefix != "" {
+			d = strings.TrimPrefix(d, rules.TrunkPrefix)
+		}
+		d = rules.CallingCode + d
+	}
+
+	// E.164 numbers are at most 15 digits; anything below 8 is not a full number
+	if len(d) < 8 || len(d) > 15 {
+		return "", fmt.Errorf("phone number %q has an invalid length for E.164", phone)
+	}
+	return "+" + d, nil
+}
+
+// FormatPhones rewrites the contact phones as E.164. Numbers that cannot be
+// formatted are kept unchanged and reported as errors. When keepOriginal is
+// true the previous values are preserved in OriginalPhones.
+func (c *Contact) FormatPhones(region string, keepOriginal bool) []error {
+	var errs []error
+	for i, phone := range c.Phones {
+		formatted, err := FormatPhoneE164(phone, region)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if formatted == phone {
+			continue
+		}
+		if keepOriginal {
+			c.OriginalPhones = append(c.OriginalPhones, phone)
+		}
+		c.Phones[i] = formatted
+	}
+	return errs
+}
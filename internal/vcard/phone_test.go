@@ -0,0 +1,175 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatPhoneE164(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		region   string
+		expected string
+		wantErr  bool
+	}{
+		// Already international
+		{"plus prefix", "+34 612 345 678", "ES", "+34612345678", false},
+		{"plus prefix any region", "+1 (555) 123-4567", "ES", "+15551234567", false},
+		{"00 prefix", "0034 612 345 678", "US", "+34612345678", false},
+		{"tel uri", "tel:+34-612-345-678", "", "+34612345678", false},
+
+		// National numbers
+		{"Spain national", "612 345 678", "ES", "+34612345678", false},
+		{"US national", "(555) 123-4567", "US", "+15551234567", false},
+		{"US with trunk 1", "1-555-123-4567", "US", "+15551234567", false},
+		{"US 011 prefix", "011 34 612 345 678", "US", "+34612345678", false},
+		{"UK trunk 0", "020 7123 4567", "GB", "+442071234567", false},
+		{"Germany trunk 0", "030 12345678", "DE", "+493012345678", false},
+		{"Italy keeps 0", "06 1234 5678", "IT", "+390612345678", false},
+		{"lowercase region", "612345678", "es", "+34612345678", false},
+
+		// Extensions are dropped
+		{"extension", "+1 555 123 4567 ext. 12", "", "+15551234567", false},
+
+		// Errors
+		{"national without region", "612 345 678", "", "", true},
+		{"unknown region", "612 345 678", "ZZ", "", true},
+		{"too short", "+34 612", "ES", "", true},
+		{"too long", "+34 6123456789012345", "ES", "", true},
+		{"no digits", "n/a", "ES", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatPhoneE164(tt.input, tt.region)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FormatPhoneE164(%q, %q) error = %v, wantErr %v", tt.input, tt.region, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("FormatPhoneE164(%q, %q) = %q, want %q", tt.input, tt.region, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestContact_FormatPhones(t *testing.T) {
+	c := Contact{Phones: []string{"612 345 678", "+34612345679", "n/a"}}
+
+	errs := c.FormatPhones("ES", true)
+	if len(errs) != 1 {
+		t.Errorf("expected 1 error, got %d: %v", len(errs), errs)
+	}
+	if c.Phones[0] != "+34612345678" || c.Phones[1] != "+34612345679" || c.Phones[2] != "n/a" {
+		t.Errorf("unexpected phones: %v", c.Phones)
+	}
+	if len(c.OriginalPhones) != 1 || c.OriginalPhones[0] != "612 345 678" {
+		t.Errorf("OriginalPhones = %v, want only the rewritten number", c.OriginalPhones)
+	}
+	if notes := BuildNotes(c); !strings.Contains(notes, "Original phones: 612 345 678") {
+		t.Errorf("notes should keep original phones, got %q", notes)
+	}
+}
+
+func TestCallingCode(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  string
+	}{
+		{"+34 612 345 678", "34"},
+		{"0034 612 345 678", "34"},
+		{"+1 (555) 123-4567", "1"},
+		{"+351 912 345 678", "351"},
+		{"tel:+44 20 7123 4567", "44"},
+		{"612 345 678", ""},
+		{"(555) 123-4567", ""},
+		{"+", ""},
+	}
+	for _, tt := range tests {
+		if got := CallingCode(tt.phone); got != tt.want {
+			t.Errorf("CallingCode(%q) = %q, want %q", tt.phone, got, tt.want)
+		}
+	}
+
+	for code := range callingCodes {
+		for n := 1; n < len(code); n++ {
+			if callingCodes[code[:n]] {
+				t.Errorf("calling code %s is a prefix of %s", code[:n], code)
+			}
+		}
+	}
+}
+
+func TestSamePhone(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{"same number", "+34 612 345 678", "+34612345678", true},
+		{"national and international", "612 345 678", "+34 612 345 678", true},
+		{"different country codes", "+33 612 345 678", "+34 612 345 678", false},
+		{"short numbers of different countries", "+45 3312 3456", "+47 3312 3456", false},
+		{"different numbers", "+34 612 345 678", "+34 612 345 679", false},
+		{"too short", "12345", "12345", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SamePhone(tt.a, tt.b); got != tt.want {
+				t.Errorf("SamePhone(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPhoneSuffixLength(t *testing.T) {
+	// 8-digit Danish and Norwegian numbers ending alike share 9 trailing
+	// digits only when the country code's last digit matches
+	a := &Contact{FormattedName: "Anne", Phones: []string{"4533123456"}}
+	b := &Contact{FormattedName: "Bjørn", Phones: []string{"33123456"}}
+	if NormalizePhoneForDedup(a.Phones[0]) == NormalizePhoneForDedup(b.Phones[0]) {
+		t.Error("9-digit suffixes of a number with and without its country code match")
+	}
+	opts := DedupOptions{PhoneSuffixLength: 8}
+	if CompareContacts(a, b, opts) != MatchStrong {
+		t.Error("8-digit suffixes of a number with and without its country code don't match")
+	}
+
+	// The index only matches numbers whose country codes agree
+	idx := NewDedupIndexWith(NewMemoryIndexStore(), opts, []*Contact{{FormattedName: "Anne", Phones: []string{"+45 3312 3456"}}})
+	if idx.IsDuplicate(&Contact{FormattedName: "Ola", Phones: []string{"+47 3312 3456"}}) {
+		t.Error("numbers with different country codes matched")
+	}
+	if !idx.IsDuplicate(&Contact{FormattedName: "Anne H.", Phones: []string{"3312 3456"}}) {
+		t.Error("national number didn't match its international form")
+	}
+}
+
+func TestPhoneMatchStrict(t *testing.T) {
+	opts := DedupOptions{PhoneMatch: PhoneMatchStrict, PhoneRegion: "ES"}
+
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{"formatting only", "+34 612 345 678", "0034-612-345-678", true},
+		{"national in the region", "612 345 678", "+34612345678", true},
+		{"longer number ending alike", "+1 555 612 345 678", "+34 612 345 678", false},
+		{"trunk digit differs", "+44 1612 345 678", "+44 2612 345 678", false},
+		{"short national number", "12345 67", "1234567", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := opts.SamePhone(tt.a, tt.b); got != tt.want {
+				t.Errorf("SamePhone(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+
+	// Without a region, national numbers only match the same digits
+	opts.PhoneRegion = ""
+	if opts.SamePhone("612 345 678", "+34 612 345 678") {
+		t.Error("national number matched an international one without a region")
+	}
+}
@@ -0,0 +1,152 @@
+package vcard
+
+import "strings"
+
+// Metaphone returns the phonetic key of a single word using the original
+// Metaphone rules, so that spellings which sound alike ("Stephen" and
+// "Steven", "Catherine" and "Katherine") get the same key. Letters
+// outside a-z are ignored after removing accents; "0" stands for "th".
+func Metaphone(word string) string {
+	var letters []byte
+	for _, r := range strings.ToLower(removeAccents(word)) {
+		if r >= 'a' && r <= 'z' {
+			letters = append(letters, byte(r))
+		}
+	}
+	if len(letters) == 0 {
+		return ""
+	}
+
+	// Initial exceptions
+	switch {
+	case hasPrefix(letters, "ae"), hasPrefix(letters, "gn"), hasPrefix(letters, "kn"),
+		hasPrefix(letters, "pn"), hasPrefix(letters, "wr"):
+		letters = letters[1:]
+	case letters[0] == 'x':
+		letters[0] = 's'
+	case hasPrefix(letters, "wh"):
+		letters = append([]byte{'w'}, letters[2:]...)
+	}
+
+	at := func(i int) byte {
+		if i < 0 || i >= len(letters) {
+			return 0
+		}
+		return letters[i]
+	}
+	isVowel := func(c byte) bool { return strings.IndexByte("aeiou", c) >= 0 }
+	frontVowel := func(c byte) bool { return c == 'e' || c == 'i' || c == 'y' }
+
+	var key strings.Builder
+	for i, c := range letters {
+		// Skip doubled letters except c
+		if c == at(i-1) && c != 'c' {
+			continue
+		}
+		next, prev := at(i+1), at(i-1)
+		switch c {
+		case 'a', 'e', 'i', 'o', 'u':
+			if i == 0 {
+				key.WriteByte(c - 'a' + 'A')
+			}
+		case 'b':
+			// Silent in a final "mb"
+			if !(prev == 'm' && i == len(letters)-1) {
+				key.WriteByte('B')
+			}
+		case 'c':
+			switch {
+			case next == 'i' && at(i+2) == 'a', next == 'h' && prev != 's':
+				key.WriteByte('X')
+			case frontVowel(next):
+				if prev != 's' {
+					key.WriteByte('S')
+				}
+			default:
+				key.WriteByte('K')
+			}
+		case 'd':
+			if next == 'g' && frontVowel(at(i+2)) {
+				key.WriteByte('J')
+			} else {
+				key.WriteByte('T')
+			}
+		case 'g':
+			switch {
+			case next == 'h' && i+2 < len(letters) && !isVowel(at(i+2)):
+				// Silent in "gh" before a consonant ("night")
+			case next == 'n' && (i+2 == len(letters) || hasPrefix(letters[i+1:], "ned") && i+4 == len(letters)):
+				// Silent in final "gn" and "gned" ("sign", "signed")
+			case prev == 'd' && frontVowel(next):
+				// Already coded as J by "dge"
+			case frontVowel(next):
+				key.WriteByte('J')
+			default:
+				key.WriteByte('K')
+			}
+		case 'h':
+			if isVowel(next) && strings.IndexByte("csptg", prev) < 0 {
+				key.WriteByte('H')
+			}
+		case 'k':
+			if prev != 'c' {
+				key.WriteByte('K')
+			}
+		case 'p':
+			if next == 'h' {
+				key.WriteByte('F')
+			} else {
+				key.WriteByte('P')
+			}
+		case 'q':
+			key.WriteByte('K')
+		case 's':
+			switch {
+			case next == 'h', next == 'i' && (at(i+2) == 'o' || at(i+2) == 'a'):
+				key.WriteByte('X')
+			default:
+				key.WriteByte('S')
+			}
+		case 't':
+			switch {
+			case next == 'i' && (at(i+2) == 'o' || at(i+2) == 'a'):
+				key.WriteByte('X')
+			case next == 'h':
+				key.WriteByte('0')
+			case next == 'c' && at(i+2) == 'h':
+				// Silent in "tch"
+			default:
+				key.WriteByte('T')
+			}
+		case 'v':
+			key.WriteByte('F')
+		case 'w', 'y':
+			if isVowel(next) {
+				key.WriteByte(c - 'a' + 'A')
+			}
+		case 'x':
+			key.WriteString("KS")
+		case 'z':
+			key.WriteByte('S')
+		default: // f j l m n r
+			key.WriteByte(c - 'a' + 'A')
+		}
+	}
+	return key.String()
+}
+
+// PhoneticNameKey returns the Metaphone keys of the words of a name,
+// space separated, or "" when the name has no letters
+func PhoneticNameKey(name string) string {
+	var keys []string
+	for _, word := range strings.Fields(NormalizeNameForDedup(name)) {
+		if k := Metaphone(word); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return strings.Join(keys, " ")
+}
+
+func hasPrefix(b []byte, prefix string) bool {
+	return strings.HasPrefix(string(b), prefix)
+}
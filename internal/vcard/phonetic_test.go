@@ -0,0 +1,74 @@
+package vcard
+
+import "testing"
+
+func TestMetaphone(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"Stephen", "STFN"},
+		{"Steven", "STFN"},
+		{"Catherine", "K0RN"},
+		{"Katherine", "K0RN"},
+		{"Thompson", "0MPSN"},
+		{"Knight", "NT"},
+		{"Xavier", "SFR"},
+		{"Judge", "JJ"},
+		{"Schmidt", "SKMTT"},
+		{"Müller", "MLR"},
+		{"Ana", "AN"},
+		{"Whitney", "WTN"},
+		{"", ""},
+		{"123", ""},
+	}
+	for _, tt := range tests {
+		if got := Metaphone(tt.word); got != tt.want {
+			t.Errorf("Metaphone(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestPhoneticNameKey(t *testing.T) {
+	pairs := [][2]string{
+		{"Stephen Smith", "Steven Smith"},
+		{"Catherine Zeta", "Katherine Zeta"},
+		{"Dr. Jon Doe", "John Doe"},
+	}
+	for _, p := range pairs {
+		a, b := PhoneticNameKey(p[0]), PhoneticNameKey(p[1])
+		if a == "" || a != b {
+			t.Errorf("PhoneticNameKey(%q) = %q, PhoneticNameKey(%q) = %q; want equal", p[0], a, p[1], b)
+		}
+	}
+	if PhoneticNameKey("Stephen Smith") == PhoneticNameKey("Stella Smith") {
+		t.Error("different names share a phonetic key")
+	}
+}
+
+func TestDedupIndex_Phonetic(t *testing.T) {
+	existing := &Contact{ObjectID: "obj1", FormattedName: "Stephen Smith"}
+	incoming := &Contact{FormattedName: "Steven Smith", Phones: []string{"+34 600 000 000"}}
+
+	idx := NewDedupIndex([]*Contact{existing})
+	if idx.IsDuplicate(incoming) {
+		t.Fatal("phonetic match without EnablePhonetic")
+	}
+
+	idx.EnablePhonetic()
+	matches := idx.FindMatches(incoming)
+	if len(matches) != 1 || matches[0].Contact != existing {
+		t.Fatalf("FindMatches() = %+v, want the phonetic match", matches)
+	}
+	m := matches[0]
+	if m.Score != PhoneticWeight || m.Strength != MatchWeak || len(m.Reasons) != 1 {
+		t.Errorf("match = %+v", m)
+	}
+
+	// Contacts added after enabling are indexed too
+	later := &Contact{FormattedName: "Katherine Jones"}
+	idx.Add(later)
+	if !idx.IsDuplicate(&Contact{FormattedName: "Catherine Jones"}) {
+		t.Error("contact added after EnablePhonetic not matched")
+	}
+}
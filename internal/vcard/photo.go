@@ -0,0 +1,128 @@
+package vcard
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rubiojr/any-vcard/internal/profile"
+)
+
+// Photo export modes
+const (
+	PhotosEmbed = "embed" // Inline the image as base64
+	PhotosLink  = "link"  // Reference the image by URI
+	PhotosNone  = "none"  // Drop photos
+)
+
+// maxPhotoSize bounds the images downloaded for embedding
+const maxPhotoSize = 5 << 20
+
+// PhotoClient downloads photos. Its timeout keeps a slow host from
+// hanging an export.
+var PhotoClient = &http.Client{Timeout: 30 * time.Second}
+
+// IsPhotoURL reports whether the photo is referenced by an http(s) URI
+func IsPhotoURL(photo string) bool {
+	return strings.HasPrefix(photo, "http://") || strings.HasPrefix(photo, "https://")
+}
+
+// FetchPhoto downloads an image and returns it as a data URI
+func FetchPhoto(ctx context.Context, client *http.Client, url string) (string, error) {
+	defer profile.Start(profile.Photos)()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch photo: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch photo: status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPhotoSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read photo: %w", err)
+	}
+	if len(data) > maxPhotoSize {
+		return "", fmt.Errorf("photo larger than %d MB", maxPhotoSize>>20)
+	}
+
+	mediaType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(mediaType, "image/") {
+		mediaType = http.DetectContentType(data)
+	}
+	if !strings.HasPrefix(mediaType, "image/") {
+		return "", fmt.Errorf("photo is not an image (%s)", mediaType)
+	}
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// DecodePhoto returns the image of an embedded photo, a data URI or raw
+// base64 (taken as JPEG), and its media type
+func DecodePhoto(photo string) ([]byte, string, error) {
+	mediaType, payload := "image/jpeg", photo
+	if rest, ok := strings.CutPrefix(photo, "data:"); ok {
+		header, data, found := strings.Cut(rest, ",")
+		if !found || !strings.HasSuffix(header, ";base64") {
+			return nil, "", fmt.Errorf("unsupported photo data URI")
+		}
+		if mime, _, _ := strings.Cut(header, ";"); strings.HasPrefix(mime, "image/") {
+			mediaType = mime
+		}
+		payload = data
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(payload), ""))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode photo: %w", err)
+	}
+	return data, mediaType, nil
+}
+
+// PhotoExtension returns the file extension for an image media type
+func PhotoExtension(mediaType string) string {
+	switch sub := strings.TrimPrefix(strings.ToLower(mediaType), "image/"); sub {
+	case "jpeg", "jpg", "pjpeg":
+		return ".jpg"
+	case "svg+xml":
+		return ".svg"
+	case "x-icon", "vnd.microsoft.icon":
+		return ".ico"
+	default:
+		return "." + sub
+	}
+}
+
+// ApplyPhotoMode rewrites the contact photo for the given mode. In embed mode
+// linked photos are downloaded; the photo is dropped when that fails and the
+// error is returned.
+func (c *Contact) ApplyPhotoMode(ctx context.Context, client *http.Client, mode string) error {
+	switch mode {
+	case PhotosNone:
+		c.Photo = ""
+	case PhotosEmbed:
+		if !IsPhotoURL(c.Photo) {
+			return nil
+		}
+		photo, err := FetchPhoto(ctx, client, c.Photo)
+		if err != nil {
+			c.Photo = ""
+			return err
+		}
+		c.Photo = photo
+	case PhotosLink:
+		// Embedded photos have no URI to link to and are kept inline
+	default:
+		return fmt.Errorf("unsupported photo mode %q (supported: %s, %s, %s)", mode, PhotosEmbed, PhotosLink, PhotosNone)
+	}
+	return nil
+}
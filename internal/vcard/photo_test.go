@@ -0,0 +1,82 @@
+package vcard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContact_ApplyPhotoMode(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/image/ok":
+			w.Write([]byte(png))
+		case "/image/text":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("not an image"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	c := Contact{Photo: srv.URL + "/image/ok"}
+	if err := c.ApplyPhotoMode(ctx, srv.Client(), PhotosEmbed); err != nil {
+		t.Fatalf("embed: %v", err)
+	}
+	if !strings.HasPrefix(c.Photo, "data:image/png;base64,") {
+		t.Errorf("embedded Photo = %q", c.Photo)
+	}
+
+	for _, path := range []string{"/image/text", "/image/missing"} {
+		c := Contact{Photo: srv.URL + path}
+		if err := c.ApplyPhotoMode(ctx, srv.Client(), PhotosEmbed); err == nil || c.Photo != "" {
+			t.Errorf("%s: expected error and dropped photo, got %v / %q", path, err, c.Photo)
+		}
+	}
+
+	c = Contact{Photo: srv.URL + "/image/ok"}
+	if err := c.ApplyPhotoMode(ctx, srv.Client(), PhotosLink); err != nil || c.Photo != srv.URL+"/image/ok" {
+		t.Errorf("link: Photo = %q, err = %v", c.Photo, err)
+	}
+
+	c = Contact{Photo: "data:image/png;base64,AAAA"}
+	if err := c.ApplyPhotoMode(ctx, srv.Client(), PhotosNone); err != nil || c.Photo != "" {
+		t.Errorf("none: Photo = %q, err = %v", c.Photo, err)
+	}
+
+	if err := c.ApplyPhotoMode(ctx, srv.Client(), "thumbnail"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
+
+func TestDecodePhoto(t *testing.T) {
+	tests := []struct {
+		photo   string
+		want    string
+		ext     string
+		wantErr bool
+	}{
+		{"data:image/png;base64,aGVsbG8=", "hello", ".png", false},
+		{"aGVs\nbG8=", "hello", ".jpg", false},
+		{"data:image/png,hello", "", "", true},
+		{"not base64!", "", "", true},
+	}
+	for _, tt := range tests {
+		data, mediaType, err := DecodePhoto(tt.photo)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("DecodePhoto(%q) error = %v, wantErr %v", tt.photo, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if string(data) != tt.want || PhotoExtension(mediaType) != tt.ext {
+			t.Errorf("DecodePhoto(%q) = %q %s, want %q %s", tt.photo, data, PhotoExtension(mediaType), tt.want, tt.ext)
+		}
+	}
+}
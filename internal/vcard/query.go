@@ -0,0 +1,363 @@
+package vcard
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Query is a parsed filter expression such as
+//
+//	org=Acme AND email~@gmail.com AND has(phone)
+//
+// Comparisons are field=value (equal), field!=value, field~value
+// (contains), field!~value and, for dates, field<value, field<=value,
+// field>value and field>=value. Multi-valued fields (email, phone, tag,
+// url) match when any of their values does. Text comparisons ignore case.
+// Terms combine with AND, OR, NOT and parentheses; values with spaces or
+// operators are quoted: title="Head of Sales".
+type Query struct {
+	root queryNode
+	text string
+}
+
+// QueryFields lists the fields a Query can test
+var QueryFields = []string{
+	"name", "given", "family", "nickname", "org", "department", "title",
+	"role", "email", "phone", "tag", "url", "note", "city", "region",
+	"country", "birthday", "modified", "uid", "source", "kind", "favorite",
+}
+
+// queryValues returns the values of a field of c
+func queryValues(c *Contact, field string) []string {
+	one := func(v string) []string {
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	}
+	switch field {
+	case "name":
+		return one(c.DisplayName())
+	case "given":
+		return one(c.GivenName)
+	case "family":
+		return one(c.FamilyName)
+	case "nickname":
+		return one(c.Nickname)
+	case "org":
+		return one(c.Organization)
+	case "department":
+		return one(c.Department)
+	case "title":
+		return one(c.Title)
+	case "role":
+		return one(c.Role)
+	case "email":
+		return c.Emails
+	case "phone":
+		return c.Phones
+	case "tag":
+		return c.Categories
+	case "url":
+		return c.URLs
+	case "note":
+		return one(c.Note)
+	case "city", "region", "country":
+		var values []string
+		for _, a := range c.Addresses {
+			switch field {
+			case "city":
+				values = append(values, one(a.City)...)
+			case "region":
+				values = append(values, one(a.Region)...)
+			default:
+				values = append(values, one(a.Country)...)
+			}
+		}
+		return values
+	case "birthday":
+		return one(c.Birthday)
+	case "modified":
+		return one(c.LastModified)
+	case "uid":
+		return one(c.UID)
+	case "source":
+		return one(c.Source)
+	case "kind":
+		return one(c.Kind)
+	case "favorite":
+		if c.Favorite {
+			return []string{"true"}
+		}
+	}
+	return nil
+}
+
+// ParseQuery parses a filter expression
+func ParseQuery(s string) (*Query, error) {
+	p := &queryParser{tokens: tokenizeQuery(s)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty query")
+	}
+	root, err := p.or()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid query %q: %w", s, err)
+	}
+	return &Query{root: root, text: s}, nil
+}
+
+// Match reports whether the contact satisfies the query
+func (q *Query) Match(c *Contact) bool {
+	return q.root.match(c)
+}
+
+// String returns the expression the query was parsed from
+func (q *Query) String() string {
+	return q.text
+}
+
+type queryNode interface {
+	match(c *Contact) bool
+}
+
+type andNode struct{ left, right queryNode }
+
+func (n andNode) match(c *Contact) bool { return n.left.match(c) && n.right.match(c) }
+
+type orNode struct{ left, right queryNode }
+
+func (n orNode) match(c *Contact) bool { return n.left.match(c) || n.right.match(c) }
+
+type notNode struct{ node queryNode }
+
+func (n notNode) match(c *Contact) bool { return !n.node.match(c) }
+
+type hasNode struct{ field string }
+
+func (n hasNode) match(c *Contact) bool { return len(queryValues(c, n.field)) > 0 }
+
+type compareNode struct {
+	field, op, value string
+}
+
+func (n compareNode) match(c *Contact) bool {
+	values := queryValues(c, n.field)
+	switch n.op {
+	case "!=":
+		return !compareNode{n.field, "=", n.value}.match(c)
+	case "!~":
+		return !compareNode{n.field, "~", n.value}.match(c)
+	}
+	return slices.ContainsFunc(values, func(v string) bool { return n.test(v) })
+}
+
+// test compares one value of the field
+func (n compareNode) test(v string) bool {
+	switch n.op {
+	case "=":
+		if n.field == "phone" {
+			return SamePhone(v, n.value)
+		}
+		if n.field == "birthday" || n.field == "modified" {
+			return queryDate(v) == queryDate(n.value)
+		}
+		return strings.EqualFold(v, n.value)
+	case "~":
+		if n.field == "phone" {
+			return strings.Contains(digitsOnly(v), digitsOnly(n.value))
+		}
+		return strings.Contains(strings.ToLower(v), strings.ToLower(n.value))
+	}
+	// Ordered comparisons only make sense for dates, compared as YYYY-MM-DD
+	cmp := strings.Compare(queryDate(v), queryDate(n.value))
+	switch n.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+// queryDate returns the YYYY-MM-DD day of a date or RFC3339 time
+func queryDate(s string) string {
+	if len(s) >= 10 && s[4] == '-' && s[7] == '-' {
+		return s[:10]
+	}
+	return s
+}
+
+func digitsOnly(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// queryToken is a word, a quoted string or one of ( ) = != ~ !~ < <= > >=
+type queryToken struct {
+	text   string
+	quoted bool
+}
+
+func tokenizeQuery(s string) []queryToken {
+	var tokens []queryToken
+	rs := []rune(s)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, queryToken{text: string(r)})
+			i++
+		case r == '"' || r == '\'':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(rs) && rs[j] != r; j++ {
+				if rs[j] == '\\' && j+1 < len(rs) {
+					j++
+				}
+				b.WriteRune(rs[j])
+			}
+			tokens = append(tokens, queryToken{text: b.String(), quoted: true})
+			i = j + 1
+		case strings.ContainsRune("=!~<>", r):
+			j := i + 1
+			if j < len(rs) && (rs[j] == '=' || (r == '!' && rs[j] == '~')) {
+				j++
+			}
+			tokens = append(tokens, queryToken{text: string(rs[i:j])})
+			i = j
+		default:
+			j := i
+			for j < len(rs) && !unicode.IsSpace(rs[j]) && !strings.ContainsRune("()=!~<>\"'", rs[j]) {
+				j++
+			}
+			tokens = append(tokens, queryToken{text: string(rs[i:j])})
+			i = j
+		}
+	}
+	return tokens
+}
+
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+// keyword reports whether the next token is the unquoted keyword, and
+// consumes it if so
+func (p *queryParser) keyword(k string) bool {
+	if p.pos < len(p.tokens) && !p.tokens[p.pos].quoted && strings.EqualFold(p.tokens[p.pos].text, k) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) next() (queryToken, error) {
+	if p.pos >= len(p.tokens) {
+		return queryToken{}, fmt.Errorf("unexpected end")
+	}
+	p.pos++
+	return p.tokens[p.pos-1], nil
+}
+
+func (p *queryParser) or() (queryNode, error) {
+	left, err := p.and()
+	for err == nil && p.keyword("OR") {
+		var right queryNode
+		if right, err = p.and(); err == nil {
+			left = orNode{left, right}
+		}
+	}
+	return left, err
+}
+
+func (p *queryParser) and() (queryNode, error) {
+	left, err := p.unary()
+	for err == nil && p.keyword("AND") {
+		var right queryNode
+		if right, err = p.unary(); err == nil {
+			left = andNode{left, right}
+		}
+	}
+	return left, err
+}
+
+func (p *queryParser) unary() (queryNode, error) {
+	if p.keyword("NOT") {
+		node, err := p.unary()
+		return notNode{node}, err
+	}
+	if p.keyword("(") {
+		node, err := p.or()
+		if err == nil && !p.keyword(")") {
+			err = fmt.Errorf("missing )")
+		}
+		return node, err
+	}
+	return p.term()
+}
+
+func (p *queryParser) term() (queryNode, error) {
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(tok.text, "has") && !tok.quoted && p.keyword("(") {
+		field, err := p.field()
+		if err == nil && !p.keyword(")") {
+			err = fmt.Errorf("missing ) after has(%s", field)
+		}
+		return hasNode{field}, err
+	}
+
+	p.pos--
+	field, err := p.field()
+	if err != nil {
+		return nil, err
+	}
+	op, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	switch op.text {
+	case "=", "!=", "~", "!~", "<", "<=", ">", ">=":
+		if op.quoted {
+			return nil, fmt.Errorf("expected an operator after %s, got %q", field, op.text)
+		}
+	default:
+		return nil, fmt.Errorf("expected an operator after %s, got %q", field, op.text)
+	}
+	value, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	return compareNode{field: field, op: op.text, value: value.text}, nil
+}
+
+// field reads a field name
+func (p *queryParser) field() (string, error) {
+	tok, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	field := strings.ToLower(tok.text)
+	if tok.quoted || !slices.Contains(QueryFields, field) {
+		return "", fmt.Errorf("unknown field %q (fields: %s)", tok.text, strings.Join(QueryFields, ", "))
+	}
+	return field, nil
+}
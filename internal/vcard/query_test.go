@@ -0,0 +1,89 @@
+package vcard
+
+import "testing"
+
+func TestQuery_Match(t *testing.T) {
+	c := &Contact{
+		GivenName:    "Jane",
+		FamilyName:   "Smith",
+		Organization: "Acme",
+		Title:        "Head of Sales",
+		Emails:       []string{"jane@acme.com", "jane.smith@gmail.com"},
+		Phones:       []string{"+34 612 345 678"},
+		Categories:   []string{"Work"},
+		Birthday:     "1985-04-12",
+		LastModified: "2024-03-01T12:00:00Z",
+		Addresses:    []Address{{City: "Madrid", Country: "Spain"}},
+	}
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"org=Acme AND email~@gmail.com AND has(phone)", true},
+		{"org=acme", true},
+		{"org=Acm", false},
+		{"org~acm", true},
+		{"org!=Acme", false},
+		{"email=JANE@ACME.COM", true},
+		{"email!~@yahoo.com", true},
+		{"phone=612345678", true},
+		{`phone~"345 678"`, true},
+		{"has(note)", false},
+		{"NOT has(note)", true},
+		{`title="Head of Sales"`, true},
+		{"title='head of sales' AND city=Madrid", true},
+		{"country=France OR tag=work", true},
+		{"country=France OR (tag=work AND org=Globex)", false},
+		{"birthday<1990-01-01", true},
+		{"modified>=2024-03-01", true},
+		{"modified>2024-03-01", false},
+		{`name="Jane Smith"`, true},
+		{"favorite=true", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+			if got := q.Match(c); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	for _, query := range []string{
+		"",
+		"colour=red",
+		"org",
+		"org=",
+		"org Acme",
+		"(org=Acme",
+		"has(phone",
+		"has(colour)",
+		"org=Acme AND",
+		"org=Acme junk",
+		"name=Jane Smith", // Unquoted values end at spaces
+	} {
+		if _, err := ParseQuery(query); err == nil {
+			t.Errorf("ParseQuery(%q) succeeded, want an error", query)
+		}
+	}
+}
+
+func TestFilter_MatchWhere(t *testing.T) {
+	q, err := ParseQuery("has(email)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := Filter{Organization: "Acme", Where: q}
+	if !f.Match(&Contact{Organization: "Acme", Emails: []string{"a@acme.com"}}) {
+		t.Error("Match() = false for a contact with an email")
+	}
+	if f.Match(&Contact{Organization: "Acme"}) {
+		t.Error("Match() = true for a contact without email")
+	}
+}
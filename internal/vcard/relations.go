@@ -0,0 +1,81 @@
+package vcard
+
+import (
+	"strings"
+
+	govcard "github.com/emersion/go-vcard"
+)
+
+// assistantFields and managerFields are the vCard extensions Outlook,
+// Evolution and KAddressBook export assistant and manager names in
+var (
+	assistantFields = []string{"X-MS-ASSISTANT", "X-ASSISTANT", "X-EVOLUTION-ASSISTANT", "X-KADDRESSBOOK-X-ASSISTANTSNAME"}
+	managerFields   = []string{"X-MS-MANAGER", "X-MANAGER", "X-EVOLUTION-MANAGER", "X-KADDRESSBOOK-X-MANAGERSNAME"}
+)
+
+// RelationType returns "assistant" or "manager" for relation labels naming
+// them, as used by vCard 4.0 RELATED types, Google's relation types and
+// Apple's X-ABLabel values like "_$!<Assistant>!$_", or "" otherwise
+func RelationType(label string) string {
+	label = strings.ToLower(strings.Trim(label, "_$!<>"))
+	switch label {
+	case "assistant", "manager":
+		return label
+	}
+	return ""
+}
+
+// parseRelations sets the assistant and manager of the contact from the
+// X- fields, RELATED properties and Apple's grouped X-ABRELATEDNAMES
+func parseRelations(card govcard.Card, c *Contact) {
+	c.Assistant = firstValue(card, assistantFields)
+	c.Manager = firstValue(card, managerFields)
+
+	set := func(label, name string) {
+		name = strings.TrimSpace(name)
+		switch RelationType(label) {
+		case "assistant":
+			if c.Assistant == "" {
+				c.Assistant = name
+			}
+		case "manager":
+			if c.Manager == "" {
+				c.Manager = name
+			}
+		}
+	}
+
+	// Names are text; URIs point at other cards and can't be resolved here
+	for _, f := range card[govcard.FieldRelated] {
+		if strings.EqualFold(f.Params.Get(govcard.ParamValue), "text") {
+			for _, t := range f.Params.Types() {
+				set(t, f.Value)
+			}
+		}
+	}
+
+	for _, f := range card["X-ABRELATEDNAMES"] {
+		if f.Group == "" {
+			continue
+		}
+		for _, l := range card["X-ABLABEL"] {
+			if l.Group == f.Group {
+				set(l.Value, f.Value)
+			}
+		}
+	}
+}
+
+func firstValue(card govcard.Card, fields []string) string {
+	for _, field := range fields {
+		if v := strings.TrimSpace(card.Value(field)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// FindByName returns the indexed contacts whose name matches name
+func (idx *DedupIndex) FindByName(name string) []*Contact {
+	return idx.store.Get(tableName, NormalizeNameForDedup(name))
+}
@@ -0,0 +1,58 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse_Relations(t *testing.T) {
+	input := `BEGIN:VCARD
+VERSION:3.0
+FN:Outlook
+X-MS-ASSISTANT:Ann Assistant
+X-MS-MANAGER:Max Manager
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+FN:Related
+RELATED;VALUE=text;TYPE=manager:Mia Boss
+RELATED;TYPE=assistant:urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6
+END:VCARD
+BEGIN:VCARD
+VERSION:3.0
+FN:Apple
+item1.X-ABRELATEDNAMES:Al Helper
+item1.X-ABLabel:_$!<Assistant>!$_
+item2.X-ABRELATEDNAMES:Sis
+item2.X-ABLabel:_$!<Sister>!$_
+END:VCARD
+`
+	contacts, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		assistant, manager string
+	}{
+		{"Ann Assistant", "Max Manager"},
+		{"", "Mia Boss"},
+		{"Al Helper", ""},
+	}
+	for i, tt := range tests {
+		c := contacts[i]
+		if c.Assistant != tt.assistant || c.Manager != tt.manager {
+			t.Errorf("%s: assistant, manager = %q, %q, want %q, %q", c.FormattedName, c.Assistant, c.Manager, tt.assistant, tt.manager)
+		}
+	}
+}
+
+func TestDedupIndex_FindByName(t *testing.T) {
+	idx := NewDedupIndex([]*Contact{{FormattedName: "José García", ObjectID: "1"}})
+	if got := idx.FindByName("jose garcia"); len(got) != 1 || got[0].ObjectID != "1" {
+		t.Errorf("FindByName() = %v", got)
+	}
+	if got := idx.FindByName("Nobody"); len(got) != 0 {
+		t.Errorf("FindByName(Nobody) = %v, want none", got)
+	}
+}
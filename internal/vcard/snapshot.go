@@ -0,0 +1,32 @@
+package vcard
+
+import (
+	"strings"
+)
+
+// EncodeSnapshot serializes the contact as it was read from its source, to
+// be stored next to the object and used as the base of later three-way
+// merges. Photos are left out to keep the snapshot small.
+func EncodeSnapshot(c Contact) (string, error) {
+	c.Photo = ""
+	c.ObjectID = ""
+	c.LastModified = ""
+	c.Properties = nil
+	var b strings.Builder
+	if err := Write(&b, []Contact{c}, Version4); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// ParseSnapshot parses a snapshot written by EncodeSnapshot
+func ParseSnapshot(s string) (*Contact, bool) {
+	if s == "" {
+		return nil, false
+	}
+	contacts, err := Parse(strings.NewReader(s))
+	if err != nil || len(contacts) != 1 {
+		return nil, false
+	}
+	return &contacts[0], true
+}
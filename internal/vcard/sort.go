@@ -0,0 +1,249 @@
+package vcard
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Locale is the BCP 47 locale names are sorted and grouped by, the root
+// collation order when empty. The CLI sets it from --locale or
+// SystemLocale.
+var Locale string
+
+// SystemLocale returns the collation locale of the environment (LC_ALL,
+// LC_COLLATE or LANG) as a BCP 47 tag, "" for the C and POSIX locales
+func SystemLocale() string {
+	for _, env := range []string{"LC_ALL", "LC_COLLATE", "LANG"} {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+		// es_ES.UTF-8@euro
+		value, _, _ = strings.Cut(value, ".")
+		value, _, _ = strings.Cut(value, "@")
+		if value == "C" || value == "POSIX" {
+			return ""
+		}
+		if _, err := language.Parse(strings.ReplaceAll(value, "_", "-")); err != nil {
+			return ""
+		}
+		return strings.ReplaceAll(value, "_", "-")
+	}
+	return ""
+}
+
+// Keys SortCards orders cards by
+const (
+	SortFamily = "family" // Family name, then given name
+	SortGiven  = "given"  // Given name, then family name
+	SortOrg    = "org"    // Organization, then family and given name
+)
+
+// NewCollator returns a case-insensitive collator for a BCP 47 locale
+// ("de", "sv-SE"), or the root collation order when locale is empty
+func NewCollator(locale string) (*collate.Collator, error) {
+	tag := language.Und
+	if locale != "" {
+		var err error
+		if tag, err = language.Parse(locale); err != nil {
+			return nil, fmt.Errorf("invalid locale %q: %w", locale, err)
+		}
+	}
+	return collate.New(tag, collate.IgnoreCase), nil
+}
+
+// SortCards reorders the vCards in data by the given key, comparing with
+// the collation rules of locale. Cards are copied byte for byte, so
+// properties this package doesn't parse are kept. Cards without the key
+// go last; ties keep their order.
+func SortCards(data []byte, by, locale string) ([]byte, error) {
+	fields, err := sortFields(by)
+	if err != nil {
+		return nil, err
+	}
+	col, err := NewCollator(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	type card struct {
+		raw  []byte
+		keys []string
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+	raws, err := splitCards(data)
+	if err != nil {
+		return nil, err
+	}
+	// Cards the split and the parser disagree on would be lost
+	all, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cards: %w", err)
+	}
+	if len(all) != len(raws) {
+		return nil, fmt.Errorf("found %d card(s) but parsed %d", len(raws), len(all))
+	}
+	var cards []card
+	for i, raw := range raws {
+		contacts, err := Parse(bytes.NewReader(raw))
+		if err != nil || len(contacts) != 1 {
+			return nil, fmt.Errorf("failed to parse card %d: %v", i+1, err)
+		}
+		cards = append(cards, card{raw: raw, keys: fields(contacts[0])})
+	}
+
+	slices.SortStableFunc(cards, func(a, b card) int {
+		return compareKeys(col, a.keys, b.keys)
+	})
+
+	var out bytes.Buffer
+	for _, c := range cards {
+		out.Write(c.raw)
+		if !bytes.HasSuffix(c.raw, []byte("\n")) {
+			out.WriteString("\r\n")
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// SortContacts orders contacts by the given key like SortCards
+func SortContacts(contacts []Contact, by, locale string) error {
+	fields, err := sortFields(by)
+	if err != nil {
+		return err
+	}
+	col, err := NewCollator(locale)
+	if err != nil {
+		return err
+	}
+	slices.SortStableFunc(contacts, func(a, b Contact) int {
+		return compareKeys(col, fields(a), fields(b))
+	})
+	return nil
+}
+
+// sortFields returns the values contacts are compared by for a sort key
+func sortFields(by string) (func(Contact) []string, error) {
+	switch by {
+	case SortFamily:
+		return func(c Contact) []string { return []string{c.FamilyName, c.GivenName, c.DisplayName()} }, nil
+	case SortGiven:
+		return func(c Contact) []string { return []string{c.GivenName, c.FamilyName, c.DisplayName()} }, nil
+	case SortOrg:
+		return func(c Contact) []string { return []string{c.Organization, c.FamilyName, c.GivenName, c.DisplayName()} }, nil
+	default:
+		return nil, fmt.Errorf("unknown sort key %q (supported: family, given, org)", by)
+	}
+}
+
+// compareKeys compares sort keys in order, putting those without the
+// first key last
+func compareKeys(col *collate.Collator, a, b []string) int {
+	if (a[0] == "") != (b[0] == "") {
+		return cmpBool(a[0] == "", b[0] == "")
+	}
+	for i := range a {
+		if c := col.CompareString(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// Letters finds the index letter names are grouped under in an address
+// book, following the alphabet of a locale: Ö is filed under O in German
+// but is a letter of its own in Swedish, as Ñ is in Spanish.
+type Letters struct {
+	loose *collate.Collator
+}
+
+// NewLetters returns the Letters of a BCP 47 locale, the root collation
+// order when locale is empty
+func NewLetters(locale string) (*Letters, error) {
+	tag := language.Und
+	if locale != "" {
+		var err error
+		if tag, err = language.Parse(locale); err != nil {
+			return nil, fmt.Errorf("invalid locale %q: %w", locale, err)
+		}
+	}
+	return &Letters{loose: collate.New(tag, collate.Loose)}, nil
+}
+
+// Of returns the uppercase index letter of name, "#" when it doesn't start
+// with a letter
+func (l *Letters) Of(name string) string {
+	r := []rune(strings.TrimSpace(name))
+	if len(r) == 0 || !unicode.IsLetter(r[0]) {
+		return "#"
+	}
+	letter := strings.ToUpper(string(r[0]))
+	// The letter without accents, when the alphabet doesn't tell them apart
+	if base := []rune(norm.NFD.String(letter)); len(base) > 1 {
+		if l.loose.CompareString(string(base[0]), letter) == 0 {
+			return string(base[0])
+		}
+	}
+	return letter
+}
+
+// cmpBool orders false before true
+func cmpBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	}
+	return 1
+}
+
+// utf8BOM is the byte order mark some exporters start files with
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// splitCards returns the raw BEGIN:VCARD ... END:VCARD blocks of data,
+// including their line endings, after a leading byte order mark. Nested
+// cards stay in their parent. Anything but blank lines outside the cards
+// is an error, as it would be dropped.
+func splitCards(data []byte) ([][]byte, error) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	var cards [][]byte
+	depth, start, n := 0, 0, 0
+	for pos := 0; pos < len(data); {
+		n++
+		end := bytes.IndexByte(data[pos:], '\n')
+		if end < 0 {
+			end = len(data)
+		} else {
+			end += pos + 1
+		}
+		line := bytes.ToUpper(bytes.TrimSpace(data[pos:end]))
+		switch {
+		case bytes.Equal(line, []byte("BEGIN:VCARD")):
+			if depth == 0 {
+				start = pos
+			}
+			depth++
+		case bytes.Equal(line, []byte("END:VCARD")) && depth > 0:
+			depth--
+			if depth == 0 {
+				cards = append(cards, data[start:end])
+			}
+		case depth == 0 && len(line) > 0:
+			return nil, fmt.Errorf("line %d is outside any card: %q", n, bytes.TrimSpace(data[pos:end]))
+		}
+		pos = end
+	}
+	if depth > 0 {
+		return nil, fmt.Errorf("card %d has no END:VCARD", len(cards)+1)
+	}
+	return cards, nil
+}
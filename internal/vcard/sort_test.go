@@ -0,0 +1,134 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSortCards(t *testing.T) {
+	card := func(n, org string) string {
+		family, given, _ := strings.Cut(n, ";")
+		s := "BEGIN:VCARD\r\nVERSION:3.0\r\nFN:" + strings.TrimSpace(given+" "+family) + "\r\nN:" + n + ";;;\r\n"
+		if org != "" {
+			s += "ORG:" + org + "\r\n"
+		}
+		return s + "X-CUSTOM:kept\r\nEND:VCARD\r\n"
+	}
+	input := card("Zorn;Anders", "Acme") + card("Åberg;Eva", "") + card("Abel;Zoe", "Beta") + card(";", "Acme")
+
+	tests := []struct {
+		name   string
+		by     string
+		locale string
+		want   []string // FN order
+	}{
+		{"family root", SortFamily, "", []string{"Zoe Abel", "Eva Åberg", "Anders Zorn", ""}},
+		{"family swedish", SortFamily, "sv", []string{"Zoe Abel", "Anders Zorn", "Eva Åberg", ""}},
+		{"given", SortGiven, "", []string{"Anders Zorn", "Eva Åberg", "Zoe Abel", ""}},
+		{"org", SortOrg, "", []string{"", "Anders Zorn", "Zoe Abel", "Eva Åberg"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := SortCards([]byte(input), tt.by, tt.locale)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(out) != len(input) || strings.Count(string(out), "X-CUSTOM:kept") != 4 {
+				t.Errorf("cards not copied verbatim:\n%s", out)
+			}
+			var got []string
+			for _, line := range strings.Split(string(out), "\r\n") {
+				if fn, ok := strings.CutPrefix(line, "FN:"); ok {
+					got = append(got, fn)
+				}
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("order = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := SortCards([]byte(input), "phone", ""); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+
+	// A byte order mark is dropped, not taken for text outside the cards
+	out, err := SortCards([]byte("\xef\xbb\xbf"+card("Zorn;Anders", "")+card("Abel;Zoe", "")), SortFamily, "")
+	if err != nil {
+		t.Fatalf("SortCards() with a BOM error = %v", err)
+	}
+	if want := card("Abel;Zoe", "") + card("Zorn;Anders", ""); string(out) != want {
+		t.Errorf("SortCards() with a BOM =\n%q\nwant\n%q", out, want)
+	}
+
+	for name, bad := range map[string]string{
+		"text between cards": card("Zorn;Anders", "") + "FN:Stray\r\n" + card("Abel;Zoe", ""),
+		"unterminated card":  card("Zorn;Anders", "") + "BEGIN:VCARD\r\nFN:Zoe\r\n",
+	} {
+		if _, err := SortCards([]byte(bad), SortFamily, ""); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := SortCards([]byte("\r\n"+card("Zorn;Anders", "")+"\r\n\r\n"), SortFamily, ""); err != nil {
+		t.Errorf("blank lines between cards: %v", err)
+	}
+}
+
+func TestSortContacts(t *testing.T) {
+	contacts := []Contact{{FamilyName: "Ñúñez"}, {FamilyName: "Nuñez"}, {FamilyName: "Oliva"}, {FamilyName: "Nyman"}}
+	if err := SortContacts(contacts, SortFamily, "es"); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range contacts {
+		got = append(got, c.FamilyName)
+	}
+	if want := "Nuñez|Nyman|Ñúñez|Oliva"; strings.Join(got, "|") != want {
+		t.Errorf("order = %q, want %q", got, want)
+	}
+}
+
+func TestLetters_Of(t *testing.T) {
+	tests := []struct {
+		locale string
+		name   string
+		want   string
+	}{
+		{"", "Östberg", "O"},
+		{"de", "Özdemir", "O"},
+		{"sv", "Östberg", "Ö"},
+		{"es", "Ñúñez", "Ñ"},
+		{"es", "Álvarez", "A"},
+		{"", "émile", "E"},
+		{"", "42 Club", "#"},
+	}
+	for _, tt := range tests {
+		l, err := NewLetters(tt.locale)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := l.Of(tt.name); got != tt.want {
+			t.Errorf("Letters(%q).Of(%q) = %q, want %q", tt.locale, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSystemLocale(t *testing.T) {
+	tests := []struct {
+		lcAll, lang string
+		want        string
+	}{
+		{"", "es_ES.UTF-8", "es-ES"},
+		{"sv_SE.UTF-8@euro", "es_ES.UTF-8", "sv-SE"},
+		{"C", "es_ES.UTF-8", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_COLLATE", "")
+		t.Setenv("LANG", tt.lang)
+		if got := SystemLocale(); got != tt.want {
+			t.Errorf("SystemLocale() with LC_ALL=%q LANG=%q = %q, want %q", tt.lcAll, tt.lang, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package vcard
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"strings"
+)
+
+// objectUIDNamespace is the UUID namespace of the UIDs derived from object
+// IDs (a random UUID, fixed forever so the UIDs are stable)
+var objectUIDNamespace = [16]byte{0x6b, 0x1f, 0x3c, 0x52, 0x9e, 0x0a, 0x4d, 0x7b, 0xa1, 0x58, 0x2e, 0xc4, 0x90, 0x37, 0xd6, 0x8f}
+
+// ObjectUID returns the UID of the contact stored in the Anytype object
+// objectID: a name-based (version 5) UUID URN, the same on every export
+func ObjectUID(objectID string) string {
+	h := sha1.New()
+	h.Write(objectUIDNamespace[:])
+	h.Write([]byte(objectID))
+	u := h.Sum(nil)[:16]
+	u[6] = u[6]&0x0f | 0x50 // Version 5
+	u[8] = u[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
+
+// ExportUID returns the UID to export the contact with: its own, else the
+// ObjectUID of its object, so cards round-tripped through other clients
+// are matched back to the object
+func (c *Contact) ExportUID() string {
+	if c.UID != "" || c.ObjectID == "" {
+		return c.UID
+	}
+	return ObjectUID(c.ObjectID)
+}
+
+// uidKeys returns the keys of the UIDs identifying the contact: its own and
+// the ObjectUID of its object
+func (c *Contact) uidKeys() []string {
+	var keys []string
+	if uid := strings.ToLower(strings.TrimSpace(c.UID)); uid != "" {
+		keys = append(keys, uid)
+	}
+	if c.ObjectID != "" {
+		if uid := ObjectUID(c.ObjectID); len(keys) == 0 || keys[0] != uid {
+			keys = append(keys, uid)
+		}
+	}
+	return keys
+}
+
+// sharedUID returns a UID identifying both a and b, or ""
+func sharedUID(a, b *Contact) string {
+	for _, ka := range a.uidKeys() {
+		for _, kb := range b.uidKeys() {
+			if ka == kb {
+				return ka
+			}
+		}
+	}
+	return ""
+}
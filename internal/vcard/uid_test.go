@@ -0,0 +1,69 @@
+package vcard
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestObjectUID(t *testing.T) {
+	uid := ObjectUID("bafyreiabc")
+	if uid != ObjectUID("bafyreiabc") {
+		t.Error("ObjectUID() is not stable")
+	}
+	if uid == ObjectUID("bafyreiabd") {
+		t.Error("ObjectUID() is the same for different objects")
+	}
+	if !regexp.MustCompile(`^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(uid) {
+		t.Errorf("ObjectUID() = %q, want a version 5 UUID URN", uid)
+	}
+}
+
+func TestEncode_ExportUID(t *testing.T) {
+	tests := []struct {
+		name    string
+		contact Contact
+		want    string
+	}{
+		{"own UID", Contact{FormattedName: "Jane", UID: "abc-123", ObjectID: "obj1"}, "abc-123"},
+		{"object", Contact{FormattedName: "Jane", ObjectID: "obj1"}, ObjectUID("obj1")},
+		{"neither", Contact{FormattedName: "Jane"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := Write(&b, []Contact{tt.contact}, Version4); err != nil {
+				t.Fatal(err)
+			}
+			contacts, err := Parse(strings.NewReader(b.String()))
+			if err != nil || len(contacts) != 1 {
+				t.Fatalf("Parse() = %v, %v", contacts, err)
+			}
+			if contacts[0].UID != tt.want {
+				t.Errorf("UID = %q, want %q", contacts[0].UID, tt.want)
+			}
+		})
+	}
+}
+
+func TestDedupIndex_UIDRoundTrip(t *testing.T) {
+	existing := &Contact{FormattedName: "Jane Doe", ObjectID: "obj1"}
+	idx := NewDedupIndex([]*Contact{existing, {FormattedName: "John Roe", UID: "ABC-123"}})
+
+	// Renamed and without the phone in another client, only the UID is left
+	back := &Contact{FormattedName: "Jane Smith", UID: existing.ExportUID()}
+	matches := idx.FindMatches(back)
+	if len(matches) != 1 || matches[0].Contact != existing || matches[0].Score != UIDWeight {
+		t.Fatalf("FindMatches() = %+v", matches)
+	}
+	if CompareContacts(back, existing, DedupOptions{}) != MatchStrong {
+		t.Error("CompareContacts() of the same UID is not strong")
+	}
+
+	if !idx.IsDuplicate(&Contact{FormattedName: "J. Roe", UID: "abc-123"}) {
+		t.Error("same UID in another case did not match")
+	}
+	if idx.IsDuplicate(&Contact{FormattedName: "Someone", UID: "other"}) {
+		t.Error("different UID matched")
+	}
+}
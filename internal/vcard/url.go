@@ -0,0 +1,89 @@
+package vcard
+
+import (
+	"net/url"
+	"strings"
+)
+
+// urlTrailingGarbage are characters commonly glued to URLs by exports and
+// copy/paste. Closing brackets are only garbage when unmatched, see
+// trimURLGarbage.
+const urlTrailingGarbage = ".,;:!?>\"' \t"
+
+// urlBrackets maps the closing brackets stripped from the end of URLs to
+// their opening ones
+var urlBrackets = map[byte]byte{')': '(', ']': '[', '}': '{'}
+
+// NormalizeURL cleans a URL for storage.
+// Handles: surrounding garbage, missing scheme, host case
+func NormalizeURL(raw string) string {
+	u := strings.TrimSpace(raw)
+	u = strings.TrimLeft(u, "<([{\"'")
+	u = trimURLGarbage(u)
+	if u == "" {
+		return ""
+	}
+
+	if !strings.Contains(u, "://") && !hasOpaqueScheme(u) {
+		u = "https://" + strings.TrimPrefix(u, "//")
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.Host == "" {
+		return u
+	}
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+	return parsed.String()
+}
+
+// trimURLGarbage strips urlTrailingGarbage from the end of u, and closing
+// brackets the URL doesn't open, so https://en.wikipedia.org/wiki/Go_(game)
+// keeps its last parenthesis
+func trimURLGarbage(u string) string {
+	for {
+		u = strings.TrimRight(u, urlTrailingGarbage)
+		if u == "" {
+			return u
+		}
+		closing := u[len(u)-1]
+		opening, ok := urlBrackets[closing]
+		if !ok || strings.Count(u, string(opening)) >= strings.Count(u, string(closing)) {
+			return u
+		}
+		u = u[:len(u)-1]
+	}
+}
+
+// hasOpaqueScheme reports whether u uses a scheme without authority (mailto:, tel:)
+func hasOpaqueScheme(u string) bool {
+	scheme, _, ok := strings.Cut(u, ":")
+	if !ok {
+		return false
+	}
+	switch strings.ToLower(scheme) {
+	case "mailto", "tel", "sms", "xmpp", "sip", "skype":
+		return true
+	}
+	return false
+}
+
+// NormalizeURLs normalizes each URL and drops duplicates that differ only
+// by a trailing slash, keeping the first occurrence
+func NormalizeURLs(urls []string) []string {
+	seen := make(map[string]struct{})
+	var result []string
+	for _, raw := range urls {
+		u := NormalizeURL(raw)
+		if u == "" {
+			continue
+		}
+		key := strings.TrimRight(u, "/")
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, u)
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+package vcard
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"already normalized", "https://example.com/path", "https://example.com/path"},
+		{"missing scheme", "example.com", "https://example.com"},
+		{"missing scheme with www", "www.example.com/about", "https://www.example.com/about"},
+		{"protocol relative", "//example.com", "https://example.com"},
+		{"http kept", "http://example.com", "http://example.com"},
+		{"uppercase host", "HTTPS://Example.COM/Path", "https://example.com/Path"},
+		{"trailing punctuation", "https://example.com/page.", "https://example.com/page"},
+		{"wrapped in brackets", "<https://example.com>", "https://example.com"},
+		{"wrapped in parentheses", "(https://example.com/a)", "https://example.com/a"},
+		{"balanced parentheses kept", "https://en.wikipedia.org/wiki/Go_(game)", "https://en.wikipedia.org/wiki/Go_(game)"},
+		{"balanced parentheses in parentheses", "(https://en.wikipedia.org/wiki/Go_(game)).", "https://en.wikipedia.org/wiki/Go_(game)"},
+		{"unmatched square bracket", "https://example.com/a]", "https://example.com/a"},
+		{"trailing quote and comma", "https://example.com\",", "https://example.com"},
+		{"whitespace", "  example.com  ", "https://example.com"},
+		{"mailto untouched", "mailto:john@example.com", "mailto:john@example.com"},
+		{"empty", "", ""},
+		{"only garbage", " ., ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NormalizeURL(tt.input)
+			if got != tt.expected {
+				t.Errorf("NormalizeURL(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeURLs(t *testing.T) {
+	got := NormalizeURLs([]string{
+		"example.com/",
+		"https://EXAMPLE.com",
+		"https://other.com",
+		"",
+		"https://other.com/",
+	})
+	want := []string{"https://example.com/", "https://other.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizeURLs() = %v, want %v", got, want)
+	}
+}
+
+func TestBuildProperties_NormalizesURLs(t *testing.T) {
+	c := Contact{
+		FormattedName: "John Doe",
+		URLs:          []string{"Example.com/", "https://example.com", "blog.example.com."},
+	}
+	props := BuildProperties(c, nil, nil)
+
+	var url, notes string
+	for _, p := range props {
+		switch p["key"] {
+		case "url":
+			url, _ = p["url"].(string)
+		case "notes":
+			notes, _ = p["text"].(string)
+		}
+	}
+	if url != "https://example.com/" {
+		t.Errorf("url property = %q, want %q", url, "https://example.com/")
+	}
+	if notes != "Additional URLs: https://blog.example.com" {
+		t.Errorf("notes = %q", notes)
+	}
+}
@@ -2,6 +2,7 @@ package vcard
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -10,27 +11,62 @@ import (
 	"time"
 
 	govcard "github.com/emersion/go-vcard"
+	"github.com/rubiojr/any-vcard/internal/profile"
 	"github.com/rubiojr/anytype-go"
 )
 
 // Contact represents a parsed vCard contact
 type Contact struct {
-	FormattedName string
-	GivenName     string
-	FamilyName    string
-	MiddleName    string
-	Prefix        string
-	Suffix        string
-	Emails        []string
-	Phones        []string
-	Addresses     []Address
-	Organization  string
-	Title         string
-	URLs          []string
-	Note          string
-	Birthday      string
-	Photo         string
-	ObjectID      string // Anytype object ID (used for merge operations)
+	FormattedName  string         `json:"formatted_name,omitempty"`
+	GivenName      string         `json:"given_name,omitempty"`
+	FamilyName     string         `json:"family_name,omitempty"`
+	MiddleName     string         `json:"middle_name,omitempty"`
+	Prefix         string         `json:"prefix,omitempty"`
+	Suffix         string         `json:"suffix,omitempty"`
+	Nickname       string         `json:"nickname,omitempty"`
+	Emails         []string       `json:"emails,omitempty"`
+	Phones         []string       `json:"phones,omitempty"`
+	Addresses      []Address      `json:"addresses,omitempty"`
+	Organization   string         `json:"organization,omitempty"`
+	Department     string         `json:"department,omitempty"` // Organizational units after the company in ORG
+	Title          string         `json:"title,omitempty"`
+	Role           string         `json:"role,omitempty"`      // Function within the organization (ROLE), distinct from the job title
+	Assistant      string         `json:"assistant,omitempty"` // Name of the contact's assistant
+	Manager        string         `json:"manager,omitempty"`   // Name of the contact's manager
+	URLs           []string       `json:"urls,omitempty"`
+	Note           string         `json:"note,omitempty"`
+	Birthday       string         `json:"birthday,omitempty"`
+	BirthdayText   string         `json:"birthday_text,omitempty"` // Year-less birthday (--MM-DD) when not stored as a date
+	Age            *int           `json:"age,omitempty"`           // Computed from Birthday when birthday fields are enabled
+	NextBirthday   string         `json:"next_birthday,omitempty"` // Computed from Birthday when birthday fields are enabled (RFC3339)
+	Completeness   *int           `json:"completeness,omitempty"`  // Computed by ComputeCompleteness when completeness scoring is enabled
+	Photo          string         `json:"photo,omitempty"`
+	Categories     []string       `json:"categories,omitempty"`      // Groups or labels the contact belongs to
+	Favorite       bool           `json:"favorite,omitempty"`        // Starred in the source address book
+	Kind           string         `json:"kind,omitempty"`            // KindOrganization for company cards, empty for people
+	Source         string         `json:"source,omitempty"`          // File or provider the contact was read from
+	UID            string         `json:"uid,omitempty"`             // Stable identifier from the source (vCard UID, provider ID)
+	ObjectID       string         `json:"object_id,omitempty"`       // Anytype object ID (used for merge operations)
+	TypeKey        string         `json:"type_key,omitempty"`        // Anytype type of the object, set by FromObject
+	LastModified   string         `json:"last_modified,omitempty"`   // Anytype object modification time (RFC3339)
+	Revision       string         `json:"revision,omitempty"`        // Last revision in the source (vCard REV)
+	Snapshot       string         `json:"-"`                         // vCard of the last import into the object, the base of three-way merges
+	History        string         `json:"history,omitempty"`         // One line per merge: date, source and fields changed
+	OriginalPhones []string       `json:"original_phones,omitempty"` // Phone values before reformatting, kept in notes
+	Enriched       []Enrichment   `json:"enriched,omitempty"`        // Values filled in from public sources, kept in notes
+	Properties     map[string]any `json:"properties,omitempty"`      // Raw Anytype property values by key, set by FromObject
+	Unmapped       []Unmapped     `json:"unmapped,omitempty"`        // vCard values with no field above, see DroppedFields
+
+	indexID uint64 // Assigned by IndexStores that keep contacts on disk
+}
+
+// KindOrganization is the Kind of cards describing a company rather than
+// a person (vCard 4.0 KIND:org, Apple's X-ABSHOWAS:COMPANY)
+const KindOrganization = "org"
+
+// IsOrganization reports whether the contact describes a company
+func (c Contact) IsOrganization() bool {
+	return c.Kind == KindOrganization
 }
 
 // DisplayName returns the best available name for the contact
@@ -50,12 +86,20 @@ func (c Contact) DisplayName() string {
 
 // Address represents a physical address
 type Address struct {
-	Street     string
-	City       string
-	Region     string
-	PostalCode string
-	Country    string
-	Full       string
+	Street     string `json:"street,omitempty"`
+	City       string `json:"city,omitempty"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Country    string `json:"country,omitempty"`
+	Full       string `json:"full,omitempty"`
+	Geo        *Geo   `json:"geo,omitempty"` // Resolved coordinates, set when geocoding is enabled
+}
+
+// Geo holds the coordinates of an address
+type Geo struct {
+	Lat    float64 `json:"lat"`
+	Lon    float64 `json:"lon"`
+	MapURL string  `json:"map_url,omitempty"`
 }
 
 // filterEmpty returns only non-empty strings
@@ -75,6 +119,25 @@ func NormalizePhone(phone string) string {
 	return strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(phone)
 }
 
+// SplitOrganization splits an ORG value like "Acme Corp;Engineering;Platform"
+// into the company name and its departments, joined with ", "
+func SplitOrganization(org string) (company, department string) {
+	parts := strings.Split(org, ";")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts[0], strings.Join(filterEmpty(parts[1:]...), ", ")
+}
+
+// JoinOrganization is the reverse of SplitOrganization, writing each
+// department as its own ORG unit
+func JoinOrganization(company, department string) string {
+	if department == "" {
+		return company
+	}
+	return company + ";" + strings.Join(strings.Split(department, ", "), ";")
+}
+
 // ParseFile parses a vCard file and returns the contacts
 func ParseFile(filePath string) ([]Contact, error) {
 	file, err := os.Open(filePath)
@@ -83,33 +146,66 @@ func ParseFile(filePath string) ([]Contact, error) {
 	}
 	defer file.Close()
 
-	decoder := govcard.NewDecoder(file)
+	return Parse(file)
+}
+
+// Parse reads all vCards from r and returns the contacts
+func Parse(r io.Reader) ([]Contact, error) {
+	decoder := NewDecoder(r)
 	var contacts []Contact
 
 	for {
-		card, err := decoder.Decode()
+		contact, err := decoder.Next()
 		if errors.Is(err, io.EOF) {
 			break
 		}
 		if err != nil {
-			return contacts, fmt.Errorf("failed to decode vCard: %w", err)
+			return contacts, err
 		}
-		contacts = append(contacts, parseCard(card))
+		contacts = append(contacts, *contact)
 	}
 
 	return contacts, nil
 }
 
+// Decoder reads contacts one at a time from a vCard stream
+type Decoder struct {
+	dec *govcard.Decoder
+}
+
+// NewDecoder creates a Decoder reading from r
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{dec: govcard.NewDecoder(r)}
+}
+
+// Next decodes the next contact. Returns io.EOF when the stream is exhausted.
+func (d *Decoder) Next() (*Contact, error) {
+	card, err := d.dec.Decode()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode vCard: %w", err)
+	}
+	contact := parseCard(card)
+	return &contact, nil
+}
+
 func parseCard(card govcard.Card) Contact {
 	contact := Contact{
 		FormattedName: card.PreferredValue(govcard.FieldFormattedName),
-		Organization:  card.PreferredValue(govcard.FieldOrganization),
 		Title:         card.PreferredValue(govcard.FieldTitle),
+		Role:          card.PreferredValue(govcard.FieldRole),
+		Nickname:      card.PreferredValue(govcard.FieldNickname),
 		Note:          card.PreferredValue(govcard.FieldNote),
 		Birthday:      card.PreferredValue(govcard.FieldBirthday),
 		Photo:         card.PreferredValue(govcard.FieldPhoto),
+		UID:           card.Value(govcard.FieldUID),
+		Revision:      card.Value(govcard.FieldRevision),
 	}
 
+	contact.Organization, contact.Department = SplitOrganization(card.PreferredValue(govcard.FieldOrganization))
+
 	if names := card.Name(); names != nil {
 		contact.FamilyName = names.FamilyName
 		contact.GivenName = names.GivenName
@@ -121,6 +217,16 @@ func parseCard(card govcard.Card) Contact {
 	contact.Emails = parseFieldValues(card, govcard.FieldEmail, "mailto:")
 	contact.Phones = parseFieldValues(card, govcard.FieldTelephone, "tel:")
 	contact.URLs = parseFieldValues(card, govcard.FieldURL, "")
+	contact.Categories = filterEmpty(card.Categories()...)
+	if len(contact.Categories) == 0 {
+		contact.Categories = nil
+	}
+	contact.Favorite = isFavoriteCard(card)
+	contact.Unmapped = unmappedFields(card)
+	parseRelations(card, &contact)
+	if card.Kind() == govcard.KindOrganization || strings.EqualFold(card.Value("X-ABSHOWAS"), "COMPANY") {
+		contact.Kind = KindOrganization
+	}
 
 	if addr := card.Address(); addr != nil {
 		street := addr.StreetAddress
@@ -156,44 +262,87 @@ func parseFieldValues(card govcard.Card, field, trimPrefix string) []string {
 	return result
 }
 
-// ParseBirthday attempts to parse birthday in common formats
+// ParseBirthday attempts to parse birthday in common formats.
+// Year-less birthdays use YearlessBirthdayPlaceholder as the year.
 func ParseBirthday(bday string) string {
-	formats := []string{"20060102", "2006-01-02"}
-	for _, format := range formats {
-		if t, err := time.Parse(format, bday); err == nil {
-			return t.Format(time.RFC3339)
-		}
+	if b, ok := ParseBirthdayDate(bday); ok {
+		return b.Time(YearlessBirthdayPlaceholder).Format(time.RFC3339)
 	}
 	return bday
 }
 
 // BuildNotes constructs the notes field including overflow data
 func BuildNotes(contact Contact) string {
+	return buildNotes(contact, false)
+}
+
+// BuildLinkedNotes is BuildNotes with the emails and phones kept in the
+// notes written as Markdown mailto: and tel: links, clickable in Anytype
+func BuildLinkedNotes(contact Contact) string {
+	return buildNotes(contact, true)
+}
+
+func buildNotes(contact Contact, links bool) string {
+	list := func(values []string, link func(string) string) string {
+		if !links {
+			return strings.Join(values, ", ")
+		}
+		linked := make([]string, len(values))
+		for i, v := range values {
+			linked[i] = link(v)
+		}
+		return strings.Join(linked, ", ")
+	}
+
 	var notes []string
 	if contact.Note != "" {
 		notes = append(notes, contact.Note)
 	}
+	if contact.Nickname != "" {
+		notes = append(notes, "Nickname: "+contact.Nickname)
+	}
 	if len(contact.Emails) > 3 {
-		notes = append(notes, "Additional emails: "+strings.Join(contact.Emails[3:], ", "))
+		notes = append(notes, "Additional emails: "+list(contact.Emails[3:], MailtoLink))
 	}
 	if len(contact.URLs) > 1 {
 		notes = append(notes, "Additional URLs: "+strings.Join(contact.URLs[1:], ", "))
 	}
+	if len(contact.Categories) > 0 {
+		notes = append(notes, "Groups: "+strings.Join(contact.Categories, ", "))
+	}
+	if len(contact.OriginalPhones) > 0 {
+		notes = append(notes, "Original phones: "+list(contact.OriginalPhones, TelLink))
+	}
+	if enriched := enrichedNotes(contact.Enriched); enriched != "" {
+		notes = append(notes, enriched)
+	}
 	return strings.Join(notes, "\n\n")
 }
 
 // Import creates an Anytype object from a Contact
 func Import(ctx context.Context, client anytype.Client, spaceID, typeKey string, phoneKeys, emailKeys []string, contact Contact, templateID string) error {
-	name := contact.DisplayName()
-	props := BuildProperties(contact, phoneKeys, emailKeys)
+	_, err := CreateObject(ctx, client, spaceID, typeKey, contact.DisplayName(), Icon(contact), BuildProperties(contact, phoneKeys, emailKeys), templateID)
+	return err
+}
+
+// Icon returns the emoji icon of the contact's object
+func Icon(contact Contact) string {
+	if contact.IsOrganization() {
+		return "🏢"
+	}
+	return "👤"
+}
 
+// CreateObject creates a contact object with already built properties and
+// an emoji icon, and returns its ID
+func CreateObject(ctx context.Context, client anytype.Client, spaceID, typeKey, name, emoji string, props []map[string]any, templateID string) (string, error) {
 	req := anytype.CreateObjectRequest{
 		TypeKey:    typeKey,
 		Name:       name,
 		Properties: props,
 		Icon: &anytype.Icon{
 			Format: anytype.IconFormatEmoji,
-			Emoji:  "👤",
+			Emoji:  emoji,
 		},
 	}
 
@@ -201,8 +350,13 @@ func Import(ctx context.Context, client anytype.Client, spaceID, typeKey string,
 		req.TemplateID = templateID
 	}
 
-	_, err := client.Space(spaceID).Objects().Create(ctx, req)
-	return err
+	defer profile.Start(profile.API)()
+	profile.AddUploaded(requestSize(req))
+	resp, err := client.Space(spaceID).Objects().Create(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return resp.Object.ID, nil
 }
 
 // Update updates an existing Anytype object with contact data
@@ -211,19 +365,35 @@ func Update(ctx context.Context, client anytype.Client, spaceID string, phoneKey
 		return fmt.Errorf("contact has no ObjectID")
 	}
 
-	props := BuildProperties(*contact, phoneKeys, emailKeys)
+	return UpdateObject(ctx, client, spaceID, contact.ObjectID, BuildProperties(*contact, phoneKeys, emailKeys))
+}
 
+// UpdateObject sets already built properties on an existing object
+func UpdateObject(ctx context.Context, client anytype.Client, spaceID, objectID string, props []map[string]any) error {
 	req := anytype.UpdateObjectRequest{
 		Properties: props,
 	}
 
-	return client.Space(spaceID).Object(contact.ObjectID).Update(ctx, req)
+	defer profile.Start(profile.API)()
+	profile.AddUploaded(requestSize(req))
+	return client.Space(spaceID).Object(objectID).Update(ctx, req)
+}
+
+// requestSize returns the approximate size of a request body
+func requestSize(req any) int {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return 0
+	}
+	return len(data)
 }
 
 // BuildProperties constructs the properties slice for a contact
 func BuildProperties(contact Contact, phoneKeys, emailKeys []string) []map[string]any {
 	var props []map[string]any
 
+	contact.URLs = NormalizeURLs(contact.URLs)
+
 	addProp := func(key string, value map[string]any) {
 		value["key"] = key
 		props = append(props, value)
@@ -240,11 +410,14 @@ func BuildProperties(contact Contact, phoneKeys, emailKeys []string) []map[strin
 		addTextProp("name", name)
 	}
 
-	addTextProp("given_name", contact.GivenName)
-	addTextProp("family_name", contact.FamilyName)
-	addTextProp("middle_name", contact.MiddleName)
-	addTextProp("prefix", contact.Prefix)
-	addTextProp("suffix", contact.Suffix)
+	// Company cards often repeat the company name as a person name
+	if !contact.IsOrganization() {
+		addTextProp("given_name", contact.GivenName)
+		addTextProp("family_name", contact.FamilyName)
+		addTextProp("middle_name", contact.MiddleName)
+		addTextProp("prefix", contact.Prefix)
+		addTextProp("suffix", contact.Suffix)
+	}
 
 	for i, email := range contact.Emails {
 		if i >= len(emailKeys) {
@@ -267,10 +440,21 @@ func BuildProperties(contact Contact, phoneKeys, emailKeys []string) []map[strin
 		addTextProp("region", addr.Region)
 		addTextProp("postal_code", addr.PostalCode)
 		addTextProp("country", addr.Country)
+		if addr.Geo != nil {
+			addProp("latitude", map[string]any{"number": addr.Geo.Lat})
+			addProp("longitude", map[string]any{"number": addr.Geo.Lon})
+			if addr.Geo.MapURL != "" {
+				addProp("map_url", map[string]any{"url": addr.Geo.MapURL})
+			}
+		}
 	}
 
 	addTextProp("organization", contact.Organization)
+	addTextProp("department", contact.Department)
 	addTextProp("title", contact.Title)
+	addTextProp("role", contact.Role)
+	addTextProp("assistant", contact.Assistant)
+	addTextProp("manager", contact.Manager)
 
 	if len(contact.URLs) > 0 {
 		addProp("url", map[string]any{"url": contact.URLs[0]})
@@ -284,6 +468,14 @@ func BuildProperties(contact Contact, phoneKeys, emailKeys []string) []map[strin
 	if contact.Birthday != "" {
 		addProp("birthday", map[string]any{"date": ParseBirthday(contact.Birthday)})
 	}
+	addTextProp("birthday_text", contact.BirthdayText)
+	props = append(props, BirthdayFieldProperties(contact)...)
+	props = append(props, CompletenessProperties(contact)...)
+
+	// Only ever set the checkbox so merges don't unstar existing contacts
+	if contact.Favorite {
+		addProp("favorite", map[string]any{"checkbox": true})
+	}
 
 	return props
 }
@@ -0,0 +1,188 @@
+package vcard
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+const twoCards = `BEGIN:VCARD
+VERSION:3.0
+FN:John Doe
+EMAIL:john@example.com
+END:VCARD
+BEGIN:VCARD
+VERSION:3.0
+FN:Jane Smith
+TEL:+1-555-234-5678
+END:VCARD
+`
+
+func TestDecoder_Next(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(twoCards))
+
+	var names []string
+	for {
+		c, err := dec.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		names = append(names, c.FormattedName)
+	}
+
+	if len(names) != 2 || names[0] != "John Doe" || names[1] != "Jane Smith" {
+		t.Errorf("decoded names = %v", names)
+	}
+
+	// Further calls keep returning EOF
+	if _, err := dec.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("Next() after end = %v, want io.EOF", err)
+	}
+}
+
+func TestParse_Malformed(t *testing.T) {
+	input := twoCards + "BEGIN:VCARD\nFN\n"
+	contacts, err := Parse(strings.NewReader(input))
+	if err == nil {
+		t.Error("expected an error for malformed input")
+	}
+	if len(contacts) != 2 {
+		t.Errorf("expected the 2 valid contacts before the error, got %d", len(contacts))
+	}
+}
+
+func TestParse_Kind(t *testing.T) {
+	input := `BEGIN:VCARD
+VERSION:4.0
+KIND:org
+FN:Acme Inc
+N:Acme Inc;;;;
+ORG:Acme Inc
+END:VCARD
+BEGIN:VCARD
+VERSION:3.0
+FN:Globex
+ORG:Globex
+X-ABSHOWAS:COMPANY
+END:VCARD
+BEGIN:VCARD
+VERSION:3.0
+FN:John Doe
+ORG:Acme Inc
+END:VCARD
+`
+	contacts, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	for i, want := range []bool{true, true, false} {
+		if got := contacts[i].IsOrganization(); got != want {
+			t.Errorf("%s: IsOrganization() = %v, want %v", contacts[i].FormattedName, got, want)
+		}
+	}
+
+	// The person name of a company card is not written
+	for _, p := range BuildProperties(contacts[0], nil, nil) {
+		if p["key"] == "family_name" {
+			t.Errorf("unexpected family_name %v for a company", p["text"])
+		}
+	}
+	if Icon(contacts[0]) != "🏢" || Icon(contacts[2]) != "👤" {
+		t.Errorf("Icon() = %q, %q", Icon(contacts[0]), Icon(contacts[2]))
+	}
+}
+
+func TestSplitOrganization(t *testing.T) {
+	tests := []struct {
+		org, company, department string
+	}{
+		{"Acme Corp", "Acme Corp", ""},
+		{"Acme Corp;Engineering;Platform", "Acme Corp", "Engineering, Platform"},
+		{"Acme Corp; Sales ;", "Acme Corp", "Sales"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		company, department := SplitOrganization(tt.org)
+		if company != tt.company || department != tt.department {
+			t.Errorf("SplitOrganization(%q) = %q, %q, want %q, %q", tt.org, company, department, tt.company, tt.department)
+		}
+	}
+}
+
+func TestJoinOrganization(t *testing.T) {
+	tests := []struct {
+		company, department, org string
+	}{
+		{"Acme Corp", "", "Acme Corp"},
+		{"Acme Corp", "Engineering", "Acme Corp;Engineering"},
+		{"Acme Corp", "Engineering, Platform", "Acme Corp;Engineering;Platform"},
+		{"", "Sales", ";Sales"},
+	}
+	for _, tt := range tests {
+		if got := JoinOrganization(tt.company, tt.department); got != tt.org {
+			t.Errorf("JoinOrganization(%q, %q) = %q, want %q", tt.company, tt.department, got, tt.org)
+		}
+	}
+}
+
+func TestScoreContacts_OrganizationIgnoresDepartment(t *testing.T) {
+	a := &Contact{FormattedName: "John Doe", Organization: "Acme Corp", Department: "Engineering"}
+	b := &Contact{FormattedName: "John Doe", Organization: "Acme Corp;Sales"} // Stored before splitting
+	m := ScoreContacts(a, b, DedupOptions{})
+	found := false
+	for _, r := range m.Reasons {
+		if r == "organization Acme Corp" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Reasons = %v, want the organization without departments", m.Reasons)
+	}
+}
+
+func TestParse_Role(t *testing.T) {
+	input := "BEGIN:VCARD\nVERSION:3.0\nFN:Jane\nTITLE:Engineer\nROLE:Tech Lead\nEND:VCARD\n"
+	contacts, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	c := contacts[0]
+	if c.Title != "Engineer" || c.Role != "Tech Lead" {
+		t.Errorf("Title, Role = %q, %q", c.Title, c.Role)
+	}
+
+	existing := Contact{FormattedName: "Jane", Title: "Engineer"}
+	if !MergeContacts(&existing, &c) || existing.Role != "Tech Lead" {
+		t.Errorf("merged Role = %q, want Tech Lead", existing.Role)
+	}
+}
+
+func TestMergeContactsWith_Provenance(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		provenance string
+		source     string
+		want       string
+	}{
+		{"header", DefaultProvenance, "icloud.vcf", "Old\n\n--- merged from icloud.vcf on 2024-06-01 ---\n\nNew"},
+		{"custom", "from {source}", "work.vcf", "Old\n\n--- from work.vcf ---\n\nNew"},
+		{"disabled", "", "icloud.vcf", "Old\n\n---\n\nNew"},
+		{"unknown source", DefaultProvenance, "", "Old\n\n---\n\nNew"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := &Contact{Note: "Old"}
+			src := &Contact{Note: "New", Source: tt.source}
+			MergeContactsWith(dst, src, MergeOptions{Provenance: tt.provenance, Now: now})
+			if dst.Note != tt.want {
+				t.Errorf("Note = %q, want %q", dst.Note, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+// Package anyvcard exposes the any-vcard import pipeline as a library:
+// vCard parsing, contact deduplication and merging, and importing into
+// an Anytype space.
+package anyvcard
+
+import (
+	"context"
+	"io"
+
+	"github.com/rubiojr/any-vcard/internal/source"
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// Contact represents a parsed vCard contact
+type Contact struct {
+	FormattedName string
+	GivenName     string
+	FamilyName    string
+	MiddleName    string
+	Prefix        string
+	Suffix        string
+	Nickname      string
+	Emails        []string
+	Phones        []string
+	Addresses     []Address
+	Organization  string
+	Department    string // Organizational units after the company in ORG
+	Title         string
+	Role          string // Function within the organization (ROLE), distinct from the job title
+	Assistant     string // Name of the contact's assistant
+	Manager       string // Name of the contact's manager
+	URLs          []string
+	Note          string
+	Birthday      string
+	Photo         string
+	Categories    []string // Groups or labels the contact belongs to
+	Favorite      bool     // Starred in the source address book
+	Kind          string   // "org" for company cards, empty for people
+	Source        string   // File or provider the contact was read from
+	UID           string   // Stable identifier from the source (vCard UID, provider ID)
+	Revision      string   // Last revision in the source (vCard REV)
+	ObjectID      string   // Anytype object ID, set for contacts stored in a space
+}
+
+// DisplayName returns the best available name for the contact
+func (c Contact) DisplayName() string {
+	return toInternal(&c).DisplayName()
+}
+
+// Address represents a physical address
+type Address struct {
+	Street     string
+	City       string
+	Region     string
+	PostalCode string
+	Country    string
+	Full       string
+}
+
+// MatchStrength indicates how confident we are in a duplicate match
+type MatchStrength int
+
+const (
+	MatchNone   MatchStrength = iota
+	MatchWeak                 // Name only
+	MatchMedium               // Name + partial data overlap
+	MatchStrong               // Phone or email match
+)
+
+// String returns the lowercase name of the strength
+func (m MatchStrength) String() string {
+	switch m {
+	case MatchWeak:
+		return "weak"
+	case MatchMedium:
+		return "medium"
+	case MatchStrong:
+		return "strong"
+	}
+	return "none"
+}
+
+// Match is a duplicate candidate with a confidence score and the reasons
+// behind it
+type Match struct {
+	Contact  *Contact
+	Score    float64 // Confidence from 0 to 1 that both are the same person
+	Strength MatchStrength
+	Reasons  []string // What they share, e.g. "phone 612345678", "email jane@example.com"
+}
+
+// ParseFile parses a vCard file and returns the contacts
+func ParseFile(path string) ([]Contact, error) {
+	contacts, err := vcard.ParseFile(path)
+	return fromInternalSlice(contacts), err
+}
+
+// ParseReader reads all vCards from r and returns the contacts
+func ParseReader(r io.Reader) ([]Contact, error) {
+	contacts, err := vcard.Parse(r)
+	return fromInternalSlice(contacts), err
+}
+
+// Decoder reads contacts one at a time from a vCard stream
+type Decoder struct {
+	dec *vcard.Decoder
+}
+
+// NewDecoder creates a Decoder reading from r. Call Next until it returns io.EOF.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{dec: vcard.NewDecoder(r)}
+}
+
+// Next decodes the next contact. Returns io.EOF when the stream is exhausted.
+func (d *Decoder) Next() (*Contact, error) {
+	c, err := d.dec.Next()
+	if err != nil {
+		return nil, err
+	}
+	return fromInternal(c), nil
+}
+
+// Source yields contacts one at a time, returning io.EOF when done
+type Source interface {
+	Next(ctx context.Context) (*Contact, error)
+	Close() error
+}
+
+// OpenSource returns the Source for a file path based on its extension
+func OpenSource(path string) (Source, error) {
+	src, err := source.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSource{src: src}, nil
+}
+
+// fileSource adapts the internal sources to Source
+type fileSource struct {
+	src source.Source
+}
+
+func (s *fileSource) Next(ctx context.Context) (*Contact, error) {
+	c, err := s.src.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return fromInternal(c), nil
+}
+
+func (s *fileSource) Close() error {
+	return s.src.Close()
+}
+
+// DedupIndex provides efficient contact deduplication. Contacts are
+// indexed as they are when added.
+type DedupIndex struct {
+	index    *vcard.DedupIndex
+	indexed  map[*Contact]*vcard.Contact
+	contacts map[*vcard.Contact]*Contact // The contacts added, by their indexed copy
+}
+
+// NewDedupIndex creates an index from a slice of contacts
+func NewDedupIndex(contacts []*Contact) *DedupIndex {
+	idx := &DedupIndex{
+		index:    vcard.NewDedupIndex(nil),
+		indexed:  make(map[*Contact]*vcard.Contact),
+		contacts: make(map[*vcard.Contact]*Contact),
+	}
+	for _, c := range contacts {
+		idx.Add(c)
+	}
+	return idx
+}
+
+// Add indexes a contact for dedup lookups
+func (idx *DedupIndex) Add(c *Contact) {
+	ic := toInternal(c)
+	idx.indexed[c] = ic
+	idx.contacts[ic] = c
+	idx.index.Add(ic)
+}
+
+// lookup returns the indexed copy of c, or a new one when c wasn't added
+func (idx *DedupIndex) lookup(c *Contact) *vcard.Contact {
+	if ic, ok := idx.indexed[c]; ok {
+		return ic
+	}
+	return toInternal(c)
+}
+
+// FindDuplicates returns contacts that likely match the given contact
+func (idx *DedupIndex) FindDuplicates(c *Contact) []*Contact {
+	var duplicates []*Contact
+	for _, d := range idx.index.FindDuplicates(idx.lookup(c)) {
+		duplicates = append(duplicates, idx.contacts[d])
+	}
+	return duplicates
+}
+
+// FindMatches returns the candidates of FindDuplicates scored against c,
+// best first
+func (idx *DedupIndex) FindMatches(c *Contact) []Match {
+	var matches []Match
+	for _, m := range idx.index.FindMatches(idx.lookup(c)) {
+		matches = append(matches, Match{
+			Contact:  idx.contacts[m.Contact],
+			Score:    m.Score,
+			Strength: matchStrength(m.Strength),
+			Reasons:  m.Reasons,
+		})
+	}
+	return matches
+}
+
+// MergeContacts merges missing fields from src into dst.
+// Returns true if any fields were merged.
+func MergeContacts(dst, src *Contact) bool {
+	merged := toInternal(dst)
+	if !vcard.MergeContacts(merged, toInternal(src)) {
+		return false
+	}
+	*dst = *fromInternal(merged)
+	return true
+}
+
+// CompareContacts returns the match strength between two contacts
+func CompareContacts(a, b *Contact) MatchStrength {
+	return matchStrength(vcard.CompareContacts(toInternal(a), toInternal(b), vcard.DedupOptions{}))
+}
+
+// ScoreContacts compares a with the candidate b
+func ScoreContacts(a, b *Contact) Match {
+	m := vcard.ScoreContacts(toInternal(a), toInternal(b), vcard.DedupOptions{})
+	return Match{Contact: b, Score: m.Score, Strength: matchStrength(m.Strength), Reasons: m.Reasons}
+}
@@ -0,0 +1,94 @@
+package anyvcard
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const sampleVCards = `BEGIN:VCARD
+VERSION:3.0
+FN:John Doe
+EMAIL:john.doe@gmail.com
+TEL:+1-555-123-4567
+END:VCARD
+BEGIN:VCARD
+VERSION:3.0
+FN:Johnny Doe
+EMAIL:johndoe+work@gmail.com
+ORG:Acme
+END:VCARD
+`
+
+func TestParseReaderDedupAndMerge(t *testing.T) {
+	contacts, err := ParseReader(strings.NewReader(sampleVCards))
+	if err != nil {
+		t.Fatalf("ParseReader() error = %v", err)
+	}
+	if len(contacts) != 2 {
+		t.Fatalf("expected 2 contacts, got %d", len(contacts))
+	}
+
+	idx := NewDedupIndex([]*Contact{&contacts[0]})
+	dups := idx.FindDuplicates(&contacts[1])
+	if len(dups) != 1 || dups[0] != &contacts[0] {
+		t.Fatalf("expected the second contact to match the first, got %v", dups)
+	}
+	if got := CompareContacts(&contacts[0], &contacts[1]); got != MatchStrong {
+		t.Errorf("CompareContacts() = %v, want MatchStrong", got)
+	}
+
+	if !MergeContacts(&contacts[0], &contacts[1]) {
+		t.Fatal("expected MergeContacts to merge the organization")
+	}
+	if contacts[0].Organization != "Acme" {
+		t.Errorf("Organization = %q, want %q", contacts[0].Organization, "Acme")
+	}
+}
+
+func TestContactConversion(t *testing.T) {
+	c := Contact{
+		FormattedName: "Jane Doe",
+		GivenName:     "Jane",
+		FamilyName:    "Doe",
+		Emails:        []string{"jane@example.com"},
+		Phones:        []string{"+1 555 0100"},
+		Addresses:     []Address{{Street: "Main St 1", City: "Springfield", Full: "Main St 1, Springfield"}},
+		Organization:  "Acme",
+		Department:    "Engineering",
+		Categories:    []string{"work"},
+		Favorite:      true,
+		UID:           "uid-1",
+		ObjectID:      "obj-1",
+	}
+	if got := fromInternal(toInternal(&c)); !reflect.DeepEqual(*got, c) {
+		t.Errorf("round trip = %+v, want %+v", *got, c)
+	}
+
+	internal := toInternal(&c)
+	internal.Emails[0] = "changed@example.com"
+	if c.Emails[0] != "jane@example.com" {
+		t.Error("converting a contact must copy its slices")
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(sampleVCards))
+	var names []string
+	for {
+		c, err := dec.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		names = append(names, c.DisplayName())
+	}
+	if !slices.Equal(names, []string{"John Doe", "Johnny Doe"}) {
+		t.Errorf("decoded %q", names)
+	}
+}
@@ -0,0 +1,121 @@
+package anyvcard
+
+import (
+	"slices"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+)
+
+// toInternal converts c to the contact the pipeline works with. Slices
+// are copied, so the pipeline never changes c.
+func toInternal(c *Contact) *vcard.Contact {
+	var addresses []vcard.Address
+	for _, a := range c.Addresses {
+		addresses = append(addresses, vcard.Address{
+			Street:     a.Street,
+			City:       a.City,
+			Region:     a.Region,
+			PostalCode: a.PostalCode,
+			Country:    a.Country,
+			Full:       a.Full,
+		})
+	}
+	return &vcard.Contact{
+		FormattedName: c.FormattedName,
+		GivenName:     c.GivenName,
+		FamilyName:    c.FamilyName,
+		MiddleName:    c.MiddleName,
+		Prefix:        c.Prefix,
+		Suffix:        c.Suffix,
+		Nickname:      c.Nickname,
+		Emails:        slices.Clone(c.Emails),
+		Phones:        slices.Clone(c.Phones),
+		Addresses:     addresses,
+		Organization:  c.Organization,
+		Department:    c.Department,
+		Title:         c.Title,
+		Role:          c.Role,
+		Assistant:     c.Assistant,
+		Manager:       c.Manager,
+		URLs:          slices.Clone(c.URLs),
+		Note:          c.Note,
+		Birthday:      c.Birthday,
+		Photo:         c.Photo,
+		Categories:    slices.Clone(c.Categories),
+		Favorite:      c.Favorite,
+		Kind:          c.Kind,
+		Source:        c.Source,
+		UID:           c.UID,
+		Revision:      c.Revision,
+		ObjectID:      c.ObjectID,
+	}
+}
+
+// fromInternal converts a contact of the pipeline to the public Contact
+func fromInternal(c *vcard.Contact) *Contact {
+	var addresses []Address
+	for _, a := range c.Addresses {
+		addresses = append(addresses, Address{
+			Street:     a.Street,
+			City:       a.City,
+			Region:     a.Region,
+			PostalCode: a.PostalCode,
+			Country:    a.Country,
+			Full:       a.Full,
+		})
+	}
+	return &Contact{
+		FormattedName: c.FormattedName,
+		GivenName:     c.GivenName,
+		FamilyName:    c.FamilyName,
+		MiddleName:    c.MiddleName,
+		Prefix:        c.Prefix,
+		Suffix:        c.Suffix,
+		Nickname:      c.Nickname,
+		Emails:        slices.Clone(c.Emails),
+		Phones:        slices.Clone(c.Phones),
+		Addresses:     addresses,
+		Organization:  c.Organization,
+		Department:    c.Department,
+		Title:         c.Title,
+		Role:          c.Role,
+		Assistant:     c.Assistant,
+		Manager:       c.Manager,
+		URLs:          slices.Clone(c.URLs),
+		Note:          c.Note,
+		Birthday:      c.Birthday,
+		Photo:         c.Photo,
+		Categories:    slices.Clone(c.Categories),
+		Favorite:      c.Favorite,
+		Kind:          c.Kind,
+		Source:        c.Source,
+		UID:           c.UID,
+		Revision:      c.Revision,
+		ObjectID:      c.ObjectID,
+	}
+}
+
+// fromInternalSlice converts the contacts of the pipeline to public ones
+func fromInternalSlice(contacts []vcard.Contact) []Contact {
+	if contacts == nil {
+		return nil
+	}
+	result := make([]Contact, 0, len(contacts))
+	for i := range contacts {
+		result = append(result, *fromInternal(&contacts[i]))
+	}
+	return result
+}
+
+// matchStrength converts a match strength of the pipeline
+func matchStrength(s vcard.MatchStrength) MatchStrength {
+	switch s {
+	case vcard.MatchWeak:
+		return MatchWeak
+	case vcard.MatchMedium:
+		return MatchMedium
+	case vcard.MatchStrong:
+		return MatchStrong
+	}
+	return MatchNone
+}
@@ -0,0 +1,138 @@
+package anyvcard
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rubiojr/any-vcard/internal/vcard"
+	"github.com/rubiojr/anytype-go"
+)
+
+// DuplicatePolicy controls what the Importer does with duplicate contacts
+type DuplicatePolicy int
+
+const (
+	DuplicatesMerge  DuplicatePolicy = iota // Merge missing fields into the existing contact
+	DuplicatesSkip                          // Leave the existing contact untouched
+	DuplicatesCreate                        // Import duplicates as new objects
+)
+
+// Outcome describes what happened to an imported contact
+type Outcome int
+
+const (
+	Created Outcome = iota
+	Merged
+	Skipped
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Created:
+		return "created"
+	case Merged:
+		return "merged"
+	default:
+		return "skipped"
+	}
+}
+
+// Importer imports contacts into an Anytype space, deduplicating against
+// the contacts already stored there and within the same run.
+type Importer struct {
+	client     anytype.Client
+	spaceID    string
+	typeKey    string
+	phoneKeys  []string
+	emailKeys  []string
+	templateID string
+	policy     DuplicatePolicy
+	index      *vcard.DedupIndex
+}
+
+// ImporterOption configures an Importer
+type ImporterOption func(*Importer)
+
+// WithTypeKey sets the object type used for contacts (default: "contact")
+func WithTypeKey(key string) ImporterOption {
+	return func(im *Importer) { im.typeKey = key }
+}
+
+// WithPropertyKeys sets the phone and email property keys, in order
+func WithPropertyKeys(phoneKeys, emailKeys []string) ImporterOption {
+	return func(im *Importer) {
+		im.phoneKeys = phoneKeys
+		im.emailKeys = emailKeys
+	}
+}
+
+// WithTemplate sets the template used when creating new contacts
+func WithTemplate(templateID string) ImporterOption {
+	return func(im *Importer) { im.templateID = templateID }
+}
+
+// WithDuplicatePolicy sets how duplicates are handled (default: DuplicatesMerge)
+func WithDuplicatePolicy(policy DuplicatePolicy) ImporterOption {
+	return func(im *Importer) { im.policy = policy }
+}
+
+// NewImporter creates an Importer for the given space. The defaults match
+// the type and properties created by the any-vcard CLI.
+func NewImporter(client anytype.Client, spaceID string, opts ...ImporterOption) *Importer {
+	im := &Importer{
+		client:    client,
+		spaceID:   spaceID,
+		typeKey:   "contact",
+		phoneKeys: []string{"phone", "phone2", "phone3"},
+		emailKeys: []string{"email", "email2", "email3"},
+		policy:    DuplicatesMerge,
+		index:     vcard.NewDedupIndex(nil),
+	}
+	for _, opt := range opts {
+		opt(im)
+	}
+	return im
+}
+
+// LoadExisting indexes the contacts already stored in the space so they
+// are detected as duplicates. Returns the number of contacts loaded.
+func (im *Importer) LoadExisting(ctx context.Context) (int, error) {
+	existing, err := vcard.FetchContacts(ctx, im.client, im.spaceID, im.typeKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch existing contacts: %w", err)
+	}
+	for _, c := range existing {
+		im.index.Add(c)
+	}
+	return len(existing), nil
+}
+
+// Import creates the contact in Anytype, or merges it into an existing
+// duplicate according to the duplicate policy. The ObjectID of contact is
+// set to the object created, or to the duplicate it matched.
+func (im *Importer) Import(ctx context.Context, contact *Contact) (Outcome, error) {
+	c := toInternal(contact)
+	if im.policy != DuplicatesCreate {
+		if duplicates := im.index.FindDuplicates(c); len(duplicates) > 0 {
+			existing := duplicates[0]
+			contact.ObjectID = existing.ObjectID
+			if im.policy == DuplicatesSkip || !vcard.MergeContacts(existing, c) {
+				return Skipped, nil
+			}
+			if err := vcard.Update(ctx, im.client, im.spaceID, im.phoneKeys, im.emailKeys, existing); err != nil {
+				return Skipped, err
+			}
+			return Merged, nil
+		}
+	}
+
+	props := vcard.BuildProperties(*c, im.phoneKeys, im.emailKeys)
+	id, err := vcard.CreateObject(ctx, im.client, im.spaceID, im.typeKey, c.DisplayName(), vcard.Icon(*c), props, im.templateID)
+	if err != nil {
+		return Skipped, err
+	}
+	c.ObjectID = id
+	contact.ObjectID = id
+	im.index.Add(c)
+	return Created, nil
+}
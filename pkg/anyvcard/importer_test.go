@@ -0,0 +1,83 @@
+package anyvcard
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/rubiojr/anytype-go"
+)
+
+// fakeClient keeps the objects created and updated in memory
+type fakeClient struct {
+	anytype.Client
+	created []anytype.CreateObjectRequest
+	updated map[string]int // Updates by object ID
+}
+
+func (f *fakeClient) Space(id string) anytype.SpaceClient { return &fakeSpace{client: f} }
+
+type fakeSpace struct {
+	anytype.SpaceClient
+	client *fakeClient
+}
+
+func (f *fakeSpace) Objects() anytype.ObjectsClient { return &fakeObjects{client: f.client} }
+func (f *fakeSpace) Object(id string) anytype.ObjectClient {
+	return &fakeObject{client: f.client, id: id}
+}
+
+type fakeObjects struct {
+	anytype.ObjectsClient
+	client *fakeClient
+}
+
+func (f *fakeObjects) Create(ctx context.Context, req anytype.CreateObjectRequest) (*anytype.ObjectResponse, error) {
+	f.client.created = append(f.client.created, req)
+	id := fmt.Sprintf("obj%d", len(f.client.created))
+	return &anytype.ObjectResponse{Object: anytype.Object{ID: id, Name: req.Name}}, nil
+}
+
+type fakeObject struct {
+	anytype.ObjectClient
+	client *fakeClient
+	id     string
+}
+
+func (f *fakeObject) Update(ctx context.Context, req anytype.UpdateObjectRequest) error {
+	f.client.updated[f.id]++
+	return nil
+}
+
+func TestImporter_ImportTwice(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeClient{updated: make(map[string]int)}
+	im := NewImporter(client, "space")
+
+	first := Contact{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}}
+	outcome, err := im.Import(ctx, &first)
+	if err != nil || outcome != Created {
+		t.Fatalf("first Import() = %v, %v; want created", outcome, err)
+	}
+	if first.ObjectID != "obj1" {
+		t.Errorf("ObjectID = %q, want the created object obj1", first.ObjectID)
+	}
+
+	second := Contact{FormattedName: "Jane Doe", Emails: []string{"jane@example.com"}, Organization: "Acme"}
+	outcome, err = im.Import(ctx, &second)
+	if err != nil || outcome != Merged {
+		t.Fatalf("second Import() = %v, %v; want merged", outcome, err)
+	}
+	if second.ObjectID != "obj1" || client.updated["obj1"] != 1 {
+		t.Errorf("ObjectID = %q, updates = %v; want obj1 updated once", second.ObjectID, client.updated)
+	}
+
+	third := second
+	third.ObjectID = ""
+	if outcome, err = im.Import(ctx, &third); err != nil || outcome != Skipped {
+		t.Errorf("third Import() = %v, %v; want skipped", outcome, err)
+	}
+	if len(client.created) != 1 {
+		t.Errorf("created %d objects, want 1", len(client.created))
+	}
+}